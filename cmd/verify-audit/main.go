@@ -0,0 +1,81 @@
+// Command verify-audit walks the audit trails of time entries and reports where they are broken, see
+// models.TimeEntryAudit. It reads the database from DB_CONNECTION like the server, without changing its schema, and
+// exits with status 1 if any trail is broken.
+//
+// Records removed from the end of a trail leave a shorter trail that is still intact. The command therefore prints
+// the head of every intact trail; keep it outside of the database and pass it back with -head on the next run to
+// reveal such records.
+//
+// Usage:
+//
+//	verify-audit [-company id [-head sequence:hash]]
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/r-52/embrace/models"
+	"github.com/r-52/embrace/repositories"
+	"github.com/r-52/embrace/services/timeentry"
+)
+
+func main() {
+	companyID := flag.Uint("company", 0, "verify the trail of this company only")
+	headFlag := flag.String("head", "", "a head printed before that the trail of the company must still contain")
+	flag.Parse()
+
+	var head *timeentry.AuditHead
+	if *headFlag != "" {
+		if *companyID == 0 {
+			fmt.Fprintln(os.Stderr, "-head requires -company")
+			os.Exit(2)
+		}
+		parsed, err := timeentry.ParseAuditHead(*headFlag)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(2)
+		}
+		head = &parsed
+	}
+
+	uow := repositories.NewUnitOfWork(models.ConnectDatabase())
+	companyIDs := []uint{*companyID}
+	if *companyID == 0 {
+		var err error
+		if companyIDs, err = uow.TimeEntryAudits().GetCompanyIDs(); err != nil {
+			fmt.Fprintf(os.Stderr, "failed to read the audit trails: %v\n", err)
+			os.Exit(2)
+		}
+	}
+
+	broken := false
+	for _, id := range companyIDs {
+		breaks, err := timeentry.VerifyAuditTrail(uow, id, head)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "failed to verify the audit trail of company %d: %v\n", id, err)
+			os.Exit(2)
+		}
+		for _, b := range breaks {
+			fmt.Println(b)
+		}
+		if len(breaks) > 0 {
+			broken = true
+			continue
+		}
+		current, err := timeentry.GetAuditHead(uow, id)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "failed to read the head of the audit trail of company %d: %v\n", id, err)
+			os.Exit(2)
+		}
+		if current == nil {
+			fmt.Printf("company %d: audit trail empty\n", id)
+		} else {
+			fmt.Printf("company %d: audit trail intact, head %s\n", id, current)
+		}
+	}
+	if broken {
+		os.Exit(1)
+	}
+}
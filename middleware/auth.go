@@ -0,0 +1,50 @@
+package middleware
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/r-52/embrace/models"
+	"github.com/r-52/embrace/services/auth"
+)
+
+const currentUserKey = "currentUser"
+
+// RequireAuthentication returns a gin middleware that verifies the bearer access token of the request
+// and stores the authenticated `models.User`, with its company and role loaded, in the request context.
+// Requests without a valid token are aborted with 401 Unauthorized.
+func RequireAuthentication(authenticator auth.AuthenticatorInterface) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		header := c.GetHeader("Authorization")
+		scheme, token, found := strings.Cut(header, " ")
+		if !found || !strings.EqualFold(scheme, "Bearer") || token == "" {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": auth.ErrInvalidToken.Error()})
+			return
+		}
+
+		user, err := authenticator.Authenticate(token)
+		if errors.Is(err, auth.ErrInvalidToken) {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": auth.ErrInvalidToken.Error()})
+			return
+		}
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+
+		c.Set(currentUserKey, user)
+		c.Next()
+	}
+}
+
+// CurrentUser returns the user stored by RequireAuthentication, or nil if the request is not authenticated.
+func CurrentUser(c *gin.Context) *models.User {
+	value, exists := c.Get(currentUserKey)
+	if !exists {
+		return nil
+	}
+	user, _ := value.(*models.User)
+	return user
+}
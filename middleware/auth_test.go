@@ -0,0 +1,69 @@
+package middleware_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/r-52/embrace/middleware"
+	"github.com/r-52/embrace/models"
+	dto "github.com/r-52/embrace/models/dto/auth"
+	"github.com/r-52/embrace/services/auth"
+)
+
+type fakeAuthenticator struct {
+	user *models.User
+}
+
+func (f *fakeAuthenticator) Login(req *dto.LoginRequest) (*dto.TokenResponse, error) {
+	return nil, nil
+}
+
+func (f *fakeAuthenticator) Refresh(req *dto.RefreshRequest) (*dto.TokenResponse, error) {
+	return nil, nil
+}
+
+func (f *fakeAuthenticator) Logout(req *dto.RefreshRequest) error {
+	return nil
+}
+
+func (f *fakeAuthenticator) Authenticate(accessToken string) (*models.User, error) {
+	if accessToken != "valid" {
+		return nil, auth.ErrInvalidToken
+	}
+	return f.user, nil
+}
+
+func newRouter(authenticator auth.AuthenticatorInterface) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/me", middleware.RequireAuthentication(authenticator), func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"email": middleware.CurrentUser(c).Email})
+	})
+	return router
+}
+
+func TestRequireAuthentication(t *testing.T) {
+	router := newRouter(&fakeAuthenticator{user: &models.User{Email: "me@test.com"}})
+
+	cases := map[string]int{
+		"":               http.StatusUnauthorized,
+		"Bearer":         http.StatusUnauthorized,
+		"Basic valid":    http.StatusUnauthorized,
+		"Bearer invalid": http.StatusUnauthorized,
+		"Bearer valid":   http.StatusOK,
+	}
+	for header, expected := range cases {
+		req := httptest.NewRequest(http.MethodGet, "/me", nil)
+		if header != "" {
+			req.Header.Set("Authorization", header)
+		}
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+
+		if rec.Code != expected {
+			t.Errorf("header %q: expected status %d, got %d", header, expected, rec.Code)
+		}
+	}
+}
@@ -0,0 +1,33 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/r-52/embrace/services/auth"
+)
+
+// RequirePermission returns a gin middleware that aborts the request with 403 Forbidden
+// unless the authenticated user's role grants at least one of the given permissions.
+// It has to run after RequireAuthentication.
+func RequirePermission(permissions ...string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		for _, permission := range permissions {
+			if HasPermission(c, permission) {
+				c.Next()
+				return
+			}
+		}
+		c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": auth.ErrPermissionDenied.Error()})
+	}
+}
+
+// HasPermission reports whether the authenticated user's role grants the permission.
+// Handlers use it to decide between scopes, e.g. reading only their own entries or the whole company's.
+func HasPermission(c *gin.Context, permission string) bool {
+	user := CurrentUser(c)
+	if user == nil {
+		return false
+	}
+	return user.Role.HasPermission(permission)
+}
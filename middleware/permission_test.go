@@ -0,0 +1,47 @@
+package middleware_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/r-52/embrace/middleware"
+	"github.com/r-52/embrace/models"
+)
+
+func TestRequirePermission(t *testing.T) {
+	user := &models.User{
+		Role: models.UserRole{
+			Permissions: []models.RolePermission{{Permission: models.PERMISSION_TIME_ENTRIES_WRITE_TEAM}},
+		},
+	}
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	protected := router.Group("", middleware.RequireAuthentication(&fakeAuthenticator{user: user}))
+	protected.GET("/own", middleware.RequirePermission(models.PERMISSION_TIME_ENTRIES_WRITE_OWN), func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+	protected.GET("/all", middleware.RequirePermission(models.PERMISSION_TIME_ENTRIES_WRITE_ALL), func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+	protected.GET("/any", middleware.RequirePermission(models.PERMISSION_QUOTAS_MANAGE, models.PERMISSION_TIME_ENTRIES_WRITE_TEAM), func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+
+	cases := map[string]int{
+		"/own": http.StatusOK,
+		"/all": http.StatusForbidden,
+		"/any": http.StatusOK,
+	}
+	for path, expected := range cases {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		req.Header.Set("Authorization", "Bearer valid")
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+
+		if rec.Code != expected {
+			t.Errorf("%s: expected status %d, got %d", path, expected, rec.Code)
+		}
+	}
+}
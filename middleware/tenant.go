@@ -0,0 +1,21 @@
+package middleware
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/r-52/embrace/repositories"
+	"gorm.io/gorm"
+)
+
+// TenantDatabase returns a session of db that is scoped to the authenticated user's company
+// and bound to the request context. Repositories and services created from it cannot
+// read or write data of other companies, and their writes are audited as changes of the user.
+// It has to be used after RequireAuthentication.
+func TenantDatabase(c *gin.Context, db *gorm.DB) *gorm.DB {
+	session := db.WithContext(c.Request.Context())
+	user := CurrentUser(c)
+	if user == nil {
+		// Without a tenant nothing may be visible, company IDs start at 1.
+		return repositories.WithTenant(session, 0)
+	}
+	return repositories.WithActor(repositories.WithTenant(session, user.CompanyID), user.ID)
+}
@@ -0,0 +1,25 @@
+package migrations
+
+import (
+	"github.com/r-52/embrace/models"
+	"github.com/r-52/embrace/services/role"
+	"gorm.io/gorm"
+)
+
+// seedDefaultRoles gives companies created before roles were scoped per company their default roles.
+// The "admin" role that used to be created together with the first user is kept and receives the admin permissions.
+func seedDefaultRoles(tx *gorm.DB) error {
+	var companies []models.Company
+	err := tx.Find(&companies).Error
+	if err != nil {
+		return err
+	}
+
+	roleService := role.NewRoleService(tx)
+	for _, company := range companies {
+		if err := roleService.SeedDefaultRoles(company.ID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,15 @@
+package migrations
+
+import (
+	"github.com/r-52/embrace/models"
+	"gorm.io/gorm"
+)
+
+// backfillTimeEntryCompany assigns time entries created before they had a company to the company of their user.
+func backfillTimeEntryCompany(tx *gorm.DB) error {
+	if !tx.Migrator().HasTable(&models.TimeEntry{}) {
+		return nil
+	}
+	return tx.Exec(`UPDATE time_entries SET company_id = (SELECT users.company_id FROM users WHERE users.id = time_entries.user_id)
+		WHERE company_id IS NULL OR company_id = 0`).Error
+}
@@ -0,0 +1,16 @@
+package migrations
+
+import (
+	"github.com/r-52/embrace/models"
+	"gorm.io/gorm"
+)
+
+// backfillUserQuotaEntitlement records the count of their quota as the entitlement of user quotas that were
+// assigned by hand before entitlements were allocated, so recalculating them does not grant the quota twice.
+func backfillUserQuotaEntitlement(tx *gorm.DB) error {
+	if !tx.Migrator().HasTable(&models.UserQuota{}) || !tx.Migrator().HasColumn(&models.UserQuota{}, "Entitlement") {
+		return nil
+	}
+	return tx.Exec(`UPDATE user_quota SET entitlement = (SELECT quota.count FROM quota WHERE quota.id = user_quota.quota_id)
+		WHERE entitlement = 0 AND EXISTS (SELECT 1 FROM quota WHERE quota.id = user_quota.quota_id)`).Error
+}
@@ -0,0 +1,39 @@
+package migrations
+
+import (
+	"fmt"
+
+	"github.com/r-52/embrace/models"
+	"gorm.io/gorm"
+)
+
+// quotaAmountColumns are the columns that held whole units of quota before they became models.QuotaAmount.
+var quotaAmountColumns = []struct {
+	model   interface{}
+	table   string
+	columns []string
+}{
+	{model: &models.Quota{}, table: "quota", columns: []string{"count", "carry_over_max"}},
+	{model: &models.UserQuota{}, table: "user_quota", columns: []string{"count", "entitlement"}},
+	{model: &models.UserQuotaReset{}, table: "user_quota_resets", columns: []string{"previous_count", "count", "carried"}},
+	{model: &models.UserQuotaCarryOver{}, table: "user_quota_carry_overs", columns: []string{"carried", "remaining", "expired"}},
+}
+
+// scaleQuotaAmounts converts quota amounts stored as whole units into thousandths of a unit.
+func scaleQuotaAmounts(tx *gorm.DB) error {
+	for _, table := range quotaAmountColumns {
+		if !tx.Migrator().HasTable(table.model) {
+			continue
+		}
+		for _, column := range table.columns {
+			if !tx.Migrator().HasColumn(table.model, column) {
+				continue
+			}
+			sql := fmt.Sprintf("UPDATE %s SET %s = %s * ?", table.table, column, column)
+			if err := tx.Exec(sql, models.QUOTA_AMOUNT_SCALE).Error; err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
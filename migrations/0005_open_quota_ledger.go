@@ -0,0 +1,24 @@
+package migrations
+
+import (
+	"time"
+
+	"github.com/r-52/embrace/models"
+	"gorm.io/gorm"
+)
+
+// openQuotaLedger books the balances of user quotas that existed before the ledger of quota transactions
+// as opening adjustments, so the ledger adds up to every balance from then on.
+func openQuotaLedger(tx *gorm.DB) error {
+	if !tx.Migrator().HasTable(&models.UserQuota{}) || !tx.Migrator().HasTable(&models.UserQuotaTransaction{}) {
+		return nil
+	}
+	now := time.Now().UTC()
+	return tx.Exec(`INSERT INTO user_quota_transactions
+		(created_at, updated_at, user_quota_id, user_id, company_id, kind, amount, balance, booked_at, reason)
+		SELECT ?, ?, user_quota.id, user_quota.user_id, users.company_id, ?, user_quota.count, user_quota.count, ?, 'opening balance'
+		FROM user_quota JOIN users ON users.id = user_quota.user_id
+		WHERE user_quota.deleted_at IS NULL AND user_quota.count <> 0
+		AND NOT EXISTS (SELECT 1 FROM user_quota_transactions WHERE user_quota_transactions.user_quota_id = user_quota.id)`,
+		now, now, models.QUOTA_TRANSACTION_ADJUSTMENT, now).Error
+}
@@ -0,0 +1,30 @@
+package migrations
+
+import (
+	"github.com/r-52/embrace/models"
+	"gorm.io/gorm"
+)
+
+// deduplicateUserQuota removes the quotas that concurrent assignments gave a user twice, before the unique index
+// on user and quota is created. Bookings always went to the oldest of them, so the younger ones only hold a second
+// entitlement and are deleted together with their ledger, carry-overs and resets.
+func deduplicateUserQuota(tx *gorm.DB) error {
+	if !tx.Migrator().HasTable(&models.UserQuota{}) {
+		return nil
+	}
+	var duplicateIDs []uint
+	err := tx.Raw(`SELECT id FROM user_quota WHERE EXISTS (SELECT 1 FROM user_quota oldest
+		WHERE oldest.user_id = user_quota.user_id AND oldest.quota_id = user_quota.quota_id AND oldest.id < user_quota.id)`).Scan(&duplicateIDs).Error
+	if err != nil || len(duplicateIDs) == 0 {
+		return err
+	}
+	for _, dependent := range []interface{}{&models.UserQuotaTransaction{}, &models.UserQuotaCarryOver{}, &models.UserQuotaReset{}} {
+		if !tx.Migrator().HasTable(dependent) {
+			continue
+		}
+		if err := tx.Unscoped().Where("user_quota_id IN ?", duplicateIDs).Delete(dependent).Error; err != nil {
+			return err
+		}
+	}
+	return tx.Unscoped().Where("id IN ?", duplicateIDs).Delete(&models.UserQuota{}).Error
+}
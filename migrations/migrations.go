@@ -0,0 +1,78 @@
+package migrations
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// SchemaMigration records a data migration that has been applied to the database.
+// Schema changes are handled by AutoMigrate in models.MigrateDatabase, migrations only
+// move or backfill data that AutoMigrate cannot derive on its own, or clean up data it would reject.
+type SchemaMigration struct {
+	ID        string    `gorm:"primaryKey"`
+	AppliedAt time.Time `gorm:"not null"`
+}
+
+type Migration struct {
+	ID      string
+	Migrate func(tx *gorm.DB) error
+}
+
+// MIGRATIONS are applied in order. Never change or remove an entry once it was released,
+// append a new one instead.
+var MIGRATIONS = []Migration{
+	{ID: "0001_seed_default_roles", Migrate: seedDefaultRoles},
+	{ID: "0002_backfill_time_entry_company", Migrate: backfillTimeEntryCompany},
+	{ID: "0003_backfill_user_quota_entitlement", Migrate: backfillUserQuotaEntitlement},
+	{ID: "0004_scale_quota_amounts", Migrate: scaleQuotaAmounts},
+	{ID: "0005_open_quota_ledger", Migrate: openQuotaLedger},
+}
+
+// PREPARATIONS are applied like MIGRATIONS, but before models.MigrateDatabase. They clean up data
+// the new schema would reject, such as duplicates of a new unique index.
+var PREPARATIONS = []Migration{
+	{ID: "0006_deduplicate_user_quota", Migrate: deduplicateUserQuota},
+}
+
+// Prepare applies every migration of PREPARATIONS that has not been recorded yet.
+func Prepare(db *gorm.DB) error {
+	return Apply(db, PREPARATIONS)
+}
+
+// Run applies every migration of MIGRATIONS that has not been recorded yet.
+func Run(db *gorm.DB) error {
+	return Apply(db, MIGRATIONS)
+}
+
+// Apply applies the given migrations that have not been recorded yet.
+// Each migration runs in its own transaction together with its record, so a failed
+// migration leaves no partial data behind and is retried on the next start.
+func Apply(db *gorm.DB, migrations []Migration) error {
+	err := db.AutoMigrate(&SchemaMigration{})
+	if err != nil {
+		return err
+	}
+
+	for _, migration := range migrations {
+		var count int64
+		err := db.Model(&SchemaMigration{}).Where("id = ?", migration.ID).Count(&count).Error
+		if err != nil {
+			return err
+		}
+		if count > 0 {
+			continue
+		}
+
+		err = db.Transaction(func(tx *gorm.DB) error {
+			if err := migration.Migrate(tx); err != nil {
+				return err
+			}
+			return tx.Create(&SchemaMigration{ID: migration.ID, AppliedAt: time.Now()}).Error
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,224 @@
+package migrations_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/r-52/embrace/migrations"
+	"github.com/r-52/embrace/models"
+	"github.com/r-52/embrace/repositories"
+	"github.com/r-52/embrace/services/role"
+	"gorm.io/gorm"
+)
+
+func TestApply_Runs_Each_Migration_Once(t *testing.T) {
+	db := repositories.GetDatabase()
+	calls := 0
+	migration := migrations.Migration{ID: "test", Migrate: func(tx *gorm.DB) error {
+		calls++
+		return nil
+	}}
+
+	for i := 0; i < 2; i++ {
+		if err := migrations.Apply(db, []migrations.Migration{migration}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	if calls != 1 {
+		t.Errorf("expected migration to run once, ran %d times", calls)
+	}
+}
+
+// legacyUserRole is the user_roles schema before role names were scoped per company.
+type legacyUserRole struct {
+	gorm.Model
+	Name          string `gorm:"unique;not null"`
+	InternalUsage int    `gorm:"default:0"`
+	CompanyID     uint
+}
+
+func (legacyUserRole) TableName() string {
+	return "user_roles"
+}
+
+func TestRun_Upgrades_Legacy_Roles(t *testing.T) {
+	db := repositories.GetDatabase()
+	if err := db.AutoMigrate(&models.Company{}, &legacyUserRole{}); err != nil {
+		t.Fatalf("failed to create legacy schema: %v", err)
+	}
+	db.Create(&models.Company{Name: "Legacy", PrimaryEmail: "legacy@test.com"})
+	db.Create(&models.Company{Name: "Orphan", PrimaryEmail: "orphan@test.com"})
+	db.Create(&legacyUserRole{Name: role.DEFAULT_ROLE_ADMIN, InternalUsage: models.ROLE_INTERNAL_USAGE_ADMIN, CompanyID: 1})
+
+	if err := db.AutoMigrate(&models.UserRole{}, &models.RolePermission{}, &models.User{}); err != nil {
+		t.Fatalf("failed to migrate schema: %v", err)
+	}
+	if err := migrations.Run(db); err != nil {
+		t.Fatalf("failed to run migrations: %v", err)
+	}
+
+	roles := repositories.NewUserRoleRepository(db)
+	for _, companyID := range []uint{1, 2} {
+		for _, defaultRole := range role.DEFAULT_ROLES {
+			seeded, err := roles.GetByCompanyIDAndName(companyID, defaultRole.Name)
+			if err != nil {
+				t.Fatalf("expected role %q for company %d, got %v", defaultRole.Name, companyID, err)
+			}
+			seeded, _ = roles.GetByIDWithPermissions(seeded.ID)
+			if len(seeded.Permissions) != len(defaultRole.Permissions) {
+				t.Errorf("expected role %q of company %d to have default permissions, got %+v", defaultRole.Name, companyID, seeded.Permissions)
+			}
+		}
+	}
+
+	// The legacy admin role keeps its ID, so users attached to it stay admins.
+	admin, _ := roles.GetByCompanyIDAndName(1, role.DEFAULT_ROLE_ADMIN)
+	if admin.ID != 1 {
+		t.Errorf("expected legacy admin role to be kept, got ID %d", admin.ID)
+	}
+}
+
+func TestRun_Backfills_Time_Entry_Company(t *testing.T) {
+	db := repositories.GetDatabase()
+	if err := db.AutoMigrate(&models.Company{}, &models.UserRole{}, &models.RolePermission{}, &models.User{}, &models.TimeEntry{}); err != nil {
+		t.Fatalf("failed to migrate schema: %v", err)
+	}
+	db.Create(&models.User{Email: "legacy@test.com", Password: "secret", CompanyID: 7})
+	db.Exec("INSERT INTO time_entries (start_time, user_id, time_entry_type_id) VALUES (?, 1, 1)", time.Now())
+
+	if err := migrations.Run(db); err != nil {
+		t.Fatalf("failed to run migrations: %v", err)
+	}
+
+	entry, err := repositories.NewTimeEntryRepository(db).GetByID(1)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if entry.CompanyID != 7 {
+		t.Errorf("expected company 7, got %d", entry.CompanyID)
+	}
+}
+
+func TestRun_Backfills_User_Quota_Entitlement(t *testing.T) {
+	db := repositories.GetDatabase()
+	if err := db.AutoMigrate(&models.Company{}, &models.UserRole{}, &models.RolePermission{}, &models.User{}, &models.Quota{}, &models.UserQuota{}); err != nil {
+		t.Fatalf("failed to migrate schema: %v", err)
+	}
+	db.Create(&models.Quota{Name: "vacation", CompanyID: 1, Count: 30})
+	db.Create(&models.UserQuota{UserID: 1, QuotaID: 1, Count: 12})
+
+	if err := migrations.Run(db); err != nil {
+		t.Fatalf("failed to run migrations: %v", err)
+	}
+
+	userQuota, err := repositories.NewUserQuotaRepository(db).GetByID(1)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if userQuota.Entitlement.Units() != 30 || userQuota.Count.Units() != 12 {
+		t.Errorf("expected entitlement 30 and count 12, got %d and %d", userQuota.Entitlement, userQuota.Count)
+	}
+}
+
+func TestRun_Scales_Quota_Amounts(t *testing.T) {
+	db := repositories.GetDatabase()
+	if err := db.AutoMigrate(&models.Company{}, &models.UserRole{}, &models.RolePermission{}, &models.User{},
+		&models.Quota{}, &models.UserQuota{}, &models.UserQuotaCarryOver{}); err != nil {
+		t.Fatalf("failed to migrate schema: %v", err)
+	}
+	db.Exec("INSERT INTO quota (name, company_id, count, carry_over_max) VALUES ('vacation', 1, 30, 5)")
+	db.Exec("INSERT INTO user_quota_carry_overs (user_quota_id, quota_reset_id, carried, remaining) VALUES (1, 1, 5, 2)")
+
+	if err := migrations.Run(db); err != nil {
+		t.Fatalf("failed to run migrations: %v", err)
+	}
+
+	quota, err := repositories.NewQuotaRepository(db).GetByID(1)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if quota.Count.Units() != 30 || quota.CarryOverMax.Units() != 5 {
+		t.Errorf("expected count 30 and carry-over max 5, got %v and %v", quota.Count, quota.CarryOverMax)
+	}
+	carryOver, err := repositories.NewUserQuotaCarryOverRepository(db).GetByID(1)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if carryOver.Carried.Units() != 5 || carryOver.Remaining.Units() != 2 {
+		t.Errorf("expected 5 carried and 2 remaining, got %v and %v", carryOver.Carried, carryOver.Remaining)
+	}
+}
+
+func TestRun_Opens_Quota_Ledger(t *testing.T) {
+	db := repositories.GetDatabase()
+	if err := db.AutoMigrate(&models.Company{}, &models.UserRole{}, &models.RolePermission{}, &models.User{},
+		&models.Quota{}, &models.UserQuota{}, &models.UserQuotaTransaction{}); err != nil {
+		t.Fatalf("failed to migrate schema: %v", err)
+	}
+	db.Exec("INSERT INTO users (email, password, company_id) VALUES ('user@example.com', 'secret', 7)")
+	db.Exec("INSERT INTO user_quota (user_id, quota_id, count) VALUES (1, 1, 12), (1, 2, 0)")
+
+	if err := migrations.Run(db); err != nil {
+		t.Fatalf("failed to run migrations: %v", err)
+	}
+
+	transactions := repositories.NewUserQuotaTransactionRepository(db)
+	opened, err := transactions.GetByUserQuotaID(1, time.Time{}, time.Time{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(opened) != 1 || opened[0].Amount.Units() != 12 || opened[0].Balance.Units() != 12 || opened[0].CompanyID != 7 {
+		t.Errorf("expected an opening balance of 12 in company 7, got %+v", opened)
+	}
+	if empty, _ := transactions.GetByUserQuotaID(2, time.Time{}, time.Time{}); len(empty) != 0 {
+		t.Errorf("expected no opening balance for an empty quota, got %+v", empty)
+	}
+}
+
+// legacyUserQuota is the user_quota schema before a user could have each quota only once.
+type legacyUserQuota struct {
+	gorm.Model
+	QuotaID uint
+	UserID  uint
+	Count   models.QuotaAmount
+}
+
+func (legacyUserQuota) TableName() string {
+	return "user_quota"
+}
+
+func TestPrepare_Deduplicates_User_Quota(t *testing.T) {
+	db := repositories.GetDatabase()
+	if err := db.AutoMigrate(&legacyUserQuota{}, &models.UserQuotaTransaction{}); err != nil {
+		t.Fatalf("failed to create legacy schema: %v", err)
+	}
+	db.Exec("INSERT INTO user_quota (user_id, quota_id, count) VALUES (1, 1, 12), (1, 1, 30), (1, 2, 5)")
+	db.Exec("INSERT INTO user_quota_transactions (user_quota_id, user_id, kind, amount, balance, booked_at) VALUES (1, 1, 'allocation', 12, 12, ?), (2, 1, 'allocation', 30, 30, ?)",
+		time.Now(), time.Now())
+
+	if err := migrations.Prepare(db); err != nil {
+		t.Fatalf("failed to prepare the database: %v", err)
+	}
+	if err := db.AutoMigrate(&models.UserQuota{}); err != nil {
+		t.Fatalf("failed to migrate schema: %v", err)
+	}
+
+	userQuotas, err := repositories.NewUserQuotaRepository(db).GetByUserID(1)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(userQuotas) != 2 || userQuotas[0].ID != 1 || userQuotas[1].ID != 3 {
+		t.Errorf("expected the oldest assignment of each quota to be kept, got %+v", userQuotas)
+	}
+	transactions := repositories.NewUserQuotaTransactionRepository(db)
+	if removed, _ := transactions.GetByUserQuotaID(2, time.Time{}, time.Time{}); len(removed) != 0 {
+		t.Errorf("expected the ledger of the duplicate to be removed, got %+v", removed)
+	}
+	if kept, _ := transactions.GetByUserQuotaID(1, time.Time{}, time.Time{}); len(kept) != 1 {
+		t.Errorf("expected the ledger of the oldest assignment to be kept, got %+v", kept)
+	}
+	if err := db.Create(&models.UserQuota{UserID: 1, QuotaID: 1}).Error; !errors.Is(err, gorm.ErrDuplicatedKey) {
+		t.Errorf("expected ErrDuplicatedKey after the migration, got %v", err)
+	}
+}
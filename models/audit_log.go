@@ -0,0 +1,34 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// AuditLog records a write to the database: who created, updated or deleted which row of which table in which
+// company, and what changed. Time entries keep their own trail, see TimeEntryAudit.
+type AuditLog struct {
+	gorm.Model
+
+	CompanyID uint `json:"-" gorm:"index;not null"`
+	// ActorID references the user who made the change. It is nil for changes made by the system, e.g. scheduled
+	// quota resets.
+	ActorID *uint `json:"actorId" gorm:"index"`
+	// Entity is the name of the table that was changed and EntityID the primary key of the changed row.
+	Entity   string `json:"entity" gorm:"index:idx_audit_logs_entity;not null"`
+	EntityID uint   `json:"entityId" gorm:"index:idx_audit_logs_entity;not null"`
+	// Operation is one of the AUDIT_OPERATION constants.
+	Operation  string    `json:"operation" gorm:"not null"`
+	RecordedAt time.Time `json:"recordedAt" gorm:"index;not null"`
+	// Diff is a JSON object of the changed columns, see AuditChange.
+	Diff string `json:"diff" gorm:"not null"`
+}
+
+// AuditChange is the change of a column recorded in AuditLog.Diff. From is missing for created rows and To for
+// deleted ones.
+type AuditChange struct {
+	From json.RawMessage `json:"from,omitempty"`
+	To   json.RawMessage `json:"to,omitempty"`
+}
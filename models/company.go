@@ -1,6 +1,10 @@
 package models
 
-import "gorm.io/gorm"
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
 
 type Company struct {
 	gorm.Model
@@ -10,6 +14,30 @@ type Company struct {
 	Website      string `json:"website"`
 	PrimaryEmail string `json:"email" gorm:"unique;not null"`
 
+	// Timezone is the IANA name of the company's timezone. Days, weeks and reset periods begin in it.
+	Timezone string `json:"timezone" gorm:"not null;default:'UTC'"`
+
+	// AllowNegativeQuota lets bookings consume more quota than a user has left.
+	AllowNegativeQuota bool `json:"allowNegativeQuota" gorm:"not null;default:false"`
+
+	// FullTimeWorkingDays are the working days per week of a full-time employee.
+	FullTimeWorkingDays int `json:"fullTimeWorkingDays" gorm:"not null;default:5"`
+	// QuotaRounding is how pro-rated quota entitlements are rounded, one of the QUOTA_ROUNDING constants.
+	QuotaRounding string `json:"quotaRounding" gorm:"not null;default:'nearest'"`
+
 	Users          []User          `json:"users"`
 	TimeEntryTypes []TimeEntryType `json:"timeEntryTypes"`
 }
+
+const QUOTA_ROUNDING_NEAREST = "nearest"
+const QUOTA_ROUNDING_UP = "up"
+const QUOTA_ROUNDING_DOWN = "down"
+
+// Location returns the company's timezone. Unknown names fall back to UTC.
+func (c *Company) Location() *time.Location {
+	location, err := time.LoadLocation(c.Timezone)
+	if err != nil || c.Timezone == "" {
+		return time.UTC
+	}
+	return location
+}
@@ -0,0 +1,63 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// COMPLIANCE_RULE constants name the labor law rules time entries are checked against.
+const COMPLIANCE_RULE_BREAKS = "breaks"
+const COMPLIANCE_RULE_REST_PERIOD = "rest_period"
+const COMPLIANCE_RULE_DAILY_MAXIMUM = "daily_maximum"
+const COMPLIANCE_RULE_SUNDAY_WORK = "sunday_work"
+const COMPLIANCE_RULE_HOLIDAY_WORK = "holiday_work"
+
+// COMPLIANCE_SEVERITY constants are the severities of a ComplianceViolation. Blocking violations reject the time entry
+// that causes them, warnings are only reported.
+const COMPLIANCE_SEVERITY_BLOCKING = "blocking"
+const COMPLIANCE_SEVERITY_WARNING = "warning"
+
+// ComplianceRuleSet configures the labor law rules of a company. Rules it does not list are enabled as warnings.
+type ComplianceRuleSet struct {
+	gorm.Model
+
+	CompanyID uint             `json:"-" gorm:"uniqueIndex"`
+	Rules     []ComplianceRule `json:"rules" gorm:"foreignKey:ComplianceRuleSetID"`
+}
+
+// ComplianceRule enables or disables a labor law rule for a company and sets the severity of its violations.
+type ComplianceRule struct {
+	gorm.Model
+
+	ComplianceRuleSetID uint   `json:"-" gorm:"index;not null"`
+	Rule                string `json:"rule" gorm:"not null"`
+	Enabled             bool   `json:"enabled" gorm:"not null"`
+	Severity            string `json:"severity" gorm:"not null"`
+}
+
+// Rule returns the configuration of a rule, or nil if the rule set does not list it.
+func (s *ComplianceRuleSet) Rule(name string) *ComplianceRule {
+	for i := range s.Rules {
+		if s.Rules[i].Rule == name {
+			return &s.Rules[i]
+		}
+	}
+	return nil
+}
+
+// ComplianceViolation is a breach of a labor law rule by the time entries of a user. It is computed from the entries
+// and never stored. Date is the day of the shift the violation belongs to, in the company's timezone.
+type ComplianceViolation struct {
+	Rule     string    `json:"rule"`
+	Severity string    `json:"severity"`
+	UserID   uint      `json:"userId"`
+	Date     time.Time `json:"date"`
+	Message  string    `json:"message"`
+	EntryIDs []uint    `json:"entryIds"`
+}
+
+// IsBlocking reports whether the violation rejects the time entries that cause it.
+func (v *ComplianceViolation) IsBlocking() bool {
+	return v.Severity == COMPLIANCE_SEVERITY_BLOCKING
+}
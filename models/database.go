@@ -7,20 +7,20 @@ import (
 	"gorm.io/gorm"
 )
 
-func OpenDatabase() *gorm.DB {
+// ConnectDatabase connects to the database named by DB_CONNECTION without changing its schema.
+func ConnectDatabase() *gorm.DB {
 	dbConnection := os.Getenv("DB_CONNECTION")
 	if dbConnection == "" {
 		dbConnection = "test.db"
 	}
-	db, err := gorm.Open(sqlite.Open(dbConnection), &gorm.Config{})
+	db, err := gorm.Open(sqlite.Open(dbConnection), &gorm.Config{TranslateError: true})
 	if err != nil {
 		panic("failed to connect database")
 	}
-
-	err = db.AutoMigrate(&Company{}, &User{}, &UserRole{}, &TimeEntry{}, &TimeEntryType{}, &UserProfile{}, &Quota{}, &UserQuota{})
-	if err != nil {
-		panic("failed to migrate database")
-	}
-
 	return db
 }
+
+// MigrateDatabase creates the tables, columns and indexes of all models that are missing.
+func MigrateDatabase(db *gorm.DB) error {
+	return db.AutoMigrate(&Company{}, &User{}, &UserRole{}, &TimeEntry{}, &TimeEntryType{}, &UserProfile{}, &Quota{}, &UserQuota{}, &RefreshToken{}, &RolePermission{}, &QuotaReset{}, &UserQuotaReset{}, &UserQuotaCarryOver{}, &UserQuotaTransaction{}, &LeaveRequest{}, &HolidayCalendar{}, &Holiday{}, &WorkSchedule{}, &WorkScheduleDay{}, &UserWorkSchedule{}, &OvertimePolicy{}, &OvertimeWorkTimeType{}, &OvertimeSettlement{}, &ComplianceRuleSet{}, &ComplianceRule{}, &DurationPolicy{}, &TimesheetPeriod{}, &TimeEntryAudit{}, &AuditLog{})
+}
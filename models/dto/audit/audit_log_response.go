@@ -0,0 +1,41 @@
+package audit
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/r-52/embrace/models"
+)
+
+type AuditLogResponse struct {
+	ID         uint            `json:"id"`
+	ActorID    *uint           `json:"actorId"`
+	Entity     string          `json:"entity"`
+	EntityID   uint            `json:"entityId"`
+	Operation  string          `json:"operation"`
+	RecordedAt time.Time       `json:"recordedAt"`
+	Diff       json.RawMessage `json:"diff"`
+}
+
+// NewAuditLogResponse maps an audit log to its API representation. The diff is embedded as an object of
+// models.AuditChange by column.
+func NewAuditLogResponse(auditLog *models.AuditLog) *AuditLogResponse {
+	return &AuditLogResponse{
+		ID:         auditLog.ID,
+		ActorID:    auditLog.ActorID,
+		Entity:     auditLog.Entity,
+		EntityID:   auditLog.EntityID,
+		Operation:  auditLog.Operation,
+		RecordedAt: auditLog.RecordedAt,
+		Diff:       json.RawMessage(auditLog.Diff),
+	}
+}
+
+// NewAuditLogResponses maps a list of audit logs to their API representation.
+func NewAuditLogResponses(auditLogs []models.AuditLog) []*AuditLogResponse {
+	responses := make([]*AuditLogResponse, 0, len(auditLogs))
+	for i := range auditLogs {
+		responses = append(responses, NewAuditLogResponse(&auditLogs[i]))
+	}
+	return responses
+}
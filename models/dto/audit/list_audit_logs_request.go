@@ -0,0 +1,16 @@
+package audit
+
+import "time"
+
+// ListAuditLogsRequest filters the audit log. Entity is the name of a table, e.g. `user_roles`. From and To are
+// calendar days, both inclusive. Limit and Offset page through the result.
+type ListAuditLogsRequest struct {
+	Entity    string    `form:"entity" json:"entity"`
+	EntityID  uint      `form:"entityId" json:"entityId" binding:"omitempty,min=1" validate:"omitempty,gte=1"`
+	ActorID   uint      `form:"actorId" json:"actorId" binding:"omitempty,min=1" validate:"omitempty,gte=1"`
+	Operation string    `form:"operation" json:"operation" binding:"omitempty,oneof=create update delete" validate:"omitempty,oneof=create update delete"`
+	From      time.Time `form:"from" json:"from" time_format:"2006-01-02" time_utc:"1"`
+	To        time.Time `form:"to" json:"to" time_format:"2006-01-02" time_utc:"1" binding:"omitempty,gtefield=From" validate:"omitempty,gtefield=From"`
+	Limit     int       `form:"limit" json:"limit" binding:"omitempty,min=1,max=1000" validate:"omitempty,gte=1,lte=1000"`
+	Offset    int       `form:"offset" json:"offset" binding:"omitempty,min=0" validate:"omitempty,gte=0"`
+}
@@ -0,0 +1,6 @@
+package auth
+
+type LoginRequest struct {
+	Email    string `form:"email" json:"email" binding:"required,email" validate:"required,email"`
+	Password string `form:"password" json:"password" binding:"required" validate:"required"`
+}
@@ -0,0 +1,5 @@
+package auth
+
+type RefreshRequest struct {
+	RefreshToken string `form:"refreshToken" json:"refreshToken" binding:"required" validate:"required"`
+}
@@ -0,0 +1,11 @@
+package auth
+
+import "time"
+
+type TokenResponse struct {
+	AccessToken           string    `json:"accessToken"`
+	AccessTokenExpiresAt  time.Time `json:"accessTokenExpiresAt"`
+	RefreshToken          string    `json:"refreshToken"`
+	RefreshTokenExpiresAt time.Time `json:"refreshTokenExpiresAt"`
+	TokenType             string    `json:"tokenType"`
+}
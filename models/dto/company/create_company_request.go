@@ -3,8 +3,15 @@ package company
 import "github.com/r-52/embrace/models/dto/user"
 
 type CreateCompanyRequest struct {
-	Name        string                  `form:"name" json:"name" binding:"required" validate:"required,min=1,max=255"`
-	Description string                  `form:"description" json:"description"`
-	Website     string                  `form:"website" json:"website"`
-	User        *user.CreateUserRequest `form:"user" json:"user" binding:"required" validate:"required"`
+	Name        string `form:"name" json:"name" binding:"required" validate:"required,min=1,max=255"`
+	Description string `form:"description" json:"description"`
+	Website     string `form:"website" json:"website"`
+
+	Timezone           string `form:"timezone" json:"timezone" binding:"omitempty,timezone"`
+	AllowNegativeQuota bool   `form:"allowNegativeQuota" json:"allowNegativeQuota"`
+
+	FullTimeWorkingDays int    `form:"fullTimeWorkingDays" json:"fullTimeWorkingDays" binding:"omitempty,min=1,max=7" validate:"omitempty,min=1,max=7"`
+	QuotaRounding       string `form:"quotaRounding" json:"quotaRounding" binding:"omitempty,oneof=nearest up down" validate:"omitempty,oneof=nearest up down"`
+
+	User *user.CreateUserRequest `form:"user" json:"user" binding:"required" validate:"required"`
 }
@@ -0,0 +1,19 @@
+package company
+
+import "github.com/r-52/embrace/models/dto/user"
+
+type CreateCompanyResponse struct {
+	ID          uint   `json:"id"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	Website     string `json:"website"`
+	Email       string `json:"email"`
+
+	Timezone           string `json:"timezone"`
+	AllowNegativeQuota bool   `json:"allowNegativeQuota"`
+
+	FullTimeWorkingDays int    `json:"fullTimeWorkingDays"`
+	QuotaRounding       string `json:"quotaRounding"`
+
+	User *user.CreateUserResponse `json:"user"`
+}
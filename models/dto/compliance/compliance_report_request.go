@@ -0,0 +1,12 @@
+package compliance
+
+import "time"
+
+// ComplianceReportRequest selects the period of a compliance report. From and To are calendar days, both inclusive,
+// covering at most compliance.MAX_COMPLIANCE_REPORT_DAYS days.
+// Without UserID the report covers every user of the company.
+type ComplianceReportRequest struct {
+	From   time.Time `form:"from" json:"from" time_format:"2006-01-02" time_utc:"1" binding:"required" validate:"required"`
+	To     time.Time `form:"to" json:"to" time_format:"2006-01-02" time_utc:"1" binding:"required,gtefield=From" validate:"required,gtefield=From"`
+	UserID uint      `form:"userId" json:"userId"`
+}
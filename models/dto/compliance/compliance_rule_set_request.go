@@ -0,0 +1,15 @@
+package compliance
+
+// ComplianceRuleSetRequest configures the labor law rules of a company, see models.ComplianceRuleSet.
+// It replaces the previous configuration, rules it does not list are enabled as warnings.
+type ComplianceRuleSetRequest struct {
+	Rules []ComplianceRuleRequest `form:"rules" json:"rules" binding:"dive" validate:"dive"`
+}
+
+// ComplianceRuleRequest enables or disables a rule and sets the severity of its violations,
+// one of the models.COMPLIANCE_SEVERITY constants.
+type ComplianceRuleRequest struct {
+	Rule     string `form:"rule" json:"rule" binding:"required" validate:"required"`
+	Enabled  bool   `form:"enabled" json:"enabled"`
+	Severity string `form:"severity" json:"severity" binding:"required,oneof=blocking warning" validate:"required,oneof=blocking warning"`
+}
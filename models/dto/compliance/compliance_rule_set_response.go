@@ -0,0 +1,22 @@
+package compliance
+
+import "github.com/r-52/embrace/models"
+
+type ComplianceRuleResponse struct {
+	Rule     string `json:"rule"`
+	Enabled  bool   `json:"enabled"`
+	Severity string `json:"severity"`
+}
+
+// NewComplianceRuleResponses maps the configuration of rules to their API representation.
+func NewComplianceRuleResponses(rules []models.ComplianceRule) []*ComplianceRuleResponse {
+	responses := make([]*ComplianceRuleResponse, 0, len(rules))
+	for _, rule := range rules {
+		responses = append(responses, &ComplianceRuleResponse{
+			Rule:     rule.Rule,
+			Enabled:  rule.Enabled,
+			Severity: rule.Severity,
+		})
+	}
+	return responses
+}
@@ -0,0 +1,39 @@
+package compliance
+
+import (
+	"time"
+
+	"github.com/r-52/embrace/models"
+)
+
+type ViolationResponse struct {
+	Rule     string `json:"rule"`
+	Severity string `json:"severity"`
+	UserID   uint   `json:"userId"`
+	Date     string `json:"date"`
+	Message  string `json:"message"`
+	EntryIDs []uint `json:"entryIds"`
+}
+
+// NewViolationResponses maps compliance violations to their API representation.
+func NewViolationResponses(violations []models.ComplianceViolation) []*ViolationResponse {
+	responses := make([]*ViolationResponse, 0, len(violations))
+	for _, violation := range violations {
+		responses = append(responses, &ViolationResponse{
+			Rule:     violation.Rule,
+			Severity: violation.Severity,
+			UserID:   violation.UserID,
+			Date:     violation.Date.Format(time.DateOnly),
+			Message:  violation.Message,
+			EntryIDs: violation.EntryIDs,
+		})
+	}
+	return responses
+}
+
+// ComplianceReportResponse lists the violations of labor law rules within a period, ordered by user and date.
+type ComplianceReportResponse struct {
+	From       string               `json:"from"`
+	To         string               `json:"to"`
+	Violations []*ViolationResponse `json:"violations"`
+}
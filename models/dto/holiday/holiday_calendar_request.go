@@ -0,0 +1,8 @@
+package holiday
+
+// HolidayCalendarRequest creates or changes a holiday calendar. Region selects the generated public holidays
+// and can be left empty for a calendar of custom holidays only.
+type HolidayCalendarRequest struct {
+	Name   string `form:"name" json:"name" binding:"required,min=2,max=100" validate:"required,min=2,max=100"`
+	Region string `form:"region" json:"region" binding:"max=10" validate:"max=10"`
+}
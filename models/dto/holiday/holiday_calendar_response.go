@@ -0,0 +1,64 @@
+package holiday
+
+import (
+	"time"
+
+	"github.com/r-52/embrace/models"
+)
+
+type HolidayCalendarResponse struct {
+	ID       uint               `json:"id"`
+	Name     string             `json:"name"`
+	Region   string             `json:"region"`
+	Holidays []*HolidayResponse `json:"holidays,omitempty"`
+}
+
+// HolidayResponse is a custom or a generated public holiday. Public holidays are not stored and have no ID.
+type HolidayResponse struct {
+	ID        uint   `json:"id,omitempty"`
+	Date      string `json:"date"`
+	Name      string `json:"name"`
+	HalfDay   bool   `json:"halfDay"`
+	Recurring bool   `json:"recurring"`
+	Public    bool   `json:"public"`
+}
+
+// NewHolidayCalendarResponse maps a holiday calendar and its custom holidays to their API representation.
+func NewHolidayCalendarResponse(calendar *models.HolidayCalendar) *HolidayCalendarResponse {
+	return &HolidayCalendarResponse{
+		ID:       calendar.ID,
+		Name:     calendar.Name,
+		Region:   calendar.Region,
+		Holidays: NewHolidayResponses(calendar.Holidays),
+	}
+}
+
+// NewHolidayCalendarResponses maps a list of holiday calendars to their API representation.
+func NewHolidayCalendarResponses(calendars []models.HolidayCalendar) []*HolidayCalendarResponse {
+	responses := make([]*HolidayCalendarResponse, 0, len(calendars))
+	for i := range calendars {
+		responses = append(responses, NewHolidayCalendarResponse(&calendars[i]))
+	}
+	return responses
+}
+
+// NewHolidayResponse maps a holiday to its API representation.
+func NewHolidayResponse(holiday *models.Holiday) *HolidayResponse {
+	return &HolidayResponse{
+		ID:        holiday.ID,
+		Date:      holiday.Date.Format(time.DateOnly),
+		Name:      holiday.Name,
+		HalfDay:   holiday.HalfDay,
+		Recurring: holiday.Recurring,
+		Public:    holiday.ID == 0,
+	}
+}
+
+// NewHolidayResponses maps a list of holidays to their API representation.
+func NewHolidayResponses(holidays []models.Holiday) []*HolidayResponse {
+	responses := make([]*HolidayResponse, 0, len(holidays))
+	for i := range holidays {
+		responses = append(responses, NewHolidayResponse(&holidays[i]))
+	}
+	return responses
+}
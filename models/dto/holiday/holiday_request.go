@@ -0,0 +1,9 @@
+package holiday
+
+// HolidayRequest adds a custom holiday on a calendar day to a calendar. A recurring holiday repeats every year from Date on.
+type HolidayRequest struct {
+	Date      string `form:"date" json:"date" binding:"required,datetime=2006-01-02" validate:"required,datetime=2006-01-02"`
+	Name      string `form:"name" json:"name" binding:"required,min=2,max=100" validate:"required,min=2,max=100"`
+	HalfDay   bool   `form:"halfDay" json:"halfDay"`
+	Recurring bool   `form:"recurring" json:"recurring"`
+}
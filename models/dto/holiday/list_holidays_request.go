@@ -0,0 +1,6 @@
+package holiday
+
+// ListHolidaysRequest selects the year whose holidays are listed. The current year is used when it is omitted.
+type ListHolidaysRequest struct {
+	Year int `form:"year" json:"year" binding:"omitempty,min=1900,max=2999" validate:"omitempty,min=1900,max=2999"`
+}
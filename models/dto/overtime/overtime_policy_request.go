@@ -0,0 +1,11 @@
+package overtime
+
+import "github.com/r-52/embrace/models"
+
+// OvertimePolicyRequest sets the overtime rules of a company, see models.OvertimePolicy.
+// Without WorkTimeTypeIDs every type that is not quota relevant counts as worked time. Caps are in hours, zero disables them.
+type OvertimePolicyRequest struct {
+	WorkTimeTypeIDs []uint             `form:"workTimeTypeIds" json:"workTimeTypeIds" binding:"dive,min=1" validate:"dive,gte=1"`
+	MonthlyCap      models.QuotaAmount `form:"monthlyCap" json:"monthlyCap" binding:"gte=0" validate:"gte=0"`
+	BalanceCap      models.QuotaAmount `form:"balanceCap" json:"balanceCap" binding:"gte=0" validate:"gte=0"`
+}
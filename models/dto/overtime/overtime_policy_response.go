@@ -0,0 +1,26 @@
+package overtime
+
+import "github.com/r-52/embrace/models"
+
+type OvertimePolicyResponse struct {
+	QuotaID         uint               `json:"quotaId"`
+	QuotaName       string             `json:"quotaName"`
+	WorkTimeTypeIDs []uint             `json:"workTimeTypeIds"`
+	MonthlyCap      models.QuotaAmount `json:"monthlyCap"`
+	BalanceCap      models.QuotaAmount `json:"balanceCap"`
+}
+
+// NewOvertimePolicyResponse maps an overtime policy to its API representation.
+func NewOvertimePolicyResponse(policy *models.OvertimePolicy) *OvertimePolicyResponse {
+	response := &OvertimePolicyResponse{
+		QuotaID:         policy.QuotaID,
+		QuotaName:       policy.Quota.Name,
+		WorkTimeTypeIDs: []uint{},
+		MonthlyCap:      policy.MonthlyCap,
+		BalanceCap:      policy.BalanceCap,
+	}
+	for _, workTimeType := range policy.WorkTimeTypes {
+		response.WorkTimeTypeIDs = append(response.WorkTimeTypeIDs, workTimeType.TimeEntryTypeID)
+	}
+	return response
+}
@@ -0,0 +1,17 @@
+package overtime
+
+import "time"
+
+// OVERTIME_GROUP_BY constants are the breakdowns of OvertimeRequest.
+const OVERTIME_GROUP_BY_DAY = "day"
+const OVERTIME_GROUP_BY_WEEK = "week"
+const OVERTIME_GROUP_BY_MONTH = "month"
+
+// OvertimeRequest selects the period of an overtime breakdown. From and To are calendar days, both inclusive,
+// covering at most overtime.MAX_OVERTIME_DAYS days.
+// GroupBy is one of the OVERTIME_GROUP_BY constants and defaults to days. Weeks start on Monday.
+type OvertimeRequest struct {
+	From    time.Time `form:"from" json:"from" time_format:"2006-01-02" time_utc:"1" binding:"required" validate:"required"`
+	To      time.Time `form:"to" json:"to" time_format:"2006-01-02" time_utc:"1" binding:"required,gtefield=From" validate:"required,gtefield=From"`
+	GroupBy string    `form:"groupBy" json:"groupBy" binding:"omitempty,oneof=day week month" validate:"omitempty,oneof=day week month"`
+}
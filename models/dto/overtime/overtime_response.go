@@ -0,0 +1,40 @@
+package overtime
+
+import "github.com/r-52/embrace/models"
+
+// OvertimeResponse breaks down the overtime of a user within a period. Overtime is WorkedHours minus TargetHours,
+// and the Overtime of a period adds up to the CumulativeOvertime since From.
+// Account is the user's overtime account, if they have one.
+type OvertimeResponse struct {
+	UserID      uint                      `json:"userId"`
+	From        string                    `json:"from"`
+	To          string                    `json:"to"`
+	GroupBy     string                    `json:"groupBy"`
+	WorkedHours float64                   `json:"workedHours"`
+	TargetHours float64                   `json:"targetHours"`
+	Overtime    float64                   `json:"overtime"`
+	Periods     []*OvertimePeriodResponse `json:"periods"`
+	Account     *OvertimeAccountResponse  `json:"account,omitempty"`
+}
+
+// OvertimePeriodResponse is the overtime of a day, week or month. Start and End are calendar days, both inclusive,
+// and cut off at the bounds of the requested period.
+type OvertimePeriodResponse struct {
+	Start              string  `json:"start"`
+	End                string  `json:"end"`
+	WorkedHours        float64 `json:"workedHours"`
+	TargetHours        float64 `json:"targetHours"`
+	Overtime           float64 `json:"overtime"`
+	CumulativeOvertime float64 `json:"cumulativeOvertime"`
+}
+
+// OvertimeAccountResponse is the running balance of an overtime account. Balance holds what was settled and booked
+// on the account so far, and Unsettled the overtime since SettledUntil, which is settled once its month has ended.
+// RunningBalance is their sum.
+type OvertimeAccountResponse struct {
+	QuotaID        uint               `json:"quotaId"`
+	Balance        models.QuotaAmount `json:"balance"`
+	SettledUntil   string             `json:"settledUntil,omitempty"`
+	Unsettled      models.QuotaAmount `json:"unsettled"`
+	RunningBalance models.QuotaAmount `json:"runningBalance"`
+}
@@ -0,0 +1,9 @@
+package overtime
+
+import "github.com/r-52/embrace/models"
+
+// PayoutRequest pays out hours of a user's overtime account, which removes them from the account.
+type PayoutRequest struct {
+	Hours  models.QuotaAmount `form:"hours" json:"hours" binding:"required,gt=0" validate:"required,gt=0"`
+	Reason string             `form:"reason" json:"reason" binding:"required,max=255" validate:"required,max=255"`
+}
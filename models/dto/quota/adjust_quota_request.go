@@ -0,0 +1,9 @@
+package quota
+
+import "github.com/r-52/embrace/models"
+
+// AdjustQuotaRequest books a manual correction on the balance of a user's quota. A negative amount is a debit.
+type AdjustQuotaRequest struct {
+	Amount models.QuotaAmount `form:"amount" json:"amount" binding:"required" validate:"required"`
+	Reason string             `form:"reason" json:"reason" binding:"required,max=255" validate:"required,max=255"`
+}
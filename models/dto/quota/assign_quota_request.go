@@ -0,0 +1,5 @@
+package quota
+
+type AssignQuotaRequest struct {
+	QuotaID uint `form:"quotaId" json:"quotaId" binding:"required,min=1" validate:"required,gte=1"`
+}
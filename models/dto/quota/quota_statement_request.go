@@ -0,0 +1,9 @@
+package quota
+
+import "time"
+
+// QuotaStatementRequest selects the period of a quota statement. From and To are calendar days, both inclusive.
+type QuotaStatementRequest struct {
+	From time.Time `form:"from" json:"from" time_format:"2006-01-02" time_utc:"1" binding:"required" validate:"required"`
+	To   time.Time `form:"to" json:"to" time_format:"2006-01-02" time_utc:"1" binding:"required,gtefield=From" validate:"required,gtefield=From"`
+}
@@ -0,0 +1,48 @@
+package quota
+
+import (
+	"time"
+
+	"github.com/r-52/embrace/models"
+)
+
+// QuotaStatementResponse lists the movements on a user's quota within a period, between the balances
+// the ledger derives for its start and end. Reconciled reports whether the ledger adds up to the current balance.
+type QuotaStatementResponse struct {
+	UserID         uint                        `json:"userId"`
+	QuotaID        uint                        `json:"quotaId"`
+	Name           string                      `json:"name"`
+	Unit           string                      `json:"unit"`
+	From           string                      `json:"from"`
+	To             string                      `json:"to"`
+	OpeningBalance models.QuotaAmount          `json:"openingBalance"`
+	ClosingBalance models.QuotaAmount          `json:"closingBalance"`
+	Transactions   []*QuotaTransactionResponse `json:"transactions"`
+	Balance        models.QuotaAmount          `json:"balance"`
+	Reconciled     bool                        `json:"reconciled"`
+}
+
+type QuotaTransactionResponse struct {
+	ID          uint               `json:"id"`
+	Kind        string             `json:"kind"`
+	Amount      models.QuotaAmount `json:"amount"`
+	Balance     models.QuotaAmount `json:"balance"`
+	BookedAt    time.Time          `json:"bookedAt"`
+	Reason      string             `json:"reason"`
+	ActorID     *uint              `json:"actorId"`
+	TimeEntryID *uint              `json:"timeEntryId"`
+}
+
+// NewQuotaTransactionResponse maps a quota transaction to its API representation.
+func NewQuotaTransactionResponse(transaction *models.UserQuotaTransaction) *QuotaTransactionResponse {
+	return &QuotaTransactionResponse{
+		ID:          transaction.ID,
+		Kind:        transaction.Kind,
+		Amount:      transaction.Amount,
+		Balance:     transaction.Balance,
+		BookedAt:    transaction.BookedAt,
+		Reason:      transaction.Reason,
+		ActorID:     transaction.ActorID,
+		TimeEntryID: transaction.TimeEntryID,
+	}
+}
@@ -0,0 +1,24 @@
+package quota
+
+import "github.com/r-52/embrace/models"
+
+type UserQuotaResponse struct {
+	ID          uint               `json:"id"`
+	QuotaID     uint               `json:"quotaId"`
+	Name        string             `json:"name"`
+	Unit        string             `json:"unit"`
+	Count       models.QuotaAmount `json:"count"`
+	Entitlement models.QuotaAmount `json:"entitlement"`
+}
+
+// NewUserQuotaResponse maps a user quota to its API representation. The quota has to be loaded for its name.
+func NewUserQuotaResponse(userQuota *models.UserQuota) *UserQuotaResponse {
+	return &UserQuotaResponse{
+		ID:          userQuota.ID,
+		QuotaID:     userQuota.QuotaID,
+		Name:        userQuota.Quota.Name,
+		Unit:        userQuota.Quota.Unit,
+		Count:       userQuota.Count,
+		Entitlement: userQuota.Entitlement,
+	}
+}
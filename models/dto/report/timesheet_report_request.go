@@ -0,0 +1,23 @@
+package report
+
+import "time"
+
+// TIMESHEET_FORMAT constants are the file formats of TimesheetReportRequest.
+const TIMESHEET_FORMAT_CSV = "csv"
+const TIMESHEET_FORMAT_XLSX = "xlsx"
+
+// TimesheetReportRequest selects the time entries of a timesheet export. From and To are calendar days, both inclusive,
+// covering at most report.MAX_REPORT_DAYS days.
+// The export covers the user with UserID, the team of the manager with ManagerID or, without either, the caller.
+// Format is one of the TIMESHEET_FORMAT constants and defaults to CSV. Columns is a comma separated list of the
+// columns to export in their order, see report.ALL_COLUMNS, and defaults to all of them. Locale selects the
+// formatting of dates and numbers, see report.LOCALES, and defaults to `en`.
+type TimesheetReportRequest struct {
+	From      time.Time `form:"from" json:"from" time_format:"2006-01-02" time_utc:"1" binding:"required" validate:"required"`
+	To        time.Time `form:"to" json:"to" time_format:"2006-01-02" time_utc:"1" binding:"required,gtefield=From" validate:"required,gtefield=From"`
+	UserID    uint      `form:"userId" json:"userId" binding:"omitempty,min=1" validate:"omitempty,gte=1"`
+	ManagerID uint      `form:"managerId" json:"managerId" binding:"omitempty,min=1,excluded_with=UserID" validate:"omitempty,gte=1,excluded_with=UserID"`
+	Format    string    `form:"format" json:"format" binding:"omitempty,oneof=csv xlsx" validate:"omitempty,oneof=csv xlsx"`
+	Columns   string    `form:"columns" json:"columns"`
+	Locale    string    `form:"locale" json:"locale"`
+}
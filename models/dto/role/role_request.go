@@ -0,0 +1,6 @@
+package role
+
+type RoleRequest struct {
+	Name        string   `form:"name" json:"name" binding:"required,min=2,max=50" validate:"required,min=2,max=50"`
+	Permissions []string `form:"permissions" json:"permissions" binding:"dive,required" validate:"dive,required"`
+}
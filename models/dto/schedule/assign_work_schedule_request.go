@@ -0,0 +1,9 @@
+package schedule
+
+// AssignWorkScheduleRequest assigns a work schedule to a user from ValidFrom until ValidUntil, both calendar days
+// and inclusive. An empty ValidUntil keeps the schedule until a later one is assigned.
+type AssignWorkScheduleRequest struct {
+	WorkScheduleID uint   `form:"workScheduleId" json:"workScheduleId" binding:"required,min=1" validate:"required,gte=1"`
+	ValidFrom      string `form:"validFrom" json:"validFrom" binding:"required,datetime=2006-01-02" validate:"required,datetime=2006-01-02"`
+	ValidUntil     string `form:"validUntil" json:"validUntil" binding:"omitempty,datetime=2006-01-02" validate:"omitempty,datetime=2006-01-02"`
+}
@@ -0,0 +1,10 @@
+package schedule
+
+import "time"
+
+// TargetHoursRequest selects the period target hours are computed for. From and To are calendar days, both inclusive,
+// covering at most schedule.MAX_TARGET_HOURS_DAYS days.
+type TargetHoursRequest struct {
+	From time.Time `form:"from" json:"from" time_format:"2006-01-02" time_utc:"1" binding:"required" validate:"required"`
+	To   time.Time `form:"to" json:"to" time_format:"2006-01-02" time_utc:"1" binding:"required,gtefield=From" validate:"required,gtefield=From"`
+}
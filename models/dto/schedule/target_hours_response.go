@@ -0,0 +1,24 @@
+package schedule
+
+// TargetHoursResponse are the hours a user is supposed to work within a period. The scheduled hours are
+// reduced by holidays and approved absences, and TargetHours is what remains.
+type TargetHoursResponse struct {
+	UserID         uint                 `json:"userId"`
+	From           string               `json:"from"`
+	To             string               `json:"to"`
+	ScheduledHours float64              `json:"scheduledHours"`
+	HolidayHours   float64              `json:"holidayHours"`
+	AbsenceHours   float64              `json:"absenceHours"`
+	TargetHours    float64              `json:"targetHours"`
+	Days           []*TargetDayResponse `json:"days"`
+}
+
+// TargetDayResponse are the target hours of a single day.
+type TargetDayResponse struct {
+	Date           string  `json:"date"`
+	ScheduledHours float64 `json:"scheduledHours"`
+	HolidayHours   float64 `json:"holidayHours"`
+	AbsenceHours   float64 `json:"absenceHours"`
+	TargetHours    float64 `json:"targetHours"`
+	Holiday        string  `json:"holiday,omitempty"`
+}
@@ -0,0 +1,20 @@
+package schedule
+
+// WorkScheduleRequest creates or changes a work schedule. Weekly schedules spread WeeklyHours over WorkingDays
+// weekdays from Monday on. Weekday schedules list the hours of every working weekday in week 0, and rotating
+// schedules list them for every week from 0 to RotationWeeks - 1.
+type WorkScheduleRequest struct {
+	Name          string                   `form:"name" json:"name" binding:"required,min=2,max=100" validate:"required,min=2,max=100"`
+	Kind          string                   `form:"kind" json:"kind" binding:"required,oneof=weekly weekdays rotating" validate:"required,oneof=weekly weekdays rotating"`
+	WeeklyHours   float64                  `form:"weeklyHours" json:"weeklyHours" binding:"omitempty,gt=0,max=168" validate:"omitempty,gt=0,max=168"`
+	WorkingDays   int                      `form:"workingDays" json:"workingDays" binding:"omitempty,min=1,max=7" validate:"omitempty,min=1,max=7"`
+	RotationWeeks int                      `form:"rotationWeeks" json:"rotationWeeks" binding:"omitempty,min=1,max=52" validate:"omitempty,min=1,max=52"`
+	Days          []WorkScheduleDayRequest `form:"days" json:"days" binding:"dive" validate:"dive"`
+}
+
+// WorkScheduleDayRequest plans the hours of a weekday, 0 being Sunday, in a week of the pattern.
+type WorkScheduleDayRequest struct {
+	Week    int     `form:"week" json:"week" binding:"min=0,max=51" validate:"min=0,max=51"`
+	Weekday int     `form:"weekday" json:"weekday" binding:"min=0,max=6" validate:"min=0,max=6"`
+	Hours   float64 `form:"hours" json:"hours" binding:"gte=0,max=24" validate:"gte=0,max=24"`
+}
@@ -0,0 +1,80 @@
+package schedule
+
+import (
+	"time"
+
+	"github.com/r-52/embrace/models"
+)
+
+type WorkScheduleResponse struct {
+	ID            uint                       `json:"id"`
+	Name          string                     `json:"name"`
+	Kind          string                     `json:"kind"`
+	WeeklyHours   float64                    `json:"weeklyHours"`
+	WorkingDays   int                        `json:"workingDays"`
+	RotationWeeks int                        `json:"rotationWeeks"`
+	Days          []*WorkScheduleDayResponse `json:"days"`
+}
+
+type WorkScheduleDayResponse struct {
+	Week    int     `json:"week"`
+	Weekday int     `json:"weekday"`
+	Hours   float64 `json:"hours"`
+}
+
+type UserWorkScheduleResponse struct {
+	ID           uint                  `json:"id"`
+	UserID       uint                  `json:"userId"`
+	ValidFrom    string                `json:"validFrom"`
+	ValidUntil   string                `json:"validUntil"`
+	WorkSchedule *WorkScheduleResponse `json:"workSchedule"`
+}
+
+// NewWorkScheduleResponse maps a work schedule and its days to their API representation.
+func NewWorkScheduleResponse(schedule *models.WorkSchedule) *WorkScheduleResponse {
+	response := &WorkScheduleResponse{
+		ID:            schedule.ID,
+		Name:          schedule.Name,
+		Kind:          schedule.Kind,
+		WeeklyHours:   schedule.WeeklyHours,
+		WorkingDays:   schedule.WorkingDays,
+		RotationWeeks: schedule.RotationWeeks,
+		Days:          make([]*WorkScheduleDayResponse, 0, len(schedule.Days)),
+	}
+	for _, day := range schedule.Days {
+		response.Days = append(response.Days, &WorkScheduleDayResponse{Week: day.Week, Weekday: int(day.Weekday), Hours: day.Hours})
+	}
+	return response
+}
+
+// NewWorkScheduleResponses maps a list of work schedules to their API representation.
+func NewWorkScheduleResponses(schedules []models.WorkSchedule) []*WorkScheduleResponse {
+	responses := make([]*WorkScheduleResponse, 0, len(schedules))
+	for i := range schedules {
+		responses = append(responses, NewWorkScheduleResponse(&schedules[i]))
+	}
+	return responses
+}
+
+// NewUserWorkScheduleResponse maps a work schedule assignment to its API representation.
+func NewUserWorkScheduleResponse(assignment *models.UserWorkSchedule) *UserWorkScheduleResponse {
+	response := &UserWorkScheduleResponse{
+		ID:           assignment.ID,
+		UserID:       assignment.UserID,
+		ValidFrom:    assignment.ValidFrom.Format(time.DateOnly),
+		WorkSchedule: NewWorkScheduleResponse(&assignment.WorkSchedule),
+	}
+	if assignment.ValidUntil.Valid {
+		response.ValidUntil = assignment.ValidUntil.Time.Format(time.DateOnly)
+	}
+	return response
+}
+
+// NewUserWorkScheduleResponses maps a list of work schedule assignments to their API representation.
+func NewUserWorkScheduleResponses(assignments []models.UserWorkSchedule) []*UserWorkScheduleResponse {
+	responses := make([]*UserWorkScheduleResponse, 0, len(assignments))
+	for i := range assignments {
+		responses = append(responses, NewUserWorkScheduleResponse(&assignments[i]))
+	}
+	return responses
+}
@@ -0,0 +1,6 @@
+package timeentry
+
+type ClockInRequest struct {
+	TimeEntryTypeID uint   `form:"timeEntryTypeId" json:"timeEntryTypeId" binding:"required,min=1" validate:"required,gte=1"`
+	Note            string `form:"note" json:"note" binding:"max=1000" validate:"max=1000"`
+}
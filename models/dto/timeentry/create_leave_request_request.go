@@ -0,0 +1,11 @@
+package timeentry
+
+// CreateLeaveRequestRequest requests leave from StartDate to EndDate, both calendar days and inclusive.
+// HalfDay requests half of a single day.
+type CreateLeaveRequestRequest struct {
+	StartDate       string `form:"startDate" json:"startDate" binding:"required,datetime=2006-01-02" validate:"required,datetime=2006-01-02"`
+	EndDate         string `form:"endDate" json:"endDate" binding:"required,datetime=2006-01-02" validate:"required,datetime=2006-01-02"`
+	HalfDay         bool   `form:"halfDay" json:"halfDay"`
+	Note            string `form:"note" json:"note" binding:"max=1000" validate:"max=1000"`
+	TimeEntryTypeID uint   `form:"timeEntryTypeId" json:"timeEntryTypeId" binding:"required,min=1" validate:"required,gte=1"`
+}
@@ -0,0 +1,11 @@
+package timeentry
+
+import "time"
+
+type CreateTimeEntryRequest struct {
+	StartTime       time.Time `form:"startTime" json:"startTime" binding:"required" validate:"required"`
+	EndTime         time.Time `form:"endTime" json:"endTime" binding:"required,gtfield=StartTime" validate:"required,gtfield=StartTime"`
+	Note            string    `form:"note" json:"note" binding:"max=1000" validate:"max=1000"`
+	TimeEntryTypeID uint      `form:"timeEntryTypeId" json:"timeEntryTypeId" binding:"required,min=1" validate:"required,gte=1"`
+	UserID          uint      `form:"userId" json:"userId" binding:"omitempty,min=1" validate:"omitempty,gte=1"`
+}
@@ -0,0 +1,6 @@
+package timeentry
+
+// DecideLeaveRequestRequest approves, rejects or cancels a leave request with an optional note.
+type DecideLeaveRequestRequest struct {
+	Note string `form:"note" json:"note" binding:"max=1000" validate:"max=1000"`
+}
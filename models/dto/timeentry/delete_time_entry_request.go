@@ -0,0 +1,6 @@
+package timeentry
+
+// DeleteTimeEntryRequest deletes a time entry with the reason recorded in the audit trail.
+type DeleteTimeEntryRequest struct {
+	Reason string `form:"reason" json:"reason" binding:"max=1000" validate:"max=1000"`
+}
@@ -0,0 +1,39 @@
+package timeentry
+
+import (
+	"time"
+
+	"github.com/r-52/embrace/models"
+)
+
+// DurationPolicyRequest sets how the durations of a company's time entries are computed, see models.DurationPolicy.
+// Increments are in minutes, zero disables them.
+type DurationPolicyRequest struct {
+	StartRounding     TimeRoundingRequest `form:"startRounding" json:"startRounding"`
+	EndRounding       TimeRoundingRequest `form:"endRounding" json:"endRounding"`
+	DeductBreaks      bool                `form:"deductBreaks" json:"deductBreaks"`
+	BillableIncrement int                 `form:"billableIncrement" json:"billableIncrement" binding:"oneof=0 5 6 10 15 30 60" validate:"oneof=0 5 6 10 15 30 60"`
+}
+
+// TimeRoundingRequest rounds times to Increment minutes. Mode is one of the models.TIME_ROUNDING constants
+// and defaults to the nearest time.
+type TimeRoundingRequest struct {
+	Increment int    `form:"increment" json:"increment" binding:"oneof=0 5 6 10 15 30 60" validate:"oneof=0 5 6 10 15 30 60"`
+	Mode      string `form:"mode" json:"mode" binding:"omitempty,oneof=nearest up down" validate:"omitempty,oneof=nearest up down"`
+}
+
+// TimeRounding maps the request to its model, with the default mode.
+func (r *TimeRoundingRequest) TimeRounding() models.TimeRounding {
+	rounding := models.TimeRounding{Increment: r.Increment, Mode: r.Mode}
+	if rounding.Mode == "" {
+		rounding.Mode = models.TIME_ROUNDING_NEAREST
+	}
+	return rounding
+}
+
+// ReapplyDurationPolicyRequest selects the time entries whose durations are computed again, the ones that start
+// from From to To. Both are calendar days and inclusive.
+type ReapplyDurationPolicyRequest struct {
+	From time.Time `form:"from" json:"from" time_format:"2006-01-02" time_utc:"1" binding:"required" validate:"required"`
+	To   time.Time `form:"to" json:"to" time_format:"2006-01-02" time_utc:"1" binding:"required,gtefield=From" validate:"required,gtefield=From"`
+}
@@ -0,0 +1,25 @@
+package timeentry
+
+import "github.com/r-52/embrace/models"
+
+type DurationPolicyResponse struct {
+	StartRounding     models.TimeRounding `json:"startRounding"`
+	EndRounding       models.TimeRounding `json:"endRounding"`
+	DeductBreaks      bool                `json:"deductBreaks"`
+	BillableIncrement int                 `json:"billableIncrement"`
+}
+
+// NewDurationPolicyResponse maps a duration policy to its API representation.
+func NewDurationPolicyResponse(policy *models.DurationPolicy) *DurationPolicyResponse {
+	return &DurationPolicyResponse{
+		StartRounding:     policy.StartRounding,
+		EndRounding:       policy.EndRounding,
+		DeductBreaks:      policy.DeductBreaks,
+		BillableIncrement: policy.BillableIncrement,
+	}
+}
+
+// ReapplyDurationPolicyResponse counts the time entries whose duration changed.
+type ReapplyDurationPolicyResponse struct {
+	Updated int `json:"updated"`
+}
@@ -0,0 +1,6 @@
+package timeentry
+
+// ImportTimeEntriesRequest books many closed time entries at once. Either all of them are booked or none.
+type ImportTimeEntriesRequest struct {
+	Entries []CreateTimeEntryRequest `form:"entries" json:"entries" binding:"required,min=1,max=1000,dive" validate:"required,min=1,max=1000,dive"`
+}
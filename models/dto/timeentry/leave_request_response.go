@@ -0,0 +1,53 @@
+package timeentry
+
+import (
+	"time"
+
+	"github.com/r-52/embrace/models"
+)
+
+type LeaveRequestResponse struct {
+	ID              uint       `json:"id"`
+	UserID          uint       `json:"userId"`
+	TimeEntryTypeID uint       `json:"timeEntryTypeId"`
+	StartDate       string     `json:"startDate"`
+	EndDate         string     `json:"endDate"`
+	HalfDay         bool       `json:"halfDay"`
+	Note            string     `json:"note"`
+	Status          string     `json:"status"`
+	DecidedByID     *uint      `json:"decidedById"`
+	DecidedAt       *time.Time `json:"decidedAt"`
+	DecisionNote    string     `json:"decisionNote"`
+	CreatedAt       time.Time  `json:"createdAt"`
+}
+
+// NewLeaveRequestResponse maps a leave request to its API representation.
+func NewLeaveRequestResponse(leaveRequest *models.LeaveRequest) *LeaveRequestResponse {
+	response := &LeaveRequestResponse{
+		ID:              leaveRequest.ID,
+		UserID:          leaveRequest.UserID,
+		TimeEntryTypeID: leaveRequest.TimeEntryTypeID,
+		StartDate:       leaveRequest.StartDate.Format(time.DateOnly),
+		EndDate:         leaveRequest.EndDate.Format(time.DateOnly),
+		HalfDay:         leaveRequest.HalfDay,
+		Note:            leaveRequest.Note,
+		Status:          leaveRequest.Status,
+		DecidedByID:     leaveRequest.DecidedByID,
+		DecisionNote:    leaveRequest.DecisionNote,
+		CreatedAt:       leaveRequest.CreatedAt,
+	}
+	if leaveRequest.DecidedAt.Valid {
+		decidedAt := leaveRequest.DecidedAt.Time
+		response.DecidedAt = &decidedAt
+	}
+	return response
+}
+
+// NewLeaveRequestResponses maps a list of leave requests to their API representation.
+func NewLeaveRequestResponses(leaveRequests []models.LeaveRequest) []*LeaveRequestResponse {
+	responses := make([]*LeaveRequestResponse, 0, len(leaveRequests))
+	for i := range leaveRequests {
+		responses = append(responses, NewLeaveRequestResponse(&leaveRequests[i]))
+	}
+	return responses
+}
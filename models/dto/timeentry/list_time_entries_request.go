@@ -0,0 +1,12 @@
+package timeentry
+
+import "time"
+
+// ListTimeEntriesRequest filters the time entries list. From and To are calendar days,
+// both inclusive. Without a user ID the entries of every user the caller may read are returned.
+type ListTimeEntriesRequest struct {
+	From            time.Time `form:"from" json:"from" time_format:"2006-01-02" time_utc:"1"`
+	To              time.Time `form:"to" json:"to" time_format:"2006-01-02" time_utc:"1" binding:"omitempty,gtefield=From" validate:"omitempty,gtefield=From"`
+	UserID          uint      `form:"userId" json:"userId" binding:"omitempty,min=1" validate:"omitempty,gte=1"`
+	TimeEntryTypeID uint      `form:"timeEntryTypeId" json:"timeEntryTypeId" binding:"omitempty,min=1" validate:"omitempty,gte=1"`
+}
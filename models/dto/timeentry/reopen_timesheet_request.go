@@ -0,0 +1,6 @@
+package timeentry
+
+// ReopenTimesheetRequest reopens a timesheet period with the reason for changing it after the sign-off.
+type ReopenTimesheetRequest struct {
+	Reason string `form:"reason" json:"reason" binding:"required,max=1000" validate:"required,max=1000"`
+}
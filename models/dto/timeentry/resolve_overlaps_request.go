@@ -0,0 +1,12 @@
+package timeentry
+
+import "time"
+
+// ResolveOverlapsRequest selects the entries of a user that start within [From, To) and whose overlaps should be resolved.
+// Strategy "merge" joins overlapping entries into one, "split" cuts earlier entries around the ones starting later.
+type ResolveOverlapsRequest struct {
+	UserID   uint      `form:"userId" json:"userId" binding:"required,min=1" validate:"required,gte=1"`
+	From     time.Time `form:"from" json:"from" binding:"required" validate:"required"`
+	To       time.Time `form:"to" json:"to" binding:"required,gtfield=From" validate:"required,gtfield=From"`
+	Strategy string    `form:"strategy" json:"strategy" binding:"required,oneof=merge split" validate:"required,oneof=merge split"`
+}
@@ -0,0 +1,56 @@
+package timeentry
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/r-52/embrace/models"
+)
+
+type TimeEntryAuditResponse struct {
+	ID           uint            `json:"id"`
+	Sequence     uint            `json:"sequence"`
+	TimeEntryID  uint            `json:"timeEntryId"`
+	Operation    string          `json:"operation"`
+	ActorID      uint            `json:"actorId"`
+	Reason       string          `json:"reason"`
+	RecordedAt   time.Time       `json:"recordedAt"`
+	Before       json.RawMessage `json:"before"`
+	After        json.RawMessage `json:"after"`
+	PreviousHash string          `json:"previousHash"`
+	Hash         string          `json:"hash"`
+}
+
+// NewTimeEntryAuditResponse maps an audit record to its API representation. The states before and after the
+// change are embedded as models.TimeEntrySnapshot objects, or null if there is none.
+func NewTimeEntryAuditResponse(audit *models.TimeEntryAudit) *TimeEntryAuditResponse {
+	return &TimeEntryAuditResponse{
+		ID:           audit.ID,
+		Sequence:     audit.Sequence,
+		TimeEntryID:  audit.TimeEntryID,
+		Operation:    audit.Operation,
+		ActorID:      audit.ActorID,
+		Reason:       audit.Reason,
+		RecordedAt:   audit.RecordedAt,
+		Before:       snapshotOrNull(audit.Before),
+		After:        snapshotOrNull(audit.After),
+		PreviousHash: audit.PreviousHash,
+		Hash:         audit.Hash,
+	}
+}
+
+// NewTimeEntryAuditResponses maps a list of audit records to their API representation.
+func NewTimeEntryAuditResponses(audits []models.TimeEntryAudit) []*TimeEntryAuditResponse {
+	responses := make([]*TimeEntryAuditResponse, 0, len(audits))
+	for i := range audits {
+		responses = append(responses, NewTimeEntryAuditResponse(&audits[i]))
+	}
+	return responses
+}
+
+func snapshotOrNull(snapshot string) json.RawMessage {
+	if snapshot == "" {
+		return json.RawMessage("null")
+	}
+	return json.RawMessage(snapshot)
+}
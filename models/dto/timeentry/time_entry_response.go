@@ -0,0 +1,60 @@
+package timeentry
+
+import (
+	"time"
+
+	"github.com/r-52/embrace/models"
+	"github.com/r-52/embrace/models/dto/compliance"
+)
+
+type TimeEntryResponse struct {
+	ID              uint       `json:"id"`
+	UserID          uint       `json:"userId"`
+	TimeEntryTypeID uint       `json:"timeEntryTypeId"`
+	StartTime       time.Time  `json:"startTime"`
+	EndTime         *time.Time `json:"endTime"`
+	Duration        *float64   `json:"duration"`
+	DeductedBreak   float64    `json:"deductedBreak"`
+	Note            string     `json:"note"`
+	Paused          bool       `json:"paused"`
+	CreatedAt       time.Time  `json:"createdAt"`
+	UpdatedAt       time.Time  `json:"updatedAt"`
+	// Warnings are the labor law rules that booking or changing the entry violated without rejecting it.
+	Warnings []*compliance.ViolationResponse `json:"warnings,omitempty"`
+}
+
+// NewTimeEntryResponse maps a time entry to its API representation.
+func NewTimeEntryResponse(entry *models.TimeEntry) *TimeEntryResponse {
+	response := &TimeEntryResponse{
+		ID:              entry.ID,
+		UserID:          entry.UserID,
+		TimeEntryTypeID: entry.TimeEntryTypeID,
+		StartTime:       entry.StartTime,
+		Note:            entry.Note,
+		DeductedBreak:   entry.DeductedBreak,
+		Paused:          entry.Paused,
+		CreatedAt:       entry.CreatedAt,
+		UpdatedAt:       entry.UpdatedAt,
+	}
+	if len(entry.Warnings) > 0 {
+		response.Warnings = compliance.NewViolationResponses(entry.Warnings)
+	}
+	if entry.EndTime.Valid {
+		endTime := entry.EndTime.Time
+		response.EndTime = &endTime
+	}
+	if entry.Duration.Valid {
+		duration := entry.Duration.Float64
+		response.Duration = &duration
+	}
+	return response
+}
+
+// NewTimeEntryResponses maps a list of time entries to their API representation.
+func NewTimeEntryResponses(entries []models.TimeEntry) []*TimeEntryResponse {
+	responses := make([]*TimeEntryResponse, 0, len(entries))
+	for i := range entries {
+		responses = append(responses, NewTimeEntryResponse(&entries[i]))
+	}
+	return responses
+}
@@ -0,0 +1,9 @@
+package timeentry
+
+// TimerResponse describes the clock of a user for the dashboard. State is "running", "paused" or "stopped".
+// Entry is the running entry, the paused entry or the entry that was just clocked out, Elapsed is its duration in hours so far.
+type TimerResponse struct {
+	State   string             `json:"state"`
+	Entry   *TimeEntryResponse `json:"entry"`
+	Elapsed float64            `json:"elapsed"`
+}
@@ -0,0 +1,57 @@
+package timeentry
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/r-52/embrace/models"
+)
+
+type TimesheetPeriodResponse struct {
+	ID           uint       `json:"id"`
+	UserID       uint       `json:"userId"`
+	Month        string     `json:"month"`
+	Status       string     `json:"status"`
+	SubmittedAt  *time.Time `json:"submittedAt"`
+	ApprovedByID *uint      `json:"approvedById"`
+	ApprovedAt   *time.Time `json:"approvedAt"`
+	LockedByID   *uint      `json:"lockedById"`
+	LockedAt     *time.Time `json:"lockedAt"`
+	ReopenedByID *uint      `json:"reopenedById"`
+	ReopenedAt   *time.Time `json:"reopenedAt"`
+	ReopenReason string     `json:"reopenReason"`
+}
+
+// NewTimesheetPeriodResponse maps a timesheet period to its API representation. The month is formatted as "2006-01".
+func NewTimesheetPeriodResponse(period *models.TimesheetPeriod) *TimesheetPeriodResponse {
+	return &TimesheetPeriodResponse{
+		ID:           period.ID,
+		UserID:       period.UserID,
+		Month:        period.Month.Format("2006-01"),
+		Status:       period.Status,
+		SubmittedAt:  timeOrNil(period.SubmittedAt),
+		ApprovedByID: period.ApprovedByID,
+		ApprovedAt:   timeOrNil(period.ApprovedAt),
+		LockedByID:   period.LockedByID,
+		LockedAt:     timeOrNil(period.LockedAt),
+		ReopenedByID: period.ReopenedByID,
+		ReopenedAt:   timeOrNil(period.ReopenedAt),
+		ReopenReason: period.ReopenReason,
+	}
+}
+
+// NewTimesheetPeriodResponses maps a list of timesheet periods to their API representation.
+func NewTimesheetPeriodResponses(periods []models.TimesheetPeriod) []*TimesheetPeriodResponse {
+	responses := make([]*TimesheetPeriodResponse, 0, len(periods))
+	for i := range periods {
+		responses = append(responses, NewTimesheetPeriodResponse(&periods[i]))
+	}
+	return responses
+}
+
+func timeOrNil(t sql.NullTime) *time.Time {
+	if !t.Valid {
+		return nil
+	}
+	return &t.Time
+}
@@ -0,0 +1,11 @@
+package timeentry
+
+import "time"
+
+type UpdateTimeEntryRequest struct {
+	StartTime       time.Time `form:"startTime" json:"startTime" binding:"required" validate:"required"`
+	EndTime         time.Time `form:"endTime" json:"endTime" binding:"required,gtfield=StartTime" validate:"required,gtfield=StartTime"`
+	Note            string    `form:"note" json:"note" binding:"max=1000" validate:"max=1000"`
+	TimeEntryTypeID uint      `form:"timeEntryTypeId" json:"timeEntryTypeId" binding:"required,min=1" validate:"required,gte=1"`
+	Reason          string    `form:"reason" json:"reason" binding:"max=1000" validate:"max=1000"`
+}
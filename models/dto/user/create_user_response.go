@@ -4,4 +4,5 @@ type CreateUserResponse struct {
 	ID        uint   `json:"id"`
 	Email     string `json:"email"`
 	CompanyID uint   `json:"companyId"`
+	RoleID    uint   `json:"roleId"`
 }
@@ -0,0 +1,13 @@
+package user
+
+import "github.com/r-52/embrace/models/dto/quota"
+
+type EmploymentResponse struct {
+	UserID             uint                       `json:"userId"`
+	EmploymentStart    string                     `json:"employmentStart"`
+	EmploymentEnd      string                     `json:"employmentEnd"`
+	WorkingDaysPerWeek int                        `json:"workingDaysPerWeek"`
+	DailyWorkingHours  float64                    `json:"dailyWorkingHours"`
+	HolidayCalendarID  *uint                      `json:"holidayCalendarId"`
+	Quotas             []*quota.UserQuotaResponse `json:"quotas"`
+}
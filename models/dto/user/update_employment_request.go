@@ -0,0 +1,13 @@
+package user
+
+// UpdateEmploymentRequest replaces the employment data of a user. The dates are calendar days and
+// EmploymentEnd is the last day of employment. An empty date leaves the employment open on that side.
+// DailyWorkingHours converts booked time into day based quotas and is left unchanged when omitted.
+// HolidayCalendarID assigns the calendar of the user's holidays, 0 removes it and omitting it leaves it unchanged.
+type UpdateEmploymentRequest struct {
+	EmploymentStart    string  `form:"employmentStart" json:"employmentStart" binding:"omitempty,datetime=2006-01-02" validate:"omitempty,datetime=2006-01-02"`
+	EmploymentEnd      string  `form:"employmentEnd" json:"employmentEnd" binding:"omitempty,datetime=2006-01-02" validate:"omitempty,datetime=2006-01-02"`
+	WorkingDaysPerWeek int     `form:"workingDaysPerWeek" json:"workingDaysPerWeek" binding:"required,min=1,max=7" validate:"required,min=1,max=7"`
+	DailyWorkingHours  float64 `form:"dailyWorkingHours" json:"dailyWorkingHours" binding:"omitempty,gt=0,max=24" validate:"omitempty,gt=0,max=24"`
+	HolidayCalendarID  *uint   `form:"holidayCalendarId" json:"holidayCalendarId"`
+}
@@ -0,0 +1,35 @@
+package models
+
+import "gorm.io/gorm"
+
+// DurationPolicy is how the Duration of a company's time entries is computed from their start and end time, which are
+// kept as booked so that a changed policy can be applied again. It only applies to types that are not quota relevant,
+// leave keeps consuming quotas as booked. Companies without a policy use the booked times as they are.
+type DurationPolicy struct {
+	gorm.Model
+
+	CompanyID uint `json:"-" gorm:"uniqueIndex"`
+
+	// StartRounding and EndRounding round the start and the end time in the company's timezone.
+	StartRounding TimeRounding `json:"startRounding" gorm:"embedded;embeddedPrefix:start_rounding_"`
+	EndRounding   TimeRounding `json:"endRounding" gorm:"embedded;embeddedPrefix:end_rounding_"`
+
+	// DeductBreaks deducts the statutory break from entries that are too long to be worked without one,
+	// but never more than the time beyond the limit of the break.
+	DeductBreaks bool `json:"deductBreaks" gorm:"not null;default:false"`
+
+	// BillableIncrement is the smallest unit billable types are booked in, in minutes. Their durations are rounded
+	// up to a multiple of it and take at least one increment. Zero disables it.
+	BillableIncrement int `json:"billableIncrement" gorm:"not null;default:0"`
+}
+
+// TimeRounding rounds times to a multiple of Increment minutes after midnight, in the direction of Mode,
+// one of the TIME_ROUNDING constants. An Increment of zero leaves times as they are.
+type TimeRounding struct {
+	Increment int    `json:"increment" gorm:"not null;default:0"`
+	Mode      string `json:"mode" gorm:"not null;default:'nearest'"`
+}
+
+const TIME_ROUNDING_NEAREST = "nearest"
+const TIME_ROUNDING_UP = "up"
+const TIME_ROUNDING_DOWN = "down"
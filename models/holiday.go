@@ -0,0 +1,44 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// HolidayCalendar is a set of days off that users are assigned to. The public holidays of Region are
+// generated for every year, and Holidays adds the custom days off of the company.
+type HolidayCalendar struct {
+	gorm.Model
+
+	CompanyID uint   `json:"-" gorm:"index"`
+	Name      string `json:"name" gorm:"not null"`
+	// Region is the ISO 3166-2 code of the region whose public holidays are generated, e.g. DE-BY.
+	// A calendar without a region only has its custom holidays.
+	Region string `json:"region"`
+
+	Holidays []Holiday `json:"holidays" gorm:"foreignKey:HolidayCalendarID"`
+}
+
+// Holiday is a day off. Generated public holidays are not stored, only custom holidays of a calendar are.
+type Holiday struct {
+	gorm.Model
+
+	HolidayCalendarID uint `json:"-" gorm:"index;not null"`
+
+	// Date is the day of the holiday at midnight UTC.
+	Date time.Time `json:"date" gorm:"not null"`
+	Name string    `json:"name" gorm:"not null"`
+	// HalfDay holidays are working days with half of the working hours, such as Christmas Eve in many companies.
+	HalfDay bool `json:"halfDay" gorm:"not null;default:false"`
+	// Recurring holidays repeat every year on the month and day of Date.
+	Recurring bool `json:"recurring" gorm:"not null;default:false"`
+}
+
+// OccursOn reports whether the holiday falls on the day, given at midnight UTC.
+func (h *Holiday) OccursOn(day time.Time) bool {
+	if h.Recurring {
+		return h.Date.Month() == day.Month() && h.Date.Day() == day.Day()
+	}
+	return h.Date.Equal(day)
+}
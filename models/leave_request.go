@@ -0,0 +1,41 @@
+package models
+
+import (
+	"database/sql"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// LeaveRequest is a request of a user to be absent on the working days from StartDate to EndDate,
+// booked on a TimeEntryType such as vacation. Approving it books the time entries of the leave,
+// which use up the matching quota, and cancelling an approved request removes them again.
+type LeaveRequest struct {
+	gorm.Model
+
+	CompanyID uint `json:"-" gorm:"index"`
+
+	UserID uint `json:"-" gorm:"index;not null"`
+	User   User `json:"user"`
+
+	TimeEntryTypeID uint          `json:"-" gorm:"not null"`
+	TimeEntryType   TimeEntryType `json:"timeEntryType"`
+
+	// StartDate and EndDate are the first and the last day of the leave, at midnight UTC.
+	StartDate time.Time `json:"startDate" gorm:"not null"`
+	EndDate   time.Time `json:"endDate" gorm:"not null"`
+	// HalfDay requests half of a single day.
+	HalfDay bool   `json:"halfDay" gorm:"not null;default:false"`
+	Note    string `json:"note"`
+
+	// Status is one of the LEAVE_REQUEST constants.
+	Status       string       `json:"status" gorm:"index;not null;default:'requested'"`
+	DecidedByID  *uint        `json:"decidedById"`
+	DecidedAt    sql.NullTime `json:"decidedAt"`
+	DecisionNote string       `json:"decisionNote"`
+}
+
+const LEAVE_REQUEST_REQUESTED = "requested"
+const LEAVE_REQUEST_APPROVED = "approved"
+const LEAVE_REQUEST_REJECTED = "rejected"
+const LEAVE_REQUEST_CANCELLED = "cancelled"
@@ -0,0 +1,81 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// OvertimePolicy are the overtime rules of a company. Overtime is the time worked beyond the target hours of a user.
+// It is settled month by month into the user's overtime account: their UserQuota of the quota QuotaID, which is
+// measured in hours and never reset. Time off in lieu is booked on a time entry type with the name of that quota
+// and consumes the account like any other quota.
+type OvertimePolicy struct {
+	gorm.Model
+
+	CompanyID uint  `json:"-" gorm:"uniqueIndex"`
+	QuotaID   uint  `json:"quotaId" gorm:"not null"`
+	Quota     Quota `json:"quota"`
+
+	// WorkTimeTypes are the time entry types whose entries count as worked time.
+	// Without any, every type that is not quota relevant counts.
+	WorkTimeTypes []OvertimeWorkTimeType `json:"workTimeTypes" gorm:"foreignKey:OvertimePolicyID"`
+
+	// MonthlyCap is the most overtime credited for a month and BalanceCap the most an account holds after
+	// overtime was credited, both in hours. Overtime beyond a cap is forfeited. Zero disables a cap.
+	// Missing hours are always debited in full.
+	MonthlyCap QuotaAmount `json:"monthlyCap" gorm:"not null;default:0"`
+	BalanceCap QuotaAmount `json:"balanceCap" gorm:"not null;default:0"`
+}
+
+// OVERTIME_QUOTA_NAME is the name of the quota that holds the overtime accounts of a company.
+const OVERTIME_QUOTA_NAME = "overtime"
+
+// OvertimeWorkTimeType marks a time entry type whose entries count as worked time.
+type OvertimeWorkTimeType struct {
+	gorm.Model
+
+	OvertimePolicyID uint `json:"-" gorm:"index;not null"`
+	TimeEntryTypeID  uint `json:"timeEntryTypeId" gorm:"not null"`
+}
+
+// CountsAsWorkTime reports whether entries of a time entry type count as worked time. Time off in lieu counts as well,
+// as it is already taken from the overtime account when it is booked, see Quota.
+func (p *OvertimePolicy) CountsAsWorkTime(timeEntryType *TimeEntryType) bool {
+	if timeEntryType.IsQuotaRelevant && p.Quota.Name != "" && timeEntryType.QuotaName == p.Quota.Name {
+		return true
+	}
+	if len(p.WorkTimeTypes) == 0 {
+		return !timeEntryType.IsQuotaRelevant
+	}
+	for _, workTimeType := range p.WorkTimeTypes {
+		if workTimeType.TimeEntryTypeID == timeEntryType.ID {
+			return true
+		}
+	}
+	return false
+}
+
+// OvertimeSettlement records that the overtime of a user in the month starting at Month was credited to their
+// overtime account. Every month is settled at most once, which the unique index guarantees. When time entries
+// of the month change later, the settlement is corrected and the difference is booked.
+type OvertimeSettlement struct {
+	gorm.Model
+
+	CompanyID uint `json:"-" gorm:"index"`
+	UserID    uint `json:"userId" gorm:"uniqueIndex:idx_overtime_settlements_user_month,priority:1;not null"`
+	// Month is the first day of the settled month, at midnight UTC.
+	Month time.Time `json:"month" gorm:"uniqueIndex:idx_overtime_settlements_user_month,priority:2;not null"`
+
+	WorkedHours float64 `json:"workedHours" gorm:"not null"`
+	TargetHours float64 `json:"targetHours" gorm:"not null"`
+	// Overtime is WorkedHours minus TargetHours. Credited is the part that was booked after applying the caps
+	// and Forfeited the rest.
+	Overtime  QuotaAmount `json:"overtime" gorm:"not null"`
+	Credited  QuotaAmount `json:"credited" gorm:"not null"`
+	Forfeited QuotaAmount `json:"forfeited" gorm:"not null;default:0"`
+
+	// UserQuotaTransactionID references the latest booking for the month, the first one or a correction.
+	// It is nil if nothing was booked.
+	UserQuotaTransactionID *uint `json:"userQuotaTransactionId"`
+}
@@ -0,0 +1,40 @@
+package models_test
+
+import (
+	"testing"
+
+	"github.com/r-52/embrace/models"
+)
+
+func TestOvertimePolicy_CountsAsWorkTime(t *testing.T) {
+	work := &models.TimeEntryType{Name: "Work"}
+	work.ID = 1
+	travel := &models.TimeEntryType{Name: "Travel"}
+	travel.ID = 2
+	vacation := &models.TimeEntryType{Name: "Vacation", IsQuotaRelevant: true, QuotaName: "vacation"}
+	vacation.ID = 3
+	timeOffInLieu := &models.TimeEntryType{Name: "Time off in lieu", IsQuotaRelevant: true, QuotaName: models.OVERTIME_QUOTA_NAME}
+	timeOffInLieu.ID = 4
+
+	defaults := &models.OvertimePolicy{Quota: models.Quota{Name: models.OVERTIME_QUOTA_NAME}}
+	listed := &models.OvertimePolicy{Quota: models.Quota{Name: models.OVERTIME_QUOTA_NAME}, WorkTimeTypes: []models.OvertimeWorkTimeType{{TimeEntryTypeID: work.ID}}}
+	cases := []struct {
+		name          string
+		policy        *models.OvertimePolicy
+		timeEntryType *models.TimeEntryType
+		expected      bool
+	}{
+		{"default work", defaults, work, true},
+		{"default vacation", defaults, vacation, false},
+		{"default time off in lieu", defaults, timeOffInLieu, true},
+		{"listed work", listed, work, true},
+		{"unlisted travel", listed, travel, false},
+		{"listed time off in lieu", listed, timeOffInLieu, true},
+		{"without a policy", &models.OvertimePolicy{}, timeOffInLieu, false},
+	}
+	for _, c := range cases {
+		if got := c.policy.CountsAsWorkTime(c.timeEntryType); got != c.expected {
+			t.Errorf("%s: expected %v, got %v", c.name, c.expected, got)
+		}
+	}
+}
@@ -0,0 +1,126 @@
+package models
+
+import (
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+// Permissions have the form `resource:action` or `resource:action:scope`.
+// A scoped permission implies the narrower scopes of the same resource and action,
+// e.g. `time_entries:write:all` also grants `time_entries:write:team` and `time_entries:write:own`.
+const PERMISSION_TIME_ENTRIES_READ_OWN = "time_entries:read:own"
+const PERMISSION_TIME_ENTRIES_READ_TEAM = "time_entries:read:team"
+const PERMISSION_TIME_ENTRIES_READ_ALL = "time_entries:read:all"
+const PERMISSION_TIME_ENTRIES_WRITE_OWN = "time_entries:write:own"
+const PERMISSION_TIME_ENTRIES_WRITE_TEAM = "time_entries:write:team"
+const PERMISSION_TIME_ENTRIES_WRITE_ALL = "time_entries:write:all"
+const PERMISSION_TIME_ENTRIES_APPROVE_TEAM = "time_entries:approve:team"
+const PERMISSION_TIME_ENTRIES_APPROVE_ALL = "time_entries:approve:all"
+const PERMISSION_TIME_ENTRY_TYPES_MANAGE = "time_entry_types:manage"
+const PERMISSION_QUOTAS_READ_OWN = "quotas:read:own"
+const PERMISSION_QUOTAS_READ_TEAM = "quotas:read:team"
+const PERMISSION_QUOTAS_READ_ALL = "quotas:read:all"
+const PERMISSION_QUOTAS_MANAGE = "quotas:manage"
+const PERMISSION_USERS_READ = "users:read"
+const PERMISSION_USERS_MANAGE = "users:manage"
+const PERMISSION_ROLES_MANAGE = "roles:manage"
+const PERMISSION_COMPANY_MANAGE = "company:manage"
+
+// ALL_PERMISSIONS lists every permission that can be assigned to a role.
+var ALL_PERMISSIONS = []string{
+	PERMISSION_TIME_ENTRIES_READ_OWN,
+	PERMISSION_TIME_ENTRIES_READ_TEAM,
+	PERMISSION_TIME_ENTRIES_READ_ALL,
+	PERMISSION_TIME_ENTRIES_WRITE_OWN,
+	PERMISSION_TIME_ENTRIES_WRITE_TEAM,
+	PERMISSION_TIME_ENTRIES_WRITE_ALL,
+	PERMISSION_TIME_ENTRIES_APPROVE_TEAM,
+	PERMISSION_TIME_ENTRIES_APPROVE_ALL,
+	PERMISSION_TIME_ENTRY_TYPES_MANAGE,
+	PERMISSION_QUOTAS_READ_OWN,
+	PERMISSION_QUOTAS_READ_TEAM,
+	PERMISSION_QUOTAS_READ_ALL,
+	PERMISSION_QUOTAS_MANAGE,
+	PERMISSION_USERS_READ,
+	PERMISSION_USERS_MANAGE,
+	PERMISSION_ROLES_MANAGE,
+	PERMISSION_COMPANY_MANAGE,
+}
+
+const PERMISSION_SCOPE_OWN = "own"
+const PERMISSION_SCOPE_TEAM = "team"
+const PERMISSION_SCOPE_ALL = "all"
+
+// Scoped permissions without their scope, which User.CanAccess completes with the scope of the owner's data.
+const PERMISSION_TIME_ENTRIES_READ = "time_entries:read"
+const PERMISSION_QUOTAS_READ = "quotas:read"
+
+var permissionScopeRank = map[string]int{
+	PERMISSION_SCOPE_OWN:  1,
+	PERMISSION_SCOPE_TEAM: 2,
+	PERMISSION_SCOPE_ALL:  3,
+}
+
+// RolePermission assigns a single permission to a role.
+type RolePermission struct {
+	gorm.Model
+
+	UserRoleID uint   `json:"-" gorm:"uniqueIndex:idx_role_permissions_role_permission;not null"`
+	Permission string `json:"permission" gorm:"uniqueIndex:idx_role_permissions_role_permission;not null"`
+}
+
+// IsKnownPermission reports whether the permission is part of ALL_PERMISSIONS.
+func IsKnownPermission(permission string) bool {
+	for _, known := range ALL_PERMISSIONS {
+		if known == permission {
+			return true
+		}
+	}
+	return false
+}
+
+// PermissionGrants reports whether a granted permission satisfies a required one,
+// taking the scope hierarchy own < team < all into account.
+func PermissionGrants(granted, required string) bool {
+	if granted == required {
+		return true
+	}
+
+	grantedBase, grantedScope, grantedScoped := cutScope(granted)
+	requiredBase, requiredScope, requiredScoped := cutScope(required)
+	if !grantedScoped || !requiredScoped || grantedBase != requiredBase {
+		return false
+	}
+	return permissionScopeRank[grantedScope] >= permissionScopeRank[requiredScope]
+}
+
+// PermissionScopeFor returns the scope the actor needs to access data of the owner:
+// their own data, data of a user they manage, or anyone else's data.
+func PermissionScopeFor(actor, owner *User) string {
+	if owner.ID == actor.ID {
+		return PERMISSION_SCOPE_OWN
+	}
+	if owner.ManagerID != nil && *owner.ManagerID == actor.ID {
+		return PERMISSION_SCOPE_TEAM
+	}
+	return PERMISSION_SCOPE_ALL
+}
+
+// CanAccess reports whether the user's role grants a scoped permission without its scope, such as
+// PERMISSION_TIME_ENTRIES_READ, in the scope of the owner's data. The role's permissions have to be loaded.
+func (u *User) CanAccess(owner *User, permission string) bool {
+	return u.Role.HasPermission(permission + ":" + PermissionScopeFor(u, owner))
+}
+
+func cutScope(permission string) (string, string, bool) {
+	index := strings.LastIndex(permission, ":")
+	if index < 0 {
+		return permission, "", false
+	}
+	scope := permission[index+1:]
+	if _, ok := permissionScopeRank[scope]; !ok {
+		return permission, "", false
+	}
+	return permission[:index], scope, true
+}
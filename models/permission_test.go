@@ -0,0 +1,43 @@
+package models_test
+
+import (
+	"testing"
+
+	"github.com/r-52/embrace/models"
+)
+
+func TestPermissionGrants(t *testing.T) {
+	cases := []struct {
+		granted  string
+		required string
+		expected bool
+	}{
+		{models.PERMISSION_QUOTAS_MANAGE, models.PERMISSION_QUOTAS_MANAGE, true},
+		{models.PERMISSION_TIME_ENTRIES_WRITE_ALL, models.PERMISSION_TIME_ENTRIES_WRITE_OWN, true},
+		{models.PERMISSION_TIME_ENTRIES_WRITE_ALL, models.PERMISSION_TIME_ENTRIES_WRITE_TEAM, true},
+		{models.PERMISSION_TIME_ENTRIES_WRITE_TEAM, models.PERMISSION_TIME_ENTRIES_WRITE_OWN, true},
+		{models.PERMISSION_TIME_ENTRIES_WRITE_OWN, models.PERMISSION_TIME_ENTRIES_WRITE_TEAM, false},
+		{models.PERMISSION_TIME_ENTRIES_READ_ALL, models.PERMISSION_TIME_ENTRIES_WRITE_OWN, false},
+		{models.PERMISSION_TIME_ENTRIES_APPROVE_TEAM, models.PERMISSION_TIME_ENTRIES_APPROVE_ALL, false},
+		{models.PERMISSION_USERS_MANAGE, models.PERMISSION_USERS_READ, false},
+	}
+	for _, c := range cases {
+		if actual := models.PermissionGrants(c.granted, c.required); actual != c.expected {
+			t.Errorf("PermissionGrants(%q, %q): expected %v, got %v", c.granted, c.required, c.expected, actual)
+		}
+	}
+}
+
+func TestUserRole_HasPermission(t *testing.T) {
+	role := models.UserRole{
+		Permissions: []models.RolePermission{
+			{Permission: models.PERMISSION_TIME_ENTRIES_READ_TEAM},
+		},
+	}
+	if !role.HasPermission(models.PERMISSION_TIME_ENTRIES_READ_OWN) {
+		t.Errorf("expected team read to grant own read")
+	}
+	if role.HasPermission(models.PERMISSION_TIME_ENTRIES_READ_ALL) {
+		t.Errorf("expected team read not to grant all read")
+	}
+}
@@ -1,17 +1,85 @@
 package models
 
-import "gorm.io/gorm"
+import (
+	"time"
 
+	"gorm.io/gorm"
+)
+
+// Quota names are unique per company. Time entry types refer to a quota by its name.
 type Quota struct {
 	gorm.Model
-	Name      string  `json:"name" gorm:"unique;not null"`
-	CompanyID uint    `json:"-"`
+	Name      string  `json:"name" gorm:"uniqueIndex:idx_quota_company_name,priority:2;not null"`
+	CompanyID uint    `json:"-" gorm:"uniqueIndex:idx_quota_company_name,priority:1"`
 	Company   Company `json:"company"`
 
-	Count        int    `json:"count" gorm:"not null"`
-	QuotaResetAt string `json:"quotaResetAt" gorm:"not null,default:'firstOfYear'"`
+	Count        QuotaAmount `json:"count" gorm:"not null"`
+	QuotaResetAt string      `json:"quotaResetAt" gorm:"not null,default:'firstOfYear'"`
+	// Unit is what Count and the balances of the quota are measured in, one of the QUOTA_UNIT constants.
+	Unit string `json:"unit" gorm:"not null;default:'days'"`
+
+	// CarryOverMax is the most unused quota a reset carries over into the next period. Zero disables carry-over.
+	CarryOverMax QuotaAmount `json:"carryOverMax" gorm:"not null;default:0"`
+	// CarryOverExpiry is the day in the new period after which carried over quota expires, as month and day ("03-31").
+	// An empty value lets carried over quota last until the next reset.
+	CarryOverExpiry string `json:"carryOverExpiry"`
 }
 
 const QUOTA_RESET_FIRST_OF_YEAR = "firstOfYear"
 const QUOTA_RESET_FIRST_OF_MONTH = "firstOfMonth"
 const QUOTA_RESET_FIRST_OF_WEEK = "firstOfWeek"
+const QUOTA_RESET_NEVER = "never"
+
+const QUOTA_UNIT_DAYS = "days"
+const QUOTA_UNIT_HALF_DAYS = "halfDays"
+const QUOTA_UNIT_HOURS = "hours"
+const QUOTA_UNIT_MINUTES = "minutes"
+
+// CARRY_OVER_EXPIRY_LAYOUT is the layout of Quota.CarryOverExpiry.
+const CARRY_OVER_EXPIRY_LAYOUT = "01-02"
+
+// PeriodStart returns the start of the reset period that contains t, in the location of t.
+// Weeks start on Monday. It returns false if the quota is never reset.
+func (q *Quota) PeriodStart(t time.Time) (time.Time, bool) {
+	day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+	switch q.QuotaResetAt {
+	case QUOTA_RESET_FIRST_OF_YEAR:
+		return time.Date(t.Year(), time.January, 1, 0, 0, 0, 0, t.Location()), true
+	case QUOTA_RESET_FIRST_OF_MONTH:
+		return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, t.Location()), true
+	case QUOTA_RESET_FIRST_OF_WEEK:
+		return day.AddDate(0, 0, -(int(day.Weekday())+6)%7), true
+	default:
+		return time.Time{}, false
+	}
+}
+
+// NextPeriodStart returns the start of the reset period following the one starting at periodStart.
+func (q *Quota) NextPeriodStart(periodStart time.Time) time.Time {
+	switch q.QuotaResetAt {
+	case QUOTA_RESET_FIRST_OF_MONTH:
+		return periodStart.AddDate(0, 1, 0)
+	case QUOTA_RESET_FIRST_OF_WEEK:
+		return periodStart.AddDate(0, 0, 7)
+	default:
+		return periodStart.AddDate(1, 0, 0)
+	}
+}
+
+// CarryOverExpiresAt returns when quota carried over into the period starting at periodStart expires:
+// at the end of the first CarryOverExpiry day on or after periodStart, in the location of periodStart.
+// It returns false if carried over quota does not expire or CarryOverExpiry is malformed.
+func (q *Quota) CarryOverExpiresAt(periodStart time.Time) (time.Time, bool) {
+	if q.CarryOverExpiry == "" {
+		return time.Time{}, false
+	}
+	day, err := time.Parse(CARRY_OVER_EXPIRY_LAYOUT, q.CarryOverExpiry)
+	if err != nil {
+		return time.Time{}, false
+	}
+	expiresAt := time.Date(periodStart.Year(), day.Month(), day.Day()+1, 0, 0, 0, 0, periodStart.Location())
+	if !expiresAt.After(periodStart) {
+		expiresAt = expiresAt.AddDate(1, 0, 0)
+	}
+	return expiresAt, true
+}
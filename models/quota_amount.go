@@ -0,0 +1,47 @@
+package models
+
+import (
+	"encoding/json"
+	"math"
+	"strconv"
+)
+
+// QuotaAmount is an amount of quota in thousandths of the unit of its Quota. Storing it as an integer
+// keeps half days and minutes of an hour exact and lets balances be summed without drift.
+// In JSON it is a plain number of units, like 1.5.
+type QuotaAmount int64
+
+// QUOTA_AMOUNT_SCALE is the number of QuotaAmount steps in one unit.
+const QUOTA_AMOUNT_SCALE = 1000
+
+// NewQuotaAmount converts a number of units into a QuotaAmount, rounded to the nearest step.
+func NewQuotaAmount(units float64) QuotaAmount {
+	return QuotaAmount(math.Round(units * QUOTA_AMOUNT_SCALE))
+}
+
+// QuotaUnits returns a QuotaAmount of whole units.
+func QuotaUnits(units int) QuotaAmount {
+	return QuotaAmount(units) * QUOTA_AMOUNT_SCALE
+}
+
+// Units returns the amount as a number of units.
+func (a QuotaAmount) Units() float64 {
+	return float64(a) / QUOTA_AMOUNT_SCALE
+}
+
+func (a QuotaAmount) String() string {
+	return strconv.FormatFloat(a.Units(), 'f', -1, 64)
+}
+
+func (a QuotaAmount) MarshalJSON() ([]byte, error) {
+	return []byte(a.String()), nil
+}
+
+func (a *QuotaAmount) UnmarshalJSON(data []byte) error {
+	var units float64
+	if err := json.Unmarshal(data, &units); err != nil {
+		return err
+	}
+	*a = NewQuotaAmount(units)
+	return nil
+}
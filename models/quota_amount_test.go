@@ -0,0 +1,36 @@
+package models_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/r-52/embrace/models"
+)
+
+func TestQuotaAmount_JSON(t *testing.T) {
+	cases := []struct {
+		amount models.QuotaAmount
+		json   string
+	}{
+		{models.QuotaUnits(30), "30"},
+		{models.NewQuotaAmount(1.5), "1.5"},
+		{models.NewQuotaAmount(-0.25), "-0.25"},
+		{models.NewQuotaAmount(7.0 / 60), "0.117"},
+	}
+	for _, c := range cases {
+		data, err := json.Marshal(c.amount)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if string(data) != c.json {
+			t.Errorf("expected %s, got %s", c.json, data)
+		}
+		var amount models.QuotaAmount
+		if err := json.Unmarshal(data, &amount); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if amount != c.amount {
+			t.Errorf("expected %d after a round trip, got %d", c.amount, amount)
+		}
+	}
+}
@@ -0,0 +1,32 @@
+package models
+
+import (
+	"database/sql"
+
+	"gorm.io/gorm"
+)
+
+// UserQuotaCarryOver is a bucket of quota a reset carried over into a new period.
+// The carried quota is part of UserQuota.Count. The bucket tracks how much of it is left,
+// so bookings can use it up before the rest of the balance, and when the rest expires.
+type UserQuotaCarryOver struct {
+	gorm.Model
+
+	UserQuotaID  uint `json:"userQuotaId" gorm:"index;not null"`
+	QuotaResetID uint `json:"quotaResetId" gorm:"not null"`
+	CompanyID    uint `json:"-" gorm:"index"`
+
+	Carried   QuotaAmount  `json:"carried" gorm:"not null"`
+	Remaining QuotaAmount  `json:"remaining" gorm:"not null"`
+	ExpiresAt sql.NullTime `json:"expiresAt" gorm:"index"`
+
+	// ExpiredAt is set once the bucket is closed, either because it expired or because the next reset absorbed it.
+	ExpiredAt sql.NullTime `json:"expiredAt"`
+	// Expired is the quota that was left when the bucket expired and was removed from the balance.
+	Expired QuotaAmount `json:"expired" gorm:"not null;default:0"`
+}
+
+// IsOpen reports whether the bucket can still be used.
+func (c *UserQuotaCarryOver) IsOpen() bool {
+	return !c.ExpiredAt.Valid
+}
@@ -0,0 +1,34 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// QuotaReset records that the balances of a Quota were reset for the period starting at PeriodStart.
+// Every period is reset at most once, which the unique index guarantees even across restarts and instances.
+type QuotaReset struct {
+	gorm.Model
+
+	QuotaID     uint      `json:"quotaId" gorm:"uniqueIndex:idx_quota_resets_quota_period,priority:1;not null"`
+	PeriodStart time.Time `json:"periodStart" gorm:"uniqueIndex:idx_quota_resets_quota_period,priority:2;not null"`
+	ExecutedAt  time.Time `json:"executedAt" gorm:"not null"`
+
+	CompanyID uint `json:"-" gorm:"index"`
+
+	Entries []UserQuotaReset `json:"entries"`
+}
+
+// UserQuotaReset records how a reset changed the balance of a single UserQuota.
+type UserQuotaReset struct {
+	gorm.Model
+
+	QuotaResetID  uint        `json:"-" gorm:"index;not null"`
+	UserQuotaID   uint        `json:"userQuotaId" gorm:"not null"`
+	UserID        uint        `json:"userId" gorm:"not null"`
+	PreviousCount QuotaAmount `json:"previousCount" gorm:"not null"`
+	Count         QuotaAmount `json:"count" gorm:"not null"`
+	// Carried is the part of Count that was carried over from PreviousCount.
+	Carried QuotaAmount `json:"carried" gorm:"not null;default:0"`
+}
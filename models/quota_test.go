@@ -0,0 +1,64 @@
+package models_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/r-52/embrace/models"
+)
+
+func TestQuota_PeriodStart(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Fatalf("failed to load location: %v", err)
+	}
+	// Thursday
+	now := time.Date(2024, 3, 7, 15, 30, 0, 0, berlin)
+	cases := []struct {
+		resetAt  string
+		t        time.Time
+		expected time.Time
+		ok       bool
+	}{
+		{models.QUOTA_RESET_FIRST_OF_YEAR, now, time.Date(2024, 1, 1, 0, 0, 0, 0, berlin), true},
+		{models.QUOTA_RESET_FIRST_OF_MONTH, now, time.Date(2024, 3, 1, 0, 0, 0, 0, berlin), true},
+		{models.QUOTA_RESET_FIRST_OF_WEEK, now, time.Date(2024, 3, 4, 0, 0, 0, 0, berlin), true},
+		{models.QUOTA_RESET_FIRST_OF_WEEK, time.Date(2024, 3, 10, 23, 0, 0, 0, berlin), time.Date(2024, 3, 4, 0, 0, 0, 0, berlin), true},
+		{models.QUOTA_RESET_FIRST_OF_WEEK, time.Date(2024, 3, 11, 0, 0, 0, 0, berlin), time.Date(2024, 3, 11, 0, 0, 0, 0, berlin), true},
+		{"", now, time.Time{}, false},
+	}
+	for _, c := range cases {
+		quota := models.Quota{QuotaResetAt: c.resetAt}
+		actual, ok := quota.PeriodStart(c.t)
+		if ok != c.ok || !actual.Equal(c.expected) {
+			t.Errorf("PeriodStart(%q, %v): expected %v %v, got %v %v", c.resetAt, c.t, c.expected, c.ok, actual, ok)
+		}
+	}
+}
+
+func TestQuota_CarryOverExpiresAt(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Fatalf("failed to load location: %v", err)
+	}
+	newYear := time.Date(2025, 1, 1, 0, 0, 0, 0, berlin)
+	cases := []struct {
+		expiry      string
+		periodStart time.Time
+		expected    time.Time
+		ok          bool
+	}{
+		{"03-31", newYear, time.Date(2025, 4, 1, 0, 0, 0, 0, berlin), true},
+		{"12-31", newYear, time.Date(2026, 1, 1, 0, 0, 0, 0, berlin), true},
+		{"03-31", time.Date(2025, 6, 1, 0, 0, 0, 0, berlin), time.Date(2026, 4, 1, 0, 0, 0, 0, berlin), true},
+		{"", newYear, time.Time{}, false},
+		{"31.03.", newYear, time.Time{}, false},
+	}
+	for _, c := range cases {
+		quota := models.Quota{CarryOverExpiry: c.expiry}
+		actual, ok := quota.CarryOverExpiresAt(c.periodStart)
+		if ok != c.ok || !actual.Equal(c.expected) {
+			t.Errorf("CarryOverExpiresAt(%q, %v): expected %v %v, got %v %v", c.expiry, c.periodStart, c.expected, c.ok, actual, ok)
+		}
+	}
+}
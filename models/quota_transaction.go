@@ -0,0 +1,42 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// UserQuotaTransaction is a movement on the balance of a UserQuota. Transactions are only ever appended,
+// so the balance at any point in time is the sum of the amounts booked before it, and Balance records
+// what UserQuota.Count was right after the movement, to reconcile the two.
+type UserQuotaTransaction struct {
+	gorm.Model
+
+	UserQuotaID uint `json:"userQuotaId" gorm:"index:idx_user_quota_transactions_user_quota_booked,priority:1;not null"`
+	UserID      uint `json:"userId" gorm:"not null"`
+	CompanyID   uint `json:"-" gorm:"index"`
+
+	// Kind is one of the QUOTA_TRANSACTION constants.
+	Kind     string      `json:"kind" gorm:"not null"`
+	Amount   QuotaAmount `json:"amount" gorm:"not null"`
+	Balance  QuotaAmount `json:"balance" gorm:"not null"`
+	BookedAt time.Time   `json:"bookedAt" gorm:"index:idx_user_quota_transactions_user_quota_booked,priority:2;not null"`
+	Reason   string      `json:"reason"`
+
+	// ActorID references the user who caused the movement. It is nil for movements of the scheduler.
+	ActorID *uint `json:"actorId"`
+
+	// The references below point to what caused the movement, depending on its kind.
+	TimeEntryID          *uint `json:"timeEntryId"`
+	QuotaResetID         *uint `json:"quotaResetId"`
+	UserQuotaCarryOverID *uint `json:"carryOverId"`
+}
+
+const QUOTA_TRANSACTION_ALLOCATION = "allocation"
+const QUOTA_TRANSACTION_CONSUMPTION = "consumption"
+const QUOTA_TRANSACTION_ADJUSTMENT = "adjustment"
+const QUOTA_TRANSACTION_CARRY_OVER = "carryOver"
+const QUOTA_TRANSACTION_EXPIRY = "expiry"
+const QUOTA_TRANSACTION_RESET = "reset"
+const QUOTA_TRANSACTION_OVERTIME = "overtime"
+const QUOTA_TRANSACTION_PAYOUT = "payout"
@@ -0,0 +1,30 @@
+package models
+
+import (
+	"database/sql"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// RefreshToken stores the server side state of an issued refresh token.
+// Tokens issued by rotating another token share its FamilyID, which allows
+// revoking the whole chain once a token is reused after it was rotated.
+type RefreshToken struct {
+	gorm.Model
+
+	TokenID  string `json:"-" gorm:"uniqueIndex;not null"`
+	FamilyID string `json:"-" gorm:"index;not null"`
+
+	UserID uint `json:"-" gorm:"index;not null"`
+	User   User `json:"user"`
+
+	ExpiresAt         time.Time    `json:"expiresAt" gorm:"not null"`
+	RevokedAt         sql.NullTime `json:"revokedAt"`
+	ReplacedByTokenID string       `json:"-"`
+}
+
+// IsActive reports whether the token is neither revoked nor expired at the given time.
+func (t *RefreshToken) IsActive(now time.Time) bool {
+	return !t.RevokedAt.Valid && now.Before(t.ExpiresAt)
+}
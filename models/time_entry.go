@@ -7,6 +7,9 @@ import (
 	"gorm.io/gorm"
 )
 
+// TimeEntry is a span of time a user booked on a TimeEntryType.
+// StartTime and EndTime are kept as booked, Duration is derived from them by the company's DurationPolicy
+// and stored in hours. An entry without an end time is a running timer, every user has at most one of them.
 type TimeEntry struct {
 	gorm.Model
 
@@ -15,11 +18,38 @@ type TimeEntry struct {
 	Duration  sql.NullFloat64 `json:"duration"`
 	Note      string          `json:"note"`
 
-	UserID uint `json:"-"`
+	// DeductedBreak are the hours of statutory break the DurationPolicy deducted from the duration.
+	DeductedBreak float64 `json:"deductedBreak" gorm:"not null;default:0"`
+
+	// Paused marks a closed entry whose timer was paused and can be resumed.
+	Paused bool `json:"paused" gorm:"not null;default:false"`
+
+	CompanyID uint `json:"-" gorm:"index"`
+
+	UserID uint `json:"-" gorm:"index;uniqueIndex:idx_time_entries_running,where:end_time IS NULL AND deleted_at IS NULL"`
 	User   User `json:"user"`
 
-	TimeEntryTypeID uint          `json:"-"`
+	TimeEntryTypeID uint          `json:"-" gorm:"index"`
 	TimeEntryType   TimeEntryType `json:"timeEntryType"`
+
+	// LeaveRequestID references the approved LeaveRequest the entry was booked for.
+	LeaveRequestID *uint `json:"-" gorm:"index"`
+
+	// Warnings are the compliance violations that booking or changing the entry caused without rejecting it.
+	// They are not stored.
+	Warnings []ComplianceViolation `json:"-" gorm:"-"`
+}
+
+// IsRunning reports whether the entry is a running timer.
+func (t *TimeEntry) IsRunning() bool {
+	return !t.EndTime.Valid
+}
+
+// Close sets the end time of the entry and derives its duration in hours from the booked times.
+func (t *TimeEntry) Close(endTime time.Time) {
+	t.EndTime = sql.NullTime{Time: endTime, Valid: true}
+	t.Duration = sql.NullFloat64{Float64: endTime.Sub(t.StartTime).Hours(), Valid: true}
+	t.DeductedBreak = 0
 }
 
 type TimeEntryType struct {
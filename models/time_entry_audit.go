@@ -0,0 +1,96 @@
+package models
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// TimeEntryAudit records a change of a time entry: who created, updated or deleted it when and why, and its state
+// before and after. Records are only ever appended and form a hash chain per company. Every record holds the Hash of
+// the record before it and its own Hash covers its content including that hash, so a record that is changed or
+// removed afterwards breaks the chain from there on. Records removed from the end of the chain leave a shorter chain
+// that is still intact, they are only revealed by comparing the chain with a head noted before.
+type TimeEntryAudit struct {
+	gorm.Model
+
+	CompanyID uint `json:"-" gorm:"uniqueIndex:idx_time_entry_audits_company_sequence,priority:1;not null"`
+	// Sequence numbers the records of a company without gaps, starting at 1.
+	Sequence uint `json:"sequence" gorm:"uniqueIndex:idx_time_entry_audits_company_sequence,priority:2;not null"`
+
+	TimeEntryID uint `json:"timeEntryId" gorm:"index;not null"`
+	// UserID references the owner of the time entry.
+	UserID uint `json:"userId" gorm:"not null"`
+	// Operation is one of the AUDIT_OPERATION constants.
+	Operation string `json:"operation" gorm:"not null"`
+	// ActorID references the user who changed the entry.
+	ActorID    uint      `json:"actorId" gorm:"not null"`
+	Reason     string    `json:"reason"`
+	RecordedAt time.Time `json:"recordedAt" gorm:"not null"`
+
+	// Before and After are TimeEntrySnapshots as JSON. Before is empty for created entries, After for deleted ones.
+	Before string `json:"before"`
+	After  string `json:"after"`
+
+	PreviousHash string `json:"previousHash" gorm:"not null"`
+	Hash         string `json:"hash" gorm:"not null"`
+}
+
+const AUDIT_OPERATION_CREATE = "create"
+const AUDIT_OPERATION_UPDATE = "update"
+const AUDIT_OPERATION_DELETE = "delete"
+
+// TimeEntrySnapshot is the state of a time entry recorded by a TimeEntryAudit. Times are in UTC.
+type TimeEntrySnapshot struct {
+	StartTime       time.Time  `json:"startTime"`
+	EndTime         *time.Time `json:"endTime"`
+	Duration        *float64   `json:"duration"`
+	DeductedBreak   float64    `json:"deductedBreak"`
+	Note            string     `json:"note"`
+	Paused          bool       `json:"paused"`
+	UserID          uint       `json:"userId"`
+	TimeEntryTypeID uint       `json:"timeEntryTypeId"`
+	LeaveRequestID  *uint      `json:"leaveRequestId"`
+}
+
+// NewTimeEntrySnapshot returns the JSON of the snapshot of a time entry, or an empty string for a nil entry.
+func NewTimeEntrySnapshot(entry *TimeEntry) (string, error) {
+	if entry == nil {
+		return "", nil
+	}
+	snapshot := TimeEntrySnapshot{
+		StartTime:       entry.StartTime.UTC(),
+		DeductedBreak:   entry.DeductedBreak,
+		Note:            entry.Note,
+		Paused:          entry.Paused,
+		UserID:          entry.UserID,
+		TimeEntryTypeID: entry.TimeEntryTypeID,
+		LeaveRequestID:  entry.LeaveRequestID,
+	}
+	if entry.EndTime.Valid {
+		endTime := entry.EndTime.Time.UTC()
+		snapshot.EndTime = &endTime
+	}
+	if entry.Duration.Valid {
+		snapshot.Duration = &entry.Duration.Float64
+	}
+	data, err := json.Marshal(snapshot)
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+// ComputeHash returns the hex encoded SHA-256 hash of the content of the record and PreviousHash.
+func (a *TimeEntryAudit) ComputeHash() string {
+	// Encoding the fields as a JSON array keeps them apart, whatever the strings contain.
+	data, _ := json.Marshal([]interface{}{
+		a.CompanyID, a.Sequence, a.TimeEntryID, a.UserID, a.Operation, a.ActorID, a.Reason,
+		a.RecordedAt.UTC().Format(time.RFC3339Nano), a.Before, a.After, a.PreviousHash,
+	})
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
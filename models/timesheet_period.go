@@ -0,0 +1,53 @@
+package models
+
+import (
+	"database/sql"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// TimesheetPeriod is the timesheet of a user for a calendar month, in the company's timezone. The user submits it,
+// their manager approves it and payroll locks the month for the whole company. The time entries that start in a
+// locked period cannot be booked, changed or deleted until an admin reopens it. Months without a stored period
+// are open.
+type TimesheetPeriod struct {
+	gorm.Model
+
+	CompanyID uint `json:"-" gorm:"index"`
+	UserID    uint `json:"userId" gorm:"uniqueIndex:idx_timesheet_periods_user_month,priority:1;not null"`
+	// Month is the first day of the month, at midnight UTC.
+	Month time.Time `json:"month" gorm:"uniqueIndex:idx_timesheet_periods_user_month,priority:2;not null"`
+
+	// Status is one of the TIMESHEET constants.
+	Status       string       `json:"status" gorm:"index;not null;default:'open'"`
+	SubmittedAt  sql.NullTime `json:"submittedAt"`
+	ApprovedByID *uint        `json:"approvedById"`
+	ApprovedAt   sql.NullTime `json:"approvedAt"`
+	LockedByID   *uint        `json:"lockedById"`
+	LockedAt     sql.NullTime `json:"lockedAt"`
+	// ReopenedByID, ReopenedAt and ReopenReason record the latest time the period was reopened.
+	ReopenedByID *uint        `json:"reopenedById"`
+	ReopenedAt   sql.NullTime `json:"reopenedAt"`
+	ReopenReason string       `json:"reopenReason"`
+}
+
+const TIMESHEET_OPEN = "open"
+const TIMESHEET_SUBMITTED = "submitted"
+const TIMESHEET_APPROVED = "approved"
+const TIMESHEET_LOCKED = "locked"
+
+// IsLocked reports whether the time entries of the period are locked.
+func (p *TimesheetPeriod) IsLocked() bool {
+	return p.Status == TIMESHEET_LOCKED
+}
+
+// Start returns the beginning of the period in `location`.
+func (p *TimesheetPeriod) Start(location *time.Location) time.Time {
+	return time.Date(p.Month.Year(), p.Month.Month(), 1, 0, 0, 0, 0, location)
+}
+
+// End returns the beginning of the month after the period in `location`.
+func (p *TimesheetPeriod) End(location *time.Location) time.Time {
+	return time.Date(p.Month.Year(), p.Month.Month()+1, 1, 0, 0, 0, 0, location)
+}
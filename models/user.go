@@ -1,6 +1,8 @@
 package models
 
 import (
+	"database/sql"
+
 	"gorm.io/gorm"
 )
 
@@ -16,18 +18,47 @@ type User struct {
 	RoleID uint     `json:"-"`
 	Role   UserRole `json:"role"`
 
+	// ManagerID references the user's manager. The users managed by someone form their team.
+	ManagerID *uint `json:"-" gorm:"index"`
+
+	// EmploymentStart and EmploymentEnd are the first and the last day of employment, stored at midnight UTC.
+	// Quota entitlements are pro-rated to the part of a period the user is employed in.
+	EmploymentStart sql.NullTime `json:"employmentStart"`
+	EmploymentEnd   sql.NullTime `json:"employmentEnd"`
+	// WorkingDaysPerWeek are the contracted working days. Quota entitlements are scaled by their share of Company.FullTimeWorkingDays.
+	WorkingDaysPerWeek int `json:"workingDaysPerWeek" gorm:"not null;default:5"`
+	// DailyWorkingHours are the contracted hours of a working day. Time entries booked on quotas
+	// measured in days are converted with them.
+	DailyWorkingHours float64 `json:"dailyWorkingHours" gorm:"not null;default:8"`
+	// HolidayCalendarID references the calendar of the user's days off. Users without one have no holidays.
+	HolidayCalendarID *uint `json:"holidayCalendarId" gorm:"index"`
+
 	UserProfile   UserProfile `json:"userProfile"`
 	UserProfileID uint        `json:"-"`
 	TimeEntries   []TimeEntry `json:"timeEntries"`
 }
 
+// DEFAULT_DAILY_WORKING_HOURS are the hours of a working day of users without DailyWorkingHours.
+const DEFAULT_DAILY_WORKING_HOURS = 8
+
+// WorkingHoursPerDay returns the contracted hours of a working day of the user.
+func (u *User) WorkingHoursPerDay() float64 {
+	if u.DailyWorkingHours <= 0 {
+		return DEFAULT_DAILY_WORKING_HOURS
+	}
+	return u.DailyWorkingHours
+}
+
+// UserQuota is the balance of a user on a quota. A user has each quota at most once.
 type UserQuota struct {
 	gorm.Model
-	QuotaID uint  `json:"-"`
-	Quota   Quota `json:"quota"`
-	UserID  uint  `json:"-"`
-	User    User  `json:"user"`
-	Count   int   `json:"count" gorm:"not null"`
+	QuotaID uint        `json:"-" gorm:"uniqueIndex:idx_user_quota_user_quota,priority:2"`
+	Quota   Quota       `json:"quota"`
+	UserID  uint        `json:"-" gorm:"uniqueIndex:idx_user_quota_user_quota,priority:1"`
+	User    User        `json:"user"`
+	Count   QuotaAmount `json:"count" gorm:"not null"`
+	// Entitlement is the part of Count the user was allocated for the current period, see QuotaAllocator.
+	Entitlement QuotaAmount `json:"entitlement" gorm:"not null;default:0"`
 }
 
 type UserProfile struct {
@@ -44,11 +75,32 @@ type UserProfile struct {
 	Phone  string `json:"phone"`
 }
 
+// UserRole names are unique per company, so every company can have its own "admin".
 type UserRole struct {
 	gorm.Model
-	Name string `json:"name" gorm:"unique;not null"`
+	Name string `json:"name" gorm:"uniqueIndex:idx_user_roles_company_name,priority:2;not null"`
 
 	InternalUsage int     `json:"internalUsage" gorm:"default:0"`
-	CompanyID     uint    `json:"-"`
+	CompanyID     uint    `json:"-" gorm:"uniqueIndex:idx_user_roles_company_name,priority:1"`
 	Company       Company `json:"company"`
+
+	Permissions []RolePermission `json:"permissions" gorm:"foreignKey:UserRoleID"`
+}
+
+// InternalUsage values of the roles every company is seeded with.
+// Custom roles created by a company use ROLE_INTERNAL_USAGE_CUSTOM.
+const ROLE_INTERNAL_USAGE_CUSTOM = 0
+const ROLE_INTERNAL_USAGE_ADMIN = 1
+const ROLE_INTERNAL_USAGE_MANAGER = 2
+const ROLE_INTERNAL_USAGE_EMPLOYEE = 3
+
+// HasPermission reports whether one of the role's permissions grants the required permission.
+// The permissions have to be loaded for this to work.
+func (r *UserRole) HasPermission(permission string) bool {
+	for _, granted := range r.Permissions {
+		if PermissionGrants(granted.Permission, permission) {
+			return true
+		}
+	}
+	return false
 }
@@ -0,0 +1,108 @@
+package models
+
+import (
+	"database/sql"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// WorkSchedule describes how many hours a user is supposed to work on each day. Users are assigned
+// schedules for validity periods, see UserWorkSchedule.
+type WorkSchedule struct {
+	gorm.Model
+
+	CompanyID uint   `json:"-" gorm:"index"`
+	Name      string `json:"name" gorm:"not null"`
+	// Kind is one of the WORK_SCHEDULE constants.
+	Kind string `json:"kind" gorm:"not null"`
+
+	// WeeklyHours of a weekly schedule are spread evenly over WorkingDays weekdays from Monday on.
+	WeeklyHours float64 `json:"weeklyHours" gorm:"not null;default:0"`
+	WorkingDays int     `json:"workingDays" gorm:"not null;default:5"`
+	// RotationWeeks is the length of the pattern of a rotating schedule.
+	RotationWeeks int `json:"rotationWeeks" gorm:"not null;default:1"`
+
+	// Days are the hours of the weekdays of weekday and rotating schedules. Missing days are days off.
+	Days []WorkScheduleDay `json:"days" gorm:"foreignKey:WorkScheduleID"`
+}
+
+const WORK_SCHEDULE_WEEKLY = "weekly"
+const WORK_SCHEDULE_WEEKDAYS = "weekdays"
+const WORK_SCHEDULE_ROTATING = "rotating"
+
+// WorkScheduleDay are the hours of a weekday in a week of a schedule's pattern.
+type WorkScheduleDay struct {
+	gorm.Model
+
+	WorkScheduleID uint `json:"-" gorm:"index;not null"`
+	// Week is the week of a rotating pattern, starting with 0. Weekday schedules only have week 0.
+	Week    int          `json:"week" gorm:"not null;default:0"`
+	Weekday time.Weekday `json:"weekday" gorm:"not null"`
+	Hours   float64      `json:"hours" gorm:"not null"`
+}
+
+// HoursOn returns the hours the schedule plans for a day. The pattern of a rotating schedule starts
+// in the week of `rotationStart`, the first day the schedule applies to a user.
+func (s *WorkSchedule) HoursOn(day, rotationStart time.Time) float64 {
+	switch s.Kind {
+	case WORK_SCHEDULE_WEEKLY:
+		if s.WorkingDays <= 0 || weekdayIndex(day.Weekday()) >= s.WorkingDays {
+			return 0
+		}
+		return s.WeeklyHours / float64(s.WorkingDays)
+	case WORK_SCHEDULE_ROTATING:
+		week := 0
+		if s.RotationWeeks > 1 {
+			week = weeksBetween(rotationStart, day) % s.RotationWeeks
+		}
+		return s.hoursOf(week, day.Weekday())
+	default:
+		return s.hoursOf(0, day.Weekday())
+	}
+}
+
+func (s *WorkSchedule) hoursOf(week int, weekday time.Weekday) float64 {
+	for _, day := range s.Days {
+		if day.Week == week && day.Weekday == weekday {
+			return day.Hours
+		}
+	}
+	return 0
+}
+
+// weekdayIndex counts the days of a week from Monday on.
+func weekdayIndex(weekday time.Weekday) int {
+	return (int(weekday) + 6) % 7
+}
+
+// weeksBetween returns the number of weeks from the week of `from` to the week of `to`, weeks beginning on Monday.
+func weeksBetween(from, to time.Time) int {
+	fromMonday := time.Date(from.Year(), from.Month(), from.Day()-weekdayIndex(from.Weekday()), 0, 0, 0, 0, time.UTC)
+	toMonday := time.Date(to.Year(), to.Month(), to.Day()-weekdayIndex(to.Weekday()), 0, 0, 0, 0, time.UTC)
+	weeks := int(toMonday.Sub(fromMonday).Hours()/24) / 7
+	if weeks < 0 {
+		return 0
+	}
+	return weeks
+}
+
+// UserWorkSchedule assigns a WorkSchedule to a user from ValidFrom until ValidUntil, both days at midnight UTC and inclusive.
+// An open ValidUntil lasts until a later schedule is assigned. The assignments of a user do not overlap.
+type UserWorkSchedule struct {
+	gorm.Model
+
+	CompanyID uint `json:"-" gorm:"index"`
+	UserID    uint `json:"userId" gorm:"index;not null"`
+
+	WorkScheduleID uint         `json:"workScheduleId" gorm:"index;not null"`
+	WorkSchedule   WorkSchedule `json:"workSchedule"`
+
+	ValidFrom  time.Time    `json:"validFrom" gorm:"not null"`
+	ValidUntil sql.NullTime `json:"validUntil"`
+}
+
+// AppliesOn reports whether the assignment is valid on the day, given at midnight UTC.
+func (s *UserWorkSchedule) AppliesOn(day time.Time) bool {
+	return !day.Before(s.ValidFrom) && (!s.ValidUntil.Valid || !day.After(s.ValidUntil.Time))
+}
@@ -0,0 +1,47 @@
+package models_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/r-52/embrace/models"
+)
+
+func TestWorkSchedule_HoursOn(t *testing.T) {
+	monday := time.Date(2024, 3, 4, 0, 0, 0, 0, time.UTC)
+	weekly := &models.WorkSchedule{Kind: models.WORK_SCHEDULE_WEEKLY, WeeklyHours: 30, WorkingDays: 4}
+	weekdays := &models.WorkSchedule{Kind: models.WORK_SCHEDULE_WEEKDAYS, Days: []models.WorkScheduleDay{
+		{Weekday: time.Monday, Hours: 9},
+		{Weekday: time.Friday, Hours: 5},
+	}}
+	rotating := &models.WorkSchedule{Kind: models.WORK_SCHEDULE_ROTATING, RotationWeeks: 2, Days: []models.WorkScheduleDay{
+		{Week: 0, Weekday: time.Monday, Hours: 8},
+		{Week: 1, Weekday: time.Saturday, Hours: 6},
+	}}
+
+	tests := []struct {
+		name     string
+		schedule *models.WorkSchedule
+		day      time.Time
+		start    time.Time
+		expected float64
+	}{
+		{name: "weekly on monday", schedule: weekly, day: monday, expected: 7.5},
+		{name: "weekly on thursday", schedule: weekly, day: monday.AddDate(0, 0, 3), expected: 7.5},
+		{name: "weekly on friday", schedule: weekly, day: monday.AddDate(0, 0, 4), expected: 0},
+		{name: "weekdays on monday", schedule: weekdays, day: monday, expected: 9},
+		{name: "weekdays on tuesday", schedule: weekdays, day: monday.AddDate(0, 0, 1), expected: 0},
+		{name: "weekdays on friday", schedule: weekdays, day: monday.AddDate(0, 0, 4), expected: 5},
+		{name: "rotation first week", schedule: rotating, day: monday, start: monday, expected: 8},
+		{name: "rotation second week", schedule: rotating, day: monday.AddDate(0, 0, 12), start: monday, expected: 6},
+		{name: "rotation third week", schedule: rotating, day: monday.AddDate(0, 0, 14), start: monday, expected: 8},
+		{name: "rotation started mid-week", schedule: rotating, day: monday.AddDate(0, 0, 7), start: monday.AddDate(0, 0, 3), expected: 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if actual := tt.schedule.HoursOn(tt.day, tt.start); actual != tt.expected {
+				t.Errorf("expected %v hours, got %v", tt.expected, actual)
+			}
+		})
+	}
+}
@@ -0,0 +1,54 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/r-52/embrace/middleware"
+	"github.com/r-52/embrace/models"
+	dto "github.com/r-52/embrace/models/dto/audit"
+	"github.com/r-52/embrace/services/audit"
+	"gorm.io/gorm"
+)
+
+func setupAuditRoutes(authenticated *gin.RouterGroup, db *gorm.DB) {
+	auditRoutes := authenticated.Group("/audit", middleware.RequirePermission(models.PERMISSION_COMPANY_MANAGE))
+	auditRoutes.GET("", func(c *gin.Context) {
+		var req dto.ListAuditLogsRequest
+		if err := c.ShouldBindQuery(&req); err != nil {
+			c.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+			return
+		}
+
+		auditLogs, err := audit.NewAuditLogService(middleware.TenantDatabase(c, db)).List(&req)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusOK, dto.NewAuditLogResponses(auditLogs))
+	})
+	// The export is in the JSON Lines format, one audit log per line.
+	auditRoutes.GET("/export", func(c *gin.Context) {
+		var req dto.ListAuditLogsRequest
+		if err := c.ShouldBindQuery(&req); err != nil {
+			c.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+			return
+		}
+
+		auditLogs, err := audit.NewAuditLogService(middleware.TenantDatabase(c, db)).Export(&req)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		c.Header("Content-Disposition", `attachment; filename="audit-log.jsonl"`)
+		c.Header("Content-Type", "application/x-ndjson")
+		c.Status(http.StatusOK)
+		encoder := json.NewEncoder(c.Writer)
+		for _, response := range dto.NewAuditLogResponses(auditLogs) {
+			if err := encoder.Encode(response); err != nil {
+				return
+			}
+		}
+	})
+}
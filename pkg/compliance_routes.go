@@ -0,0 +1,69 @@
+package main
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/r-52/embrace/middleware"
+	"github.com/r-52/embrace/models"
+	dto "github.com/r-52/embrace/models/dto/compliance"
+	"github.com/r-52/embrace/services/compliance"
+	"gorm.io/gorm"
+)
+
+func setupComplianceRoutes(authenticated *gin.RouterGroup, db *gorm.DB) {
+	complianceRoutes := authenticated.Group("/compliance")
+	complianceRoutes.GET("/rules", func(c *gin.Context) {
+		rules, err := compliance.NewComplianceService(middleware.TenantDatabase(c, db)).GetRules(middleware.CurrentUser(c).CompanyID)
+		if err != nil {
+			respondComplianceError(c, err)
+			return
+		}
+		c.JSON(http.StatusOK, dto.NewComplianceRuleResponses(rules))
+	})
+	complianceRoutes.PUT("/rules", middleware.RequirePermission(models.PERMISSION_COMPANY_MANAGE), func(c *gin.Context) {
+		var req dto.ComplianceRuleSetRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+			return
+		}
+
+		rules, err := compliance.NewComplianceService(middleware.TenantDatabase(c, db)).UpdateRules(middleware.CurrentUser(c).CompanyID, &req)
+		if err != nil {
+			respondComplianceError(c, err)
+			return
+		}
+		c.JSON(http.StatusOK, dto.NewComplianceRuleResponses(rules))
+	})
+	complianceRoutes.GET("/report", middleware.RequirePermission(models.PERMISSION_TIME_ENTRIES_READ_ALL), func(c *gin.Context) {
+		var req dto.ComplianceReportRequest
+		if err := c.ShouldBindQuery(&req); err != nil {
+			c.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+			return
+		}
+
+		violations, err := compliance.NewComplianceService(middleware.TenantDatabase(c, db)).Report(middleware.CurrentUser(c).CompanyID, &req)
+		if err != nil {
+			respondComplianceError(c, err)
+			return
+		}
+		c.JSON(http.StatusOK, &dto.ComplianceReportResponse{
+			From:       req.From.Format(time.DateOnly),
+			To:         req.To.Format(time.DateOnly),
+			Violations: dto.NewViolationResponses(violations),
+		})
+	})
+}
+
+func respondComplianceError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, compliance.ErrUnknownComplianceRule), errors.Is(err, compliance.ErrInvalidCompliancePeriod):
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
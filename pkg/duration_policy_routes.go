@@ -0,0 +1,51 @@
+package main
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/r-52/embrace/middleware"
+	"github.com/r-52/embrace/models"
+	dto "github.com/r-52/embrace/models/dto/timeentry"
+	"github.com/r-52/embrace/services/timeentry"
+	"gorm.io/gorm"
+)
+
+func setupDurationPolicyRoutes(authenticated *gin.RouterGroup, db *gorm.DB) {
+	authenticated.GET("/duration-policy", func(c *gin.Context) {
+		policy, err := timeentry.NewDurationPolicyService(middleware.TenantDatabase(c, db)).GetPolicy(middleware.CurrentUser(c).CompanyID)
+		if err != nil {
+			respondTimeEntryError(c, err)
+			return
+		}
+		c.JSON(http.StatusOK, dto.NewDurationPolicyResponse(policy))
+	})
+	authenticated.PUT("/duration-policy", middleware.RequirePermission(models.PERMISSION_COMPANY_MANAGE), func(c *gin.Context) {
+		var req dto.DurationPolicyRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+			return
+		}
+
+		policy, err := timeentry.NewDurationPolicyService(middleware.TenantDatabase(c, db)).UpdatePolicy(middleware.CurrentUser(c).CompanyID, &req)
+		if err != nil {
+			respondTimeEntryError(c, err)
+			return
+		}
+		c.JSON(http.StatusOK, dto.NewDurationPolicyResponse(policy))
+	})
+	authenticated.POST("/duration-policy/reapply", middleware.RequirePermission(models.PERMISSION_COMPANY_MANAGE), func(c *gin.Context) {
+		var req dto.ReapplyDurationPolicyRequest
+		if err := c.ShouldBindQuery(&req); err != nil {
+			c.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+			return
+		}
+
+		updated, err := timeentry.NewDurationPolicyService(middleware.TenantDatabase(c, db)).Reapply(middleware.CurrentUser(c), &req)
+		if err != nil {
+			respondTimeEntryError(c, err)
+			return
+		}
+		c.JSON(http.StatusOK, &dto.ReapplyDurationPolicyResponse{Updated: updated})
+	})
+}
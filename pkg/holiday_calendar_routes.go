@@ -0,0 +1,158 @@
+package main
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/r-52/embrace/middleware"
+	"github.com/r-52/embrace/models"
+	dto "github.com/r-52/embrace/models/dto/holiday"
+	"github.com/r-52/embrace/services/holiday"
+	"gorm.io/gorm"
+)
+
+func setupHolidayCalendarRoutes(authenticated *gin.RouterGroup, db *gorm.DB) {
+	calendarRoutes := authenticated.Group("/holiday-calendars")
+	calendarRoutes.GET("/regions", func(c *gin.Context) {
+		c.JSON(http.StatusOK, holiday.REGIONS)
+	})
+	calendarRoutes.GET("", func(c *gin.Context) {
+		calendars, err := holiday.NewHolidayCalendarService(middleware.TenantDatabase(c, db)).ListCalendars(middleware.CurrentUser(c).CompanyID)
+		if err != nil {
+			respondHolidayError(c, err)
+			return
+		}
+		c.JSON(http.StatusOK, dto.NewHolidayCalendarResponses(calendars))
+	})
+	calendarRoutes.GET("/:id", func(c *gin.Context) {
+		id, ok := idParam(c)
+		if !ok {
+			return
+		}
+
+		calendar, err := holiday.NewHolidayCalendarService(middleware.TenantDatabase(c, db)).GetCalendar(middleware.CurrentUser(c).CompanyID, id)
+		if err != nil {
+			respondHolidayError(c, err)
+			return
+		}
+		c.JSON(http.StatusOK, dto.NewHolidayCalendarResponse(calendar))
+	})
+	calendarRoutes.GET("/:id/holidays", func(c *gin.Context) {
+		id, ok := idParam(c)
+		if !ok {
+			return
+		}
+		var req dto.ListHolidaysRequest
+		if err := c.ShouldBindQuery(&req); err != nil {
+			c.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+			return
+		}
+		if req.Year == 0 {
+			req.Year = time.Now().Year()
+		}
+
+		holidays, err := holiday.NewHolidayCalendarService(middleware.TenantDatabase(c, db)).ListHolidays(middleware.CurrentUser(c).CompanyID, id, req.Year)
+		if err != nil {
+			respondHolidayError(c, err)
+			return
+		}
+		c.JSON(http.StatusOK, dto.NewHolidayResponses(holidays))
+	})
+
+	manageRoutes := calendarRoutes.Group("", middleware.RequirePermission(models.PERMISSION_COMPANY_MANAGE))
+	manageRoutes.POST("", func(c *gin.Context) {
+		var req dto.HolidayCalendarRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+			return
+		}
+
+		calendar, err := holiday.NewHolidayCalendarService(middleware.TenantDatabase(c, db)).CreateCalendar(middleware.CurrentUser(c).CompanyID, &req)
+		if err != nil {
+			respondHolidayError(c, err)
+			return
+		}
+		c.JSON(http.StatusCreated, dto.NewHolidayCalendarResponse(calendar))
+	})
+	manageRoutes.PUT("/:id", func(c *gin.Context) {
+		id, ok := idParam(c)
+		if !ok {
+			return
+		}
+		var req dto.HolidayCalendarRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+			return
+		}
+
+		calendar, err := holiday.NewHolidayCalendarService(middleware.TenantDatabase(c, db)).UpdateCalendar(middleware.CurrentUser(c).CompanyID, id, &req)
+		if err != nil {
+			respondHolidayError(c, err)
+			return
+		}
+		c.JSON(http.StatusOK, dto.NewHolidayCalendarResponse(calendar))
+	})
+	manageRoutes.DELETE("/:id", func(c *gin.Context) {
+		id, ok := idParam(c)
+		if !ok {
+			return
+		}
+
+		err := holiday.NewHolidayCalendarService(middleware.TenantDatabase(c, db)).DeleteCalendar(middleware.CurrentUser(c).CompanyID, id)
+		if err != nil {
+			respondHolidayError(c, err)
+			return
+		}
+		c.Status(http.StatusNoContent)
+	})
+	manageRoutes.POST("/:id/holidays", func(c *gin.Context) {
+		id, ok := idParam(c)
+		if !ok {
+			return
+		}
+		var req dto.HolidayRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+			return
+		}
+
+		created, err := holiday.NewHolidayCalendarService(middleware.TenantDatabase(c, db)).AddHoliday(middleware.CurrentUser(c).CompanyID, id, &req)
+		if err != nil {
+			respondHolidayError(c, err)
+			return
+		}
+		c.JSON(http.StatusCreated, dto.NewHolidayResponse(created))
+	})
+	manageRoutes.DELETE("/:id/holidays/:holidayId", func(c *gin.Context) {
+		id, ok := idParam(c)
+		if !ok {
+			return
+		}
+		holidayID, ok := uintParam(c, "holidayId")
+		if !ok {
+			return
+		}
+
+		err := holiday.NewHolidayCalendarService(middleware.TenantDatabase(c, db)).DeleteHoliday(middleware.CurrentUser(c).CompanyID, id, holidayID)
+		if err != nil {
+			respondHolidayError(c, err)
+			return
+		}
+		c.Status(http.StatusNoContent)
+	})
+}
+
+func respondHolidayError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, holiday.ErrUnknownRegion):
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+	case errors.Is(err, holiday.ErrHolidayCalendarInUse):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
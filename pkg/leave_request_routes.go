@@ -0,0 +1,78 @@
+package main
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/r-52/embrace/middleware"
+	"github.com/r-52/embrace/models"
+	dto "github.com/r-52/embrace/models/dto/timeentry"
+	"github.com/r-52/embrace/services/timeentry"
+	"gorm.io/gorm"
+)
+
+// leaveRequestDecision is one of the LeaveRequestService methods that move a leave request to another status.
+type leaveRequestDecision func(s *timeentry.LeaveRequestService, actor *models.User, id uint, req *dto.DecideLeaveRequestRequest, now time.Time) (*models.LeaveRequest, error)
+
+func setupLeaveRequestRoutes(authenticated *gin.RouterGroup, db *gorm.DB) {
+	leaveRequestRoutes := authenticated.Group("/leave-requests")
+	leaveRequestRoutes.GET("", func(c *gin.Context) {
+		leaveRequests, err := timeentry.NewLeaveRequestService(middleware.TenantDatabase(c, db)).List(middleware.CurrentUser(c))
+		if err != nil {
+			respondTimeEntryError(c, err)
+			return
+		}
+		c.JSON(http.StatusOK, dto.NewLeaveRequestResponses(leaveRequests))
+	})
+	leaveRequestRoutes.GET("/inbox", func(c *gin.Context) {
+		leaveRequests, err := timeentry.NewLeaveRequestService(middleware.TenantDatabase(c, db)).Inbox(middleware.CurrentUser(c))
+		if err != nil {
+			respondTimeEntryError(c, err)
+			return
+		}
+		c.JSON(http.StatusOK, dto.NewLeaveRequestResponses(leaveRequests))
+	})
+	leaveRequestRoutes.POST("", func(c *gin.Context) {
+		var req dto.CreateLeaveRequestRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+			return
+		}
+
+		leaveRequest, err := timeentry.NewLeaveRequestService(middleware.TenantDatabase(c, db)).Request(middleware.CurrentUser(c), &req)
+		if err != nil {
+			respondTimeEntryError(c, err)
+			return
+		}
+		c.JSON(http.StatusCreated, dto.NewLeaveRequestResponse(leaveRequest))
+	})
+	leaveRequestRoutes.POST("/:id/approve", decideLeaveRequest(db, (*timeentry.LeaveRequestService).Approve))
+	leaveRequestRoutes.POST("/:id/reject", decideLeaveRequest(db, (*timeentry.LeaveRequestService).Reject))
+	leaveRequestRoutes.POST("/:id/cancel", decideLeaveRequest(db, (*timeentry.LeaveRequestService).Cancel))
+}
+
+// decideLeaveRequest handles a decision on a leave request. The request body with the note is optional.
+func decideLeaveRequest(db *gorm.DB, decision leaveRequestDecision) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, ok := idParam(c)
+		if !ok {
+			return
+		}
+		var req dto.DecideLeaveRequestRequest
+		if err := c.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
+			c.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+			return
+		}
+
+		service := timeentry.NewLeaveRequestService(middleware.TenantDatabase(c, db))
+		leaveRequest, err := decision(service, middleware.CurrentUser(c), id, &req, time.Now())
+		if err != nil {
+			respondTimeEntryError(c, err)
+			return
+		}
+		c.JSON(http.StatusOK, dto.NewLeaveRequestResponse(leaveRequest))
+	}
+}
@@ -1,6 +1,8 @@
 package main
 
 import (
+	"context"
+	"errors"
 	"net/http"
 	"os"
 	"path"
@@ -8,8 +10,20 @@ import (
 
 	"github.com/gin-gonic/gin"
 	"github.com/joho/godotenv"
+	"github.com/r-52/embrace/middleware"
+	"github.com/r-52/embrace/migrations"
 	"github.com/r-52/embrace/models"
+	authdto "github.com/r-52/embrace/models/dto/auth"
+	"github.com/r-52/embrace/models/dto/company"
+	quotadto "github.com/r-52/embrace/models/dto/quota"
 	"github.com/r-52/embrace/models/dto/user"
+	"github.com/r-52/embrace/repositories"
+	"github.com/r-52/embrace/services/auth"
+	companies "github.com/r-52/embrace/services/company"
+	"github.com/r-52/embrace/services/overtime"
+	"github.com/r-52/embrace/services/quota"
+	users "github.com/r-52/embrace/services/user"
+	"gorm.io/gorm"
 )
 
 func main() {
@@ -17,7 +31,27 @@ func main() {
 
 	// Initialize the database
 	// and run the migrations
-	models.OpenDatabase()
+	db := models.ConnectDatabase()
+	if err := migrations.Prepare(db); err != nil {
+		panic("failed to prepare the database")
+	}
+	if err := models.MigrateDatabase(db); err != nil {
+		panic("failed to migrate database")
+	}
+	if err := repositories.RegisterTenantCallbacks(db); err != nil {
+		panic("failed to register tenant callbacks")
+	}
+	if err := repositories.RegisterAuditCallbacks(db); err != nil {
+		panic("failed to register audit callbacks")
+	}
+	if err := migrations.Run(db); err != nil {
+		panic("failed to run data migrations")
+	}
+
+	quota.NewResetScheduler(db, time.Minute).Start(context.Background())
+	overtime.NewSettlementScheduler(db, time.Hour).Start(context.Background())
+
+	authenticator := auth.NewAuthenticator(db, auth.NewTokenService(jwtSecret()))
 
 	router := gin.Default()
 
@@ -29,25 +63,114 @@ func main() {
 	})
 
 	apiV1 := router.Group("/api/v1")
-	setupUserRoutes(apiV1)
-	setupCompanyRoutes(apiV1)
+	setupAuthRoutes(apiV1, authenticator)
+	setupCompanyRoutes(apiV1, db)
+
+	authenticated := apiV1.Group("", middleware.RequireAuthentication(authenticator))
+	setupUserRoutes(authenticated, db)
+	setupRoleRoutes(authenticated, db)
+	setupTimeEntryRoutes(authenticated, db)
+	setupLeaveRequestRoutes(authenticated, db)
+	setupHolidayCalendarRoutes(authenticated, db)
+	setupWorkScheduleRoutes(authenticated, db)
+	setupOvertimeRoutes(authenticated, db)
+	setupComplianceRoutes(authenticated, db)
+	setupDurationPolicyRoutes(authenticated, db)
+	setupTimesheetRoutes(authenticated, db)
+	setupAuditRoutes(authenticated, db)
+	setupReportRoutes(authenticated, db)
 
 	router.Run()
 
 }
 
-func setupCompanyRoutes(apiV1 *gin.RouterGroup) {
-	companies := apiV1.Group("/companies")
-	companies.POST("/create", func(c *gin.Context) {
-		c.JSON(http.StatusOK, gin.H{
-			"message": "Company created",
-		})
+func setupAuthRoutes(apiV1 *gin.RouterGroup, authenticator *auth.Authenticator) {
+	authRoutes := apiV1.Group("/auth")
+	authRoutes.POST("/login", func(c *gin.Context) {
+		var req authdto.LoginRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+			return
+		}
+
+		res, err := authenticator.Login(&req)
+		if errors.Is(err, auth.ErrInvalidCredentials) {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+			return
+		}
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+
+		c.JSON(http.StatusOK, res)
+	})
+	authRoutes.POST("/refresh", func(c *gin.Context) {
+		var req authdto.RefreshRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+			return
+		}
+
+		res, err := authenticator.Refresh(&req)
+		if errors.Is(err, auth.ErrInvalidToken) || errors.Is(err, auth.ErrTokenRevoked) {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+			return
+		}
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+
+		c.JSON(http.StatusOK, res)
+	})
+	authRoutes.POST("/logout", func(c *gin.Context) {
+		var req authdto.RefreshRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+			return
+		}
+
+		err := authenticator.Logout(&req)
+		if errors.Is(err, auth.ErrInvalidToken) {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+			return
+		}
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+
+		c.Status(http.StatusNoContent)
 	})
 }
 
-func setupUserRoutes(apiV1 *gin.RouterGroup) {
-	users := apiV1.Group("/users")
-	users.POST("/create", func(c *gin.Context) {
+func setupCompanyRoutes(apiV1 *gin.RouterGroup, db *gorm.DB) {
+	companyRoutes := apiV1.Group("/companies")
+	companyRoutes.POST("/create", func(c *gin.Context) {
+		var req company.CreateCompanyRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+			return
+		}
+
+		res, err := companies.NewCompanyCreator(db).CreateCompany(&req)
+		if errors.Is(err, companies.ErrCompanyAlreadyExists) || errors.Is(err, users.ErrEmailAlreadyExists) {
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+			return
+		}
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+
+		c.JSON(http.StatusCreated, res)
+	})
+}
+
+func setupUserRoutes(authenticated *gin.RouterGroup, db *gorm.DB) {
+	userRoutes := authenticated.Group("/users", middleware.RequirePermission(models.PERMISSION_USERS_MANAGE))
+	userRoutes.POST("/create", func(c *gin.Context) {
 		var req user.CreateUserRequest
 		if err := c.ShouldBindJSON(&req); err != nil {
 			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
@@ -58,6 +181,109 @@ func setupUserRoutes(apiV1 *gin.RouterGroup) {
 			"message": "User created",
 		})
 	})
+	userRoutes.PUT("/:id/employment", func(c *gin.Context) {
+		id, ok := idParam(c)
+		if !ok {
+			return
+		}
+		var req user.UpdateEmploymentRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+			return
+		}
+
+		res, err := users.NewEmploymentService(middleware.TenantDatabase(c, db)).UpdateEmployment(middleware.CurrentUser(c), id, &req, time.Now())
+		if err != nil {
+			respondUserError(c, err)
+			return
+		}
+		c.JSON(http.StatusOK, res)
+	})
+
+	authenticated.POST("/users/:id/quotas", middleware.RequirePermission(models.PERMISSION_QUOTAS_MANAGE), func(c *gin.Context) {
+		id, ok := idParam(c)
+		if !ok {
+			return
+		}
+		var req quotadto.AssignQuotaRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+			return
+		}
+
+		userQuota, err := quota.NewQuotaAllocator(middleware.TenantDatabase(c, db)).Assign(middleware.CurrentUser(c), id, req.QuotaID, time.Now())
+		if err != nil {
+			respondUserError(c, err)
+			return
+		}
+		c.JSON(http.StatusCreated, quotadto.NewUserQuotaResponse(userQuota))
+	})
+	authenticated.POST("/users/:id/quotas/:quotaId/adjustments", middleware.RequirePermission(models.PERMISSION_QUOTAS_MANAGE), func(c *gin.Context) {
+		id, ok := idParam(c)
+		if !ok {
+			return
+		}
+		quotaID, ok := uintParam(c, "quotaId")
+		if !ok {
+			return
+		}
+		var req quotadto.AdjustQuotaRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+			return
+		}
+
+		transaction, err := quota.NewQuotaLedger(middleware.TenantDatabase(c, db)).Adjust(middleware.CurrentUser(c), id, quotaID, req.Amount, req.Reason, time.Now())
+		if err != nil {
+			respondUserError(c, err)
+			return
+		}
+		c.JSON(http.StatusCreated, quotadto.NewQuotaTransactionResponse(transaction))
+	})
+	authenticated.GET("/users/:id/quotas/:quotaId/statement", func(c *gin.Context) {
+		id, ok := idParam(c)
+		if !ok {
+			return
+		}
+		quotaID, ok := uintParam(c, "quotaId")
+		if !ok {
+			return
+		}
+		var req quotadto.QuotaStatementRequest
+		if err := c.ShouldBindQuery(&req); err != nil {
+			c.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+			return
+		}
+
+		statement, err := quota.NewQuotaLedger(middleware.TenantDatabase(c, db)).Statement(middleware.CurrentUser(c), id, quotaID, req.From, req.To)
+		if err != nil {
+			respondUserError(c, err)
+			return
+		}
+		c.JSON(http.StatusOK, statement)
+	})
+}
+
+func respondUserError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, users.ErrEmploymentEndBeforeStart), errors.Is(err, users.ErrUnknownHolidayCalendar),
+		errors.Is(err, quota.ErrUnknownQuota), errors.Is(err, quota.ErrQuotaNotAssigned):
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+	case errors.Is(err, quota.ErrQuotaAlreadyAssigned):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
+
+func jwtSecret() []byte {
+	secret := os.Getenv("JWT_SECRET")
+	if secret == "" {
+		panic("JWT_SECRET is not set")
+	}
+	return []byte(secret)
 }
 
 func prepareEnv() {
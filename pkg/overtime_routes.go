@@ -0,0 +1,92 @@
+package main
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/r-52/embrace/middleware"
+	"github.com/r-52/embrace/models"
+	dto "github.com/r-52/embrace/models/dto/overtime"
+	quotadto "github.com/r-52/embrace/models/dto/quota"
+	"github.com/r-52/embrace/services/overtime"
+	"github.com/r-52/embrace/services/quota"
+	"gorm.io/gorm"
+)
+
+func setupOvertimeRoutes(authenticated *gin.RouterGroup, db *gorm.DB) {
+	authenticated.GET("/overtime-policy", func(c *gin.Context) {
+		policy, err := overtime.NewOvertimeService(middleware.TenantDatabase(c, db)).GetPolicy(middleware.CurrentUser(c).CompanyID)
+		if err != nil {
+			respondOvertimeError(c, err)
+			return
+		}
+		c.JSON(http.StatusOK, dto.NewOvertimePolicyResponse(policy))
+	})
+	authenticated.PUT("/overtime-policy", middleware.RequirePermission(models.PERMISSION_COMPANY_MANAGE), func(c *gin.Context) {
+		var req dto.OvertimePolicyRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+			return
+		}
+
+		policy, err := overtime.NewOvertimeService(middleware.TenantDatabase(c, db)).UpdatePolicy(middleware.CurrentUser(c).CompanyID, &req)
+		if err != nil {
+			respondOvertimeError(c, err)
+			return
+		}
+		c.JSON(http.StatusOK, dto.NewOvertimePolicyResponse(policy))
+	})
+
+	authenticated.GET("/users/:id/overtime", func(c *gin.Context) {
+		id, ok := idParam(c)
+		if !ok {
+			return
+		}
+		var req dto.OvertimeRequest
+		if err := c.ShouldBindQuery(&req); err != nil {
+			c.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+			return
+		}
+
+		res, err := overtime.NewOvertimeService(middleware.TenantDatabase(c, db)).Overtime(middleware.CurrentUser(c), id, &req, time.Now())
+		if err != nil {
+			respondOvertimeError(c, err)
+			return
+		}
+		c.JSON(http.StatusOK, res)
+	})
+	authenticated.POST("/users/:id/overtime/payouts", middleware.RequirePermission(models.PERMISSION_QUOTAS_MANAGE), func(c *gin.Context) {
+		id, ok := idParam(c)
+		if !ok {
+			return
+		}
+		var req dto.PayoutRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+			return
+		}
+
+		transaction, err := overtime.NewOvertimeService(middleware.TenantDatabase(c, db)).Payout(middleware.CurrentUser(c), id, &req, time.Now())
+		if err != nil {
+			respondOvertimeError(c, err)
+			return
+		}
+		c.JSON(http.StatusCreated, quotadto.NewQuotaTransactionResponse(transaction))
+	})
+}
+
+func respondOvertimeError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, overtime.ErrUnknownWorkTimeType), errors.Is(err, overtime.ErrOvertimeNotConfigured), errors.Is(err, quota.ErrQuotaNotAssigned),
+		errors.Is(err, overtime.ErrInvalidOvertimePeriod):
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+	case errors.Is(err, overtime.ErrPayoutExceedsBalance), errors.Is(err, overtime.ErrOvertimeQuotaConflict):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
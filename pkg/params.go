@@ -0,0 +1,35 @@
+package main
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// idParam parses the `:id` path parameter. On failure it writes a 400 response and returns false.
+func idParam(c *gin.Context) (uint, bool) {
+	return uintParam(c, "id")
+}
+
+// uintParam parses a path parameter holding an ID. On failure it writes a 400 response and returns false.
+func uintParam(c *gin.Context, name string) (uint, bool) {
+	id, err := strconv.ParseUint(c.Param(name), 10, 64)
+	if err != nil || id == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid " + name})
+		return 0, false
+	}
+	return uint(id), true
+}
+
+// monthParam parses the `:month` path parameter formatted as "2006-01" to the first day of the month at midnight UTC.
+// On failure it writes a 400 response and returns false.
+func monthParam(c *gin.Context) (time.Time, bool) {
+	month, err := time.Parse("2006-01", c.Param("month"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid month"})
+		return time.Time{}, false
+	}
+	return month, true
+}
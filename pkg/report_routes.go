@@ -0,0 +1,57 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/r-52/embrace/middleware"
+	dto "github.com/r-52/embrace/models/dto/report"
+	"github.com/r-52/embrace/services/report"
+	"gorm.io/gorm"
+)
+
+func setupReportRoutes(authenticated *gin.RouterGroup, db *gorm.DB) {
+	reportRoutes := authenticated.Group("/reports")
+	reportRoutes.GET("/timesheet", func(c *gin.Context) {
+		var req dto.TimesheetReportRequest
+		if err := c.ShouldBindQuery(&req); err != nil {
+			c.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+			return
+		}
+
+		timesheet, err := report.NewTimesheetReportService(middleware.TenantDatabase(c, db)).Report(middleware.CurrentUser(c), &req, time.Now())
+		if err != nil {
+			respondReportError(c, err)
+			return
+		}
+		filename := fmt.Sprintf("timesheet-%s-%s.%s", timesheet.From.Format(time.DateOnly), timesheet.To.Format(time.DateOnly), timesheet.Format)
+		c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, filename))
+		if timesheet.Format == dto.TIMESHEET_FORMAT_XLSX {
+			c.Header("Content-Type", "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet")
+			c.Status(http.StatusOK)
+			err = report.WriteXLSX(c.Writer, timesheet)
+		} else {
+			c.Header("Content-Type", "text/csv; charset=utf-8")
+			c.Status(http.StatusOK)
+			err = report.WriteCSV(c.Writer, timesheet)
+		}
+		if err != nil {
+			c.Error(err)
+		}
+	})
+}
+
+func respondReportError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, report.ErrUnknownColumn), errors.Is(err, report.ErrUnknownLocale),
+		errors.Is(err, report.ErrInvalidReportPeriod), errors.Is(err, report.ErrReportTooLarge):
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
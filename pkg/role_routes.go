@@ -0,0 +1,88 @@
+package main
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/r-52/embrace/middleware"
+	"github.com/r-52/embrace/models"
+	"github.com/r-52/embrace/models/dto/role"
+	roles "github.com/r-52/embrace/services/role"
+	"gorm.io/gorm"
+)
+
+func setupRoleRoutes(authenticated *gin.RouterGroup, db *gorm.DB) {
+	authenticated.GET("/permissions", func(c *gin.Context) {
+		c.JSON(http.StatusOK, models.ALL_PERMISSIONS)
+	})
+
+	roleRoutes := authenticated.Group("/roles", middleware.RequirePermission(models.PERMISSION_ROLES_MANAGE))
+	roleRoutes.GET("", func(c *gin.Context) {
+		res, err := roles.NewRoleService(middleware.TenantDatabase(c, db)).ListRoles(middleware.CurrentUser(c).CompanyID)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusOK, res)
+	})
+	roleRoutes.POST("", func(c *gin.Context) {
+		var req role.RoleRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+			return
+		}
+
+		res, err := roles.NewRoleService(middleware.TenantDatabase(c, db)).CreateRole(middleware.CurrentUser(c).CompanyID, &req)
+		if err != nil {
+			respondRoleError(c, err)
+			return
+		}
+		c.JSON(http.StatusCreated, res)
+	})
+	roleRoutes.PUT("/:id", func(c *gin.Context) {
+		id, ok := idParam(c)
+		if !ok {
+			return
+		}
+		var req role.RoleRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+			return
+		}
+
+		res, err := roles.NewRoleService(middleware.TenantDatabase(c, db)).UpdateRole(middleware.CurrentUser(c).CompanyID, id, &req)
+		if err != nil {
+			respondRoleError(c, err)
+			return
+		}
+		c.JSON(http.StatusOK, res)
+	})
+	roleRoutes.DELETE("/:id", func(c *gin.Context) {
+		id, ok := idParam(c)
+		if !ok {
+			return
+		}
+
+		err := roles.NewRoleService(middleware.TenantDatabase(c, db)).DeleteRole(middleware.CurrentUser(c).CompanyID, id)
+		if err != nil {
+			respondRoleError(c, err)
+			return
+		}
+		c.Status(http.StatusNoContent)
+	})
+}
+
+func respondRoleError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, roles.ErrUnknownPermission):
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+	case errors.Is(err, roles.ErrRoleAlreadyExists), errors.Is(err, roles.ErrSystemRole), errors.Is(err, roles.ErrRoleInUse),
+		errors.Is(err, roles.ErrAdminRoleLocked):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
@@ -0,0 +1,228 @@
+package main
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/r-52/embrace/middleware"
+	"github.com/r-52/embrace/models"
+	compliancedto "github.com/r-52/embrace/models/dto/compliance"
+	dto "github.com/r-52/embrace/models/dto/timeentry"
+	"github.com/r-52/embrace/services/auth"
+	"github.com/r-52/embrace/services/compliance"
+	"github.com/r-52/embrace/services/quota"
+	"github.com/r-52/embrace/services/timeentry"
+	"gorm.io/gorm"
+)
+
+func setupTimeEntryRoutes(authenticated *gin.RouterGroup, db *gorm.DB) {
+	timeEntryRoutes := authenticated.Group("/time-entries")
+	timeEntryRoutes.GET("", func(c *gin.Context) {
+		var req dto.ListTimeEntriesRequest
+		if err := c.ShouldBindQuery(&req); err != nil {
+			c.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+			return
+		}
+
+		entries, err := timeentry.NewTimeEntryService(middleware.TenantDatabase(c, db)).List(middleware.CurrentUser(c), &req)
+		if err != nil {
+			respondTimeEntryError(c, err)
+			return
+		}
+		c.JSON(http.StatusOK, dto.NewTimeEntryResponses(entries))
+	})
+	timeEntryRoutes.GET("/:id", func(c *gin.Context) {
+		id, ok := idParam(c)
+		if !ok {
+			return
+		}
+
+		entry, err := timeentry.NewTimeEntryService(middleware.TenantDatabase(c, db)).Get(middleware.CurrentUser(c), id)
+		if err != nil {
+			respondTimeEntryError(c, err)
+			return
+		}
+		c.JSON(http.StatusOK, dto.NewTimeEntryResponse(entry))
+	})
+	timeEntryRoutes.GET("/:id/history", func(c *gin.Context) {
+		id, ok := idParam(c)
+		if !ok {
+			return
+		}
+
+		audits, err := timeentry.NewTimeEntryService(middleware.TenantDatabase(c, db)).History(middleware.CurrentUser(c), id)
+		if err != nil {
+			respondTimeEntryError(c, err)
+			return
+		}
+		c.JSON(http.StatusOK, dto.NewTimeEntryAuditResponses(audits))
+	})
+	timeEntryRoutes.POST("", func(c *gin.Context) {
+		var req dto.CreateTimeEntryRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+			return
+		}
+
+		entry, err := timeentry.NewTimeEntryService(middleware.TenantDatabase(c, db)).Create(middleware.CurrentUser(c), &req)
+		if err != nil {
+			respondTimeEntryError(c, err)
+			return
+		}
+		c.JSON(http.StatusCreated, dto.NewTimeEntryResponse(entry))
+	})
+	timeEntryRoutes.POST("/import", func(c *gin.Context) {
+		var req dto.ImportTimeEntriesRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+			return
+		}
+
+		entries, err := timeentry.NewTimeEntryService(middleware.TenantDatabase(c, db)).Import(middleware.CurrentUser(c), &req)
+		if err != nil {
+			respondTimeEntryError(c, err)
+			return
+		}
+		c.JSON(http.StatusCreated, dto.NewTimeEntryResponses(entries))
+	})
+	timeEntryRoutes.POST("/resolve-overlaps", middleware.RequirePermission(models.PERMISSION_TIME_ENTRIES_WRITE_ALL), func(c *gin.Context) {
+		var req dto.ResolveOverlapsRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+			return
+		}
+
+		entries, err := timeentry.NewTimeEntryService(middleware.TenantDatabase(c, db)).ResolveOverlaps(middleware.CurrentUser(c), &req)
+		if err != nil {
+			respondTimeEntryError(c, err)
+			return
+		}
+		c.JSON(http.StatusOK, dto.NewTimeEntryResponses(entries))
+	})
+	timeEntryRoutes.PUT("/:id", func(c *gin.Context) {
+		id, ok := idParam(c)
+		if !ok {
+			return
+		}
+		var req dto.UpdateTimeEntryRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+			return
+		}
+
+		entry, err := timeentry.NewTimeEntryService(middleware.TenantDatabase(c, db)).Update(middleware.CurrentUser(c), id, &req)
+		if err != nil {
+			respondTimeEntryError(c, err)
+			return
+		}
+		c.JSON(http.StatusOK, dto.NewTimeEntryResponse(entry))
+	})
+	timeEntryRoutes.DELETE("/:id", func(c *gin.Context) {
+		id, ok := idParam(c)
+		if !ok {
+			return
+		}
+		var req dto.DeleteTimeEntryRequest
+		if err := c.ShouldBindQuery(&req); err != nil {
+			c.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+			return
+		}
+
+		err := timeentry.NewTimeEntryService(middleware.TenantDatabase(c, db)).Delete(middleware.CurrentUser(c), id, &req)
+		if err != nil {
+			respondTimeEntryError(c, err)
+			return
+		}
+		c.Status(http.StatusNoContent)
+	})
+
+	timeEntryRoutes.GET("/timer", func(c *gin.Context) {
+		res, err := timeentry.NewTimerService(middleware.TenantDatabase(c, db)).Current(middleware.CurrentUser(c), time.Now())
+		if err != nil {
+			respondTimeEntryError(c, err)
+			return
+		}
+		c.JSON(http.StatusOK, res)
+	})
+	timeEntryRoutes.POST("/clock-in", func(c *gin.Context) {
+		var req dto.ClockInRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+			return
+		}
+
+		res, err := timeentry.NewTimerService(middleware.TenantDatabase(c, db)).ClockIn(middleware.CurrentUser(c), &req, time.Now())
+		if err != nil {
+			respondTimeEntryError(c, err)
+			return
+		}
+		c.JSON(http.StatusCreated, res)
+	})
+	timeEntryRoutes.POST("/clock-out", func(c *gin.Context) {
+		res, err := timeentry.NewTimerService(middleware.TenantDatabase(c, db)).ClockOut(middleware.CurrentUser(c), time.Now())
+		if err != nil {
+			respondTimeEntryError(c, err)
+			return
+		}
+		c.JSON(http.StatusOK, res)
+	})
+	timeEntryRoutes.POST("/pause", func(c *gin.Context) {
+		res, err := timeentry.NewTimerService(middleware.TenantDatabase(c, db)).Pause(middleware.CurrentUser(c), time.Now())
+		if err != nil {
+			respondTimeEntryError(c, err)
+			return
+		}
+		c.JSON(http.StatusOK, res)
+	})
+	timeEntryRoutes.POST("/resume", func(c *gin.Context) {
+		res, err := timeentry.NewTimerService(middleware.TenantDatabase(c, db)).Resume(middleware.CurrentUser(c), time.Now())
+		if err != nil {
+			respondTimeEntryError(c, err)
+			return
+		}
+		c.JSON(http.StatusCreated, res)
+	})
+}
+
+func respondTimeEntryError(c *gin.Context, err error) {
+	body := gin.H{"error": err.Error()}
+	var importErr *timeentry.ImportError
+	if errors.As(err, &importErr) {
+		err = importErr.Err
+		body = gin.H{"error": err.Error(), "index": importErr.Index}
+	}
+	var overlapErr *timeentry.OverlapError
+	if errors.As(err, &overlapErr) {
+		body["conflicts"] = overlapErr.EntryIDs
+	}
+	var violationErr *compliance.ViolationError
+	if errors.As(err, &violationErr) {
+		body["violations"] = compliancedto.NewViolationResponses(violationErr.Violations)
+	}
+
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		c.JSON(http.StatusNotFound, body)
+	case errors.Is(err, auth.ErrPermissionDenied):
+		c.JSON(http.StatusForbidden, body)
+	case errors.Is(err, timeentry.ErrUnknownTimeEntryType), errors.Is(err, timeentry.ErrUnknownUser),
+		errors.Is(err, timeentry.ErrEndBeforeStart), errors.Is(err, timeentry.ErrTimeEntryTooLong),
+		errors.Is(err, timeentry.ErrQuotaRelevantTimer), errors.Is(err, timeentry.ErrQuotaRelevantEntry),
+		errors.Is(err, quota.ErrUnknownQuota), errors.Is(err, quota.ErrQuotaNotAssigned),
+		errors.Is(err, timeentry.ErrInvalidLeavePeriod), errors.Is(err, timeentry.ErrLeaveTypeNotQuotaRelevant),
+		errors.Is(err, compliance.ErrComplianceViolation):
+		c.JSON(http.StatusUnprocessableEntity, body)
+	case errors.Is(err, timeentry.ErrAlreadyClockedIn), errors.Is(err, timeentry.ErrNotClockedIn),
+		errors.Is(err, timeentry.ErrTimerPaused), errors.Is(err, timeentry.ErrTimerNotPaused),
+		errors.Is(err, timeentry.ErrTimeEntryOverlaps), errors.Is(err, quota.ErrQuotaExceeded),
+		errors.Is(err, timeentry.ErrLeaveRequestNotPending), errors.Is(err, timeentry.ErrLeaveRequestClosed),
+		errors.Is(err, timeentry.ErrTimesheetLocked), errors.Is(err, timeentry.ErrTimesheetNotOpen),
+		errors.Is(err, timeentry.ErrTimesheetNotSubmitted), errors.Is(err, timeentry.ErrTimesheetOpen),
+		errors.Is(err, timeentry.ErrLeaveEntry):
+		c.JSON(http.StatusConflict, body)
+	default:
+		c.JSON(http.StatusInternalServerError, body)
+	}
+}
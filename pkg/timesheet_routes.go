@@ -0,0 +1,126 @@
+package main
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/r-52/embrace/middleware"
+	"github.com/r-52/embrace/models"
+	dto "github.com/r-52/embrace/models/dto/timeentry"
+	"github.com/r-52/embrace/services/timeentry"
+	"gorm.io/gorm"
+)
+
+func setupTimesheetRoutes(authenticated *gin.RouterGroup, db *gorm.DB) {
+	timesheetRoutes := authenticated.Group("/timesheets/:month")
+	timesheetRoutes.GET("", func(c *gin.Context) {
+		month, ok := monthParam(c)
+		if !ok {
+			return
+		}
+
+		actor := middleware.CurrentUser(c)
+		period, err := timeentry.NewTimesheetService(middleware.TenantDatabase(c, db)).Get(actor, actor.ID, month)
+		if err != nil {
+			respondTimeEntryError(c, err)
+			return
+		}
+		c.JSON(http.StatusOK, dto.NewTimesheetPeriodResponse(period))
+	})
+	timesheetRoutes.GET("/missing", func(c *gin.Context) {
+		month, ok := monthParam(c)
+		if !ok {
+			return
+		}
+
+		periods, err := timeentry.NewTimesheetService(middleware.TenantDatabase(c, db)).Missing(middleware.CurrentUser(c), month)
+		if err != nil {
+			respondTimeEntryError(c, err)
+			return
+		}
+		c.JSON(http.StatusOK, dto.NewTimesheetPeriodResponses(periods))
+	})
+	timesheetRoutes.GET("/users/:userId", func(c *gin.Context) {
+		month, ok := monthParam(c)
+		if !ok {
+			return
+		}
+		userID, ok := uintParam(c, "userId")
+		if !ok {
+			return
+		}
+
+		period, err := timeentry.NewTimesheetService(middleware.TenantDatabase(c, db)).Get(middleware.CurrentUser(c), userID, month)
+		if err != nil {
+			respondTimeEntryError(c, err)
+			return
+		}
+		c.JSON(http.StatusOK, dto.NewTimesheetPeriodResponse(period))
+	})
+	timesheetRoutes.POST("/submit", func(c *gin.Context) {
+		month, ok := monthParam(c)
+		if !ok {
+			return
+		}
+
+		period, err := timeentry.NewTimesheetService(middleware.TenantDatabase(c, db)).Submit(middleware.CurrentUser(c), month, time.Now())
+		if err != nil {
+			respondTimeEntryError(c, err)
+			return
+		}
+		c.JSON(http.StatusOK, dto.NewTimesheetPeriodResponse(period))
+	})
+	timesheetRoutes.POST("/users/:userId/approve", func(c *gin.Context) {
+		month, ok := monthParam(c)
+		if !ok {
+			return
+		}
+		userID, ok := uintParam(c, "userId")
+		if !ok {
+			return
+		}
+
+		period, err := timeentry.NewTimesheetService(middleware.TenantDatabase(c, db)).Approve(middleware.CurrentUser(c), userID, month, time.Now())
+		if err != nil {
+			respondTimeEntryError(c, err)
+			return
+		}
+		c.JSON(http.StatusOK, dto.NewTimesheetPeriodResponse(period))
+	})
+	timesheetRoutes.POST("/lock", middleware.RequirePermission(models.PERMISSION_COMPANY_MANAGE), func(c *gin.Context) {
+		month, ok := monthParam(c)
+		if !ok {
+			return
+		}
+
+		periods, err := timeentry.NewTimesheetService(middleware.TenantDatabase(c, db)).Lock(middleware.CurrentUser(c), month, time.Now())
+		if err != nil {
+			respondTimeEntryError(c, err)
+			return
+		}
+		c.JSON(http.StatusOK, dto.NewTimesheetPeriodResponses(periods))
+	})
+	timesheetRoutes.POST("/users/:userId/reopen", middleware.RequirePermission(models.PERMISSION_COMPANY_MANAGE), func(c *gin.Context) {
+		month, ok := monthParam(c)
+		if !ok {
+			return
+		}
+		userID, ok := uintParam(c, "userId")
+		if !ok {
+			return
+		}
+		var req dto.ReopenTimesheetRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+			return
+		}
+
+		period, err := timeentry.NewTimesheetService(middleware.TenantDatabase(c, db)).Reopen(middleware.CurrentUser(c), userID, month, &req, time.Now())
+		if err != nil {
+			respondTimeEntryError(c, err)
+			return
+		}
+		c.JSON(http.StatusOK, dto.NewTimesheetPeriodResponse(period))
+	})
+}
@@ -0,0 +1,168 @@
+package main
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/r-52/embrace/middleware"
+	"github.com/r-52/embrace/models"
+	dto "github.com/r-52/embrace/models/dto/schedule"
+	"github.com/r-52/embrace/services/schedule"
+	"gorm.io/gorm"
+)
+
+func setupWorkScheduleRoutes(authenticated *gin.RouterGroup, db *gorm.DB) {
+	scheduleRoutes := authenticated.Group("/work-schedules")
+	scheduleRoutes.GET("", func(c *gin.Context) {
+		schedules, err := schedule.NewWorkScheduleService(middleware.TenantDatabase(c, db)).ListSchedules(middleware.CurrentUser(c).CompanyID)
+		if err != nil {
+			respondScheduleError(c, err)
+			return
+		}
+		c.JSON(http.StatusOK, dto.NewWorkScheduleResponses(schedules))
+	})
+	scheduleRoutes.GET("/:id", func(c *gin.Context) {
+		id, ok := idParam(c)
+		if !ok {
+			return
+		}
+
+		res, err := schedule.NewWorkScheduleService(middleware.TenantDatabase(c, db)).GetSchedule(middleware.CurrentUser(c).CompanyID, id)
+		if err != nil {
+			respondScheduleError(c, err)
+			return
+		}
+		c.JSON(http.StatusOK, dto.NewWorkScheduleResponse(res))
+	})
+
+	manageRoutes := scheduleRoutes.Group("", middleware.RequirePermission(models.PERMISSION_COMPANY_MANAGE))
+	manageRoutes.POST("", func(c *gin.Context) {
+		var req dto.WorkScheduleRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+			return
+		}
+
+		res, err := schedule.NewWorkScheduleService(middleware.TenantDatabase(c, db)).CreateSchedule(middleware.CurrentUser(c).CompanyID, &req)
+		if err != nil {
+			respondScheduleError(c, err)
+			return
+		}
+		c.JSON(http.StatusCreated, dto.NewWorkScheduleResponse(res))
+	})
+	manageRoutes.PUT("/:id", func(c *gin.Context) {
+		id, ok := idParam(c)
+		if !ok {
+			return
+		}
+		var req dto.WorkScheduleRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+			return
+		}
+
+		res, err := schedule.NewWorkScheduleService(middleware.TenantDatabase(c, db)).UpdateSchedule(middleware.CurrentUser(c).CompanyID, id, &req)
+		if err != nil {
+			respondScheduleError(c, err)
+			return
+		}
+		c.JSON(http.StatusOK, dto.NewWorkScheduleResponse(res))
+	})
+	manageRoutes.DELETE("/:id", func(c *gin.Context) {
+		id, ok := idParam(c)
+		if !ok {
+			return
+		}
+
+		err := schedule.NewWorkScheduleService(middleware.TenantDatabase(c, db)).DeleteSchedule(middleware.CurrentUser(c).CompanyID, id)
+		if err != nil {
+			respondScheduleError(c, err)
+			return
+		}
+		c.Status(http.StatusNoContent)
+	})
+
+	assignmentRoutes := authenticated.Group("/users/:id/work-schedules", middleware.RequirePermission(models.PERMISSION_USERS_MANAGE))
+	assignmentRoutes.GET("", func(c *gin.Context) {
+		id, ok := idParam(c)
+		if !ok {
+			return
+		}
+
+		assignments, err := schedule.NewWorkScheduleService(middleware.TenantDatabase(c, db)).ListAssignments(id)
+		if err != nil {
+			respondScheduleError(c, err)
+			return
+		}
+		c.JSON(http.StatusOK, dto.NewUserWorkScheduleResponses(assignments))
+	})
+	assignmentRoutes.POST("", func(c *gin.Context) {
+		id, ok := idParam(c)
+		if !ok {
+			return
+		}
+		var req dto.AssignWorkScheduleRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+			return
+		}
+
+		assignment, err := schedule.NewWorkScheduleService(middleware.TenantDatabase(c, db)).AssignSchedule(id, &req)
+		if err != nil {
+			respondScheduleError(c, err)
+			return
+		}
+		c.JSON(http.StatusCreated, dto.NewUserWorkScheduleResponse(assignment))
+	})
+	assignmentRoutes.DELETE("/:assignmentId", func(c *gin.Context) {
+		id, ok := idParam(c)
+		if !ok {
+			return
+		}
+		assignmentID, ok := uintParam(c, "assignmentId")
+		if !ok {
+			return
+		}
+
+		err := schedule.NewWorkScheduleService(middleware.TenantDatabase(c, db)).DeleteAssignment(id, assignmentID)
+		if err != nil {
+			respondScheduleError(c, err)
+			return
+		}
+		c.Status(http.StatusNoContent)
+	})
+
+	authenticated.GET("/users/:id/target-hours", func(c *gin.Context) {
+		id, ok := idParam(c)
+		if !ok {
+			return
+		}
+		var req dto.TargetHoursRequest
+		if err := c.ShouldBindQuery(&req); err != nil {
+			c.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+			return
+		}
+
+		res, err := schedule.NewTargetHoursService(middleware.TenantDatabase(c, db)).TargetHours(middleware.CurrentUser(c), id, req.From, req.To)
+		if err != nil {
+			respondScheduleError(c, err)
+			return
+		}
+		c.JSON(http.StatusOK, res)
+	})
+}
+
+func respondScheduleError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, schedule.ErrInvalidWorkSchedule), errors.Is(err, schedule.ErrUnknownWorkSchedule), errors.Is(err, schedule.ErrInvalidValidityPeriod),
+		errors.Is(err, schedule.ErrInvalidTargetHoursPeriod):
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+	case errors.Is(err, schedule.ErrWorkScheduleOverlaps), errors.Is(err, schedule.ErrWorkScheduleInUse):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
@@ -0,0 +1,302 @@
+package repositories
+
+import (
+	"bytes"
+	"context"
+	"database/sql/driver"
+	"encoding/json"
+	"reflect"
+	"time"
+
+	"github.com/r-52/embrace/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/schema"
+)
+
+type actorContextKey struct{}
+
+// auditExcluded lists the tables whose writes are not recorded in the audit log.
+var auditExcluded = map[string]bool{
+	"audit_logs": true,
+	// Time entries keep their own trail, see models.TimeEntryAudit.
+	"time_entries":      true,
+	"time_entry_audits": true,
+	// Refresh tokens change with every login and refresh.
+	"refresh_tokens": true,
+}
+
+// auditRedacted lists the columns whose values are replaced in the audit log, only that they changed is recorded.
+var auditRedacted = map[string]bool{
+	"password": true,
+}
+
+// auditIgnored lists the columns that are left out of the audit log because they change with every write.
+var auditIgnored = map[string]bool{
+	"created_at": true,
+	"updated_at": true,
+	"deleted_at": true,
+}
+
+const auditBeforeKey = "audit:before"
+
+var auditRedactedValue = json.RawMessage(`"[redacted]"`)
+
+// WithActor returns a session of db whose writes are recorded in the audit log as changes of the user.
+// Writes of sessions without an actor are recorded as changes of the system.
+func WithActor(db *gorm.DB, actorID uint) *gorm.DB {
+	return db.WithContext(context.WithValue(db.Statement.Context, actorContextKey{}, actorID))
+}
+
+// ActorFromContext returns the user ID a context was bound to by WithActor.
+func ActorFromContext(ctx context.Context) (uint, bool) {
+	if ctx == nil {
+		return 0, false
+	}
+	actorID, ok := ctx.Value(actorContextKey{}).(uint)
+	return actorID, ok
+}
+
+// RegisterAuditCallbacks installs the callbacks that record every create, update and delete of a model in the
+// audit log, see models.AuditLog. Only the changed columns of updates are recorded and writes that change
+// nothing are not recorded at all. Raw SQL is not recorded.
+// It has to be called once for every database connection after RegisterTenantCallbacks.
+func RegisterAuditCallbacks(db *gorm.DB) error {
+	callbacks := db.Callback()
+	if err := callbacks.Create().After("gorm:create").Register("audit:create", auditCreate); err != nil {
+		return err
+	}
+	if err := callbacks.Update().After("tenant:update").Before("gorm:update").Register("audit:before_update", auditBefore); err != nil {
+		return err
+	}
+	if err := callbacks.Update().After("gorm:update").Register("audit:update", auditUpdate); err != nil {
+		return err
+	}
+	if err := callbacks.Delete().After("tenant:delete").Before("gorm:delete").Register("audit:before_delete", auditBefore); err != nil {
+		return err
+	}
+	return callbacks.Delete().After("gorm:delete").Register("audit:delete", auditDelete)
+}
+
+func auditable(db *gorm.DB) bool {
+	stmt := db.Statement
+	return !db.DryRun && stmt.Schema != nil && stmt.Schema.PrioritizedPrimaryField != nil && !auditExcluded[stmt.Table]
+}
+
+// auditSession returns a new session on the connection of db, so audit queries join its transaction.
+func auditSession(db *gorm.DB) *gorm.DB {
+	return db.Session(&gorm.Session{NewDB: true})
+}
+
+func auditCreate(db *gorm.DB) {
+	if db.Error != nil || !auditable(db) {
+		return
+	}
+	// Associations are saved with ON CONFLICT DO NOTHING, rows that existed before were not created.
+	if _, ok := db.Statement.Clauses["ON CONFLICT"]; ok && db.RowsAffected == 0 {
+		return
+	}
+	eachRow(db.Statement.ReflectValue, func(row reflect.Value) {
+		if db.Error == nil {
+			recordAudit(db, models.AUDIT_OPERATION_CREATE, row, auditDiff(db, reflect.Value{}, row))
+		}
+	})
+}
+
+// auditBefore keeps the rows an update or delete is about to change, so their changes can be recorded afterwards.
+func auditBefore(db *gorm.DB) {
+	if db.Error != nil || !auditable(db) || db.Statement.SQL.Len() > 0 {
+		return
+	}
+	stmt := db.Statement
+	conditions := []clause.Expression{}
+	if where, ok := stmt.Clauses["WHERE"].Expression.(clause.Where); ok {
+		conditions = append(conditions, where.Exprs...)
+	}
+	// The primary key of the model is only added to the conditions by the write itself.
+	_, values := schema.GetIdentityFieldValuesMap(stmt.Context, stmt.ReflectValue, stmt.Schema.PrimaryFields)
+	if column, queryValues := schema.ToQueryValues(stmt.Table, stmt.Schema.PrimaryFieldDBNames, values); len(queryValues) > 0 {
+		conditions = append(conditions, clause.IN{Column: column, Values: queryValues})
+	}
+	if len(conditions) == 0 {
+		// Writes without conditions are rejected by gorm.
+		return
+	}
+
+	query := auditSession(db).Model(reflect.New(stmt.Schema.ModelType).Interface())
+	if stmt.Unscoped {
+		query = query.Unscoped()
+	}
+	rows := reflect.New(reflect.SliceOf(stmt.Schema.ModelType))
+	if err := query.Where(clause.And(conditions...)).Find(rows.Interface()).Error; err != nil {
+		db.AddError(err)
+		return
+	}
+	db.InstanceSet(auditBeforeKey, rows.Elem())
+}
+
+func auditUpdate(db *gorm.DB) {
+	before, ok := auditRowsBefore(db)
+	if !ok {
+		return
+	}
+	stmt := db.Statement
+	ids := make([]interface{}, before.Len())
+	for i := range ids {
+		ids[i], _ = stmt.Schema.PrioritizedPrimaryField.ValueOf(stmt.Context, before.Index(i))
+	}
+	after := reflect.New(reflect.SliceOf(stmt.Schema.ModelType))
+	err := auditSession(db).Unscoped().
+		Where(clause.IN{Column: clause.Column{Name: stmt.Schema.PrioritizedPrimaryField.DBName}, Values: ids}).
+		Find(after.Interface()).Error
+	if err != nil {
+		db.AddError(err)
+		return
+	}
+	afterByID := map[interface{}]reflect.Value{}
+	for i := 0; i < after.Elem().Len(); i++ {
+		row := after.Elem().Index(i)
+		id, _ := stmt.Schema.PrioritizedPrimaryField.ValueOf(stmt.Context, row)
+		afterByID[id] = row
+	}
+
+	for i, id := range ids {
+		row, ok := afterByID[id]
+		if !ok {
+			continue
+		}
+		if diff := auditDiff(db, before.Index(i), row); len(diff) > 0 {
+			recordAudit(db, models.AUDIT_OPERATION_UPDATE, row, diff)
+		}
+	}
+}
+
+func auditDelete(db *gorm.DB) {
+	before, ok := auditRowsBefore(db)
+	if !ok {
+		return
+	}
+	for i := 0; i < before.Len() && db.Error == nil; i++ {
+		recordAudit(db, models.AUDIT_OPERATION_DELETE, before.Index(i), auditDiff(db, before.Index(i), reflect.Value{}))
+	}
+}
+
+// auditRowsBefore returns the rows kept by auditBefore if the write succeeded and changed any rows.
+func auditRowsBefore(db *gorm.DB) (reflect.Value, bool) {
+	if db.Error != nil || db.RowsAffected == 0 {
+		return reflect.Value{}, false
+	}
+	before, ok := db.InstanceGet(auditBeforeKey)
+	if !ok {
+		return reflect.Value{}, false
+	}
+	rows := before.(reflect.Value)
+	return rows, rows.Len() > 0
+}
+
+// auditDiff returns the columns that differ between the `before` and `after` state of a row. `before` is invalid
+// for created rows and `after` for deleted ones.
+func auditDiff(db *gorm.DB, before, after reflect.Value) map[string]models.AuditChange {
+	diff := map[string]models.AuditChange{}
+	for _, field := range db.Statement.Schema.Fields {
+		if field.DBName == "" || auditIgnored[field.DBName] {
+			continue
+		}
+		var change models.AuditChange
+		if before.IsValid() {
+			change.From = auditValue(db.Statement.Context, field, before)
+		}
+		if after.IsValid() {
+			change.To = auditValue(db.Statement.Context, field, after)
+		}
+		if before.IsValid() && after.IsValid() && bytes.Equal(change.From, change.To) {
+			continue
+		}
+		if auditRedacted[field.DBName] {
+			if change.From != nil {
+				change.From = auditRedactedValue
+			}
+			if change.To != nil {
+				change.To = auditRedactedValue
+			}
+		}
+		diff[field.DBName] = change
+	}
+	return diff
+}
+
+// auditValue returns the JSON of the value of a column, as stored in the database.
+func auditValue(ctx context.Context, field *schema.Field, row reflect.Value) json.RawMessage {
+	value, _ := field.ValueOf(ctx, row)
+	if valuer, ok := value.(driver.Valuer); ok {
+		value, _ = valuer.Value()
+	}
+	data, err := json.Marshal(value)
+	if err != nil {
+		return json.RawMessage("null")
+	}
+	return data
+}
+
+func recordAudit(db *gorm.DB, operation string, row reflect.Value, diff map[string]models.AuditChange) {
+	stmt := db.Statement
+	data, err := json.Marshal(diff)
+	if err != nil {
+		db.AddError(err)
+		return
+	}
+	id, _ := stmt.Schema.PrioritizedPrimaryField.ValueOf(stmt.Context, row)
+	entityID, ok := id.(uint)
+	if !ok || entityID == 0 {
+		return
+	}
+	auditLog := &models.AuditLog{
+		CompanyID:  auditCompanyOf(db, row),
+		Entity:     stmt.Table,
+		EntityID:   entityID,
+		Operation:  operation,
+		RecordedAt: time.Now().UTC(),
+		Diff:       string(data),
+	}
+	if actorID, ok := ActorFromContext(stmt.Context); ok {
+		auditLog.ActorID = &actorID
+	}
+	db.AddError(NewAuditLogRepository(auditSession(db)).Create(auditLog))
+}
+
+// auditCompanyOf returns the company a changed row belongs to: the tenant of the session, or for writes outside
+// of a tenant the company referenced by the row or its parent, see tenantParents.
+func auditCompanyOf(db *gorm.DB, row reflect.Value) uint {
+	stmt := db.Statement
+	if companyID, ok := TenantFromContext(stmt.Context); ok {
+		return companyID
+	}
+	if stmt.Table == "companies" {
+		id, _ := stmt.Schema.PrioritizedPrimaryField.ValueOf(stmt.Context, row)
+		companyID, _ := id.(uint)
+		return companyID
+	}
+	if field := stmt.Schema.LookUpField("CompanyID"); field != nil {
+		value, _ := field.ValueOf(stmt.Context, row)
+		companyID, _ := value.(uint)
+		return companyID
+	}
+	parent, ok := tenantParents[stmt.Table]
+	if !ok {
+		return 0
+	}
+	field := stmt.Schema.LookUpField(parent.column)
+	if field == nil {
+		return 0
+	}
+	value, _ := field.ValueOf(stmt.Context, row)
+	var companyIDs []uint
+	err := auditSession(db).
+		Table(parent.parentTable).
+		Where(clause.Eq{Column: clause.Column{Name: parent.parentColumn}, Value: value}).
+		Pluck("company_id", &companyIDs).Error
+	if err != nil || len(companyIDs) == 0 {
+		return 0
+	}
+	return companyIDs[0]
+}
@@ -0,0 +1,96 @@
+package repositories
+
+import (
+	"time"
+
+	"github.com/r-52/embrace/models"
+	"gorm.io/gorm"
+)
+
+type AuditLogRepository struct {
+	Database *gorm.DB
+}
+
+// AuditLogFilter narrows down the audit logs returned by AuditLogRepository.Find.
+// Zero values are ignored. From is inclusive and To is exclusive, both compare against the recorded time.
+// NewestFirst reverses the order, Limit and Offset page through the result.
+type AuditLogFilter struct {
+	Entity      string
+	EntityID    uint
+	ActorID     uint
+	Operation   string
+	From        time.Time
+	To          time.Time
+	NewestFirst bool
+	Limit       int
+	Offset      int
+}
+
+type AuditLogRepositoryInterface interface {
+	// Create appends a new audit log to the database.
+	// It takes a pointer to a `models.AuditLog` instance as input and returns an error.
+	Create(auditLog *models.AuditLog) error
+
+	// Find retrieves the audit logs matching the filter in the order they were recorded.
+	// It takes an `AuditLogFilter` as input and returns a slice of `models.AuditLog` instances and an error.
+	Find(filter AuditLogFilter) ([]models.AuditLog, error)
+}
+
+// NewAuditLogRepository creates a new instance of AuditLogRepository with the provided database connection.
+// It takes a *gorm.DB as an argument, which represents the database connection, and returns a pointer to a AuditLogRepository.
+func NewAuditLogRepository(db *gorm.DB) *AuditLogRepository {
+	return &AuditLogRepository{
+		Database: db,
+	}
+}
+
+// Create appends a new audit log to the database. Audit logs are never updated or deleted.
+// If there is a database error, it returns a non-nil error.
+func (r *AuditLogRepository) Create(auditLog *models.AuditLog) error {
+	err := r.Database.Create(auditLog).Error
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// Find retrieves the audit logs matching the filter in the order they were recorded.
+// If there is a database error, it returns a non-nil error.
+func (r *AuditLogRepository) Find(filter AuditLogFilter) ([]models.AuditLog, error) {
+	var auditLogs []models.AuditLog
+	query := r.Database.Model(&models.AuditLog{})
+	if filter.Entity != "" {
+		query = query.Where("entity = ?", filter.Entity)
+	}
+	if filter.EntityID != 0 {
+		query = query.Where("entity_id = ?", filter.EntityID)
+	}
+	if filter.ActorID != 0 {
+		query = query.Where("actor_id = ?", filter.ActorID)
+	}
+	if filter.Operation != "" {
+		query = query.Where("operation = ?", filter.Operation)
+	}
+	if !filter.From.IsZero() {
+		query = query.Where("recorded_at >= ?", filter.From)
+	}
+	if !filter.To.IsZero() {
+		query = query.Where("recorded_at < ?", filter.To)
+	}
+	if filter.NewestFirst {
+		query = query.Order("id DESC")
+	} else {
+		query = query.Order("id")
+	}
+	if filter.Limit > 0 {
+		query = query.Limit(filter.Limit)
+	}
+	if filter.Offset > 0 {
+		query = query.Offset(filter.Offset)
+	}
+	err := query.Find(&auditLogs).Error
+	if err != nil {
+		return nil, err
+	}
+	return auditLogs, nil
+}
@@ -0,0 +1,178 @@
+package repositories_test
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/r-52/embrace/models"
+	"github.com/r-52/embrace/repositories"
+	"gorm.io/gorm"
+)
+
+// setupAuditTestDB initializes the database for testing using the common setup method and records every write.
+func setupAuditTestDB(t *testing.T) *gorm.DB {
+	db := GetDatabase() // Use the method from common_test.go
+	if err := repositories.RegisterAuditCallbacks(db); err != nil {
+		t.Fatalf("failed to register audit callbacks: %v", err)
+	}
+
+	err := db.AutoMigrate(&models.Company{}, &models.User{}, &models.UserRole{}, &models.RolePermission{}, &models.UserProfile{},
+		&models.TimeEntryType{}, &models.TimeEntry{}, &models.AuditLog{})
+	if err != nil {
+		t.Fatalf("failed to migrate database: %v", err)
+	}
+	return db
+}
+
+func auditLogsOf(t *testing.T, db *gorm.DB, entity string) []models.AuditLog {
+	auditLogs, err := repositories.NewAuditLogRepository(db).Find(repositories.AuditLogFilter{Entity: entity})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	return auditLogs
+}
+
+func diffOf(t *testing.T, auditLog models.AuditLog) map[string]models.AuditChange {
+	var diff map[string]models.AuditChange
+	if err := json.Unmarshal([]byte(auditLog.Diff), &diff); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	return diff
+}
+
+func TestAuditCallbacks_Record_Writes(t *testing.T) {
+	db := setupAuditTestDB(t)
+	company := &models.Company{Name: "Company", PrimaryEmail: "a@company.com"}
+	if err := repositories.NewCompanyRepository(db).Create(company); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if logs := auditLogsOf(t, db, "companies"); len(logs) != 1 || logs[0].CompanyID != company.ID || logs[0].ActorID != nil {
+		t.Errorf("expected the company to be created by the system, got %+v", logs)
+	}
+
+	admin := repositories.WithActor(repositories.WithTenant(db, company.ID), 7)
+	roles := repositories.NewUserRoleRepository(admin)
+	role := &models.UserRole{Name: "admin"}
+	if err := roles.Create(role); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	role.Name = "owner"
+	if err := roles.Update(role); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := roles.Update(role); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := roles.Delete(role.ID); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	logs := auditLogsOf(t, db, "user_roles")
+	if len(logs) != 3 {
+		t.Fatalf("expected a create, an update and a delete, got %+v", logs)
+	}
+	for i, operation := range []string{models.AUDIT_OPERATION_CREATE, models.AUDIT_OPERATION_UPDATE, models.AUDIT_OPERATION_DELETE} {
+		if logs[i].Operation != operation || logs[i].EntityID != role.ID || logs[i].CompanyID != company.ID || logs[i].ActorID == nil || *logs[i].ActorID != 7 {
+			t.Errorf("unexpected audit log %d: %+v", i, logs[i])
+		}
+	}
+	if diff := diffOf(t, logs[0]); string(diff["name"].To) != `"admin"` || diff["name"].From != nil {
+		t.Errorf("unexpected create diff: %v", logs[0].Diff)
+	}
+	if diff := diffOf(t, logs[1]); len(diff) != 1 || string(diff["name"].From) != `"admin"` || string(diff["name"].To) != `"owner"` {
+		t.Errorf("expected only the name to change, got %v", logs[1].Diff)
+	}
+	if diff := diffOf(t, logs[2]); string(diff["name"].From) != `"owner"` || diff["name"].To != nil {
+		t.Errorf("unexpected delete diff: %v", logs[2].Diff)
+	}
+}
+
+func TestAuditCallbacks_Redact_And_Resolve_Company(t *testing.T) {
+	db := setupAuditTestDB(t)
+	company := &models.Company{Name: "Company", PrimaryEmail: "a@company.com"}
+	mustCreate(t, db, company)
+	role := &models.UserRole{Name: "admin", CompanyID: company.ID}
+	mustCreate(t, db, role)
+
+	// Writes outside of a tenant are assigned to the company of the parent row.
+	mustCreate(t, db, &models.RolePermission{UserRoleID: role.ID, Permission: models.PERMISSION_USERS_READ})
+	if logs := auditLogsOf(t, db, "role_permissions"); len(logs) != 1 || logs[0].CompanyID != company.ID {
+		t.Errorf("expected the permission to belong to the role's company, got %+v", logs)
+	}
+
+	users := repositories.NewUserRepository(repositories.WithTenant(db, company.ID))
+	user := &models.User{Email: "a@user.com", Password: "secret", RoleID: role.ID, UserProfile: models.UserProfile{Slug: "a"}}
+	if err := users.Create(user); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := db.Model(user).Update("password", "changed").Error; err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	logs := auditLogsOf(t, db, "users")
+	if len(logs) != 2 {
+		t.Fatalf("expected a create and an update, got %+v", logs)
+	}
+	if diff := diffOf(t, logs[1]); len(diff) != 1 || string(diff["password"].From) != `"[redacted]"` || string(diff["password"].To) != `"[redacted]"` {
+		t.Errorf("expected the password to be redacted, got %v", logs[1].Diff)
+	}
+	if len(auditLogsOf(t, db, "user_profiles")) != 1 {
+		t.Errorf("expected the created profile to be recorded")
+	}
+
+	timeEntryType := &models.TimeEntryType{Name: "Work", CompanyID: company.ID}
+	mustCreate(t, db, timeEntryType)
+	mustCreate(t, db, &models.TimeEntry{StartTime: time.Now(), CompanyID: company.ID, UserID: user.ID, TimeEntryTypeID: timeEntryType.ID})
+	if logs := auditLogsOf(t, db, "time_entries"); len(logs) != 0 {
+		t.Errorf("expected time entries to keep their own trail, got %+v", logs)
+	}
+}
+
+func TestAuditLogRepository_Find(t *testing.T) {
+	db := setupAuditTestDB(t)
+	repo := repositories.NewAuditLogRepository(db)
+	actorID := uint(3)
+	recordedAt := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	for i, auditLog := range []*models.AuditLog{
+		{CompanyID: 1, Entity: "users", EntityID: 1, Operation: models.AUDIT_OPERATION_CREATE, RecordedAt: recordedAt, Diff: "{}"},
+		{CompanyID: 1, Entity: "users", EntityID: 1, Operation: models.AUDIT_OPERATION_UPDATE, RecordedAt: recordedAt.Add(time.Hour), Diff: "{}", ActorID: &actorID},
+		{CompanyID: 1, Entity: "quota", EntityID: 2, Operation: models.AUDIT_OPERATION_UPDATE, RecordedAt: recordedAt.AddDate(0, 0, 1), Diff: "{}", ActorID: &actorID},
+	} {
+		if err := repo.Create(auditLog); err != nil {
+			t.Fatalf("unexpected error creating audit log %d: %v", i, err)
+		}
+	}
+
+	tests := []struct {
+		name     string
+		filter   repositories.AuditLogFilter
+		expected []uint
+	}{
+		{"all", repositories.AuditLogFilter{}, []uint{1, 2, 3}},
+		{"newest first", repositories.AuditLogFilter{NewestFirst: true, Limit: 2}, []uint{3, 2}},
+		{"page", repositories.AuditLogFilter{Limit: 1, Offset: 1}, []uint{2}},
+		{"entity", repositories.AuditLogFilter{Entity: "users", EntityID: 1}, []uint{1, 2}},
+		{"actor and operation", repositories.AuditLogFilter{ActorID: actorID, Operation: models.AUDIT_OPERATION_UPDATE}, []uint{2, 3}},
+		{"range", repositories.AuditLogFilter{From: recordedAt.Add(time.Minute), To: recordedAt.AddDate(0, 0, 1)}, []uint{2}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			auditLogs, err := repo.Find(tt.filter)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			ids := []uint{}
+			for _, auditLog := range auditLogs {
+				ids = append(ids, auditLog.ID)
+			}
+			if len(ids) != len(tt.expected) {
+				t.Fatalf("expected %v, got %v", tt.expected, ids)
+			}
+			for i := range ids {
+				if ids[i] != tt.expected[i] {
+					t.Errorf("expected %v, got %v", tt.expected, ids)
+				}
+			}
+		})
+	}
+}
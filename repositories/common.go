@@ -6,9 +6,12 @@ import (
 )
 
 func GetDatabase() *gorm.DB {
-	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{TranslateError: true})
 	if err != nil {
 		panic("failed to connect to database")
 	}
+	if err := RegisterTenantCallbacks(db); err != nil {
+		panic("failed to register tenant callbacks")
+	}
 	return db
 }
@@ -1,14 +1,18 @@
 package repositories_test
 
 import (
+	"github.com/r-52/embrace/repositories"
 	"gorm.io/driver/sqlite"
 	"gorm.io/gorm"
 )
 
 func GetDatabase() *gorm.DB {
-	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{TranslateError: true})
 	if err != nil {
 		panic("failed to connect to database")
 	}
+	if err := repositories.RegisterTenantCallbacks(db); err != nil {
+		panic("failed to register tenant callbacks")
+	}
 	return db
 }
@@ -25,6 +25,14 @@ type CompanyRepositoryInterface interface {
 	// GetByUserID retrieves a company record associated with a specific user ID.
 	// It takes an unsigned integer `userID` as input and returns a pointer to a `models.Company` instance and an error.
 	GetByUserID(userID uint) (*models.Company, error)
+
+	// GetByName retrieves a company record by its name.
+	// It takes a string `name` as input and returns a pointer to a `models.Company` instance and an error.
+	GetByName(name string) (*models.Company, error)
+
+	// GetAll retrieves all company records ordered by ID.
+	// It returns a slice of `models.Company` instances and an error.
+	GetAll() ([]models.Company, error)
 }
 
 type CompanyRepository struct {
@@ -78,3 +86,25 @@ func (r *CompanyRepository) GetByUserID(userID uint) (*models.Company, error) {
 	}
 	return &company, nil
 }
+
+// GetByName retrieves a company record by its name.
+// It takes a string `name` as input and returns a pointer to a `models.Company` instance and an error.
+func (r *CompanyRepository) GetByName(name string) (*models.Company, error) {
+	var company models.Company
+	err := r.Database.Where("name = ?", name).First(&company).Error
+	if err != nil {
+		return nil, err
+	}
+	return &company, nil
+}
+
+// GetAll retrieves all company records ordered by ID.
+// It returns a slice of `models.Company` instances and an error.
+func (r *CompanyRepository) GetAll() ([]models.Company, error) {
+	var companies []models.Company
+	err := r.Database.Order("id").Find(&companies).Error
+	if err != nil {
+		return nil, err
+	}
+	return companies, nil
+}
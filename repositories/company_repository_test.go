@@ -136,3 +136,27 @@ func TestCompanyRepository_GetByUserID(t *testing.T) {
 		t.Errorf("expected ErrRecordNotFound, got %v", err)
 	}
 }
+
+func TestCompanyRepository_GetByName(t *testing.T) {
+	db := setupCompanyTestDB(t)
+	repo := repositories.NewCompanyRepository(db)
+
+	// Insert a test company
+	company := &models.Company{Name: "Named Company", PrimaryEmail: "named@company.com"}
+	db.Create(company)
+
+	// Test retrieving the company by name
+	result, err := repo.GetByName("Named Company")
+	if err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if result.ID != company.ID {
+		t.Errorf("expected %v, got %v", company, result)
+	}
+
+	// Test retrieving a non-existent company
+	_, err = repo.GetByName("Unknown Company")
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		t.Errorf("expected ErrRecordNotFound, got %v", err)
+	}
+}
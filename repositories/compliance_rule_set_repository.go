@@ -0,0 +1,80 @@
+package repositories
+
+import (
+	"github.com/r-52/embrace/models"
+	"gorm.io/gorm"
+)
+
+type ComplianceRuleSetRepository struct {
+	Database *gorm.DB
+}
+
+type ComplianceRuleSetRepositoryInterface interface {
+	// GetByCompanyID retrieves the compliance rule set of a company together with its rules.
+	// It takes an unsigned integer `companyID` as input and returns a pointer to a `models.ComplianceRuleSet` instance and an error.
+	GetByCompanyID(companyID uint) (*models.ComplianceRuleSet, error)
+
+	// Create inserts a new compliance rule set and its rules into the database.
+	// It takes a pointer to a `models.ComplianceRuleSet` instance as input and returns an error.
+	Create(ruleSet *models.ComplianceRuleSet) error
+
+	// Update updates an existing compliance rule set in the database and replaces its rules.
+	// It takes a pointer to a `models.ComplianceRuleSet` instance as input and returns an error.
+	Update(ruleSet *models.ComplianceRuleSet) error
+}
+
+// NewComplianceRuleSetRepository creates a new instance of ComplianceRuleSetRepository with the provided database connection.
+// It takes a *gorm.DB as an argument, which represents the database connection, and returns a pointer to a ComplianceRuleSetRepository.
+func NewComplianceRuleSetRepository(db *gorm.DB) *ComplianceRuleSetRepository {
+	return &ComplianceRuleSetRepository{
+		Database: db,
+	}
+}
+
+// GetByCompanyID retrieves the compliance rule set of a company together with its rules, ordered by name.
+// If the company has no rule set or if there is a database error, it returns a non-nil error.
+func (r *ComplianceRuleSetRepository) GetByCompanyID(companyID uint) (*models.ComplianceRuleSet, error) {
+	var ruleSet models.ComplianceRuleSet
+	err := r.Database.Preload("Rules", func(db *gorm.DB) *gorm.DB {
+		return db.Order("rule")
+	}).Where("company_id = ?", companyID).First(&ruleSet).Error
+	if err != nil {
+		return nil, err
+	}
+	return &ruleSet, nil
+}
+
+// Create inserts a new compliance rule set and its rules into the database.
+// If the create operation fails, it returns a non-nil error.
+func (r *ComplianceRuleSetRepository) Create(ruleSet *models.ComplianceRuleSet) error {
+	err := r.Database.Create(ruleSet).Error
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// Update updates an existing compliance rule set in the database and replaces its rules with the ones of `ruleSet`.
+// The previous rules are removed permanently. If a database operation fails, it returns a non-nil error.
+func (r *ComplianceRuleSetRepository) Update(ruleSet *models.ComplianceRuleSet) error {
+	err := r.Database.Omit("Rules").Save(ruleSet).Error
+	if err != nil {
+		return err
+	}
+	err = r.Database.Unscoped().Where("compliance_rule_set_id = ?", ruleSet.ID).Delete(&models.ComplianceRule{}).Error
+	if err != nil {
+		return err
+	}
+	if len(ruleSet.Rules) == 0 {
+		return nil
+	}
+	for i := range ruleSet.Rules {
+		ruleSet.Rules[i].ID = 0
+		ruleSet.Rules[i].ComplianceRuleSetID = ruleSet.ID
+	}
+	err = r.Database.Create(&ruleSet.Rules).Error
+	if err != nil {
+		return err
+	}
+	return nil
+}
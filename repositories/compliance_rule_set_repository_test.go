@@ -0,0 +1,47 @@
+package repositories_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/r-52/embrace/models"
+	"github.com/r-52/embrace/repositories"
+	"gorm.io/gorm"
+)
+
+func TestComplianceRuleSetRepository_Update_Replaces_Rules(t *testing.T) {
+	db := GetDatabase()
+	if err := db.AutoMigrate(&models.ComplianceRuleSet{}, &models.ComplianceRule{}); err != nil {
+		t.Fatalf("failed to migrate database: %v", err)
+	}
+	repo := repositories.NewComplianceRuleSetRepository(db)
+
+	ruleSet := &models.ComplianceRuleSet{CompanyID: 1, Rules: []models.ComplianceRule{
+		{Rule: models.COMPLIANCE_RULE_SUNDAY_WORK, Enabled: false, Severity: models.COMPLIANCE_SEVERITY_WARNING},
+		{Rule: models.COMPLIANCE_RULE_BREAKS, Enabled: true, Severity: models.COMPLIANCE_SEVERITY_BLOCKING},
+	}}
+	if err := repo.Create(ruleSet); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	loaded, err := repo.GetByCompanyID(1)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(loaded.Rules) != 2 || loaded.Rules[0].Rule != models.COMPLIANCE_RULE_BREAKS || loaded.Rule(models.COMPLIANCE_RULE_SUNDAY_WORK) == nil {
+		t.Errorf("expected the rules ordered by name, got %+v", loaded.Rules)
+	}
+
+	loaded.Rules = []models.ComplianceRule{{Rule: models.COMPLIANCE_RULE_REST_PERIOD, Enabled: true, Severity: models.COMPLIANCE_SEVERITY_BLOCKING}}
+	if err := repo.Update(loaded); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	loaded, _ = repo.GetByCompanyID(1)
+	if len(loaded.Rules) != 1 || loaded.Rule(models.COMPLIANCE_RULE_REST_PERIOD) == nil || loaded.Rule(models.COMPLIANCE_RULE_BREAKS) != nil {
+		t.Errorf("expected the rules to be replaced, got %+v", loaded.Rules)
+	}
+
+	if _, err := repo.GetByCompanyID(2); !errors.Is(err, gorm.ErrRecordNotFound) {
+		t.Errorf("expected ErrRecordNotFound, got %v", err)
+	}
+}
@@ -0,0 +1,63 @@
+package repositories
+
+import (
+	"github.com/r-52/embrace/models"
+	"gorm.io/gorm"
+)
+
+type DurationPolicyRepository struct {
+	Database *gorm.DB
+}
+
+type DurationPolicyRepositoryInterface interface {
+	// GetByCompanyID retrieves the duration policy of a company.
+	// It takes an unsigned integer `companyID` as input and returns a pointer to a `models.DurationPolicy` instance and an error.
+	GetByCompanyID(companyID uint) (*models.DurationPolicy, error)
+
+	// Create inserts a new duration policy into the database.
+	// It takes a pointer to a `models.DurationPolicy` instance as input and returns an error.
+	Create(policy *models.DurationPolicy) error
+
+	// Update updates an existing duration policy in the database.
+	// It takes a pointer to a `models.DurationPolicy` instance as input and returns an error.
+	Update(policy *models.DurationPolicy) error
+}
+
+// NewDurationPolicyRepository creates a new instance of DurationPolicyRepository with the provided database connection.
+// It takes a *gorm.DB as an argument, which represents the database connection, and returns a pointer to a DurationPolicyRepository.
+func NewDurationPolicyRepository(db *gorm.DB) *DurationPolicyRepository {
+	return &DurationPolicyRepository{
+		Database: db,
+	}
+}
+
+// GetByCompanyID retrieves the duration policy of a company.
+// If the company has no policy or if there is a database error, it returns a non-nil error.
+func (r *DurationPolicyRepository) GetByCompanyID(companyID uint) (*models.DurationPolicy, error) {
+	var policy models.DurationPolicy
+	err := r.Database.Where("company_id = ?", companyID).First(&policy).Error
+	if err != nil {
+		return nil, err
+	}
+	return &policy, nil
+}
+
+// Create inserts a new duration policy into the database.
+// If the company has a policy already, it returns gorm.ErrDuplicatedKey.
+func (r *DurationPolicyRepository) Create(policy *models.DurationPolicy) error {
+	err := r.Database.Create(policy).Error
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// Update updates an existing duration policy in the database.
+// If the update operation fails, it returns a non-nil error.
+func (r *DurationPolicyRepository) Update(policy *models.DurationPolicy) error {
+	err := r.Database.Save(policy).Error
+	if err != nil {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,134 @@
+package repositories
+
+import (
+	"github.com/r-52/embrace/models"
+	"gorm.io/gorm"
+)
+
+type HolidayCalendarRepository struct {
+	Database *gorm.DB
+}
+
+type HolidayCalendarRepositoryInterface interface {
+	// GetByID retrieves a holiday calendar together with its custom holidays by its ID.
+	// It takes an unsigned integer `id` as input and returns a pointer to a `models.HolidayCalendar` instance and an error.
+	GetByID(id uint) (*models.HolidayCalendar, error)
+
+	// GetByCompanyID retrieves the holiday calendars of a company ordered by name.
+	// It takes an unsigned integer `companyID` as input and returns a slice of `models.HolidayCalendar` instances and an error.
+	GetByCompanyID(companyID uint) ([]models.HolidayCalendar, error)
+
+	// Create inserts a new holiday calendar into the database.
+	// It takes a pointer to a `models.HolidayCalendar` instance as input and returns an error.
+	Create(calendar *models.HolidayCalendar) error
+
+	// Update updates an existing holiday calendar in the database.
+	// It takes a pointer to a `models.HolidayCalendar` instance as input and returns an error.
+	Update(calendar *models.HolidayCalendar) error
+
+	// Delete removes a holiday calendar and its custom holidays from the database by its ID.
+	// It takes an unsigned integer `id` as input and returns an error.
+	Delete(id uint) error
+
+	// CreateHoliday inserts a new custom holiday of a calendar into the database.
+	// It takes a pointer to a `models.Holiday` instance as input and returns an error.
+	CreateHoliday(holiday *models.Holiday) error
+
+	// DeleteHoliday removes a custom holiday from a calendar.
+	// It takes two unsigned integers `calendarID` and `holidayID` as input and returns an error.
+	DeleteHoliday(calendarID, holidayID uint) error
+}
+
+// NewHolidayCalendarRepository creates a new instance of HolidayCalendarRepository with the provided database connection.
+// It takes a *gorm.DB as an argument, which represents the database connection, and returns a pointer to a HolidayCalendarRepository.
+func NewHolidayCalendarRepository(db *gorm.DB) *HolidayCalendarRepository {
+	return &HolidayCalendarRepository{
+		Database: db,
+	}
+}
+
+// GetByID retrieves a holiday calendar together with its custom holidays, ordered by date, by its ID.
+// If the calendar with the specified ID is not found or if there is a database error, it returns a non-nil error.
+func (r *HolidayCalendarRepository) GetByID(id uint) (*models.HolidayCalendar, error) {
+	var calendar models.HolidayCalendar
+	err := r.Database.Preload("Holidays", func(db *gorm.DB) *gorm.DB {
+		return db.Order("date, id")
+	}).First(&calendar, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &calendar, nil
+}
+
+// GetByCompanyID retrieves the holiday calendars of a company ordered by name, without their holidays.
+// If there is a database error, it returns a non-nil error.
+func (r *HolidayCalendarRepository) GetByCompanyID(companyID uint) ([]models.HolidayCalendar, error) {
+	var calendars []models.HolidayCalendar
+	err := r.Database.Where("company_id = ?", companyID).Order("name, id").Find(&calendars).Error
+	if err != nil {
+		return nil, err
+	}
+	return calendars, nil
+}
+
+// Create inserts a new holiday calendar into the database.
+// If the create operation fails, it returns a non-nil error.
+func (r *HolidayCalendarRepository) Create(calendar *models.HolidayCalendar) error {
+	err := r.Database.Create(calendar).Error
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// Update updates the name and region of an existing holiday calendar. Its holidays are left untouched.
+// If the update operation fails, it returns a non-nil error.
+func (r *HolidayCalendarRepository) Update(calendar *models.HolidayCalendar) error {
+	err := r.Database.Omit("Holidays").Save(calendar).Error
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// Delete removes a holiday calendar and its custom holidays from the database by its ID.
+// If the calendar with the specified ID is not found or if the delete operation fails, it returns a non-nil error.
+func (r *HolidayCalendarRepository) Delete(id uint) error {
+	var calendar models.HolidayCalendar
+	err := r.Database.First(&calendar, id).Error
+	if err != nil {
+		return err
+	}
+	err = r.Database.Where("holiday_calendar_id = ?", calendar.ID).Delete(&models.Holiday{}).Error
+	if err != nil {
+		return err
+	}
+	err = r.Database.Delete(&calendar).Error
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// CreateHoliday inserts a new custom holiday of a calendar into the database.
+// If the create operation fails, it returns a non-nil error.
+func (r *HolidayCalendarRepository) CreateHoliday(holiday *models.Holiday) error {
+	err := r.Database.Create(holiday).Error
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// DeleteHoliday removes a custom holiday from a calendar.
+// If the calendar has no holiday with the specified ID or if the delete operation fails, it returns a non-nil error.
+func (r *HolidayCalendarRepository) DeleteHoliday(calendarID, holidayID uint) error {
+	result := r.Database.Where("id = ? AND holiday_calendar_id = ?", holidayID, calendarID).Delete(&models.Holiday{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
@@ -0,0 +1,75 @@
+package repositories_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/r-52/embrace/models"
+	"github.com/r-52/embrace/repositories"
+	"gorm.io/gorm"
+)
+
+// setupHolidayCalendarTestDB initializes the database for testing using the common setup method.
+func setupHolidayCalendarTestDB(t *testing.T) *gorm.DB {
+	db := GetDatabase() // Use the method from common_test.go
+
+	// Auto-migrate the HolidayCalendar and Holiday models
+	err := db.AutoMigrate(&models.HolidayCalendar{}, &models.Holiday{})
+	if err != nil {
+		t.Fatalf("failed to migrate database: %v", err)
+	}
+
+	return db
+}
+
+func TestHolidayCalendarRepository_Holidays(t *testing.T) {
+	db := setupHolidayCalendarTestDB(t)
+	repo := repositories.NewHolidayCalendarRepository(db)
+
+	calendar := &models.HolidayCalendar{CompanyID: 1, Name: "Munich", Region: "DE-BY"}
+	if err := repo.Create(calendar); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	later := &models.Holiday{HolidayCalendarID: calendar.ID, Date: time.Date(2024, 12, 31, 0, 0, 0, 0, time.UTC), Name: "Silvester", HalfDay: true}
+	earlier := &models.Holiday{HolidayCalendarID: calendar.ID, Date: time.Date(2024, 12, 24, 0, 0, 0, 0, time.UTC), Name: "Heiligabend", HalfDay: true}
+	for _, holiday := range []*models.Holiday{later, earlier} {
+		if err := repo.CreateHoliday(holiday); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	loaded, err := repo.GetByID(calendar.ID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(loaded.Holidays) != 2 || loaded.Holidays[0].ID != earlier.ID {
+		t.Errorf("expected the holidays ordered by date, got %v", loaded.Holidays)
+	}
+
+	// Test updating the calendar without touching its holidays
+	loaded.Name = "Bavaria"
+	loaded.Holidays = nil
+	if err := repo.Update(loaded); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := repo.DeleteHoliday(calendar.ID+1, later.ID); !errors.Is(err, gorm.ErrRecordNotFound) {
+		t.Errorf("expected ErrRecordNotFound for a holiday of another calendar, got %v", err)
+	}
+	if err := repo.DeleteHoliday(calendar.ID, later.ID); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	loaded, _ = repo.GetByID(calendar.ID)
+	if loaded.Name != "Bavaria" || len(loaded.Holidays) != 1 {
+		t.Errorf("unexpected calendar: %+v", loaded)
+	}
+
+	if err := repo.Delete(calendar.ID); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var remaining int64
+	db.Model(&models.Holiday{}).Count(&remaining)
+	if remaining != 0 {
+		t.Errorf("expected the holidays to be deleted with the calendar, got %d", remaining)
+	}
+}
@@ -0,0 +1,129 @@
+package repositories
+
+import (
+	"time"
+
+	"github.com/r-52/embrace/models"
+	"gorm.io/gorm"
+)
+
+type LeaveRequestRepository struct {
+	Database *gorm.DB
+}
+
+type LeaveRequestRepositoryInterface interface {
+	// GetByID retrieves a leave request by its ID.
+	// It takes an unsigned integer `id` as input and returns a pointer to a `models.LeaveRequest` instance and an error.
+	GetByID(id uint) (*models.LeaveRequest, error)
+
+	// Create inserts a new leave request into the database.
+	// It takes a pointer to a `models.LeaveRequest` instance as input and returns an error.
+	Create(leaveRequest *models.LeaveRequest) error
+
+	// GetByUserID retrieves the leave requests of a user, the latest leave first.
+	// It takes an unsigned integer `userID` as input and returns a slice of `models.LeaveRequest` instances and an error.
+	GetByUserID(userID uint) ([]models.LeaveRequest, error)
+
+	// GetPending retrieves the requested leave of the given users, the earliest leave first.
+	// It takes a slice of user IDs as input and returns a slice of `models.LeaveRequest` instances and an error.
+	GetPending(userIDs []uint) ([]models.LeaveRequest, error)
+
+	// GetApprovedBetween retrieves the approved leave of a user overlapping the days from `from` to `to`, ordered by start.
+	// It takes an unsigned integer `userID` and two days as input and returns a slice of `models.LeaveRequest` instances and an error.
+	GetApprovedBetween(userID uint, from, to time.Time) ([]models.LeaveRequest, error)
+
+	// Transition moves a leave request from one status to another and records the decision.
+	// It takes the updated `models.LeaveRequest` and the status it is expected to have as input and returns an error.
+	Transition(leaveRequest *models.LeaveRequest, from string) error
+}
+
+// NewLeaveRequestRepository creates a new instance of LeaveRequestRepository with the provided database connection.
+// It takes a *gorm.DB as an argument, which represents the database connection, and returns a pointer to a LeaveRequestRepository.
+func NewLeaveRequestRepository(db *gorm.DB) *LeaveRequestRepository {
+	return &LeaveRequestRepository{
+		Database: db,
+	}
+}
+
+// GetByID retrieves a leave request by its ID.
+// If the leave request with the specified ID is not found or if there is a database error, it returns a non-nil error.
+func (r *LeaveRequestRepository) GetByID(id uint) (*models.LeaveRequest, error) {
+	var leaveRequest models.LeaveRequest
+	err := r.Database.First(&leaveRequest, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &leaveRequest, nil
+}
+
+// Create inserts a new leave request into the database.
+// If the create operation fails, it returns a non-nil error.
+func (r *LeaveRequestRepository) Create(leaveRequest *models.LeaveRequest) error {
+	err := r.Database.Create(leaveRequest).Error
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// GetByUserID retrieves the leave requests of a user, the latest leave first.
+// If there is a database error, it returns a non-nil error.
+func (r *LeaveRequestRepository) GetByUserID(userID uint) ([]models.LeaveRequest, error) {
+	var leaveRequests []models.LeaveRequest
+	err := r.Database.Where("user_id = ?", userID).Order("start_date DESC, id DESC").Find(&leaveRequests).Error
+	if err != nil {
+		return nil, err
+	}
+	return leaveRequests, nil
+}
+
+// GetPending retrieves the requested leave of the given users, the earliest leave first.
+// A nil slice retrieves the requested leave of every user.
+// If there is a database error, it returns a non-nil error.
+func (r *LeaveRequestRepository) GetPending(userIDs []uint) ([]models.LeaveRequest, error) {
+	var leaveRequests []models.LeaveRequest
+	query := r.Database.Where("status = ?", models.LEAVE_REQUEST_REQUESTED)
+	if userIDs != nil {
+		query = query.Where("user_id IN ?", userIDs)
+	}
+	err := query.Order("start_date, id").Find(&leaveRequests).Error
+	if err != nil {
+		return nil, err
+	}
+	return leaveRequests, nil
+}
+
+// GetApprovedBetween retrieves the approved leave of a user overlapping the days from `from` to `to`, ordered by start.
+// If there is a database error, it returns a non-nil error.
+func (r *LeaveRequestRepository) GetApprovedBetween(userID uint, from, to time.Time) ([]models.LeaveRequest, error) {
+	var leaveRequests []models.LeaveRequest
+	err := r.Database.
+		Where("user_id = ? AND status = ? AND start_date <= ? AND end_date >= ?", userID, models.LEAVE_REQUEST_APPROVED, to, from).
+		Order("start_date, id").
+		Find(&leaveRequests).Error
+	if err != nil {
+		return nil, err
+	}
+	return leaveRequests, nil
+}
+
+// Transition moves a leave request from the status `from` to its new status and records the decision.
+// Only one of concurrent transitions of the same request succeeds, the others return gorm.ErrRecordNotFound,
+// as they do if the request does not exist or does not have the status `from`.
+func (r *LeaveRequestRepository) Transition(leaveRequest *models.LeaveRequest, from string) error {
+	result := r.Database.Model(&models.LeaveRequest{}).
+		Where("id = ? AND status = ?", leaveRequest.ID, from).
+		Updates(map[string]interface{}{
+			"status":        leaveRequest.Status,
+			"decided_by_id": leaveRequest.DecidedByID,
+			"decided_at":    leaveRequest.DecidedAt,
+			"decision_note": leaveRequest.DecisionNote,
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
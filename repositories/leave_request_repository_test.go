@@ -0,0 +1,78 @@
+package repositories_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/r-52/embrace/models"
+	"github.com/r-52/embrace/repositories"
+	"gorm.io/gorm"
+)
+
+// setupLeaveRequestTestDB initializes the database for testing using the common setup method.
+func setupLeaveRequestTestDB(t *testing.T) *gorm.DB {
+	db := GetDatabase() // Use the method from common_test.go
+
+	// Auto-migrate the LeaveRequest model
+	err := db.AutoMigrate(&models.LeaveRequest{})
+	if err != nil {
+		t.Fatalf("failed to migrate database: %v", err)
+	}
+
+	return db
+}
+
+func TestLeaveRequestRepository_GetPending_And_Transition(t *testing.T) {
+	db := setupLeaveRequestTestDB(t)
+	repo := repositories.NewLeaveRequestRepository(db)
+
+	march := time.Date(2025, 3, 3, 0, 0, 0, 0, time.UTC)
+	april := time.Date(2025, 4, 7, 0, 0, 0, 0, time.UTC)
+	later := &models.LeaveRequest{UserID: 1, StartDate: april, EndDate: april, Status: models.LEAVE_REQUEST_REQUESTED}
+	earlier := &models.LeaveRequest{UserID: 1, StartDate: march, EndDate: march, Status: models.LEAVE_REQUEST_REQUESTED}
+	other := &models.LeaveRequest{UserID: 2, StartDate: march, EndDate: march, Status: models.LEAVE_REQUEST_REQUESTED}
+	for _, leaveRequest := range []*models.LeaveRequest{later, earlier, other} {
+		if err := repo.Create(leaveRequest); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	pending, err := repo.GetPending([]uint{1})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(pending) != 2 || pending[0].ID != earlier.ID || pending[1].ID != later.ID {
+		t.Errorf("expected the requests of user 1, the earliest leave first, got %v", pending)
+	}
+	if all, _ := repo.GetPending(nil); len(all) != 3 {
+		t.Errorf("expected 3 pending requests of all users, got %d", len(all))
+	}
+
+	// Test a decision and a second, concurrent one
+	earlier.Status = models.LEAVE_REQUEST_APPROVED
+	earlier.DecisionNote = "enjoy"
+	if err := repo.Transition(earlier, models.LEAVE_REQUEST_REQUESTED); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	stale := *earlier
+	stale.Status = models.LEAVE_REQUEST_REJECTED
+	if err := repo.Transition(&stale, models.LEAVE_REQUEST_REQUESTED); !errors.Is(err, gorm.ErrRecordNotFound) {
+		t.Errorf("expected ErrRecordNotFound, got %v", err)
+	}
+	decided, _ := repo.GetByID(earlier.ID)
+	if decided.Status != models.LEAVE_REQUEST_APPROVED || decided.DecisionNote != "enjoy" {
+		t.Errorf("unexpected decision: %+v", decided)
+	}
+
+	requests, err := repo.GetByUserID(1)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(requests) != 2 || requests[0].ID != later.ID {
+		t.Errorf("expected the latest leave first, got %v", requests)
+	}
+	if pending, _ := repo.GetPending([]uint{1}); len(pending) != 1 {
+		t.Errorf("expected 1 pending request after the decision, got %d", len(pending))
+	}
+}
@@ -0,0 +1,80 @@
+package repositories
+
+import (
+	"github.com/r-52/embrace/models"
+	"gorm.io/gorm"
+)
+
+type OvertimePolicyRepository struct {
+	Database *gorm.DB
+}
+
+type OvertimePolicyRepositoryInterface interface {
+	// GetByCompanyID retrieves the overtime policy of a company together with its quota and work time types.
+	// It takes an unsigned integer `companyID` as input and returns a pointer to a `models.OvertimePolicy` instance and an error.
+	GetByCompanyID(companyID uint) (*models.OvertimePolicy, error)
+
+	// Create inserts a new overtime policy and its work time types into the database.
+	// It takes a pointer to a `models.OvertimePolicy` instance as input and returns an error.
+	Create(policy *models.OvertimePolicy) error
+
+	// Update updates an existing overtime policy in the database and replaces its work time types.
+	// It takes a pointer to a `models.OvertimePolicy` instance as input and returns an error.
+	Update(policy *models.OvertimePolicy) error
+}
+
+// NewOvertimePolicyRepository creates a new instance of OvertimePolicyRepository with the provided database connection.
+// It takes a *gorm.DB as an argument, which represents the database connection, and returns a pointer to an OvertimePolicyRepository.
+func NewOvertimePolicyRepository(db *gorm.DB) *OvertimePolicyRepository {
+	return &OvertimePolicyRepository{
+		Database: db,
+	}
+}
+
+// GetByCompanyID retrieves the overtime policy of a company together with its quota and its work time types, ordered by type.
+// If the company has no policy or if there is a database error, it returns a non-nil error.
+func (r *OvertimePolicyRepository) GetByCompanyID(companyID uint) (*models.OvertimePolicy, error) {
+	var policy models.OvertimePolicy
+	err := r.Database.Preload("Quota").Preload("WorkTimeTypes", func(db *gorm.DB) *gorm.DB {
+		return db.Order("time_entry_type_id")
+	}).Where("company_id = ?", companyID).First(&policy).Error
+	if err != nil {
+		return nil, err
+	}
+	return &policy, nil
+}
+
+// Create inserts a new overtime policy and its work time types into the database.
+// If the create operation fails, it returns a non-nil error.
+func (r *OvertimePolicyRepository) Create(policy *models.OvertimePolicy) error {
+	err := r.Database.Omit("Quota").Create(policy).Error
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// Update updates an existing overtime policy in the database and replaces its work time types with the ones of `policy`.
+// The previous work time types are removed permanently. If a database operation fails, it returns a non-nil error.
+func (r *OvertimePolicyRepository) Update(policy *models.OvertimePolicy) error {
+	err := r.Database.Omit("Quota", "WorkTimeTypes").Save(policy).Error
+	if err != nil {
+		return err
+	}
+	err = r.Database.Unscoped().Where("overtime_policy_id = ?", policy.ID).Delete(&models.OvertimeWorkTimeType{}).Error
+	if err != nil {
+		return err
+	}
+	if len(policy.WorkTimeTypes) == 0 {
+		return nil
+	}
+	for i := range policy.WorkTimeTypes {
+		policy.WorkTimeTypes[i].ID = 0
+		policy.WorkTimeTypes[i].OvertimePolicyID = policy.ID
+	}
+	err = r.Database.Create(&policy.WorkTimeTypes).Error
+	if err != nil {
+		return err
+	}
+	return nil
+}
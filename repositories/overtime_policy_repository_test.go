@@ -0,0 +1,89 @@
+package repositories_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/r-52/embrace/models"
+	"github.com/r-52/embrace/repositories"
+	"gorm.io/gorm"
+)
+
+// setupOvertimeTestDB initializes the database for testing using the common setup method.
+func setupOvertimeTestDB(t *testing.T) *gorm.DB {
+	db := GetDatabase() // Use the method from common_test.go
+
+	// Auto-migrate the Quota, OvertimePolicy, OvertimeWorkTimeType and OvertimeSettlement models
+	err := db.AutoMigrate(&models.Quota{}, &models.OvertimePolicy{}, &models.OvertimeWorkTimeType{}, &models.OvertimeSettlement{})
+	if err != nil {
+		t.Fatalf("failed to migrate database: %v", err)
+	}
+
+	return db
+}
+
+func TestOvertimePolicyRepository_Update_Replaces_Work_Time_Types(t *testing.T) {
+	db := setupOvertimeTestDB(t)
+	repo := repositories.NewOvertimePolicyRepository(db)
+
+	quota := &models.Quota{Name: models.OVERTIME_QUOTA_NAME, CompanyID: 1, Unit: models.QUOTA_UNIT_HOURS, QuotaResetAt: models.QUOTA_RESET_NEVER}
+	if err := db.Create(quota).Error; err != nil {
+		t.Fatalf("failed to create quota: %v", err)
+	}
+	policy := &models.OvertimePolicy{CompanyID: 1, QuotaID: quota.ID, WorkTimeTypes: []models.OvertimeWorkTimeType{{TimeEntryTypeID: 3}, {TimeEntryTypeID: 2}}}
+	if err := repo.Create(policy); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	loaded, err := repo.GetByCompanyID(1)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if loaded.Quota.Name != models.OVERTIME_QUOTA_NAME || len(loaded.WorkTimeTypes) != 2 || loaded.WorkTimeTypes[0].TimeEntryTypeID != 2 {
+		t.Errorf("expected the quota and the types ordered by type, got %+v", loaded)
+	}
+
+	loaded.MonthlyCap = models.QuotaUnits(20)
+	loaded.WorkTimeTypes = []models.OvertimeWorkTimeType{{TimeEntryTypeID: 4}}
+	if err := repo.Update(loaded); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	loaded, _ = repo.GetByCompanyID(1)
+	if loaded.MonthlyCap != models.QuotaUnits(20) || len(loaded.WorkTimeTypes) != 1 || loaded.WorkTimeTypes[0].TimeEntryTypeID != 4 {
+		t.Errorf("expected the types to be replaced, got %+v", loaded)
+	}
+
+	if _, err := repo.GetByCompanyID(2); !errors.Is(err, gorm.ErrRecordNotFound) {
+		t.Errorf("expected ErrRecordNotFound, got %v", err)
+	}
+}
+
+func TestOvertimeSettlementRepository_Settles_Months_Once(t *testing.T) {
+	db := setupOvertimeTestDB(t)
+	repo := repositories.NewOvertimeSettlementRepository(db)
+
+	if _, err := repo.GetLatestByUserID(1); !errors.Is(err, gorm.ErrRecordNotFound) {
+		t.Errorf("expected ErrRecordNotFound, got %v", err)
+	}
+	february := time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC)
+	for _, month := range []time.Time{february, february.AddDate(0, -1, 0)} {
+		if err := repo.Create(&models.OvertimeSettlement{UserID: 1, Month: month}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	if err := repo.Create(&models.OvertimeSettlement{UserID: 1, Month: february}); !errors.Is(err, gorm.ErrDuplicatedKey) {
+		t.Errorf("expected ErrDuplicatedKey, got %v", err)
+	}
+
+	latest, err := repo.GetLatestByUserID(1)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !latest.Month.Equal(february) {
+		t.Errorf("expected February to be the latest month, got %v", latest.Month)
+	}
+	if settlements, _ := repo.GetByUserID(1); len(settlements) != 2 || !settlements[0].Month.Before(settlements[1].Month) {
+		t.Errorf("expected 2 settlements ordered by month, got %+v", settlements)
+	}
+}
@@ -0,0 +1,91 @@
+package repositories
+
+import (
+	"github.com/r-52/embrace/models"
+	"gorm.io/gorm"
+)
+
+type OvertimeSettlementRepository struct {
+	Database *gorm.DB
+}
+
+type OvertimeSettlementRepositoryInterface interface {
+	// Create inserts a new overtime settlement into the database.
+	// It takes a pointer to a `models.OvertimeSettlement` instance as input and returns an error.
+	Create(settlement *models.OvertimeSettlement) error
+
+	// GetByUserID retrieves the overtime settlements of a user ordered by month.
+	// It takes an unsigned integer `userID` as input and returns a slice of `models.OvertimeSettlement` instances and an error.
+	GetByUserID(userID uint) ([]models.OvertimeSettlement, error)
+
+	// GetLatestByUserID retrieves the overtime settlement of a user for the latest month.
+	// It takes an unsigned integer `userID` as input and returns a pointer to a `models.OvertimeSettlement` instance and an error.
+	GetLatestByUserID(userID uint) (*models.OvertimeSettlement, error)
+
+	// Correct persists the recomputed hours of a settlement that still has the given overtime and credited hours.
+	// It takes a pointer to a `models.OvertimeSettlement` instance and the previous amounts as input and returns an error.
+	Correct(settlement *models.OvertimeSettlement, overtime, credited models.QuotaAmount) error
+}
+
+// NewOvertimeSettlementRepository creates a new instance of OvertimeSettlementRepository with the provided database connection.
+// It takes a *gorm.DB as an argument, which represents the database connection, and returns a pointer to an OvertimeSettlementRepository.
+func NewOvertimeSettlementRepository(db *gorm.DB) *OvertimeSettlementRepository {
+	return &OvertimeSettlementRepository{
+		Database: db,
+	}
+}
+
+// Create inserts a new overtime settlement into the database.
+// If a settlement for the month exists already, it returns gorm.ErrDuplicatedKey.
+func (r *OvertimeSettlementRepository) Create(settlement *models.OvertimeSettlement) error {
+	err := r.Database.Create(settlement).Error
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// GetByUserID retrieves the overtime settlements of a user ordered by month.
+// If there is a database error, it returns a non-nil error.
+func (r *OvertimeSettlementRepository) GetByUserID(userID uint) ([]models.OvertimeSettlement, error) {
+	var settlements []models.OvertimeSettlement
+	err := r.Database.Where("user_id = ?", userID).Order("month").Find(&settlements).Error
+	if err != nil {
+		return nil, err
+	}
+	return settlements, nil
+}
+
+// GetLatestByUserID retrieves the overtime settlement of a user for the latest month.
+// If the user has no settlement or if there is a database error, it returns a non-nil error.
+func (r *OvertimeSettlementRepository) GetLatestByUserID(userID uint) (*models.OvertimeSettlement, error) {
+	var settlement models.OvertimeSettlement
+	err := r.Database.Where("user_id = ?", userID).Order("month DESC").First(&settlement).Error
+	if err != nil {
+		return nil, err
+	}
+	return &settlement, nil
+}
+
+// Correct persists the recomputed hours, amounts and booking of a settlement. The update only applies while the
+// settlement still has the previous overtime and credited hours, so of two concurrent corrections only one succeeds.
+// The other one returns gorm.ErrRecordNotFound.
+func (r *OvertimeSettlementRepository) Correct(settlement *models.OvertimeSettlement, overtime, credited models.QuotaAmount) error {
+	result := r.Database.Model(&models.OvertimeSettlement{}).
+		Where("id = ? AND overtime = ? AND credited = ?", settlement.ID, overtime, credited).
+		Updates(map[string]interface{}{
+			"worked_hours":              settlement.WorkedHours,
+			"target_hours":              settlement.TargetHours,
+			"overtime":                  settlement.Overtime,
+			"credited":                  settlement.Credited,
+			"forfeited":                 settlement.Forfeited,
+			"user_quota_transaction_id": settlement.UserQuotaTransactionID,
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
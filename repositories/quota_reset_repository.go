@@ -0,0 +1,82 @@
+package repositories
+
+import (
+	"time"
+
+	"github.com/r-52/embrace/models"
+	"gorm.io/gorm"
+)
+
+type QuotaResetRepository struct {
+	Database *gorm.DB
+}
+
+type QuotaResetRepositoryInterface interface {
+	// GetByID retrieves a quota reset record together with its entries by its ID.
+	// It takes an unsigned integer `id` as input and returns a pointer to a `models.QuotaReset` instance and an error.
+	GetByID(id uint) (*models.QuotaReset, error)
+
+	// Create inserts a new quota reset record and its entries into the database.
+	// It takes a pointer to a `models.QuotaReset` instance as input and returns an error.
+	Create(quotaReset *models.QuotaReset) error
+
+	// GetByQuotaID retrieves the resets of a quota ordered by period, the latest first.
+	// It takes an unsigned integer `quotaID` as input and returns a slice of `models.QuotaReset` instances and an error.
+	GetByQuotaID(quotaID uint) ([]models.QuotaReset, error)
+
+	// GetByQuotaIDAndPeriodStart retrieves the reset of a quota for the period starting at `periodStart`.
+	// It takes an unsigned integer `quotaID` and a time as input and returns a pointer to a `models.QuotaReset` instance and an error.
+	GetByQuotaIDAndPeriodStart(quotaID uint, periodStart time.Time) (*models.QuotaReset, error)
+}
+
+// NewQuotaResetRepository creates a new instance of QuotaResetRepository with the provided database connection.
+// It takes a *gorm.DB as an argument, which represents the database connection, and returns a pointer to a QuotaResetRepository.
+func NewQuotaResetRepository(db *gorm.DB) *QuotaResetRepository {
+	return &QuotaResetRepository{
+		Database: db,
+	}
+}
+
+// GetByID retrieves a quota reset record together with its entries by its ID.
+// If the quota reset with the specified ID is not found or if there is a database error, it returns a non-nil error.
+func (r *QuotaResetRepository) GetByID(id uint) (*models.QuotaReset, error) {
+	var quotaReset models.QuotaReset
+	err := r.Database.Preload("Entries").First(&quotaReset, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &quotaReset, nil
+}
+
+// Create inserts a new quota reset record and its entries into the database.
+// If the period of the quota was already reset, it returns gorm.ErrDuplicatedKey when the
+// database was opened with TranslateError. Otherwise, if the create operation fails, it returns a non-nil error.
+func (r *QuotaResetRepository) Create(quotaReset *models.QuotaReset) error {
+	err := r.Database.Create(quotaReset).Error
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// GetByQuotaID retrieves the resets of a quota ordered by period, the latest first.
+// If there is a database error, it returns a non-nil error.
+func (r *QuotaResetRepository) GetByQuotaID(quotaID uint) ([]models.QuotaReset, error) {
+	var quotaResets []models.QuotaReset
+	err := r.Database.Preload("Entries").Where("quota_id = ?", quotaID).Order("period_start DESC").Find(&quotaResets).Error
+	if err != nil {
+		return nil, err
+	}
+	return quotaResets, nil
+}
+
+// GetByQuotaIDAndPeriodStart retrieves the reset of a quota for the period starting at `periodStart`.
+// If the period was not reset yet or if there is a database error, it returns a non-nil error.
+func (r *QuotaResetRepository) GetByQuotaIDAndPeriodStart(quotaID uint, periodStart time.Time) (*models.QuotaReset, error) {
+	var quotaReset models.QuotaReset
+	err := r.Database.Where("quota_id = ? AND period_start = ?", quotaID, periodStart).First(&quotaReset).Error
+	if err != nil {
+		return nil, err
+	}
+	return &quotaReset, nil
+}
@@ -0,0 +1,86 @@
+package repositories_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/r-52/embrace/models"
+	"github.com/r-52/embrace/repositories"
+	"gorm.io/gorm"
+)
+
+// setupQuotaResetTestDB initializes the database for testing using the common setup method.
+func setupQuotaResetTestDB(t *testing.T) *gorm.DB {
+	db := GetDatabase() // Use the method from common_test.go
+
+	// Auto-migrate the QuotaReset models
+	err := db.AutoMigrate(&models.QuotaReset{}, &models.UserQuotaReset{})
+	if err != nil {
+		t.Fatalf("failed to migrate database: %v", err)
+	}
+
+	return db
+}
+
+func TestQuotaResetRepository_Create_And_GetByID(t *testing.T) {
+	db := setupQuotaResetTestDB(t)
+	repo := repositories.NewQuotaResetRepository(db)
+
+	reset := &models.QuotaReset{
+		QuotaID:     1,
+		PeriodStart: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+		ExecutedAt:  time.Now(),
+		Entries:     []models.UserQuotaReset{{UserQuotaID: 1, UserID: 1, PreviousCount: 3, Count: 30}},
+	}
+	if err := repo.Create(reset); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	result, err := repo.GetByID(reset.ID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(result.Entries) != 1 || result.Entries[0].PreviousCount != 3 || result.Entries[0].Count != 30 {
+		t.Errorf("expected the entry to be loaded, got %v", result.Entries)
+	}
+
+	// Test that a period can only be reset once
+	duplicate := &models.QuotaReset{QuotaID: 1, PeriodStart: reset.PeriodStart, ExecutedAt: time.Now()}
+	if err := repo.Create(duplicate); !errors.Is(err, gorm.ErrDuplicatedKey) {
+		t.Errorf("expected ErrDuplicatedKey, got %v", err)
+	}
+}
+
+func TestQuotaResetRepository_GetByQuotaID(t *testing.T) {
+	db := setupQuotaResetTestDB(t)
+	repo := repositories.NewQuotaResetRepository(db)
+
+	january := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	february := time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC)
+	db.Create(&models.QuotaReset{QuotaID: 1, PeriodStart: january, ExecutedAt: january})
+	db.Create(&models.QuotaReset{QuotaID: 1, PeriodStart: february, ExecutedAt: february})
+	db.Create(&models.QuotaReset{QuotaID: 2, PeriodStart: february, ExecutedAt: february})
+
+	results, err := repo.GetByQuotaID(1)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(results) != 2 || !results[0].PeriodStart.Equal(february) {
+		t.Errorf("expected 2 resets with the latest first, got %v", results)
+	}
+
+	result, err := repo.GetByQuotaIDAndPeriodStart(1, january)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.QuotaID != 1 || !result.PeriodStart.Equal(january) {
+		t.Errorf("expected the january reset, got %v", result)
+	}
+
+	// Test retrieving a period that was not reset
+	_, err = repo.GetByQuotaIDAndPeriodStart(2, january)
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		t.Errorf("expected ErrRecordNotFound, got %v", err)
+	}
+}
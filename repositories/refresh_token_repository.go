@@ -0,0 +1,123 @@
+package repositories
+
+import (
+	"time"
+
+	"github.com/r-52/embrace/models"
+	"gorm.io/gorm"
+)
+
+type RefreshTokenRepository struct {
+	Database *gorm.DB
+}
+
+type RefreshTokenRepositoryInterface interface {
+	// GetByTokenID retrieves a refresh token record by its token ID (the `jti` claim).
+	// It takes a string `tokenID` as input and returns a pointer to a `models.RefreshToken` instance and an error.
+	GetByTokenID(tokenID string) (*models.RefreshToken, error)
+
+	// Create inserts a new refresh token record into the database.
+	// It takes a pointer to a `models.RefreshToken` instance as input and returns an error.
+	Create(refreshToken *models.RefreshToken) error
+
+	// Update updates an existing refresh token record in the database.
+	// It takes a pointer to a `models.RefreshToken` instance as input and returns an error.
+	Update(refreshToken *models.RefreshToken) error
+
+	// Rotate revokes a still active refresh token and records the token that replaces it.
+	// It takes the strings `tokenID` and `replacedByTokenID` and the revocation time as input and returns an error.
+	Rotate(tokenID, replacedByTokenID string, revokedAt time.Time) error
+
+	// RevokeFamily revokes every still active refresh token of a rotation family.
+	// It takes a string `familyID` and the revocation time as input and returns an error.
+	RevokeFamily(familyID string, revokedAt time.Time) error
+
+	// RevokeByUserID revokes every still active refresh token of a user.
+	// It takes an unsigned integer `userID` and the revocation time as input and returns an error.
+	RevokeByUserID(userID uint, revokedAt time.Time) error
+}
+
+// NewRefreshTokenRepository creates a new instance of RefreshTokenRepository with the provided database connection.
+// It takes a *gorm.DB as an argument, which represents the database connection, and returns a pointer to a RefreshTokenRepository.
+func NewRefreshTokenRepository(db *gorm.DB) *RefreshTokenRepository {
+	return &RefreshTokenRepository{
+		Database: db,
+	}
+}
+
+// GetByTokenID retrieves a refresh token record by its token ID.
+// It takes a string `tokenID` as input and returns a pointer to a `models.RefreshToken` instance and an error.
+// If the token is not found or if there is a database error, it returns a non-nil error.
+func (r *RefreshTokenRepository) GetByTokenID(tokenID string) (*models.RefreshToken, error) {
+	var refreshToken models.RefreshToken
+	err := r.Database.Where("token_id = ?", tokenID).First(&refreshToken).Error
+	if err != nil {
+		return nil, err
+	}
+	return &refreshToken, nil
+}
+
+// Create inserts a new refresh token record into the database.
+// It takes a pointer to a `models.RefreshToken` instance as input and returns an error.
+// If the create operation fails, it returns a non-nil error.
+func (r *RefreshTokenRepository) Create(refreshToken *models.RefreshToken) error {
+	err := r.Database.Create(refreshToken).Error
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// Update updates an existing refresh token record in the database.
+// It takes a pointer to a `models.RefreshToken` instance as input and returns an error.
+// If the update operation fails, it returns a non-nil error.
+func (r *RefreshTokenRepository) Update(refreshToken *models.RefreshToken) error {
+	err := r.Database.Save(refreshToken).Error
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// Rotate revokes a still active refresh token and records the token that replaces it.
+// Only one of concurrent rotations of the same token succeeds, the others return gorm.ErrRecordNotFound,
+// as they do if the token does not exist or was already revoked.
+func (r *RefreshTokenRepository) Rotate(tokenID, replacedByTokenID string, revokedAt time.Time) error {
+	result := r.Database.Model(&models.RefreshToken{}).
+		Where("token_id = ? AND revoked_at IS NULL", tokenID).
+		Updates(map[string]interface{}{
+			"revoked_at":           revokedAt,
+			"replaced_by_token_id": replacedByTokenID,
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// RevokeFamily revokes every still active refresh token of a rotation family.
+// If the update operation fails, it returns a non-nil error.
+func (r *RefreshTokenRepository) RevokeFamily(familyID string, revokedAt time.Time) error {
+	err := r.Database.Model(&models.RefreshToken{}).
+		Where("family_id = ? AND revoked_at IS NULL", familyID).
+		Update("revoked_at", revokedAt).Error
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// RevokeByUserID revokes every still active refresh token of a user.
+// If the update operation fails, it returns a non-nil error.
+func (r *RefreshTokenRepository) RevokeByUserID(userID uint, revokedAt time.Time) error {
+	err := r.Database.Model(&models.RefreshToken{}).
+		Where("user_id = ? AND revoked_at IS NULL", userID).
+		Update("revoked_at", revokedAt).Error
+	if err != nil {
+		return err
+	}
+	return nil
+}
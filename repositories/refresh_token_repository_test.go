@@ -0,0 +1,120 @@
+package repositories_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/r-52/embrace/models"
+	"github.com/r-52/embrace/repositories"
+	"gorm.io/gorm"
+)
+
+// setupRefreshTokenTestDB initializes the database for testing using the common setup method.
+func setupRefreshTokenTestDB(t *testing.T) *gorm.DB {
+	db := GetDatabase() // Use the method from common_test.go
+
+	// Auto-migrate the RefreshToken model
+	err := db.AutoMigrate(&models.RefreshToken{})
+	if err != nil {
+		t.Fatalf("failed to migrate database: %v", err)
+	}
+
+	return db
+}
+
+func TestRefreshTokenRepository_GetByTokenID(t *testing.T) {
+	db := setupRefreshTokenTestDB(t)
+	repo := repositories.NewRefreshTokenRepository(db)
+
+	// Insert a test token
+	token := &models.RefreshToken{TokenID: "abc", FamilyID: "family", UserID: 1, ExpiresAt: time.Now().Add(time.Hour)}
+	db.Create(token)
+
+	// Test retrieving the token by token ID
+	result, err := repo.GetByTokenID("abc")
+	if err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if result.ID != token.ID || !result.IsActive(time.Now()) {
+		t.Errorf("expected %v, got %v", token, result)
+	}
+
+	// Test retrieving a non-existent token
+	_, err = repo.GetByTokenID("unknown")
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		t.Errorf("expected ErrRecordNotFound, got %v", err)
+	}
+}
+
+func TestRefreshTokenRepository_Rotate(t *testing.T) {
+	db := setupRefreshTokenTestDB(t)
+	repo := repositories.NewRefreshTokenRepository(db)
+
+	// Insert a test token
+	db.Create(&models.RefreshToken{TokenID: "a", FamilyID: "family", UserID: 1, ExpiresAt: time.Now().Add(time.Hour)})
+
+	// Test rotating the token
+	if err := repo.Rotate("a", "b", time.Now()); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	result, _ := repo.GetByTokenID("a")
+	if result.IsActive(time.Now()) || result.ReplacedByTokenID != "b" {
+		t.Errorf("expected a revoked token replaced by b, got %v", result)
+	}
+
+	// Test rotating the token again and rotating a non-existent token
+	for _, tokenID := range []string{"a", "unknown"} {
+		if err := repo.Rotate(tokenID, "c", time.Now()); !errors.Is(err, gorm.ErrRecordNotFound) {
+			t.Errorf("expected ErrRecordNotFound for %s, got %v", tokenID, err)
+		}
+	}
+	result, _ = repo.GetByTokenID("a")
+	if result.ReplacedByTokenID != "b" {
+		t.Errorf("expected the first rotation to be kept, got %v", result)
+	}
+}
+
+func TestRefreshTokenRepository_RevokeFamily(t *testing.T) {
+	db := setupRefreshTokenTestDB(t)
+	repo := repositories.NewRefreshTokenRepository(db)
+
+	// Insert two tokens of one family and one of another
+	expiresAt := time.Now().Add(time.Hour)
+	db.Create(&models.RefreshToken{TokenID: "a", FamilyID: "family", UserID: 1, ExpiresAt: expiresAt})
+	db.Create(&models.RefreshToken{TokenID: "b", FamilyID: "family", UserID: 1, ExpiresAt: expiresAt})
+	db.Create(&models.RefreshToken{TokenID: "c", FamilyID: "other", UserID: 1, ExpiresAt: expiresAt})
+
+	err := repo.RevokeFamily("family", time.Now())
+	if err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	for tokenID, active := range map[string]bool{"a": false, "b": false, "c": true} {
+		result, _ := repo.GetByTokenID(tokenID)
+		if result.IsActive(time.Now()) != active {
+			t.Errorf("expected token %s active=%v", tokenID, active)
+		}
+	}
+}
+
+func TestRefreshTokenRepository_RevokeByUserID(t *testing.T) {
+	db := setupRefreshTokenTestDB(t)
+	repo := repositories.NewRefreshTokenRepository(db)
+
+	// Insert tokens of two users
+	expiresAt := time.Now().Add(time.Hour)
+	db.Create(&models.RefreshToken{TokenID: "a", FamilyID: "one", UserID: 1, ExpiresAt: expiresAt})
+	db.Create(&models.RefreshToken{TokenID: "b", FamilyID: "two", UserID: 2, ExpiresAt: expiresAt})
+
+	err := repo.RevokeByUserID(1, time.Now())
+	if err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	first, _ := repo.GetByTokenID("a")
+	second, _ := repo.GetByTokenID("b")
+	if first.IsActive(time.Now()) || !second.IsActive(time.Now()) {
+		t.Errorf("expected only the first user's token to be revoked")
+	}
+}
@@ -0,0 +1,228 @@
+package repositories
+
+import (
+	"context"
+	"reflect"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type tenantContextKey struct{}
+
+// tenantParent describes how rows of a table without a company_id column belong to a company:
+// `column` of the table references `parentColumn` of `parentTable`, which has a company_id column.
+type tenantParent struct {
+	column       string
+	parentTable  string
+	parentColumn string
+}
+
+// tenantParents lists the tables that are scoped through another table.
+// Tables that neither have a company_id column nor are listed here are not tenant specific.
+// Tables with a company_id column are listed when new rows must also reference a parent of the same company.
+var tenantParents = map[string]tenantParent{
+	"user_profiles":            {column: "id", parentTable: "users", parentColumn: "user_profile_id"},
+	"user_quota":               {column: "user_id", parentTable: "users", parentColumn: "id"},
+	"role_permissions":         {column: "user_role_id", parentTable: "user_roles", parentColumn: "id"},
+	"refresh_tokens":           {column: "user_id", parentTable: "users", parentColumn: "id"},
+	"user_quota_resets":        {column: "quota_reset_id", parentTable: "quota_resets", parentColumn: "id"},
+	"time_entries":             {column: "user_id", parentTable: "users", parentColumn: "id"},
+	"leave_requests":           {column: "user_id", parentTable: "users", parentColumn: "id"},
+	"holidays":                 {column: "holiday_calendar_id", parentTable: "holiday_calendars", parentColumn: "id"},
+	"work_schedule_days":       {column: "work_schedule_id", parentTable: "work_schedules", parentColumn: "id"},
+	"user_work_schedules":      {column: "user_id", parentTable: "users", parentColumn: "id"},
+	"overtime_work_time_types": {column: "overtime_policy_id", parentTable: "overtime_policies", parentColumn: "id"},
+	"overtime_settlements":     {column: "user_id", parentTable: "users", parentColumn: "id"},
+	"compliance_rules":         {column: "compliance_rule_set_id", parentTable: "compliance_rule_sets", parentColumn: "id"},
+	"timesheet_periods":        {column: "user_id", parentTable: "users", parentColumn: "id"},
+}
+
+// WithTenant returns a session of db that is scoped to a single company.
+// Every repository created from the session only reads, updates and deletes rows of that company,
+// and new rows are assigned to it. Rows of other companies behave as if they did not exist,
+// so accessing them fails with gorm.ErrRecordNotFound.
+// The scope is kept in the statement context and therefore survives transactions and preloads.
+// Raw SQL is not scoped.
+func WithTenant(db *gorm.DB, companyID uint) *gorm.DB {
+	return db.WithContext(context.WithValue(db.Statement.Context, tenantContextKey{}, companyID))
+}
+
+// TenantFromContext returns the company ID a context was scoped to by WithTenant.
+func TenantFromContext(ctx context.Context) (uint, bool) {
+	if ctx == nil {
+		return 0, false
+	}
+	companyID, ok := ctx.Value(tenantContextKey{}).(uint)
+	return companyID, ok
+}
+
+// RegisterTenantCallbacks installs the callbacks that enforce WithTenant.
+// It has to be called once for every database connection before tenant scoped sessions are used.
+func RegisterTenantCallbacks(db *gorm.DB) error {
+	callbacks := db.Callback()
+	if err := callbacks.Create().Before("gorm:create").Register("tenant:create", tenantCreate); err != nil {
+		return err
+	}
+	if err := callbacks.Query().Before("gorm:query").Register("tenant:query", tenantWhere); err != nil {
+		return err
+	}
+	if err := callbacks.Update().Before("gorm:update").Register("tenant:update", tenantUpdate); err != nil {
+		return err
+	}
+	if err := callbacks.Delete().Before("gorm:delete").Register("tenant:delete", tenantWhere); err != nil {
+		return err
+	}
+	return callbacks.Row().Before("gorm:row").Register("tenant:row", tenantWhere)
+}
+
+func tenantOf(db *gorm.DB) (uint, bool) {
+	if db.Statement.Schema == nil || db.Statement.SQL.Len() > 0 {
+		return 0, false
+	}
+	return TenantFromContext(db.Statement.Context)
+}
+
+func tenantWhere(db *gorm.DB) {
+	companyID, ok := tenantOf(db)
+	if !ok || db.Error != nil {
+		return
+	}
+	if expression := tenantCondition(db.Statement, companyID); expression != nil {
+		db.Statement.AddClause(clause.Where{Exprs: []clause.Expression{expression}})
+	}
+}
+
+func tenantCondition(stmt *gorm.Statement, companyID uint) clause.Expression {
+	if stmt.Table == "companies" {
+		return clause.Eq{Column: clause.Column{Table: clause.CurrentTable, Name: "id"}, Value: companyID}
+	}
+	if stmt.Schema.LookUpField("CompanyID") != nil {
+		return clause.Eq{Column: clause.Column{Table: clause.CurrentTable, Name: "company_id"}, Value: companyID}
+	}
+	if parent, ok := tenantParents[stmt.Table]; ok {
+		return clause.Expr{
+			SQL: "? IN (SELECT ? FROM ? WHERE company_id = ?)",
+			Vars: []interface{}{
+				clause.Column{Table: clause.CurrentTable, Name: parent.column},
+				clause.Column{Name: parent.parentColumn},
+				clause.Table{Name: parent.parentTable},
+				companyID,
+			},
+		}
+	}
+	return nil
+}
+
+func tenantCreate(db *gorm.DB) {
+	companyID, ok := tenantOf(db)
+	if !ok || db.Error != nil {
+		return
+	}
+	stmt := db.Statement
+
+	// Save falls back to an upsert when the update matched no row. Within a tenant that
+	// would overwrite a row of another company, so it is reported as not found instead.
+	if onConflict, ok := stmt.Clauses["ON CONFLICT"].Expression.(clause.OnConflict); ok && onConflict.UpdateAll {
+		db.AddError(gorm.ErrRecordNotFound)
+		return
+	}
+	if stmt.Table == "companies" {
+		db.AddError(gorm.ErrRecordNotFound)
+		return
+	}
+
+	if field := stmt.Schema.LookUpField("CompanyID"); field != nil {
+		eachRow(stmt.ReflectValue, func(row reflect.Value) {
+			value, zero := field.ValueOf(stmt.Context, row)
+			if zero {
+				db.AddError(field.Set(stmt.Context, row, companyID))
+				return
+			}
+			if value != companyID {
+				db.AddError(gorm.ErrRecordNotFound)
+			}
+		})
+		if db.Error != nil {
+			return
+		}
+	}
+
+	parent, ok := tenantParents[stmt.Table]
+	if !ok {
+		return
+	}
+	field := stmt.Schema.LookUpField(parent.column)
+	if field == nil {
+		return
+	}
+	parentIDs := map[interface{}]bool{}
+	eachRow(stmt.ReflectValue, func(row reflect.Value) {
+		if value, zero := field.ValueOf(stmt.Context, row); !zero {
+			parentIDs[value] = true
+		}
+	})
+	for parentID := range parentIDs {
+		var count int64
+		err := db.Session(&gorm.Session{NewDB: true}).
+			Table(parent.parentTable).
+			Where(clause.Eq{Column: clause.Column{Name: parent.parentColumn}, Value: parentID}).
+			Where("company_id = ?", companyID).
+			Count(&count).Error
+		if err != nil {
+			db.AddError(err)
+			return
+		}
+		if count == 0 {
+			db.AddError(gorm.ErrRecordNotFound)
+			return
+		}
+	}
+}
+
+func tenantUpdate(db *gorm.DB) {
+	companyID, ok := tenantOf(db)
+	if !ok || db.Error != nil {
+		return
+	}
+	stmt := db.Statement
+
+	// Save writes every column, so a zero company ID is filled in and moving
+	// a row to another company is rejected.
+	if field := stmt.Schema.LookUpField("CompanyID"); field != nil && stmt.Table != "companies" {
+		switch dest := stmt.Dest.(type) {
+		case map[string]interface{}:
+			for _, key := range []string{"company_id", "CompanyID"} {
+				if value, exists := dest[key]; exists && value != companyID {
+					db.AddError(gorm.ErrRecordNotFound)
+					return
+				}
+			}
+		default:
+			destValue := reflect.Indirect(reflect.ValueOf(stmt.Dest))
+			if destValue.Kind() == reflect.Struct && destValue.Type() == stmt.Schema.ModelType {
+				value, zero := field.ValueOf(stmt.Context, destValue)
+				if zero {
+					db.AddError(field.Set(stmt.Context, destValue, companyID))
+				} else if value != companyID {
+					db.AddError(gorm.ErrRecordNotFound)
+					return
+				}
+			}
+		}
+	}
+
+	tenantWhere(db)
+}
+
+func eachRow(value reflect.Value, fn func(row reflect.Value)) {
+	value = reflect.Indirect(value)
+	switch value.Kind() {
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < value.Len(); i++ {
+			fn(reflect.Indirect(value.Index(i)))
+		}
+	case reflect.Struct:
+		fn(value)
+	}
+}
@@ -0,0 +1,348 @@
+package repositories_test
+
+import (
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/r-52/embrace/models"
+	"github.com/r-52/embrace/repositories"
+	"gorm.io/gorm"
+)
+
+// tenantFixture holds one row of every model for each of two companies.
+type tenantFixture struct {
+	own     map[string]uint
+	foreign map[string]uint
+}
+
+func setupTenantTestDB(t *testing.T) (*gorm.DB, *tenantFixture) {
+	db := GetDatabase() // Use the method from common_test.go
+
+	err := db.AutoMigrate(&models.Company{}, &models.User{}, &models.UserRole{}, &models.RolePermission{}, &models.UserProfile{},
+		&models.Quota{}, &models.UserQuota{}, &models.TimeEntryType{}, &models.TimeEntry{}, &models.RefreshToken{},
+		&models.QuotaReset{}, &models.UserQuotaReset{}, &models.UserQuotaCarryOver{}, &models.UserQuotaTransaction{}, &models.LeaveRequest{},
+		&models.HolidayCalendar{}, &models.Holiday{}, &models.WorkSchedule{}, &models.WorkScheduleDay{}, &models.UserWorkSchedule{},
+		&models.OvertimePolicy{}, &models.OvertimeWorkTimeType{}, &models.OvertimeSettlement{}, &models.ComplianceRuleSet{}, &models.ComplianceRule{},
+		&models.DurationPolicy{}, &models.TimesheetPeriod{}, &models.TimeEntryAudit{}, &models.AuditLog{})
+	if err != nil {
+		t.Fatalf("failed to migrate database: %v", err)
+	}
+
+	fixture := &tenantFixture{own: map[string]uint{}, foreign: map[string]uint{}}
+	for i, ids := range []map[string]uint{fixture.own, fixture.foreign} {
+		suffix := string(rune('a' + i))
+		company := &models.Company{Name: "Company " + suffix, PrimaryEmail: suffix + "@company.com"}
+		mustCreate(t, db, company)
+		role := &models.UserRole{Name: "admin", CompanyID: company.ID}
+		mustCreate(t, db, role)
+		permission := &models.RolePermission{UserRoleID: role.ID, Permission: models.PERMISSION_USERS_READ}
+		mustCreate(t, db, permission)
+		user := &models.User{
+			Email:       suffix + "@user.com",
+			CompanyID:   company.ID,
+			RoleID:      role.ID,
+			UserProfile: models.UserProfile{Slug: "user-" + suffix},
+		}
+		mustCreate(t, db, user)
+		quota := &models.Quota{Name: "Vacation " + suffix, CompanyID: company.ID, Count: 30}
+		mustCreate(t, db, quota)
+		userQuota := &models.UserQuota{UserID: user.ID, QuotaID: quota.ID, Count: 30}
+		mustCreate(t, db, userQuota)
+		timeEntryType := &models.TimeEntryType{Name: "Work " + suffix, CompanyID: company.ID}
+		mustCreate(t, db, timeEntryType)
+		timeEntry := &models.TimeEntry{StartTime: time.Now(), CompanyID: company.ID, UserID: user.ID, TimeEntryTypeID: timeEntryType.ID}
+		mustCreate(t, db, timeEntry)
+		refreshToken := &models.RefreshToken{TokenID: "token-" + suffix, FamilyID: suffix, UserID: user.ID, ExpiresAt: time.Now().Add(time.Hour)}
+		mustCreate(t, db, refreshToken)
+		quotaReset := &models.QuotaReset{
+			QuotaID:     quota.ID,
+			PeriodStart: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+			ExecutedAt:  time.Now(),
+			CompanyID:   company.ID,
+			Entries:     []models.UserQuotaReset{{UserQuotaID: userQuota.ID, UserID: user.ID, PreviousCount: 2, Count: 30}},
+		}
+		mustCreate(t, db, quotaReset)
+		carryOver := &models.UserQuotaCarryOver{UserQuotaID: userQuota.ID, QuotaResetID: quotaReset.ID, CompanyID: company.ID, Carried: 2, Remaining: 2}
+		mustCreate(t, db, carryOver)
+		transaction := &models.UserQuotaTransaction{UserQuotaID: userQuota.ID, UserID: user.ID, CompanyID: company.ID, Kind: models.QUOTA_TRANSACTION_ALLOCATION, Amount: 2, BookedAt: time.Now()}
+		mustCreate(t, db, transaction)
+		leaveRequest := &models.LeaveRequest{CompanyID: company.ID, UserID: user.ID, TimeEntryTypeID: timeEntryType.ID, StartDate: time.Now(), EndDate: time.Now()}
+		mustCreate(t, db, leaveRequest)
+		holidayCalendar := &models.HolidayCalendar{CompanyID: company.ID, Name: "Calendar " + suffix, Region: "DE"}
+		mustCreate(t, db, holidayCalendar)
+		holiday := &models.Holiday{HolidayCalendarID: holidayCalendar.ID, Date: time.Date(2024, 12, 24, 0, 0, 0, 0, time.UTC), Name: "Heiligabend"}
+		mustCreate(t, db, holiday)
+		workSchedule := &models.WorkSchedule{CompanyID: company.ID, Name: "Schedule " + suffix, Kind: models.WORK_SCHEDULE_WEEKDAYS,
+			Days: []models.WorkScheduleDay{{Weekday: time.Monday, Hours: 8}}}
+		mustCreate(t, db, workSchedule)
+		userWorkSchedule := &models.UserWorkSchedule{CompanyID: company.ID, UserID: user.ID, WorkScheduleID: workSchedule.ID, ValidFrom: time.Now()}
+		mustCreate(t, db, userWorkSchedule)
+		overtimePolicy := &models.OvertimePolicy{CompanyID: company.ID, QuotaID: quota.ID,
+			WorkTimeTypes: []models.OvertimeWorkTimeType{{TimeEntryTypeID: timeEntryType.ID}}}
+		mustCreate(t, db, overtimePolicy)
+		overtimeSettlement := &models.OvertimeSettlement{CompanyID: company.ID, UserID: user.ID, Month: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)}
+		mustCreate(t, db, overtimeSettlement)
+		complianceRuleSet := &models.ComplianceRuleSet{CompanyID: company.ID,
+			Rules: []models.ComplianceRule{{Rule: models.COMPLIANCE_RULE_BREAKS, Enabled: true, Severity: models.COMPLIANCE_SEVERITY_BLOCKING}}}
+		mustCreate(t, db, complianceRuleSet)
+		durationPolicy := &models.DurationPolicy{CompanyID: company.ID, DeductBreaks: true}
+		mustCreate(t, db, durationPolicy)
+		timesheetPeriod := &models.TimesheetPeriod{CompanyID: company.ID, UserID: user.ID, Month: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)}
+		mustCreate(t, db, timesheetPeriod)
+		timeEntryAudit := &models.TimeEntryAudit{CompanyID: company.ID, Sequence: 1, TimeEntryID: timeEntry.ID, UserID: user.ID,
+			Operation: models.AUDIT_OPERATION_CREATE, ActorID: user.ID, RecordedAt: time.Now()}
+		mustCreate(t, db, timeEntryAudit)
+		auditLog := &models.AuditLog{CompanyID: company.ID, Entity: "user_roles", EntityID: role.ID, Operation: models.AUDIT_OPERATION_CREATE,
+			RecordedAt: time.Now(), Diff: "{}"}
+		mustCreate(t, db, auditLog)
+
+		ids["companies"] = company.ID
+		ids["user_roles"] = role.ID
+		ids["role_permissions"] = permission.ID
+		ids["users"] = user.ID
+		ids["user_profiles"] = user.UserProfileID
+		ids["quota"] = quota.ID
+		ids["user_quota"] = userQuota.ID
+		ids["time_entry_types"] = timeEntryType.ID
+		ids["time_entries"] = timeEntry.ID
+		ids["refresh_tokens"] = refreshToken.ID
+		ids["quota_resets"] = quotaReset.ID
+		ids["user_quota_resets"] = quotaReset.Entries[0].ID
+		ids["user_quota_carry_overs"] = carryOver.ID
+		ids["user_quota_transactions"] = transaction.ID
+		ids["leave_requests"] = leaveRequest.ID
+		ids["holiday_calendars"] = holidayCalendar.ID
+		ids["holidays"] = holiday.ID
+		ids["work_schedules"] = workSchedule.ID
+		ids["work_schedule_days"] = workSchedule.Days[0].ID
+		ids["user_work_schedules"] = userWorkSchedule.ID
+		ids["overtime_policies"] = overtimePolicy.ID
+		ids["overtime_work_time_types"] = overtimePolicy.WorkTimeTypes[0].ID
+		ids["overtime_settlements"] = overtimeSettlement.ID
+		ids["compliance_rule_sets"] = complianceRuleSet.ID
+		ids["compliance_rules"] = complianceRuleSet.Rules[0].ID
+		ids["duration_policies"] = durationPolicy.ID
+		ids["timesheet_periods"] = timesheetPeriod.ID
+		ids["time_entry_audits"] = timeEntryAudit.ID
+		ids["audit_logs"] = auditLog.ID
+	}
+	return db, fixture
+}
+
+func mustCreate(t *testing.T, db *gorm.DB, value interface{}) {
+	t.Helper()
+	if err := db.Create(value).Error; err != nil {
+		t.Fatalf("failed to seed %T: %v", value, err)
+	}
+}
+
+// tenantModels returns a constructor for every tenant specific model, keyed by table name.
+func tenantModels() map[string]func() interface{} {
+	return map[string]func() interface{}{
+		"companies":                func() interface{} { return &models.Company{} },
+		"user_roles":               func() interface{} { return &models.UserRole{} },
+		"role_permissions":         func() interface{} { return &models.RolePermission{} },
+		"users":                    func() interface{} { return &models.User{} },
+		"user_profiles":            func() interface{} { return &models.UserProfile{} },
+		"quota":                    func() interface{} { return &models.Quota{} },
+		"user_quota":               func() interface{} { return &models.UserQuota{} },
+		"time_entry_types":         func() interface{} { return &models.TimeEntryType{} },
+		"time_entries":             func() interface{} { return &models.TimeEntry{} },
+		"refresh_tokens":           func() interface{} { return &models.RefreshToken{} },
+		"quota_resets":             func() interface{} { return &models.QuotaReset{} },
+		"user_quota_resets":        func() interface{} { return &models.UserQuotaReset{} },
+		"user_quota_carry_overs":   func() interface{} { return &models.UserQuotaCarryOver{} },
+		"user_quota_transactions":  func() interface{} { return &models.UserQuotaTransaction{} },
+		"leave_requests":           func() interface{} { return &models.LeaveRequest{} },
+		"holiday_calendars":        func() interface{} { return &models.HolidayCalendar{} },
+		"holidays":                 func() interface{} { return &models.Holiday{} },
+		"work_schedules":           func() interface{} { return &models.WorkSchedule{} },
+		"work_schedule_days":       func() interface{} { return &models.WorkScheduleDay{} },
+		"user_work_schedules":      func() interface{} { return &models.UserWorkSchedule{} },
+		"overtime_policies":        func() interface{} { return &models.OvertimePolicy{} },
+		"overtime_work_time_types": func() interface{} { return &models.OvertimeWorkTimeType{} },
+		"overtime_settlements":     func() interface{} { return &models.OvertimeSettlement{} },
+		"compliance_rule_sets":     func() interface{} { return &models.ComplianceRuleSet{} },
+		"compliance_rules":         func() interface{} { return &models.ComplianceRule{} },
+		"duration_policies":        func() interface{} { return &models.DurationPolicy{} },
+		"timesheet_periods":        func() interface{} { return &models.TimesheetPeriod{} },
+		"time_entry_audits":        func() interface{} { return &models.TimeEntryAudit{} },
+		"audit_logs":               func() interface{} { return &models.AuditLog{} },
+	}
+}
+
+func TestTenant_Isolates_Reads_For_Every_Model(t *testing.T) {
+	db, fixture := setupTenantTestDB(t)
+	tenant := repositories.WithTenant(db, fixture.own["companies"])
+
+	for table, newModel := range tenantModels() {
+		if err := tenant.First(newModel(), fixture.own[table]).Error; err != nil {
+			t.Errorf("%s: expected own row to be visible, got %v", table, err)
+		}
+		if err := tenant.First(newModel(), fixture.foreign[table]).Error; !errors.Is(err, gorm.ErrRecordNotFound) {
+			t.Errorf("%s: expected foreign row to be not found, got %v", table, err)
+		}
+
+		var count int64
+		if err := tenant.Model(newModel()).Count(&count).Error; err != nil {
+			t.Errorf("%s: unexpected error: %v", table, err)
+		}
+		if count != 1 {
+			t.Errorf("%s: expected 1 visible row, got %d", table, count)
+		}
+	}
+}
+
+func TestTenant_Isolates_Deletes_For_Every_Model(t *testing.T) {
+	db, fixture := setupTenantTestDB(t)
+	tenant := repositories.WithTenant(db, fixture.own["companies"])
+
+	for table, newModel := range tenantModels() {
+		result := tenant.Where("id = ?", fixture.foreign[table]).Delete(newModel())
+		if result.Error != nil {
+			t.Errorf("%s: unexpected error: %v", table, result.Error)
+		}
+		if result.RowsAffected != 0 {
+			t.Errorf("%s: expected foreign row not to be deleted, deleted %d", table, result.RowsAffected)
+		}
+		if err := db.First(newModel(), fixture.foreign[table]).Error; err != nil {
+			t.Errorf("%s: expected foreign row to still exist, got %v", table, err)
+		}
+	}
+}
+
+func TestTenant_Isolates_Updates_For_Every_Model(t *testing.T) {
+	db, fixture := setupTenantTestDB(t)
+	tenant := repositories.WithTenant(db, fixture.own["companies"])
+
+	for table, newModel := range tenantModels() {
+		result := tenant.Model(newModel()).Where("id = ?", fixture.foreign[table]).Update("updated_at", time.Unix(0, 0))
+		if result.Error != nil {
+			t.Errorf("%s: unexpected error: %v", table, result.Error)
+		}
+		if result.RowsAffected != 0 {
+			t.Errorf("%s: expected foreign row not to be updated, updated %d", table, result.RowsAffected)
+		}
+	}
+}
+
+func TestTenant_Repositories_Return_Not_Found_Across_Tenants(t *testing.T) {
+	db, fixture := setupTenantTestDB(t)
+	uow := repositories.NewUnitOfWork(db).ForTenant(fixture.own["companies"])
+
+	if _, err := uow.Companies().GetByID(fixture.foreign["companies"]); !errors.Is(err, gorm.ErrRecordNotFound) {
+		t.Errorf("companies: expected ErrRecordNotFound, got %v", err)
+	}
+	if _, err := uow.Users().GetByEmail("b@user.com"); !errors.Is(err, gorm.ErrRecordNotFound) {
+		t.Errorf("users: expected ErrRecordNotFound, got %v", err)
+	}
+	if _, err := uow.Users().GetPreloadedUserByID(fixture.foreign["users"]); !errors.Is(err, gorm.ErrRecordNotFound) {
+		t.Errorf("users: expected ErrRecordNotFound, got %v", err)
+	}
+	if err := uow.Users().Delete(fixture.foreign["users"]); !errors.Is(err, gorm.ErrRecordNotFound) {
+		t.Errorf("users: expected ErrRecordNotFound, got %v", err)
+	}
+	if err := uow.UserRoles().Delete(fixture.foreign["user_roles"]); !errors.Is(err, gorm.ErrRecordNotFound) {
+		t.Errorf("user_roles: expected ErrRecordNotFound, got %v", err)
+	}
+	if _, err := uow.UserProfiles().GetBySlug("user-b"); !errors.Is(err, gorm.ErrRecordNotFound) {
+		t.Errorf("user_profiles: expected ErrRecordNotFound, got %v", err)
+	}
+	if _, err := uow.Quotas().GetByID(fixture.foreign["quota"]); !errors.Is(err, gorm.ErrRecordNotFound) {
+		t.Errorf("quota: expected ErrRecordNotFound, got %v", err)
+	}
+	if quotas, _ := uow.Quotas().GetByCompanyID(fixture.foreign["companies"]); len(quotas) != 0 {
+		t.Errorf("quota: expected no foreign quotas, got %d", len(quotas))
+	}
+	if userQuotas, _ := uow.UserQuotas().GetByUserID(fixture.foreign["users"]); len(userQuotas) != 0 {
+		t.Errorf("user_quota: expected no foreign user quotas, got %d", len(userQuotas))
+	}
+	if _, err := uow.TimeEntryTypes().GetByID(fixture.foreign["time_entry_types"]); !errors.Is(err, gorm.ErrRecordNotFound) {
+		t.Errorf("time_entry_types: expected ErrRecordNotFound, got %v", err)
+	}
+	if _, err := uow.RefreshTokens().GetByTokenID("token-b"); !errors.Is(err, gorm.ErrRecordNotFound) {
+		t.Errorf("refresh_tokens: expected ErrRecordNotFound, got %v", err)
+	}
+
+	// The preloaded company and role belong to the own tenant.
+	user, err := uow.Users().GetByIDWithCompanyAndRole(fixture.own["users"])
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if user.Company.ID != fixture.own["companies"] || len(user.Role.Permissions) != 1 {
+		t.Errorf("unexpected preloaded user: %+v", user)
+	}
+}
+
+func TestTenant_Update_Of_Foreign_Row_Does_Not_Upsert(t *testing.T) {
+	db, fixture := setupTenantTestDB(t)
+	uow := repositories.NewUnitOfWork(db).ForTenant(fixture.own["companies"])
+
+	foreign, err := repositories.NewQuotaRepository(db).GetByID(fixture.foreign["quota"])
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	foreign.Name = "Hijacked"
+	if err := uow.Quotas().Update(foreign); !errors.Is(err, gorm.ErrRecordNotFound) {
+		t.Errorf("expected ErrRecordNotFound, got %v", err)
+	}
+
+	unchanged, _ := repositories.NewQuotaRepository(db).GetByID(fixture.foreign["quota"])
+	if unchanged.Name == "Hijacked" || unchanged.CompanyID != fixture.foreign["companies"] {
+		t.Errorf("expected foreign quota to be unchanged, got %+v", unchanged)
+	}
+
+	// Moving an own row to another company is rejected as well.
+	own, _ := uow.Quotas().GetByID(fixture.own["quota"])
+	own.CompanyID = fixture.foreign["companies"]
+	if err := uow.Quotas().Update(own); !errors.Is(err, gorm.ErrRecordNotFound) {
+		t.Errorf("expected ErrRecordNotFound, got %v", err)
+	}
+}
+
+func TestTenant_Create_Assigns_Company(t *testing.T) {
+	db, fixture := setupTenantTestDB(t)
+	uow := repositories.NewUnitOfWork(db).ForTenant(fixture.own["companies"])
+
+	quota := &models.Quota{Name: "Overtime", Count: 10}
+	if err := uow.Quotas().Create(quota); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if quota.CompanyID != fixture.own["companies"] {
+		t.Errorf("expected company %d to be assigned, got %d", fixture.own["companies"], quota.CompanyID)
+	}
+
+	foreignQuota := &models.Quota{Name: "Foreign", CompanyID: fixture.foreign["companies"]}
+	if err := uow.Quotas().Create(foreignQuota); !errors.Is(err, gorm.ErrRecordNotFound) {
+		t.Errorf("expected ErrRecordNotFound, got %v", err)
+	}
+
+	foreignUserQuota := &models.UserQuota{UserID: fixture.foreign["users"], QuotaID: quota.ID}
+	if err := uow.UserQuotas().Create(foreignUserQuota); !errors.Is(err, gorm.ErrRecordNotFound) {
+		t.Errorf("expected ErrRecordNotFound, got %v", err)
+	}
+
+	foreignEntry := &models.TimeEntry{StartTime: time.Now(), UserID: fixture.foreign["users"], EndTime: sql.NullTime{}}
+	if err := repositories.WithTenant(db, fixture.own["companies"]).Create(foreignEntry).Error; !errors.Is(err, gorm.ErrRecordNotFound) {
+		t.Errorf("expected ErrRecordNotFound, got %v", err)
+	}
+
+	if err := uow.Companies().Create(&models.Company{Name: "Another", PrimaryEmail: "another@company.com"}); !errors.Is(err, gorm.ErrRecordNotFound) {
+		t.Errorf("expected ErrRecordNotFound, got %v", err)
+	}
+}
+
+func TestTenant_Scope_Survives_Transactions(t *testing.T) {
+	db, fixture := setupTenantTestDB(t)
+	uow := repositories.NewUnitOfWork(db).ForTenant(fixture.own["companies"])
+
+	err := uow.Transaction(func(uow *repositories.UnitOfWork) error {
+		_, err := uow.Users().GetByID(fixture.foreign["users"])
+		return err
+	})
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		t.Errorf("expected ErrRecordNotFound, got %v", err)
+	}
+}
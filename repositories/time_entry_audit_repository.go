@@ -0,0 +1,94 @@
+package repositories
+
+import (
+	"github.com/r-52/embrace/models"
+	"gorm.io/gorm"
+)
+
+type TimeEntryAuditRepository struct {
+	Database *gorm.DB
+}
+
+type TimeEntryAuditRepositoryInterface interface {
+	// Create appends a new audit record to the database.
+	// It takes a pointer to a `models.TimeEntryAudit` instance as input and returns an error.
+	Create(audit *models.TimeEntryAudit) error
+
+	// GetLatestByCompanyID retrieves the audit record of a company with the highest sequence number.
+	// It takes an unsigned integer `companyID` as input and returns a pointer to a `models.TimeEntryAudit` instance and an error.
+	GetLatestByCompanyID(companyID uint) (*models.TimeEntryAudit, error)
+
+	// GetByTimeEntryID retrieves the audit records of a time entry ordered by sequence number.
+	// It takes an unsigned integer `timeEntryID` as input and returns a slice of `models.TimeEntryAudit` instances and an error.
+	GetByTimeEntryID(timeEntryID uint) ([]models.TimeEntryAudit, error)
+
+	// GetByCompanyID retrieves the audit records of a company ordered by sequence number.
+	// It takes an unsigned integer `companyID` as input and returns a slice of `models.TimeEntryAudit` instances and an error.
+	GetByCompanyID(companyID uint) ([]models.TimeEntryAudit, error)
+
+	// GetCompanyIDs retrieves the IDs of the companies with audit records in ascending order.
+	// It returns a slice of unsigned integers and an error.
+	GetCompanyIDs() ([]uint, error)
+}
+
+// NewTimeEntryAuditRepository creates a new instance of TimeEntryAuditRepository with the provided database connection.
+// It takes a *gorm.DB as an argument, which represents the database connection, and returns a pointer to a TimeEntryAuditRepository.
+func NewTimeEntryAuditRepository(db *gorm.DB) *TimeEntryAuditRepository {
+	return &TimeEntryAuditRepository{
+		Database: db,
+	}
+}
+
+// Create appends a new audit record to the database. Records are never updated or deleted.
+// If the company has a record with the same sequence number, it returns gorm.ErrDuplicatedKey.
+func (r *TimeEntryAuditRepository) Create(audit *models.TimeEntryAudit) error {
+	err := r.Database.Create(audit).Error
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// GetLatestByCompanyID retrieves the audit record of a company with the highest sequence number.
+// If the company has no records or if there is a database error, it returns a non-nil error.
+func (r *TimeEntryAuditRepository) GetLatestByCompanyID(companyID uint) (*models.TimeEntryAudit, error) {
+	var audit models.TimeEntryAudit
+	err := r.Database.Where("company_id = ?", companyID).Order("sequence DESC").First(&audit).Error
+	if err != nil {
+		return nil, err
+	}
+	return &audit, nil
+}
+
+// GetByTimeEntryID retrieves the audit records of a time entry ordered by sequence number.
+// If there is a database error, it returns a non-nil error.
+func (r *TimeEntryAuditRepository) GetByTimeEntryID(timeEntryID uint) ([]models.TimeEntryAudit, error) {
+	var audits []models.TimeEntryAudit
+	err := r.Database.Where("time_entry_id = ?", timeEntryID).Order("sequence").Find(&audits).Error
+	if err != nil {
+		return nil, err
+	}
+	return audits, nil
+}
+
+// GetByCompanyID retrieves the audit records of a company ordered by sequence number.
+// If there is a database error, it returns a non-nil error.
+func (r *TimeEntryAuditRepository) GetByCompanyID(companyID uint) ([]models.TimeEntryAudit, error) {
+	var audits []models.TimeEntryAudit
+	err := r.Database.Where("company_id = ?", companyID).Order("sequence").Find(&audits).Error
+	if err != nil {
+		return nil, err
+	}
+	return audits, nil
+}
+
+// GetCompanyIDs retrieves the IDs of the companies with audit records in ascending order.
+// If there is a database error, it returns a non-nil error.
+func (r *TimeEntryAuditRepository) GetCompanyIDs() ([]uint, error) {
+	var companyIDs []uint
+	err := r.Database.Model(&models.TimeEntryAudit{}).Distinct("company_id").Order("company_id").Pluck("company_id", &companyIDs).Error
+	if err != nil {
+		return nil, err
+	}
+	return companyIDs, nil
+}
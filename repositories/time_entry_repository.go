@@ -0,0 +1,314 @@
+package repositories
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/r-52/embrace/models"
+	"gorm.io/gorm"
+)
+
+type TimeEntryRepository struct {
+	Database *gorm.DB
+}
+
+// TimeEntryFilter narrows down the time entries returned by TimeEntryRepository.Find.
+// Zero values are ignored. From is inclusive and To is exclusive, both compare against the start time.
+type TimeEntryFilter struct {
+	CompanyID       uint
+	UserIDs         []uint
+	TimeEntryTypeID uint
+	From            time.Time
+	To              time.Time
+}
+
+type TimeEntryRepositoryInterface interface {
+	// GetByID retrieves a time entry record from the database by its ID.
+	// It takes an unsigned integer `id` as input and returns a pointer to a `models.TimeEntry` instance and an error.
+	GetByID(id uint) (*models.TimeEntry, error)
+
+	// Create inserts a new time entry record into the database.
+	// It takes a pointer to a `models.TimeEntry` instance as input and returns an error.
+	Create(timeEntry *models.TimeEntry) error
+
+	// Update updates an existing time entry record in the database.
+	// It takes a pointer to a `models.TimeEntry` instance as input and returns an error.
+	Update(timeEntry *models.TimeEntry) error
+
+	// Delete removes a time entry record from the database by its ID.
+	// It takes an unsigned integer `id` as input and returns an error.
+	Delete(id uint) error
+
+	// GetByUserID retrieves all time entries of a user ordered by start time.
+	// It takes an unsigned integer `userID` as input and returns a slice of `models.TimeEntry` instances and an error.
+	GetByUserID(userID uint) ([]models.TimeEntry, error)
+
+	// GetByUserIDAndDateRange retrieves the time entries of a user that start within [from, to) ordered by start time.
+	// It takes an unsigned integer `userID` and two times as input and returns a slice of `models.TimeEntry` instances and an error.
+	GetByUserIDAndDateRange(userID uint, from, to time.Time) ([]models.TimeEntry, error)
+
+	// GetByCompanyIDAndDateRange retrieves the time entries of a company that start within [from, to) ordered by start time.
+	// It takes an unsigned integer `companyID` and two times as input and returns a slice of `models.TimeEntry` instances and an error.
+	GetByCompanyIDAndDateRange(companyID uint, from, to time.Time) ([]models.TimeEntry, error)
+
+	// GetByTimeEntryTypeID retrieves all time entries booked on a time entry type ordered by start time.
+	// It takes an unsigned integer `timeEntryTypeID` as input and returns a slice of `models.TimeEntry` instances and an error.
+	GetByTimeEntryTypeID(timeEntryTypeID uint) ([]models.TimeEntry, error)
+
+	// Find retrieves the time entries matching the filter ordered by start time.
+	// It takes a `TimeEntryFilter` as input and returns a slice of `models.TimeEntry` instances and an error.
+	Find(filter TimeEntryFilter) ([]models.TimeEntry, error)
+
+	// CountByUserID counts the time entries of a user.
+	// It takes an unsigned integer `userID` as input and returns the count as an int64 and an error.
+	CountByUserID(userID uint) (int64, error)
+
+	// GetRunningByUserID retrieves the running time entry of a user, the one without an end time.
+	// It takes an unsigned integer `userID` as input and returns a pointer to a `models.TimeEntry` instance and an error.
+	GetRunningByUserID(userID uint) (*models.TimeEntry, error)
+
+	// GetPausedByUserID retrieves the paused time entry of a user.
+	// It takes an unsigned integer `userID` as input and returns a pointer to a `models.TimeEntry` instance and an error.
+	GetPausedByUserID(userID uint) (*models.TimeEntry, error)
+
+	// Stop persists the end time, duration, deducted break and paused flag of a running time entry.
+	// It takes a pointer to a `models.TimeEntry` instance as input and returns an error.
+	Stop(timeEntry *models.TimeEntry) error
+
+	// Unpause clears the paused flag of a time entry.
+	// It takes an unsigned integer `id` as input and returns an error.
+	Unpause(id uint) error
+
+	// GetOverlapping retrieves the time entries of a user that overlap the span from start to end ordered by start time.
+	// It takes an unsigned integer `userID`, the span and the ID of an entry to ignore as input and returns a slice of `models.TimeEntry` instances and an error.
+	GetOverlapping(userID uint, start time.Time, end sql.NullTime, excludeID uint) ([]models.TimeEntry, error)
+
+	// GetByLeaveRequestID retrieves the time entries booked for a leave request ordered by start time.
+	// It takes an unsigned integer `leaveRequestID` as input and returns a slice of `models.TimeEntry` instances and an error.
+	GetByLeaveRequestID(leaveRequestID uint) ([]models.TimeEntry, error)
+
+	// CountChangedSince counts the time entries of a user starting within [from, to) that were created, updated or deleted after `since`.
+	// It takes an unsigned integer `userID` and three times as input and returns the count as an int64 and an error.
+	CountChangedSince(userID uint, from, to, since time.Time) (int64, error)
+}
+
+// NewTimeEntryRepository creates a new instance of TimeEntryRepository with the provided database connection.
+// It takes a *gorm.DB as an argument, which represents the database connection, and returns a pointer to a TimeEntryRepository.
+func NewTimeEntryRepository(db *gorm.DB) *TimeEntryRepository {
+	return &TimeEntryRepository{
+		Database: db,
+	}
+}
+
+// GetByID retrieves a time entry record from the database by its ID.
+// It takes an unsigned integer `id` as input and returns a pointer to a
+// `models.TimeEntry` instance and an error. If the time entry with the specified
+// ID is not found or if there is a database error, it returns a non-nil error.
+func (r *TimeEntryRepository) GetByID(id uint) (*models.TimeEntry, error) {
+	var timeEntry models.TimeEntry
+	err := r.Database.First(&timeEntry, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &timeEntry, nil
+}
+
+// Create inserts a new time entry record into the database.
+// It takes a pointer to a `models.TimeEntry` instance as input and returns an error.
+// If the create operation fails, it returns a non-nil error.
+func (r *TimeEntryRepository) Create(timeEntry *models.TimeEntry) error {
+	err := r.Database.Create(timeEntry).Error
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// Update updates an existing time entry record in the database.
+// It takes a pointer to a `models.TimeEntry` instance as input and returns an error.
+// If the update operation fails, it returns a non-nil error.
+func (r *TimeEntryRepository) Update(timeEntry *models.TimeEntry) error {
+	err := r.Database.Omit("User", "TimeEntryType").Save(timeEntry).Error
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// Delete removes a time entry record from the database by its ID.
+// It takes an unsigned integer `id` as input and returns an error.
+// If the time entry with the specified ID is not found or if the delete operation fails, it returns a non-nil error.
+func (r *TimeEntryRepository) Delete(id uint) error {
+	var timeEntry models.TimeEntry
+	err := r.Database.First(&timeEntry, id).Error
+	if err != nil {
+		return err
+	}
+	err = r.Database.Delete(&timeEntry).Error
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// GetByUserID retrieves all time entries of a user ordered by start time.
+// If there is a database error, it returns a non-nil error.
+func (r *TimeEntryRepository) GetByUserID(userID uint) ([]models.TimeEntry, error) {
+	return r.Find(TimeEntryFilter{UserIDs: []uint{userID}})
+}
+
+// GetByUserIDAndDateRange retrieves the time entries of a user that start within [from, to) ordered by start time.
+// If there is a database error, it returns a non-nil error.
+func (r *TimeEntryRepository) GetByUserIDAndDateRange(userID uint, from, to time.Time) ([]models.TimeEntry, error) {
+	return r.Find(TimeEntryFilter{UserIDs: []uint{userID}, From: from, To: to})
+}
+
+// GetByCompanyIDAndDateRange retrieves the time entries of a company that start within [from, to) ordered by start time.
+// If there is a database error, it returns a non-nil error.
+func (r *TimeEntryRepository) GetByCompanyIDAndDateRange(companyID uint, from, to time.Time) ([]models.TimeEntry, error) {
+	return r.Find(TimeEntryFilter{CompanyID: companyID, From: from, To: to})
+}
+
+// GetByTimeEntryTypeID retrieves all time entries booked on a time entry type ordered by start time.
+// If there is a database error, it returns a non-nil error.
+func (r *TimeEntryRepository) GetByTimeEntryTypeID(timeEntryTypeID uint) ([]models.TimeEntry, error) {
+	return r.Find(TimeEntryFilter{TimeEntryTypeID: timeEntryTypeID})
+}
+
+// Find retrieves the time entries matching the filter ordered by start time.
+// If there is a database error, it returns a non-nil error.
+func (r *TimeEntryRepository) Find(filter TimeEntryFilter) ([]models.TimeEntry, error) {
+	var timeEntries []models.TimeEntry
+	query := r.Database.Model(&models.TimeEntry{})
+	if filter.CompanyID != 0 {
+		query = query.Where("company_id = ?", filter.CompanyID)
+	}
+	if len(filter.UserIDs) > 0 {
+		query = query.Where("user_id IN ?", filter.UserIDs)
+	}
+	if filter.TimeEntryTypeID != 0 {
+		query = query.Where("time_entry_type_id = ?", filter.TimeEntryTypeID)
+	}
+	if !filter.From.IsZero() {
+		query = query.Where("start_time >= ?", filter.From)
+	}
+	if !filter.To.IsZero() {
+		query = query.Where("start_time < ?", filter.To)
+	}
+	err := query.Order("start_time").Order("id").Find(&timeEntries).Error
+	if err != nil {
+		return nil, err
+	}
+	return timeEntries, nil
+}
+
+// CountByUserID counts the time entries of a user.
+// If there is a database error, it returns a non-nil error.
+func (r *TimeEntryRepository) CountByUserID(userID uint) (int64, error) {
+	var count int64
+	err := r.Database.Model(&models.TimeEntry{}).Where("user_id = ?", userID).Count(&count).Error
+	if err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+// GetRunningByUserID retrieves the running time entry of a user, the one without an end time.
+// If the user has no running time entry, it returns gorm.ErrRecordNotFound.
+func (r *TimeEntryRepository) GetRunningByUserID(userID uint) (*models.TimeEntry, error) {
+	var timeEntry models.TimeEntry
+	err := r.Database.Where("user_id = ? AND end_time IS NULL", userID).First(&timeEntry).Error
+	if err != nil {
+		return nil, err
+	}
+	return &timeEntry, nil
+}
+
+// GetPausedByUserID retrieves the paused time entry of a user.
+// If the user has no paused time entry, it returns gorm.ErrRecordNotFound.
+func (r *TimeEntryRepository) GetPausedByUserID(userID uint) (*models.TimeEntry, error) {
+	var timeEntry models.TimeEntry
+	err := r.Database.Where("user_id = ? AND paused = ?", userID, true).Order("end_time DESC").First(&timeEntry).Error
+	if err != nil {
+		return nil, err
+	}
+	return &timeEntry, nil
+}
+
+// Stop persists the end time, duration, deducted break and paused flag of a running time entry.
+// The update only applies while the entry is still running, so of two concurrent
+// requests stopping the same entry only one succeeds. The other one and entries
+// that are not running return gorm.ErrRecordNotFound.
+func (r *TimeEntryRepository) Stop(timeEntry *models.TimeEntry) error {
+	result := r.Database.Model(timeEntry).Where("end_time IS NULL").Updates(map[string]interface{}{
+		"end_time":       timeEntry.EndTime,
+		"duration":       timeEntry.Duration,
+		"deducted_break": timeEntry.DeductedBreak,
+		"paused":         timeEntry.Paused,
+	})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// Unpause clears the paused flag of a time entry.
+// If the entry does not exist or is not paused, it returns gorm.ErrRecordNotFound.
+func (r *TimeEntryRepository) Unpause(id uint) error {
+	result := r.Database.Model(&models.TimeEntry{}).Where("id = ? AND paused = ?", id, true).Update("paused", false)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// GetOverlapping retrieves the time entries of a user that overlap the span from start to end ordered by start time.
+// An invalid end describes a running timer that overlaps everything after its start, the same holds for running entries
+// in the database. Spans that only touch do not overlap. The entry with `excludeID` is ignored, so an entry does not
+// overlap itself when it is updated.
+// If there is a database error, it returns a non-nil error.
+func (r *TimeEntryRepository) GetOverlapping(userID uint, start time.Time, end sql.NullTime, excludeID uint) ([]models.TimeEntry, error) {
+	var timeEntries []models.TimeEntry
+	query := r.Database.Where("user_id = ? AND id <> ?", userID, excludeID).
+		Where("end_time IS NULL OR end_time > ?", start)
+	if end.Valid {
+		query = query.Where("start_time < ?", end.Time)
+	}
+	err := query.Order("start_time").Order("id").Find(&timeEntries).Error
+	if err != nil {
+		return nil, err
+	}
+	return timeEntries, nil
+}
+
+// GetByLeaveRequestID retrieves the time entries booked for a leave request ordered by start time.
+// If there is a database error, it returns a non-nil error.
+func (r *TimeEntryRepository) GetByLeaveRequestID(leaveRequestID uint) ([]models.TimeEntry, error) {
+	var timeEntries []models.TimeEntry
+	err := r.Database.Where("leave_request_id = ?", leaveRequestID).Order("start_time").Order("id").Find(&timeEntries).Error
+	if err != nil {
+		return nil, err
+	}
+	return timeEntries, nil
+}
+
+// CountChangedSince counts the time entries of a user starting within [from, to) that were created, updated or
+// deleted after `since`. Deleted entries count as well.
+// If there is a database error, it returns a non-nil error.
+func (r *TimeEntryRepository) CountChangedSince(userID uint, from, to, since time.Time) (int64, error) {
+	var count int64
+	err := r.Database.Unscoped().Model(&models.TimeEntry{}).
+		Where("user_id = ? AND start_time >= ? AND start_time < ?", userID, from, to).
+		Where("updated_at > ? OR deleted_at > ?", since, since).
+		Count(&count).Error
+	if err != nil {
+		return 0, err
+	}
+	return count, nil
+}
@@ -0,0 +1,247 @@
+package repositories_test
+
+import (
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/r-52/embrace/models"
+	"github.com/r-52/embrace/repositories"
+	"gorm.io/gorm"
+)
+
+func setupTimeEntryTestDB(t *testing.T) *gorm.DB {
+	db := GetDatabase()
+	if err := db.AutoMigrate(&models.TimeEntry{}); err != nil {
+		t.Fatalf("failed to migrate database: %v", err)
+	}
+	return db
+}
+
+func seedTimeEntries(t *testing.T, db *gorm.DB, entries ...*models.TimeEntry) {
+	for _, entry := range entries {
+		if err := db.Create(entry).Error; err != nil {
+			t.Fatalf("failed to seed database: %v", err)
+		}
+	}
+}
+
+func newTimeEntry(companyID, userID, timeEntryTypeID uint, start time.Time, hours int) *models.TimeEntry {
+	entry := &models.TimeEntry{CompanyID: companyID, UserID: userID, TimeEntryTypeID: timeEntryTypeID, StartTime: start}
+	entry.Close(start.Add(time.Duration(hours) * time.Hour))
+	return entry
+}
+
+func TestTimeEntryRepository_CRUD(t *testing.T) {
+	db := setupTimeEntryTestDB(t)
+	repo := repositories.NewTimeEntryRepository(db)
+
+	start := time.Date(2024, 3, 4, 8, 0, 0, 0, time.UTC)
+	entry := newTimeEntry(1, 1, 1, start, 8)
+	if err := repo.Create(entry); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	result, err := repo.GetByID(entry.ID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !result.StartTime.Equal(start) || result.Duration.Float64 != 8 {
+		t.Errorf("unexpected entry: %+v", result)
+	}
+
+	result.Note = "updated"
+	result.Close(start.Add(4 * time.Hour))
+	if err := repo.Update(result); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	result, err = repo.GetByID(entry.ID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Note != "updated" || result.Duration.Float64 != 4 {
+		t.Errorf("unexpected entry after update: %+v", result)
+	}
+
+	if err := repo.Delete(entry.ID); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := repo.GetByID(entry.ID); !errors.Is(err, gorm.ErrRecordNotFound) {
+		t.Errorf("expected ErrRecordNotFound, got %v", err)
+	}
+	if err := repo.Delete(entry.ID); !errors.Is(err, gorm.ErrRecordNotFound) {
+		t.Errorf("expected ErrRecordNotFound, got %v", err)
+	}
+}
+
+func TestTimeEntryRepository_Find(t *testing.T) {
+	db := setupTimeEntryTestDB(t)
+	repo := repositories.NewTimeEntryRepository(db)
+
+	monday := time.Date(2024, 3, 4, 8, 0, 0, 0, time.UTC)
+	tuesday := monday.AddDate(0, 0, 1)
+	wednesday := monday.AddDate(0, 0, 2)
+	seedTimeEntries(t, db,
+		newTimeEntry(1, 1, 1, tuesday, 8),
+		newTimeEntry(1, 1, 2, monday, 8),
+		newTimeEntry(1, 2, 1, wednesday, 8),
+		newTimeEntry(2, 3, 3, monday, 8),
+	)
+
+	tests := []struct {
+		name     string
+		filter   repositories.TimeEntryFilter
+		expected []uint
+	}{
+		{name: "company", filter: repositories.TimeEntryFilter{CompanyID: 1}, expected: []uint{2, 1, 3}},
+		{name: "users", filter: repositories.TimeEntryFilter{UserIDs: []uint{1, 3}}, expected: []uint{2, 4, 1}},
+		{name: "type", filter: repositories.TimeEntryFilter{TimeEntryTypeID: 1}, expected: []uint{1, 3}},
+		{name: "range", filter: repositories.TimeEntryFilter{CompanyID: 1, From: tuesday, To: wednesday}, expected: []uint{1}},
+		{name: "open range", filter: repositories.TimeEntryFilter{From: tuesday}, expected: []uint{1, 3}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			entries, err := repo.Find(tt.filter)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if len(entries) != len(tt.expected) {
+				t.Fatalf("expected %d entries, got %d", len(tt.expected), len(entries))
+			}
+			for i, entry := range entries {
+				if entry.ID != tt.expected[i] {
+					t.Errorf("expected entry %d at position %d, got %d", tt.expected[i], i, entry.ID)
+				}
+			}
+		})
+	}
+}
+
+func TestTimeEntryRepository_GetByUserIDAndDateRange(t *testing.T) {
+	db := setupTimeEntryTestDB(t)
+	repo := repositories.NewTimeEntryRepository(db)
+
+	monday := time.Date(2024, 3, 4, 8, 0, 0, 0, time.UTC)
+	seedTimeEntries(t, db,
+		newTimeEntry(1, 1, 1, monday, 8),
+		newTimeEntry(1, 1, 1, monday.AddDate(0, 0, 7), 8),
+		newTimeEntry(1, 2, 1, monday, 8),
+	)
+
+	entries, err := repo.GetByUserIDAndDateRange(1, monday, monday.AddDate(0, 0, 7))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(entries) != 1 || entries[0].ID != 1 {
+		t.Errorf("unexpected entries: %+v", entries)
+	}
+
+	count, err := repo.CountByUserID(1)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if count != 2 {
+		t.Errorf("expected count 2, got %d", count)
+	}
+}
+
+func TestTimeEntryRepository_Running_Entries(t *testing.T) {
+	db := setupTimeEntryTestDB(t)
+	repo := repositories.NewTimeEntryRepository(db)
+
+	start := time.Date(2024, 3, 4, 8, 0, 0, 0, time.UTC)
+	running := &models.TimeEntry{CompanyID: 1, UserID: 1, TimeEntryTypeID: 1, StartTime: start}
+	if err := repo.Create(running); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	second := &models.TimeEntry{CompanyID: 1, UserID: 1, TimeEntryTypeID: 1, StartTime: start}
+	if err := repo.Create(second); !errors.Is(err, gorm.ErrDuplicatedKey) {
+		t.Errorf("expected ErrDuplicatedKey for a second running entry, got %v", err)
+	}
+
+	result, err := repo.GetRunningByUserID(1)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.ID != running.ID {
+		t.Errorf("expected entry %d, got %d", running.ID, result.ID)
+	}
+
+	result.Close(start.Add(2 * time.Hour))
+	result.Paused = true
+	if err := repo.Stop(result); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := repo.Stop(result); !errors.Is(err, gorm.ErrRecordNotFound) {
+		t.Errorf("expected ErrRecordNotFound when stopping twice, got %v", err)
+	}
+	if _, err := repo.GetRunningByUserID(1); !errors.Is(err, gorm.ErrRecordNotFound) {
+		t.Errorf("expected ErrRecordNotFound, got %v", err)
+	}
+
+	paused, err := repo.GetPausedByUserID(1)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if paused.ID != running.ID || paused.Duration.Float64 != 2 {
+		t.Errorf("unexpected paused entry: %+v", paused)
+	}
+	if err := repo.Unpause(paused.ID); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := repo.Unpause(paused.ID); !errors.Is(err, gorm.ErrRecordNotFound) {
+		t.Errorf("expected ErrRecordNotFound when unpausing twice, got %v", err)
+	}
+
+	// Once the first entry is closed the user can start a new one.
+	if err := repo.Create(second); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestTimeEntryRepository_GetOverlapping(t *testing.T) {
+	db := setupTimeEntryTestDB(t)
+	repo := repositories.NewTimeEntryRepository(db)
+
+	monday := time.Date(2024, 3, 4, 8, 0, 0, 0, time.UTC)
+	seedTimeEntries(t, db,
+		newTimeEntry(1, 1, 1, monday, 4),
+		newTimeEntry(1, 1, 1, monday.Add(5*time.Hour), 2),
+		newTimeEntry(1, 2, 1, monday, 8),
+		&models.TimeEntry{CompanyID: 1, UserID: 1, TimeEntryTypeID: 1, StartTime: monday.Add(10 * time.Hour)},
+	)
+
+	closedAt := func(hours int) sql.NullTime {
+		return sql.NullTime{Time: monday.Add(time.Duration(hours) * time.Hour), Valid: true}
+	}
+	tests := []struct {
+		name      string
+		start     time.Time
+		end       sql.NullTime
+		excludeID uint
+		expected  []uint
+	}{
+		{name: "touching", start: monday.Add(4 * time.Hour), end: closedAt(5)},
+		{name: "spanning", start: monday.Add(3 * time.Hour), end: closedAt(6), expected: []uint{1, 2}},
+		{name: "excluded", start: monday, end: closedAt(1), excludeID: 1},
+		{name: "running entry", start: monday.Add(11 * time.Hour), end: closedAt(12), expected: []uint{4}},
+		{name: "new running timer", start: monday.Add(6 * time.Hour), expected: []uint{2, 4}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			entries, err := repo.GetOverlapping(1, tt.start, tt.end, tt.excludeID)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if len(entries) != len(tt.expected) {
+				t.Fatalf("expected %d entries, got %d", len(tt.expected), len(entries))
+			}
+			for i, entry := range entries {
+				if entry.ID != tt.expected[i] {
+					t.Errorf("expected entry %d at position %d, got %d", tt.expected[i], i, entry.ID)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,96 @@
+package repositories
+
+import (
+	"time"
+
+	"github.com/r-52/embrace/models"
+	"gorm.io/gorm"
+)
+
+type TimesheetPeriodRepository struct {
+	Database *gorm.DB
+}
+
+type TimesheetPeriodRepositoryInterface interface {
+	// GetByUserIDAndMonth retrieves the timesheet period of a user for a month.
+	// It takes an unsigned integer `userID` and the first day of the month as input and returns a pointer to a `models.TimesheetPeriod` instance and an error.
+	GetByUserIDAndMonth(userID uint, month time.Time) (*models.TimesheetPeriod, error)
+
+	// GetByMonth retrieves the stored timesheet periods of a month ordered by user.
+	// It takes the first day of the month as input and returns a slice of `models.TimesheetPeriod` instances and an error.
+	GetByMonth(month time.Time) ([]models.TimesheetPeriod, error)
+
+	// Create inserts a new timesheet period into the database.
+	// It takes a pointer to a `models.TimesheetPeriod` instance as input and returns an error.
+	Create(period *models.TimesheetPeriod) error
+
+	// Transition moves a timesheet period from one status to another and records who did it.
+	// It takes the updated `models.TimesheetPeriod` and the status it is expected to have as input and returns an error.
+	Transition(period *models.TimesheetPeriod, from string) error
+}
+
+// NewTimesheetPeriodRepository creates a new instance of TimesheetPeriodRepository with the provided database connection.
+// It takes a *gorm.DB as an argument, which represents the database connection, and returns a pointer to a TimesheetPeriodRepository.
+func NewTimesheetPeriodRepository(db *gorm.DB) *TimesheetPeriodRepository {
+	return &TimesheetPeriodRepository{
+		Database: db,
+	}
+}
+
+// GetByUserIDAndMonth retrieves the timesheet period of a user for a month.
+// If the user has no period for the month or if there is a database error, it returns a non-nil error.
+func (r *TimesheetPeriodRepository) GetByUserIDAndMonth(userID uint, month time.Time) (*models.TimesheetPeriod, error) {
+	var period models.TimesheetPeriod
+	err := r.Database.Where("user_id = ? AND month = ?", userID, month).First(&period).Error
+	if err != nil {
+		return nil, err
+	}
+	return &period, nil
+}
+
+// GetByMonth retrieves the stored timesheet periods of a month ordered by user.
+// If there is a database error, it returns a non-nil error.
+func (r *TimesheetPeriodRepository) GetByMonth(month time.Time) ([]models.TimesheetPeriod, error) {
+	var periods []models.TimesheetPeriod
+	err := r.Database.Where("month = ?", month).Order("user_id").Find(&periods).Error
+	if err != nil {
+		return nil, err
+	}
+	return periods, nil
+}
+
+// Create inserts a new timesheet period into the database.
+// If the user has a period for the month already, it returns gorm.ErrDuplicatedKey.
+func (r *TimesheetPeriodRepository) Create(period *models.TimesheetPeriod) error {
+	err := r.Database.Create(period).Error
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// Transition moves a timesheet period from the status `from` to its new status and records who did it.
+// Only one of concurrent transitions of the same period succeeds, the others return gorm.ErrRecordNotFound,
+// as they do if the period does not exist or does not have the status `from`.
+func (r *TimesheetPeriodRepository) Transition(period *models.TimesheetPeriod, from string) error {
+	result := r.Database.Model(&models.TimesheetPeriod{}).
+		Where("id = ? AND status = ?", period.ID, from).
+		Updates(map[string]interface{}{
+			"status":         period.Status,
+			"submitted_at":   period.SubmittedAt,
+			"approved_by_id": period.ApprovedByID,
+			"approved_at":    period.ApprovedAt,
+			"locked_by_id":   period.LockedByID,
+			"locked_at":      period.LockedAt,
+			"reopened_by_id": period.ReopenedByID,
+			"reopened_at":    period.ReopenedAt,
+			"reopen_reason":  period.ReopenReason,
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
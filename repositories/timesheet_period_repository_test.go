@@ -0,0 +1,72 @@
+package repositories_test
+
+import (
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/r-52/embrace/models"
+	"github.com/r-52/embrace/repositories"
+	"gorm.io/gorm"
+)
+
+// setupTimesheetPeriodTestDB initializes the database for testing using the common setup method.
+func setupTimesheetPeriodTestDB(t *testing.T) *gorm.DB {
+	db := GetDatabase() // Use the method from common_test.go
+
+	// Auto-migrate the TimesheetPeriod model
+	err := db.AutoMigrate(&models.TimesheetPeriod{})
+	if err != nil {
+		t.Fatalf("failed to migrate database: %v", err)
+	}
+
+	return db
+}
+
+func TestTimesheetPeriodRepository_GetByMonth_And_Transition(t *testing.T) {
+	db := setupTimesheetPeriodTestDB(t)
+	repo := repositories.NewTimesheetPeriodRepository(db)
+
+	march := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+	april := time.Date(2025, 4, 1, 0, 0, 0, 0, time.UTC)
+	second := &models.TimesheetPeriod{UserID: 2, Month: march, Status: models.TIMESHEET_OPEN}
+	first := &models.TimesheetPeriod{UserID: 1, Month: march, Status: models.TIMESHEET_OPEN}
+	later := &models.TimesheetPeriod{UserID: 1, Month: april, Status: models.TIMESHEET_OPEN}
+	for _, period := range []*models.TimesheetPeriod{second, first, later} {
+		if err := repo.Create(period); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	if err := repo.Create(&models.TimesheetPeriod{UserID: 1, Month: march, Status: models.TIMESHEET_OPEN}); err == nil {
+		t.Errorf("expected a second period of the same month to be rejected")
+	}
+
+	periods, err := repo.GetByMonth(march)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(periods) != 2 || periods[0].ID != first.ID || periods[1].ID != second.ID {
+		t.Errorf("expected the periods of March ordered by user, got %v", periods)
+	}
+
+	// Test a submission and a second, concurrent one
+	first.Status = models.TIMESHEET_SUBMITTED
+	first.SubmittedAt = sql.NullTime{Time: time.Now(), Valid: true}
+	if err := repo.Transition(first, models.TIMESHEET_OPEN); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := repo.Transition(first, models.TIMESHEET_OPEN); !errors.Is(err, gorm.ErrRecordNotFound) {
+		t.Errorf("expected ErrRecordNotFound, got %v", err)
+	}
+	submitted, err := repo.GetByUserIDAndMonth(1, march)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if submitted.Status != models.TIMESHEET_SUBMITTED || !submitted.SubmittedAt.Valid {
+		t.Errorf("unexpected submission: %+v", submitted)
+	}
+	if _, err := repo.GetByUserIDAndMonth(2, april); !errors.Is(err, gorm.ErrRecordNotFound) {
+		t.Errorf("expected ErrRecordNotFound, got %v", err)
+	}
+}
@@ -0,0 +1,202 @@
+package repositories
+
+import (
+	"gorm.io/gorm"
+)
+
+type UnitOfWork struct {
+	Database *gorm.DB
+}
+
+type UnitOfWorkInterface interface {
+	// Transaction runs fn inside a single database transaction.
+	// The UnitOfWork passed to fn is bound to the transaction, so every repository obtained from it joins the transaction.
+	// If fn returns an error or panics, all writes are rolled back; otherwise they are committed together.
+	Transaction(fn func(uow *UnitOfWork) error) error
+
+	// ForTenant returns a unit of work whose repositories are scoped to a single company, see WithTenant.
+	ForTenant(companyID uint) *UnitOfWork
+
+	// Companies returns a CompanyRepository bound to the unit of work.
+	Companies() *CompanyRepository
+
+	// Users returns a UserRepository bound to the unit of work.
+	Users() *UserRepository
+
+	// UserRoles returns a UserRoleRepository bound to the unit of work.
+	UserRoles() *UserRoleRepository
+
+	// UserProfiles returns a UserProfileRepository bound to the unit of work.
+	UserProfiles() *UserProfileRepository
+
+	// Quotas returns a QuotaRepository bound to the unit of work.
+	Quotas() *QuotaRepository
+
+	// UserQuotas returns a UserQuotaRepository bound to the unit of work.
+	UserQuotas() *UserQuotaRepository
+
+	// TimeEntryTypes returns a TimeEntryTypeRepository bound to the unit of work.
+	TimeEntryTypes() *TimeEntryTypeRepository
+
+	// RefreshTokens returns a RefreshTokenRepository bound to the unit of work.
+	RefreshTokens() *RefreshTokenRepository
+
+	// TimeEntries returns a TimeEntryRepository bound to the unit of work.
+	TimeEntries() *TimeEntryRepository
+
+	// QuotaResets returns a QuotaResetRepository bound to the unit of work.
+	QuotaResets() *QuotaResetRepository
+
+	// UserQuotaCarryOvers returns a UserQuotaCarryOverRepository bound to the unit of work.
+	UserQuotaCarryOvers() *UserQuotaCarryOverRepository
+
+	// UserQuotaTransactions returns a UserQuotaTransactionRepository bound to the unit of work.
+	UserQuotaTransactions() *UserQuotaTransactionRepository
+
+	// LeaveRequests returns a LeaveRequestRepository bound to the unit of work.
+	LeaveRequests() *LeaveRequestRepository
+
+	// HolidayCalendars returns a HolidayCalendarRepository bound to the unit of work.
+	HolidayCalendars() *HolidayCalendarRepository
+
+	// WorkSchedules returns a WorkScheduleRepository bound to the unit of work.
+	WorkSchedules() *WorkScheduleRepository
+
+	// UserWorkSchedules returns a UserWorkScheduleRepository bound to the unit of work.
+	UserWorkSchedules() *UserWorkScheduleRepository
+
+	// OvertimePolicies returns an OvertimePolicyRepository bound to the unit of work.
+	OvertimePolicies() *OvertimePolicyRepository
+
+	// OvertimeSettlements returns an OvertimeSettlementRepository bound to the unit of work.
+	OvertimeSettlements() *OvertimeSettlementRepository
+
+	// ComplianceRuleSets returns a ComplianceRuleSetRepository bound to the unit of work.
+	ComplianceRuleSets() *ComplianceRuleSetRepository
+
+	// DurationPolicies returns a DurationPolicyRepository bound to the unit of work.
+	DurationPolicies() *DurationPolicyRepository
+
+	// TimesheetPeriods returns a TimesheetPeriodRepository bound to the unit of work.
+	TimesheetPeriods() *TimesheetPeriodRepository
+
+	// TimeEntryAudits returns a TimeEntryAuditRepository bound to the unit of work.
+	TimeEntryAudits() *TimeEntryAuditRepository
+
+	// AuditLogs returns a AuditLogRepository bound to the unit of work.
+	AuditLogs() *AuditLogRepository
+}
+
+// NewUnitOfWork creates a new instance of UnitOfWork with the provided database connection.
+// It takes a *gorm.DB as an argument, which represents the database connection, and returns a pointer to a UnitOfWork.
+func NewUnitOfWork(db *gorm.DB) *UnitOfWork {
+	return &UnitOfWork{
+		Database: db,
+	}
+}
+
+// Transaction runs fn inside a single database transaction.
+// Calling Transaction on a UnitOfWork that is already inside a transaction
+// creates a nested transaction (savepoint), so services can be composed freely.
+func (u *UnitOfWork) Transaction(fn func(uow *UnitOfWork) error) error {
+	return u.Database.Transaction(func(tx *gorm.DB) error {
+		return fn(NewUnitOfWork(tx))
+	})
+}
+
+// ForTenant returns a unit of work whose repositories only see and write rows of the given company.
+func (u *UnitOfWork) ForTenant(companyID uint) *UnitOfWork {
+	return NewUnitOfWork(WithTenant(u.Database, companyID))
+}
+
+func (u *UnitOfWork) Companies() *CompanyRepository {
+	return NewCompanyRepository(u.Database)
+}
+
+func (u *UnitOfWork) Users() *UserRepository {
+	return NewUserRepository(u.Database)
+}
+
+func (u *UnitOfWork) UserRoles() *UserRoleRepository {
+	return NewUserRoleRepository(u.Database)
+}
+
+func (u *UnitOfWork) UserProfiles() *UserProfileRepository {
+	return NewUserProfileRepository(u.Database)
+}
+
+func (u *UnitOfWork) Quotas() *QuotaRepository {
+	return NewQuotaRepository(u.Database)
+}
+
+func (u *UnitOfWork) UserQuotas() *UserQuotaRepository {
+	return NewUserQuotaRepository(u.Database)
+}
+
+func (u *UnitOfWork) TimeEntryTypes() *TimeEntryTypeRepository {
+	return NewTimeEntryTypeRepository(u.Database)
+}
+
+func (u *UnitOfWork) RefreshTokens() *RefreshTokenRepository {
+	return NewRefreshTokenRepository(u.Database)
+}
+
+func (u *UnitOfWork) TimeEntries() *TimeEntryRepository {
+	return NewTimeEntryRepository(u.Database)
+}
+
+func (u *UnitOfWork) QuotaResets() *QuotaResetRepository {
+	return NewQuotaResetRepository(u.Database)
+}
+
+func (u *UnitOfWork) UserQuotaCarryOvers() *UserQuotaCarryOverRepository {
+	return NewUserQuotaCarryOverRepository(u.Database)
+}
+
+func (u *UnitOfWork) UserQuotaTransactions() *UserQuotaTransactionRepository {
+	return NewUserQuotaTransactionRepository(u.Database)
+}
+
+func (u *UnitOfWork) LeaveRequests() *LeaveRequestRepository {
+	return NewLeaveRequestRepository(u.Database)
+}
+
+func (u *UnitOfWork) HolidayCalendars() *HolidayCalendarRepository {
+	return NewHolidayCalendarRepository(u.Database)
+}
+
+func (u *UnitOfWork) WorkSchedules() *WorkScheduleRepository {
+	return NewWorkScheduleRepository(u.Database)
+}
+
+func (u *UnitOfWork) UserWorkSchedules() *UserWorkScheduleRepository {
+	return NewUserWorkScheduleRepository(u.Database)
+}
+
+func (u *UnitOfWork) OvertimePolicies() *OvertimePolicyRepository {
+	return NewOvertimePolicyRepository(u.Database)
+}
+
+func (u *UnitOfWork) OvertimeSettlements() *OvertimeSettlementRepository {
+	return NewOvertimeSettlementRepository(u.Database)
+}
+
+func (u *UnitOfWork) ComplianceRuleSets() *ComplianceRuleSetRepository {
+	return NewComplianceRuleSetRepository(u.Database)
+}
+
+func (u *UnitOfWork) DurationPolicies() *DurationPolicyRepository {
+	return NewDurationPolicyRepository(u.Database)
+}
+
+func (u *UnitOfWork) TimesheetPeriods() *TimesheetPeriodRepository {
+	return NewTimesheetPeriodRepository(u.Database)
+}
+
+func (u *UnitOfWork) TimeEntryAudits() *TimeEntryAuditRepository {
+	return NewTimeEntryAuditRepository(u.Database)
+}
+
+func (u *UnitOfWork) AuditLogs() *AuditLogRepository {
+	return NewAuditLogRepository(u.Database)
+}
@@ -0,0 +1,94 @@
+package repositories_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/r-52/embrace/models"
+	"github.com/r-52/embrace/repositories"
+	"gorm.io/gorm"
+)
+
+// setupUnitOfWorkTestDB initializes the database for testing using the common setup method.
+func setupUnitOfWorkTestDB(t *testing.T) *gorm.DB {
+	db := GetDatabase() // Use the method from common_test.go
+
+	err := db.AutoMigrate(&models.Company{}, &models.User{}, &models.UserRole{}, &models.UserProfile{})
+	if err != nil {
+		t.Fatalf("failed to migrate database: %v", err)
+	}
+
+	return db
+}
+
+func TestUnitOfWork_Transaction_Commits(t *testing.T) {
+	db := setupUnitOfWorkTestDB(t)
+	uow := repositories.NewUnitOfWork(db)
+
+	company := &models.Company{Name: "Committed Company", PrimaryEmail: "committed@example.com"}
+	err := uow.Transaction(func(uow *repositories.UnitOfWork) error {
+		if err := uow.Companies().Create(company); err != nil {
+			return err
+		}
+		return uow.Users().Create(&models.User{Email: "committed@example.com", CompanyID: company.ID})
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if _, err := repositories.NewCompanyRepository(db).GetByID(company.ID); err != nil {
+		t.Errorf("expected company to be committed, got %v", err)
+	}
+	if _, err := repositories.NewUserRepository(db).GetByEmail("committed@example.com"); err != nil {
+		t.Errorf("expected user to be committed, got %v", err)
+	}
+}
+
+func TestUnitOfWork_Transaction_RollsBack(t *testing.T) {
+	db := setupUnitOfWorkTestDB(t)
+	uow := repositories.NewUnitOfWork(db)
+
+	failure := errors.New("failure")
+	err := uow.Transaction(func(uow *repositories.UnitOfWork) error {
+		if err := uow.Companies().Create(&models.Company{Name: "Rolled Back Company", PrimaryEmail: "rolled@example.com"}); err != nil {
+			return err
+		}
+		if err := uow.Users().Create(&models.User{Email: "rolled@example.com"}); err != nil {
+			return err
+		}
+		return failure
+	})
+	if !errors.Is(err, failure) {
+		t.Fatalf("expected failure, got %v", err)
+	}
+
+	if _, err := repositories.NewCompanyRepository(db).GetByName("Rolled Back Company"); !errors.Is(err, gorm.ErrRecordNotFound) {
+		t.Errorf("expected company to be rolled back, got %v", err)
+	}
+	if _, err := repositories.NewUserRepository(db).GetByEmail("rolled@example.com"); !errors.Is(err, gorm.ErrRecordNotFound) {
+		t.Errorf("expected user to be rolled back, got %v", err)
+	}
+}
+
+func TestUnitOfWork_Transaction_Nested_RollsBack_Outer(t *testing.T) {
+	db := setupUnitOfWorkTestDB(t)
+	uow := repositories.NewUnitOfWork(db)
+
+	failure := errors.New("failure")
+	err := uow.Transaction(func(outer *repositories.UnitOfWork) error {
+		err := outer.Transaction(func(inner *repositories.UnitOfWork) error {
+			return inner.Companies().Create(&models.Company{Name: "Nested Company", PrimaryEmail: "nested@example.com"})
+		})
+		if err != nil {
+			return err
+		}
+		return failure
+	})
+	if !errors.Is(err, failure) {
+		t.Fatalf("expected failure, got %v", err)
+	}
+
+	if _, err := repositories.NewCompanyRepository(db).GetByName("Nested Company"); !errors.Is(err, gorm.ErrRecordNotFound) {
+		t.Errorf("expected nested write to be rolled back, got %v", err)
+	}
+}
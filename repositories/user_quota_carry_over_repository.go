@@ -0,0 +1,142 @@
+package repositories
+
+import (
+	"time"
+
+	"github.com/r-52/embrace/models"
+	"gorm.io/gorm"
+)
+
+type UserQuotaCarryOverRepository struct {
+	Database *gorm.DB
+}
+
+type UserQuotaCarryOverRepositoryInterface interface {
+	// GetByID retrieves a carry-over bucket by its ID.
+	// It takes an unsigned integer `id` as input and returns a pointer to a `models.UserQuotaCarryOver` instance and an error.
+	GetByID(id uint) (*models.UserQuotaCarryOver, error)
+
+	// Create inserts a new carry-over bucket into the database.
+	// It takes a pointer to a `models.UserQuotaCarryOver` instance as input and returns an error.
+	Create(carryOver *models.UserQuotaCarryOver) error
+
+	// GetByUserQuotaID retrieves all carry-over buckets of a UserQuota ordered by ID.
+	// It takes an unsigned integer `userQuotaID` as input and returns a slice of `models.UserQuotaCarryOver` instances and an error.
+	GetByUserQuotaID(userQuotaID uint) ([]models.UserQuotaCarryOver, error)
+
+	// GetOpenByUserQuotaID retrieves the open carry-over buckets of a UserQuota, the ones expiring first first.
+	// It takes an unsigned integer `userQuotaID` as input and returns a slice of `models.UserQuotaCarryOver` instances and an error.
+	GetOpenByUserQuotaID(userQuotaID uint) ([]models.UserQuotaCarryOver, error)
+
+	// GetDue retrieves the open carry-over buckets that expire at or before `now`.
+	// It takes a time as input and returns a slice of `models.UserQuotaCarryOver` instances and an error.
+	GetDue(now time.Time) ([]models.UserQuotaCarryOver, error)
+
+	// SetRemaining sets the quota left in an open carry-over bucket.
+	// It takes an unsigned integer `id` and the remaining quota as input and returns an error.
+	SetRemaining(id uint, remaining models.QuotaAmount) error
+
+	// Close closes an open carry-over bucket, recording the quota that expired unused.
+	// It takes an unsigned integer `id`, the expired quota and the time as input and returns an error.
+	Close(id uint, expired models.QuotaAmount, at time.Time) error
+}
+
+// NewUserQuotaCarryOverRepository creates a new instance of UserQuotaCarryOverRepository with the provided database connection.
+// It takes a *gorm.DB as an argument, which represents the database connection, and returns a pointer to a UserQuotaCarryOverRepository.
+func NewUserQuotaCarryOverRepository(db *gorm.DB) *UserQuotaCarryOverRepository {
+	return &UserQuotaCarryOverRepository{
+		Database: db,
+	}
+}
+
+// GetByID retrieves a carry-over bucket by its ID.
+// If the bucket with the specified ID is not found or if there is a database error, it returns a non-nil error.
+func (r *UserQuotaCarryOverRepository) GetByID(id uint) (*models.UserQuotaCarryOver, error) {
+	var carryOver models.UserQuotaCarryOver
+	err := r.Database.First(&carryOver, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &carryOver, nil
+}
+
+// Create inserts a new carry-over bucket into the database.
+// If the create operation fails, it returns a non-nil error.
+func (r *UserQuotaCarryOverRepository) Create(carryOver *models.UserQuotaCarryOver) error {
+	err := r.Database.Create(carryOver).Error
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// GetByUserQuotaID retrieves all carry-over buckets of a UserQuota ordered by ID.
+// If there is a database error, it returns a non-nil error.
+func (r *UserQuotaCarryOverRepository) GetByUserQuotaID(userQuotaID uint) ([]models.UserQuotaCarryOver, error) {
+	var carryOvers []models.UserQuotaCarryOver
+	err := r.Database.Where("user_quota_id = ?", userQuotaID).Order("id").Find(&carryOvers).Error
+	if err != nil {
+		return nil, err
+	}
+	return carryOvers, nil
+}
+
+// GetOpenByUserQuotaID retrieves the open carry-over buckets of a UserQuota, the ones expiring first first.
+// Buckets that never expire come last.
+// If there is a database error, it returns a non-nil error.
+func (r *UserQuotaCarryOverRepository) GetOpenByUserQuotaID(userQuotaID uint) ([]models.UserQuotaCarryOver, error) {
+	var carryOvers []models.UserQuotaCarryOver
+	err := r.Database.
+		Where("user_quota_id = ? AND expired_at IS NULL", userQuotaID).
+		Order("expires_at IS NULL, expires_at, id").
+		Find(&carryOvers).Error
+	if err != nil {
+		return nil, err
+	}
+	return carryOvers, nil
+}
+
+// GetDue retrieves the open carry-over buckets that expire at or before `now`.
+// If there is a database error, it returns a non-nil error.
+func (r *UserQuotaCarryOverRepository) GetDue(now time.Time) ([]models.UserQuotaCarryOver, error) {
+	var carryOvers []models.UserQuotaCarryOver
+	err := r.Database.
+		Where("expired_at IS NULL AND expires_at <= ?", now.UTC()).
+		Order("expires_at, id").
+		Find(&carryOvers).Error
+	if err != nil {
+		return nil, err
+	}
+	return carryOvers, nil
+}
+
+// SetRemaining sets the quota left in an open carry-over bucket.
+// If the bucket does not exist or is closed, it returns gorm.ErrRecordNotFound.
+func (r *UserQuotaCarryOverRepository) SetRemaining(id uint, remaining models.QuotaAmount) error {
+	result := r.Database.Model(&models.UserQuotaCarryOver{}).
+		Where("id = ? AND expired_at IS NULL", id).
+		Update("remaining", remaining)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// Close closes an open carry-over bucket, recording the quota that expired unused.
+// Only one of concurrent calls closes the bucket, the others return gorm.ErrRecordNotFound,
+// as they do if the bucket does not exist.
+func (r *UserQuotaCarryOverRepository) Close(id uint, expired models.QuotaAmount, at time.Time) error {
+	result := r.Database.Model(&models.UserQuotaCarryOver{}).
+		Where("id = ? AND expired_at IS NULL", id).
+		Updates(map[string]interface{}{"remaining": 0, "expired": expired, "expired_at": at})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
@@ -0,0 +1,92 @@
+package repositories_test
+
+import (
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/r-52/embrace/models"
+	"github.com/r-52/embrace/repositories"
+	"gorm.io/gorm"
+)
+
+// setupUserQuotaCarryOverTestDB initializes the database for testing using the common setup method.
+func setupUserQuotaCarryOverTestDB(t *testing.T) *gorm.DB {
+	db := GetDatabase() // Use the method from common_test.go
+
+	// Auto-migrate the UserQuotaCarryOver model
+	err := db.AutoMigrate(&models.UserQuotaCarryOver{})
+	if err != nil {
+		t.Fatalf("failed to migrate database: %v", err)
+	}
+
+	return db
+}
+
+func TestUserQuotaCarryOverRepository_GetOpenByUserQuotaID(t *testing.T) {
+	db := setupUserQuotaCarryOverTestDB(t)
+	repo := repositories.NewUserQuotaCarryOverRepository(db)
+
+	march := time.Date(2025, 4, 1, 0, 0, 0, 0, time.UTC)
+	june := time.Date(2025, 7, 1, 0, 0, 0, 0, time.UTC)
+	never := &models.UserQuotaCarryOver{UserQuotaID: 1, Carried: 1, Remaining: 1}
+	late := &models.UserQuotaCarryOver{UserQuotaID: 1, Carried: 2, Remaining: 2, ExpiresAt: sql.NullTime{Time: june, Valid: true}}
+	early := &models.UserQuotaCarryOver{UserQuotaID: 1, Carried: 3, Remaining: 3, ExpiresAt: sql.NullTime{Time: march, Valid: true}}
+	closed := &models.UserQuotaCarryOver{UserQuotaID: 1, Carried: 4, ExpiredAt: sql.NullTime{Time: march, Valid: true}}
+	other := &models.UserQuotaCarryOver{UserQuotaID: 2, Carried: 5, Remaining: 5}
+	for _, carryOver := range []*models.UserQuotaCarryOver{never, late, early, closed, other} {
+		if err := repo.Create(carryOver); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	results, err := repo.GetOpenByUserQuotaID(1)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(results) != 3 || results[0].ID != early.ID || results[1].ID != late.ID || results[2].ID != never.ID {
+		t.Errorf("expected the open carry-overs expiring first first, got %v", results)
+	}
+
+	// Test retrieving the carry-overs that are due
+	due, err := repo.GetDue(march)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(due) != 1 || due[0].ID != early.ID {
+		t.Errorf("expected the march carry-over to be due, got %v", due)
+	}
+}
+
+func TestUserQuotaCarryOverRepository_SetRemaining_And_Close(t *testing.T) {
+	db := setupUserQuotaCarryOverTestDB(t)
+	repo := repositories.NewUserQuotaCarryOverRepository(db)
+
+	carryOver := &models.UserQuotaCarryOver{UserQuotaID: 1, Carried: 5, Remaining: 5}
+	if err := repo.Create(carryOver); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := repo.SetRemaining(carryOver.ID, 3); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := repo.Close(carryOver.ID, 3, time.Now()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	result, err := repo.GetByID(carryOver.ID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Remaining != 0 || result.Expired != 3 || result.IsOpen() {
+		t.Errorf("expected the carry-over to be closed with 3 expired, got %+v", result)
+	}
+
+	// Test that closed carry-overs are not changed
+	if err := repo.Close(carryOver.ID, 0, time.Now()); !errors.Is(err, gorm.ErrRecordNotFound) {
+		t.Errorf("expected ErrRecordNotFound, got %v", err)
+	}
+	if err := repo.SetRemaining(carryOver.ID, 1); !errors.Is(err, gorm.ErrRecordNotFound) {
+		t.Errorf("expected ErrRecordNotFound, got %v", err)
+	}
+}
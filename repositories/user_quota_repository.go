@@ -24,6 +24,9 @@ type UserQuotaRepositoryInterface interface {
 	GetByUserIDAndQuotaID(userID, quotaID uint) (*models.UserQuota, error)
 	CountByUserID(userID uint) (int64, error)
 	GetByUserIDAndQuotaName(userID uint, quotaName string) (*models.UserQuota, error)
+	Adjust(id uint, delta models.QuotaAmount, allowNegative bool) error
+	GetByQuotaID(quotaID uint) ([]models.UserQuota, error)
+	Reallocate(id uint, entitlement models.QuotaAmount) error
 }
 
 // GetByID retrieves a UserQuota record from the database by its ID.
@@ -117,9 +120,59 @@ func (r *UserQuotaRepository) CountByUserID(userID uint) (int64, error) {
 // If the UserQuota with the specified user ID and quota name is not found or if there is a database error, it returns a non-nil error.
 func (r *UserQuotaRepository) GetByUserIDAndQuotaName(userID uint, quotaName string) (*models.UserQuota, error) {
 	var userQuota models.UserQuota
-	err := r.Database.Where("user_id = ? AND quota_name = ?", userID, quotaName).First(&userQuota).Error
+	err := r.Database.
+		Joins("JOIN quota ON quota.id = user_quota.quota_id AND quota.deleted_at IS NULL").
+		Where("user_quota.user_id = ? AND quota.name = ?", userID, quotaName).
+		First(&userQuota).Error
 	if err != nil {
 		return nil, err
 	}
 	return &userQuota, nil
 }
+
+// Adjust adds `delta` to the count of a UserQuota in a single statement, so concurrent bookings cannot lose updates.
+// A negative delta is a debit. Unless `allowNegative` is set, a debit that would leave the count below zero is not applied.
+// If the UserQuota does not exist or the debit is not applied, it returns gorm.ErrRecordNotFound.
+func (r *UserQuotaRepository) Adjust(id uint, delta models.QuotaAmount, allowNegative bool) error {
+	query := r.Database.Model(&models.UserQuota{}).Where("id = ?", id)
+	if delta < 0 && !allowNegative {
+		query = query.Where("count >= ?", -delta)
+	}
+	result := query.Update("count", gorm.Expr("count + ?", delta))
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// GetByQuotaID retrieves all UserQuota records of a quota ordered by ID.
+// It takes an unsigned integer `quotaID` as input and returns a slice of `models.UserQuota` instances and an error.
+// If there is a database error, it returns a non-nil error.
+func (r *UserQuotaRepository) GetByQuotaID(quotaID uint) ([]models.UserQuota, error) {
+	var userQuotas []models.UserQuota
+	err := r.Database.Where("quota_id = ?", quotaID).Order("id").Find(&userQuotas).Error
+	if err != nil {
+		return nil, err
+	}
+	return userQuotas, nil
+}
+
+// Reallocate replaces the entitlement of a UserQuota and adds the difference to the old one to its count
+// in a single statement, so what was booked in the meantime stays consumed.
+// If the UserQuota does not exist, it returns gorm.ErrRecordNotFound.
+func (r *UserQuotaRepository) Reallocate(id uint, entitlement models.QuotaAmount) error {
+	result := r.Database.Model(&models.UserQuota{}).Where("id = ?", id).Updates(map[string]interface{}{
+		"count":       gorm.Expr("count + ? - entitlement", entitlement),
+		"entitlement": entitlement,
+	})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
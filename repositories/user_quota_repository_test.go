@@ -13,8 +13,8 @@ import (
 func setupUserQuotaDB(t *testing.T) *gorm.DB {
 	db := GetDatabase() // Use the method from common_test.go
 
-	// Auto-migrate the UserQuota model
-	err := db.AutoMigrate(&models.UserQuota{})
+	// Auto-migrate the UserQuota model and the Quota it refers to
+	err := db.AutoMigrate(&models.UserQuota{}, &models.Quota{})
 	if err != nil {
 		t.Fatalf("failed to migrate database: %v", err)
 	}
@@ -83,6 +83,12 @@ func TestUserQuotaRepository_Create(t *testing.T) {
 	if result.UserID != userQuota.UserID || result.QuotaID != userQuota.QuotaID {
 		t.Errorf("expected %v, got %v", userQuota, result)
 	}
+
+	// Test creating the same quota for the user again
+	err = repo.Create(&models.UserQuota{UserID: 1, QuotaID: 1})
+	if !errors.Is(err, gorm.ErrDuplicatedKey) {
+		t.Errorf("expected ErrDuplicatedKey, got %v", err)
+	}
 }
 
 func TestUserQuotaRepository_Update(t *testing.T) {
@@ -167,3 +173,87 @@ func TestUserQuotaRepository_CountByUserID(t *testing.T) {
 		t.Errorf("expected count 2, got %d", count)
 	}
 }
+
+func TestUserQuotaRepository_GetByUserIDAndQuotaName(t *testing.T) {
+	db := setupUserQuotaDB(t)
+	repo := repositories.UserQuotaRepository{Database: db}
+
+	// Insert test Quotas and UserQuotas
+	vacation := &models.Quota{Name: "vacation", CompanyID: 1}
+	overtime := &models.Quota{Name: "overtime", CompanyID: 1}
+	db.Create(vacation)
+	db.Create(overtime)
+	db.Create(&models.UserQuota{UserID: 1, QuotaID: overtime.ID})
+	userQuota := &models.UserQuota{UserID: 1, QuotaID: vacation.ID}
+	db.Create(userQuota)
+
+	// Test retrieving the UserQuota by UserID and the name of its Quota
+	result, err := repo.GetByUserIDAndQuotaName(1, "vacation")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.ID != userQuota.ID {
+		t.Errorf("expected %v, got %v", userQuota, result)
+	}
+
+	_, err = repo.GetByUserIDAndQuotaName(2, "vacation")
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		t.Errorf("expected ErrRecordNotFound, got %v", err)
+	}
+}
+
+func TestUserQuotaRepository_Adjust(t *testing.T) {
+	db := setupUserQuotaDB(t)
+	repo := repositories.UserQuotaRepository{Database: db}
+
+	// Insert a test UserQuota
+	userQuota := &models.UserQuota{UserID: 1, QuotaID: 1, Count: 2}
+	db.Create(userQuota)
+
+	steps := []struct {
+		delta         models.QuotaAmount
+		allowNegative bool
+		expectedErr   error
+		expected      models.QuotaAmount
+	}{
+		{delta: -2, expected: 0},
+		{delta: -1, expectedErr: gorm.ErrRecordNotFound, expected: 0},
+		{delta: -1, allowNegative: true, expected: -1},
+		{delta: 3, expected: 2},
+	}
+	for _, step := range steps {
+		err := repo.Adjust(userQuota.ID, step.delta, step.allowNegative)
+		if !errors.Is(err, step.expectedErr) {
+			t.Errorf("expected %v, got %v", step.expectedErr, err)
+		}
+		result, _ := repo.GetByID(userQuota.ID)
+		if result.Count != step.expected {
+			t.Errorf("expected count %d, got %d", step.expected, result.Count)
+		}
+	}
+
+	if err := repo.Adjust(999, 1, true); !errors.Is(err, gorm.ErrRecordNotFound) {
+		t.Errorf("expected ErrRecordNotFound, got %v", err)
+	}
+}
+
+func TestUserQuotaRepository_Reallocate(t *testing.T) {
+	db := setupUserQuotaDB(t)
+	repo := repositories.UserQuotaRepository{Database: db}
+
+	// Insert a test UserQuota of which 5 were consumed
+	userQuota := &models.UserQuota{UserID: 1, QuotaID: 1, Count: 25, Entitlement: 30}
+	db.Create(userQuota)
+
+	if err := repo.Reallocate(userQuota.ID, 15); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	result, _ := repo.GetByID(userQuota.ID)
+	if result.Count != 10 || result.Entitlement != 15 {
+		t.Errorf("expected count 10 and entitlement 15, got %d and %d", result.Count, result.Entitlement)
+	}
+
+	if err := repo.Reallocate(999, 1); !errors.Is(err, gorm.ErrRecordNotFound) {
+		t.Errorf("expected ErrRecordNotFound, got %v", err)
+	}
+}
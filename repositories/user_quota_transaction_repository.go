@@ -0,0 +1,93 @@
+package repositories
+
+import (
+	"time"
+
+	"github.com/r-52/embrace/models"
+	"gorm.io/gorm"
+)
+
+// UserQuotaTransactionRepository appends to and reads the ledger of quota movements.
+// The ledger is append-only, so there is no way to update or delete a transaction.
+type UserQuotaTransactionRepository struct {
+	Database *gorm.DB
+}
+
+type UserQuotaTransactionRepositoryInterface interface {
+	// GetByID retrieves a quota transaction by its ID.
+	// It takes an unsigned integer `id` as input and returns a pointer to a `models.UserQuotaTransaction` instance and an error.
+	GetByID(id uint) (*models.UserQuotaTransaction, error)
+
+	// Create appends a new quota transaction to the ledger.
+	// It takes a pointer to a `models.UserQuotaTransaction` instance as input and returns an error.
+	Create(transaction *models.UserQuotaTransaction) error
+
+	// GetByUserQuotaID retrieves the transactions of a UserQuota booked within [from, to) in the order they were booked.
+	// It takes an unsigned integer `userQuotaID` and two times as input and returns a slice of `models.UserQuotaTransaction` instances and an error.
+	GetByUserQuotaID(userQuotaID uint, from, to time.Time) ([]models.UserQuotaTransaction, error)
+
+	// SumBefore sums the amounts of the transactions of a UserQuota booked before `before`.
+	// It takes an unsigned integer `userQuotaID` and a time as input and returns the sum and an error.
+	SumBefore(userQuotaID uint, before time.Time) (models.QuotaAmount, error)
+}
+
+// NewUserQuotaTransactionRepository creates a new instance of UserQuotaTransactionRepository with the provided database connection.
+// It takes a *gorm.DB as an argument, which represents the database connection, and returns a pointer to a UserQuotaTransactionRepository.
+func NewUserQuotaTransactionRepository(db *gorm.DB) *UserQuotaTransactionRepository {
+	return &UserQuotaTransactionRepository{
+		Database: db,
+	}
+}
+
+// GetByID retrieves a quota transaction by its ID.
+// If the transaction with the specified ID is not found or if there is a database error, it returns a non-nil error.
+func (r *UserQuotaTransactionRepository) GetByID(id uint) (*models.UserQuotaTransaction, error) {
+	var transaction models.UserQuotaTransaction
+	err := r.Database.First(&transaction, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &transaction, nil
+}
+
+// Create appends a new quota transaction to the ledger.
+// If the create operation fails, it returns a non-nil error.
+func (r *UserQuotaTransactionRepository) Create(transaction *models.UserQuotaTransaction) error {
+	err := r.Database.Create(transaction).Error
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// GetByUserQuotaID retrieves the transactions of a UserQuota booked within [from, to) in the order they were booked.
+// A zero `to` leaves the range open at the end.
+// If there is a database error, it returns a non-nil error.
+func (r *UserQuotaTransactionRepository) GetByUserQuotaID(userQuotaID uint, from, to time.Time) ([]models.UserQuotaTransaction, error) {
+	var transactions []models.UserQuotaTransaction
+	query := r.Database.Where("user_quota_id = ? AND booked_at >= ?", userQuotaID, from.UTC())
+	if !to.IsZero() {
+		query = query.Where("booked_at < ?", to.UTC())
+	}
+	err := query.Order("booked_at, id").Find(&transactions).Error
+	if err != nil {
+		return nil, err
+	}
+	return transactions, nil
+}
+
+// SumBefore sums the amounts of the transactions of a UserQuota booked before `before`.
+// A zero `before` sums all transactions, which is the balance the ledger derives.
+// If there is a database error, it returns a non-nil error.
+func (r *UserQuotaTransactionRepository) SumBefore(userQuotaID uint, before time.Time) (models.QuotaAmount, error) {
+	var sum models.QuotaAmount
+	query := r.Database.Model(&models.UserQuotaTransaction{}).Where("user_quota_id = ?", userQuotaID)
+	if !before.IsZero() {
+		query = query.Where("booked_at < ?", before.UTC())
+	}
+	err := query.Select("COALESCE(SUM(amount), 0)").Scan(&sum).Error
+	if err != nil {
+		return 0, err
+	}
+	return sum, nil
+}
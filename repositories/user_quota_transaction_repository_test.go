@@ -0,0 +1,68 @@
+package repositories_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/r-52/embrace/models"
+	"github.com/r-52/embrace/repositories"
+	"gorm.io/gorm"
+)
+
+// setupUserQuotaTransactionTestDB initializes the database for testing using the common setup method.
+func setupUserQuotaTransactionTestDB(t *testing.T) *gorm.DB {
+	db := GetDatabase() // Use the method from common_test.go
+
+	// Auto-migrate the UserQuotaTransaction model
+	err := db.AutoMigrate(&models.UserQuotaTransaction{})
+	if err != nil {
+		t.Fatalf("failed to migrate database: %v", err)
+	}
+
+	return db
+}
+
+func TestUserQuotaTransactionRepository_GetByUserQuotaID_And_SumBefore(t *testing.T) {
+	db := setupUserQuotaTransactionTestDB(t)
+	repo := repositories.NewUserQuotaTransactionRepository(db)
+
+	january := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	february := time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC)
+	march := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+	allocation := &models.UserQuotaTransaction{UserQuotaID: 1, Kind: models.QUOTA_TRANSACTION_ALLOCATION, Amount: models.QuotaUnits(30), BookedAt: january}
+	consumption := &models.UserQuotaTransaction{UserQuotaID: 1, Kind: models.QUOTA_TRANSACTION_CONSUMPTION, Amount: models.NewQuotaAmount(-1.5), BookedAt: february}
+	adjustment := &models.UserQuotaTransaction{UserQuotaID: 1, Kind: models.QUOTA_TRANSACTION_ADJUSTMENT, Amount: models.QuotaUnits(2), BookedAt: march}
+	other := &models.UserQuotaTransaction{UserQuotaID: 2, Kind: models.QUOTA_TRANSACTION_ALLOCATION, Amount: models.QuotaUnits(5), BookedAt: february}
+	for _, transaction := range []*models.UserQuotaTransaction{adjustment, consumption, allocation, other} {
+		if err := repo.Create(transaction); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	results, err := repo.GetByUserQuotaID(1, january, march)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(results) != 2 || results[0].ID != allocation.ID || results[1].ID != consumption.ID {
+		t.Errorf("expected the transactions before march in the order they were booked, got %v", results)
+	}
+
+	// Test summing the transactions before a point in time and in total
+	opening, err := repo.SumBefore(1, february)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if opening.Units() != 30 {
+		t.Errorf("expected 30 before february, got %v", opening)
+	}
+	total, err := repo.SumBefore(1, time.Time{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if total.Units() != 30.5 {
+		t.Errorf("expected 30.5 in total, got %v", total)
+	}
+	if empty, err := repo.SumBefore(3, time.Time{}); err != nil || empty != 0 {
+		t.Errorf("expected 0 without transactions, got %v, %v", empty, err)
+	}
+}
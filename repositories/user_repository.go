@@ -1,6 +1,8 @@
 package repositories
 
 import (
+	"strings"
+
 	"github.com/r-52/embrace/models"
 	"gorm.io/gorm"
 	"gorm.io/gorm/clause"
@@ -19,6 +21,10 @@ type UserRepositoryInterface interface {
 	// It takes an unsigned integer `id` as input and returns a pointer to a `models.User` instance and an error.
 	GetPreloadedUserByID(id uint) (*models.User, error)
 
+	// GetByIDWithCompanyAndRole retrieves a user record with its company, role and role permissions preloaded by its ID.
+	// It takes an unsigned integer `id` as input and returns a pointer to a `models.User` instance and an error.
+	GetByIDWithCompanyAndRole(id uint) (*models.User, error)
+
 	// Create inserts a new user record into the database.
 	// It takes a pointer to a `models.User` instance as input and returns an error.
 	Create(user *models.User) error
@@ -46,6 +52,22 @@ type UserRepositoryInterface interface {
 	// GetByEmail retrieves a user record from the database by its email.
 	// It takes a string `email` as input and returns a pointer to a `models.User` instance and an error.
 	GetByEmail(email string) (*models.User, error)
+
+	// GetByExactEmail retrieves a user record by its email, compared exactly apart from case.
+	// It takes a string `email` as input and returns a pointer to a `models.User` instance and an error.
+	GetByExactEmail(email string) (*models.User, error)
+
+	// CountByRoleID returns the count of users assigned to a specific role.
+	// It takes an unsigned integer `roleID` as input and returns an integer count and an error.
+	CountByRoleID(roleID uint) (int64, error)
+
+	// GetByManagerID retrieves all users managed by a specific user, i.e. the user's team.
+	// It takes an unsigned integer `managerID` as input and returns a slice of pointers to `models.User` instances and an error.
+	GetByManagerID(managerID uint) ([]*models.User, error)
+
+	// CountByHolidayCalendarID returns the count of users assigned to a specific holiday calendar.
+	// It takes an unsigned integer `calendarID` as input and returns an integer count and an error.
+	CountByHolidayCalendarID(calendarID uint) (int64, error)
 }
 
 // NewUserRepository creates a new instance of UserRepository with the provided database connection.
@@ -81,6 +103,19 @@ func (r *UserRepository) GetPreloadedUserByID(id uint) (*models.User, error) {
 	return &user, nil
 }
 
+// GetByIDWithCompanyAndRole retrieves a user record with its company, role and role permissions preloaded by its ID.
+// It takes an unsigned integer `id` as input and returns a pointer to a
+// `models.User` instance and an error. If the user with the specified
+// ID is not found or if there is a database error, it returns a non-nil error.
+func (r *UserRepository) GetByIDWithCompanyAndRole(id uint) (*models.User, error) {
+	var user models.User
+	err := r.Database.Preload("Company").Preload("Role.Permissions").First(&user, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
 // Create inserts a new user record into the database.
 // It takes a pointer to a `models.User` instance as input and returns an error.
 // If the create operation fails, it returns a non-nil error.
@@ -164,3 +199,52 @@ func (r *UserRepository) GetByEmail(email string) (*models.User, error) {
 	}
 	return &user, nil
 }
+
+// GetByExactEmail retrieves a user record by its email, compared exactly apart from case. Unlike GetByEmail it does
+// not treat `%` and `_` as wildcards, which makes it the lookup for authentication.
+// It takes a string `email` as input and returns a pointer to a `models.User` instance and an error.
+// If the user with the specified email is not found or if there is a database error, it returns a non-nil error.
+func (r *UserRepository) GetByExactEmail(email string) (*models.User, error) {
+	var user models.User
+	err := r.Database.Where("LOWER(email) = ?", strings.ToLower(strings.TrimSpace(email))).First(&user).Error
+	if err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+// CountByRoleID returns the count of users assigned to a specific role.
+// It takes an unsigned integer `roleID` as input and returns an integer count and an error.
+// If there is a database error, it returns a non-nil error.
+func (r *UserRepository) CountByRoleID(roleID uint) (int64, error) {
+	var count int64
+	err := r.Database.Model(&models.User{}).Where("role_id = ?", roleID).Count(&count).Error
+	if err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+// GetByManagerID retrieves all users managed by a specific user, i.e. the user's team.
+// It takes an unsigned integer `managerID` as input and returns a slice of pointers to `models.User` instances and an error.
+// If there is a database error, it returns a non-nil error.
+func (r *UserRepository) GetByManagerID(managerID uint) ([]*models.User, error) {
+	var users []*models.User
+	err := r.Database.Where("manager_id = ?", managerID).Find(&users).Error
+	if err != nil {
+		return nil, err
+	}
+	return users, nil
+}
+
+// CountByHolidayCalendarID returns the count of users assigned to a specific holiday calendar.
+// It takes an unsigned integer `calendarID` as input and returns an integer count and an error.
+// If there is a database error, it returns a non-nil error.
+func (r *UserRepository) CountByHolidayCalendarID(calendarID uint) (int64, error) {
+	var count int64
+	err := r.Database.Model(&models.User{}).Where("holiday_calendar_id = ?", calendarID).Count(&count).Error
+	if err != nil {
+		return 0, err
+	}
+	return count, nil
+}
@@ -178,6 +178,32 @@ func TestUserRepository_GetByEmail(t *testing.T) {
 	}
 }
 
+func TestUserRepository_GetByExactEmail(t *testing.T) {
+	db := setupUserTestDB(t)
+	repo := repositories.NewUserRepository(db)
+
+	// Insert a test user
+	user := &models.User{Email: "Email@example.com"}
+	db.Create(user)
+
+	// Test retrieving the user by email in another case
+	result, err := repo.GetByExactEmail(" email@EXAMPLE.com")
+	if err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if result == nil || result.ID != user.ID {
+		t.Errorf("expected %v, got %v", user, result)
+	}
+
+	// Test that wildcards match nothing
+	for _, pattern := range []string{"%", "%@example.com", "_mail@example.com"} {
+		_, err = repo.GetByExactEmail(pattern)
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			t.Errorf("expected ErrRecordNotFound for %q, got %v", pattern, err)
+		}
+	}
+}
+
 func TestUserRepository_GetCountByCompanyID(t *testing.T) {
 	db := setupUserTestDB(t)
 	repo := repositories.NewUserRepository(db)
@@ -9,6 +9,44 @@ type UserRoleRepository struct {
 	Database *gorm.DB
 }
 
+type UserRoleRepositoryInterface interface {
+	// GetByID retrieves a user role record from the database by its ID.
+	// It takes an unsigned integer `id` as input and returns a pointer to a `models.UserRole` instance and an error.
+	GetByID(id uint) (*models.UserRole, error)
+
+	// GetByIDWithPermissions retrieves a user role record with its permissions preloaded by its ID.
+	// It takes an unsigned integer `id` as input and returns a pointer to a `models.UserRole` instance and an error.
+	GetByIDWithPermissions(id uint) (*models.UserRole, error)
+
+	// Create inserts a new user role record into the database.
+	// It takes a pointer to a `models.UserRole` instance as input and returns an error.
+	Create(userRole *models.UserRole) error
+
+	// Update updates an existing user role record in the database.
+	// It takes a pointer to a `models.UserRole` instance as input and returns an error.
+	Update(userRole *models.UserRole) error
+
+	// Delete removes a user role record from the database by its ID.
+	// It takes an unsigned integer `id` as input and returns an error.
+	Delete(id uint) error
+
+	// GetByCompanyID retrieves all user roles associated with a specific company ID.
+	// It takes an unsigned integer `companyID` as input and returns a slice of `models.UserRole` instances and an error.
+	GetByCompanyID(companyID uint) ([]models.UserRole, error)
+
+	// GetByCompanyIDAndName retrieves a user role record by company ID and name.
+	// It takes an unsigned integer `companyID` and a string `name` as input and returns a pointer to a `models.UserRole` instance and an error.
+	GetByCompanyIDAndName(companyID uint, name string) (*models.UserRole, error)
+
+	// CountByCompanyID counts the number of user roles associated with a specific company ID.
+	// It takes an unsigned integer `companyID` as input and returns the count as an int64 and an error.
+	CountByCompanyID(companyID uint) (int64, error)
+
+	// ReplacePermissions replaces all permissions of a role with the given ones.
+	// It takes an unsigned integer `roleID` and a slice of permission strings as input and returns an error.
+	ReplacePermissions(roleID uint, permissions []string) error
+}
+
 // NewUserRoleRepository creates a new instance of UserRoleRepository with the provided database connection.
 // It takes a *gorm.DB as an argument, which represents the database connection, and returns a pointer to a UserRoleRepository.
 func NewUserRoleRepository(db *gorm.DB) *UserRoleRepository {
@@ -30,6 +68,19 @@ func (r *UserRoleRepository) GetByID(id uint) (*models.UserRole, error) {
 	return &userRole, nil
 }
 
+// GetByIDWithPermissions retrieves a user role record with its permissions preloaded by its ID.
+// It takes an unsigned integer `id` as input and returns a pointer to a
+// `models.UserRole` instance and an error. If the user role with the specified
+// ID is not found or if there is a database error, it returns a non-nil error.
+func (r *UserRoleRepository) GetByIDWithPermissions(id uint) (*models.UserRole, error) {
+	var userRole models.UserRole
+	err := r.Database.Preload("Permissions").First(&userRole, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &userRole, nil
+}
+
 // Create inserts a new user role record into the database.
 // It takes a pointer to a `models.UserRole` instance as input and returns an error.
 // If the create operation fails, it returns a non-nil error.
@@ -68,12 +119,12 @@ func (r *UserRoleRepository) Delete(id uint) error {
 	return nil
 }
 
-// GetByCompanyID retrieves all user roles associated with a specific company ID.
+// GetByCompanyID retrieves all user roles associated with a specific company ID with their permissions preloaded.
 // It takes an unsigned integer `companyID` as input and returns a slice of `models.UserRole`
 // instances and an error. If there is a database error, it returns a non-nil error.
 func (r *UserRoleRepository) GetByCompanyID(companyID uint) ([]models.UserRole, error) {
 	var userRoles []models.UserRole
-	err := r.Database.Where("company_id = ?", companyID).Find(&userRoles).Error
+	err := r.Database.Preload("Permissions").Where("company_id = ?", companyID).Find(&userRoles).Error
 	if err != nil {
 		return nil, err
 	}
@@ -91,3 +142,38 @@ func (r *UserRoleRepository) CountByCompanyID(companyID uint) (int64, error) {
 	}
 	return count, nil
 }
+
+// GetByCompanyIDAndName retrieves a user role record by company ID and name.
+// It takes an unsigned integer `companyID` and a string `name` as input and returns a pointer to a `models.UserRole` instance and an error.
+// If the user role is not found or if there is a database error, it returns a non-nil error.
+func (r *UserRoleRepository) GetByCompanyIDAndName(companyID uint, name string) (*models.UserRole, error) {
+	var userRole models.UserRole
+	err := r.Database.Where("company_id = ? AND name = ?", companyID, name).First(&userRole).Error
+	if err != nil {
+		return nil, err
+	}
+	return &userRole, nil
+}
+
+// ReplacePermissions replaces all permissions of a role with the given ones.
+// It takes an unsigned integer `roleID` and a slice of permission strings as input and returns an error.
+// The previous permissions are removed permanently. If a database operation fails, it returns a non-nil error.
+func (r *UserRoleRepository) ReplacePermissions(roleID uint, permissions []string) error {
+	err := r.Database.Unscoped().Where("user_role_id = ?", roleID).Delete(&models.RolePermission{}).Error
+	if err != nil {
+		return err
+	}
+	if len(permissions) == 0 {
+		return nil
+	}
+
+	rolePermissions := make([]models.RolePermission, 0, len(permissions))
+	for _, permission := range permissions {
+		rolePermissions = append(rolePermissions, models.RolePermission{UserRoleID: roleID, Permission: permission})
+	}
+	err = r.Database.Create(&rolePermissions).Error
+	if err != nil {
+		return err
+	}
+	return nil
+}
@@ -14,7 +14,7 @@ func setupUserRoleTestDB(t *testing.T) *gorm.DB {
 	db := GetDatabase() // Use the method from common_test.go
 
 	// Auto-migrate the UserRole model
-	err := db.AutoMigrate(&models.UserRole{})
+	err := db.AutoMigrate(&models.UserRole{}, &models.RolePermission{})
 	if err != nil {
 		t.Fatalf("failed to migrate database: %v", err)
 	}
@@ -57,3 +57,54 @@ func TestUserRoleRepository_Create(t *testing.T) {
 		t.Errorf("unexpected error: %v", err)
 	}
 }
+
+func TestUserRoleRepository_GetByCompanyIDAndName(t *testing.T) {
+	db := setupUserRoleTestDB(t)
+	repo := repositories.NewUserRoleRepository(db)
+
+	// Insert a test user role
+	userRole := &models.UserRole{Name: "Manager", CompanyID: 1}
+	db.Create(userRole)
+
+	// Test retrieving the user role by company ID and name
+	result, err := repo.GetByCompanyIDAndName(1, "Manager")
+	if err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if result.ID != userRole.ID {
+		t.Errorf("expected %v, got %v", userRole, result)
+	}
+
+	// Test retrieving the role of another company
+	_, err = repo.GetByCompanyIDAndName(2, "Manager")
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		t.Errorf("expected ErrRecordNotFound, got %v", err)
+	}
+}
+
+func TestUserRoleRepository_ReplacePermissions(t *testing.T) {
+	db := setupUserRoleTestDB(t)
+	repo := repositories.NewUserRoleRepository(db)
+
+	// Insert a test user role
+	userRole := &models.UserRole{Name: "Employee", CompanyID: 1}
+	db.Create(userRole)
+
+	// Assign permissions twice, the second call replaces the first
+	err := repo.ReplacePermissions(userRole.ID, []string{models.PERMISSION_USERS_READ, models.PERMISSION_QUOTAS_MANAGE})
+	if err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	err = repo.ReplacePermissions(userRole.ID, []string{models.PERMISSION_USERS_READ})
+	if err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	result, err := repo.GetByIDWithPermissions(userRole.ID)
+	if err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if len(result.Permissions) != 1 || result.Permissions[0].Permission != models.PERMISSION_USERS_READ {
+		t.Errorf("unexpected permissions: %+v", result.Permissions)
+	}
+}
@@ -0,0 +1,137 @@
+package repositories
+
+import (
+	"time"
+
+	"github.com/r-52/embrace/models"
+	"gorm.io/gorm"
+)
+
+type UserWorkScheduleRepository struct {
+	Database *gorm.DB
+}
+
+type UserWorkScheduleRepositoryInterface interface {
+	// GetByID retrieves a work schedule assignment by its ID.
+	// It takes an unsigned integer `id` as input and returns a pointer to a `models.UserWorkSchedule` instance and an error.
+	GetByID(id uint) (*models.UserWorkSchedule, error)
+
+	// Create inserts a new work schedule assignment into the database.
+	// It takes a pointer to a `models.UserWorkSchedule` instance as input and returns an error.
+	Create(assignment *models.UserWorkSchedule) error
+
+	// Update updates an existing work schedule assignment in the database.
+	// It takes a pointer to a `models.UserWorkSchedule` instance as input and returns an error.
+	Update(assignment *models.UserWorkSchedule) error
+
+	// Delete removes a work schedule assignment from the database by its ID.
+	// It takes an unsigned integer `id` as input and returns an error.
+	Delete(id uint) error
+
+	// GetByUserID retrieves the work schedule assignments of a user with their schedules, ordered by validity.
+	// It takes an unsigned integer `userID` as input and returns a slice of `models.UserWorkSchedule` instances and an error.
+	GetByUserID(userID uint) ([]models.UserWorkSchedule, error)
+
+	// GetByUserIDBetween retrieves the work schedule assignments of a user valid on any day from `from` to `to`,
+	// with their schedules and ordered by validity.
+	// It takes an unsigned integer `userID` and two days as input and returns a slice of `models.UserWorkSchedule` instances and an error.
+	GetByUserIDBetween(userID uint, from, to time.Time) ([]models.UserWorkSchedule, error)
+
+	// CountByWorkScheduleID returns the count of assignments of a specific work schedule.
+	// It takes an unsigned integer `scheduleID` as input and returns an integer count and an error.
+	CountByWorkScheduleID(scheduleID uint) (int64, error)
+}
+
+// NewUserWorkScheduleRepository creates a new instance of UserWorkScheduleRepository with the provided database connection.
+// It takes a *gorm.DB as an argument, which represents the database connection, and returns a pointer to a UserWorkScheduleRepository.
+func NewUserWorkScheduleRepository(db *gorm.DB) *UserWorkScheduleRepository {
+	return &UserWorkScheduleRepository{
+		Database: db,
+	}
+}
+
+// GetByID retrieves a work schedule assignment by its ID.
+// If the assignment with the specified ID is not found or if there is a database error, it returns a non-nil error.
+func (r *UserWorkScheduleRepository) GetByID(id uint) (*models.UserWorkSchedule, error) {
+	var assignment models.UserWorkSchedule
+	err := r.Database.First(&assignment, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &assignment, nil
+}
+
+// Create inserts a new work schedule assignment into the database.
+// If the create operation fails, it returns a non-nil error.
+func (r *UserWorkScheduleRepository) Create(assignment *models.UserWorkSchedule) error {
+	err := r.Database.Omit("WorkSchedule").Create(assignment).Error
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// Update updates an existing work schedule assignment in the database.
+// If the update operation fails, it returns a non-nil error.
+func (r *UserWorkScheduleRepository) Update(assignment *models.UserWorkSchedule) error {
+	err := r.Database.Omit("WorkSchedule").Save(assignment).Error
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// Delete removes a work schedule assignment from the database by its ID.
+// If the assignment with the specified ID is not found or if the delete operation fails, it returns a non-nil error.
+func (r *UserWorkScheduleRepository) Delete(id uint) error {
+	var assignment models.UserWorkSchedule
+	err := r.Database.First(&assignment, id).Error
+	if err != nil {
+		return err
+	}
+	err = r.Database.Delete(&assignment).Error
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func preloadWorkSchedule(db *gorm.DB) *gorm.DB {
+	return db.Preload("WorkSchedule").Preload("WorkSchedule.Days", orderedWorkScheduleDays)
+}
+
+// GetByUserID retrieves the work schedule assignments of a user with their schedules, ordered by validity.
+// If there is a database error, it returns a non-nil error.
+func (r *UserWorkScheduleRepository) GetByUserID(userID uint) ([]models.UserWorkSchedule, error) {
+	var assignments []models.UserWorkSchedule
+	err := preloadWorkSchedule(r.Database).Where("user_id = ?", userID).Order("valid_from, id").Find(&assignments).Error
+	if err != nil {
+		return nil, err
+	}
+	return assignments, nil
+}
+
+// GetByUserIDBetween retrieves the work schedule assignments of a user valid on any day from `from` to `to`,
+// with their schedules and ordered by validity. If there is a database error, it returns a non-nil error.
+func (r *UserWorkScheduleRepository) GetByUserIDBetween(userID uint, from, to time.Time) ([]models.UserWorkSchedule, error) {
+	var assignments []models.UserWorkSchedule
+	err := preloadWorkSchedule(r.Database).
+		Where("user_id = ? AND valid_from <= ? AND (valid_until IS NULL OR valid_until >= ?)", userID, to, from).
+		Order("valid_from, id").
+		Find(&assignments).Error
+	if err != nil {
+		return nil, err
+	}
+	return assignments, nil
+}
+
+// CountByWorkScheduleID returns the count of assignments of a specific work schedule.
+// If there is a database error, it returns a non-nil error.
+func (r *UserWorkScheduleRepository) CountByWorkScheduleID(scheduleID uint) (int64, error) {
+	var count int64
+	err := r.Database.Model(&models.UserWorkSchedule{}).Where("work_schedule_id = ?", scheduleID).Count(&count).Error
+	if err != nil {
+		return 0, err
+	}
+	return count, nil
+}
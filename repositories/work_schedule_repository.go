@@ -0,0 +1,120 @@
+package repositories
+
+import (
+	"github.com/r-52/embrace/models"
+	"gorm.io/gorm"
+)
+
+type WorkScheduleRepository struct {
+	Database *gorm.DB
+}
+
+type WorkScheduleRepositoryInterface interface {
+	// GetByID retrieves a work schedule together with its days by its ID.
+	// It takes an unsigned integer `id` as input and returns a pointer to a `models.WorkSchedule` instance and an error.
+	GetByID(id uint) (*models.WorkSchedule, error)
+
+	// GetByCompanyID retrieves the work schedules of a company together with their days, ordered by name.
+	// It takes an unsigned integer `companyID` as input and returns a slice of `models.WorkSchedule` instances and an error.
+	GetByCompanyID(companyID uint) ([]models.WorkSchedule, error)
+
+	// Create inserts a new work schedule and its days into the database.
+	// It takes a pointer to a `models.WorkSchedule` instance as input and returns an error.
+	Create(schedule *models.WorkSchedule) error
+
+	// Update updates an existing work schedule in the database and replaces its days.
+	// It takes a pointer to a `models.WorkSchedule` instance as input and returns an error.
+	Update(schedule *models.WorkSchedule) error
+
+	// Delete removes a work schedule and its days from the database by its ID.
+	// It takes an unsigned integer `id` as input and returns an error.
+	Delete(id uint) error
+}
+
+// NewWorkScheduleRepository creates a new instance of WorkScheduleRepository with the provided database connection.
+// It takes a *gorm.DB as an argument, which represents the database connection, and returns a pointer to a WorkScheduleRepository.
+func NewWorkScheduleRepository(db *gorm.DB) *WorkScheduleRepository {
+	return &WorkScheduleRepository{
+		Database: db,
+	}
+}
+
+func orderedWorkScheduleDays(db *gorm.DB) *gorm.DB {
+	return db.Order("week, weekday")
+}
+
+// GetByID retrieves a work schedule together with its days, ordered by week and weekday, by its ID.
+// If the work schedule with the specified ID is not found or if there is a database error, it returns a non-nil error.
+func (r *WorkScheduleRepository) GetByID(id uint) (*models.WorkSchedule, error) {
+	var schedule models.WorkSchedule
+	err := r.Database.Preload("Days", orderedWorkScheduleDays).First(&schedule, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &schedule, nil
+}
+
+// GetByCompanyID retrieves the work schedules of a company together with their days, ordered by name.
+// If there is a database error, it returns a non-nil error.
+func (r *WorkScheduleRepository) GetByCompanyID(companyID uint) ([]models.WorkSchedule, error) {
+	var schedules []models.WorkSchedule
+	err := r.Database.Preload("Days", orderedWorkScheduleDays).Where("company_id = ?", companyID).Order("name, id").Find(&schedules).Error
+	if err != nil {
+		return nil, err
+	}
+	return schedules, nil
+}
+
+// Create inserts a new work schedule and its days into the database.
+// If the create operation fails, it returns a non-nil error.
+func (r *WorkScheduleRepository) Create(schedule *models.WorkSchedule) error {
+	err := r.Database.Create(schedule).Error
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// Update updates an existing work schedule in the database and replaces its days with the ones of `schedule`.
+// The previous days are removed permanently. If a database operation fails, it returns a non-nil error.
+func (r *WorkScheduleRepository) Update(schedule *models.WorkSchedule) error {
+	err := r.Database.Omit("Days").Save(schedule).Error
+	if err != nil {
+		return err
+	}
+	err = r.Database.Unscoped().Where("work_schedule_id = ?", schedule.ID).Delete(&models.WorkScheduleDay{}).Error
+	if err != nil {
+		return err
+	}
+	if len(schedule.Days) == 0 {
+		return nil
+	}
+	for i := range schedule.Days {
+		schedule.Days[i].ID = 0
+		schedule.Days[i].WorkScheduleID = schedule.ID
+	}
+	err = r.Database.Create(&schedule.Days).Error
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// Delete removes a work schedule and its days from the database by its ID.
+// If the work schedule with the specified ID is not found or if the delete operation fails, it returns a non-nil error.
+func (r *WorkScheduleRepository) Delete(id uint) error {
+	var schedule models.WorkSchedule
+	err := r.Database.First(&schedule, id).Error
+	if err != nil {
+		return err
+	}
+	err = r.Database.Where("work_schedule_id = ?", schedule.ID).Delete(&models.WorkScheduleDay{}).Error
+	if err != nil {
+		return err
+	}
+	err = r.Database.Delete(&schedule).Error
+	if err != nil {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,115 @@
+package repositories_test
+
+import (
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/r-52/embrace/models"
+	"github.com/r-52/embrace/repositories"
+	"gorm.io/gorm"
+)
+
+// setupWorkScheduleTestDB initializes the database for testing using the common setup method.
+func setupWorkScheduleTestDB(t *testing.T) *gorm.DB {
+	db := GetDatabase() // Use the method from common_test.go
+
+	// Auto-migrate the WorkSchedule, WorkScheduleDay and UserWorkSchedule models
+	err := db.AutoMigrate(&models.WorkSchedule{}, &models.WorkScheduleDay{}, &models.UserWorkSchedule{})
+	if err != nil {
+		t.Fatalf("failed to migrate database: %v", err)
+	}
+
+	return db
+}
+
+func TestWorkScheduleRepository_Update_Replaces_Days(t *testing.T) {
+	db := setupWorkScheduleTestDB(t)
+	repo := repositories.NewWorkScheduleRepository(db)
+
+	schedule := &models.WorkSchedule{CompanyID: 1, Name: "Part time", Kind: models.WORK_SCHEDULE_WEEKDAYS, Days: []models.WorkScheduleDay{
+		{Weekday: time.Tuesday, Hours: 6},
+		{Weekday: time.Monday, Hours: 8},
+	}}
+	if err := repo.Create(schedule); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	loaded, err := repo.GetByID(schedule.ID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(loaded.Days) != 2 || loaded.Days[0].Weekday != time.Monday {
+		t.Errorf("expected the days ordered by weekday, got %v", loaded.Days)
+	}
+
+	loaded.Name = "Mondays"
+	loaded.Days = []models.WorkScheduleDay{{Weekday: time.Monday, Hours: 4}}
+	if err := repo.Update(loaded); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	loaded, _ = repo.GetByID(schedule.ID)
+	if loaded.Name != "Mondays" || len(loaded.Days) != 1 || loaded.Days[0].Hours != 4 {
+		t.Errorf("expected the days to be replaced, got %+v", loaded)
+	}
+
+	if err := repo.Delete(schedule.ID); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var remaining int64
+	db.Model(&models.WorkScheduleDay{}).Count(&remaining)
+	if remaining != 0 {
+		t.Errorf("expected the days to be deleted with the schedule, got %d", remaining)
+	}
+}
+
+func TestUserWorkScheduleRepository_GetByUserIDBetween(t *testing.T) {
+	db := setupWorkScheduleTestDB(t)
+	repo := repositories.NewUserWorkScheduleRepository(db)
+
+	schedule := &models.WorkSchedule{CompanyID: 1, Name: "Full time", Kind: models.WORK_SCHEDULE_WEEKLY, WeeklyHours: 40, WorkingDays: 5}
+	if err := repositories.NewWorkScheduleRepository(db).Create(schedule); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	january := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	march := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	past := &models.UserWorkSchedule{UserID: 1, WorkScheduleID: schedule.ID, ValidFrom: january, ValidUntil: sql.NullTime{Time: march.AddDate(0, 0, -1), Valid: true}}
+	current := &models.UserWorkSchedule{UserID: 1, WorkScheduleID: schedule.ID, ValidFrom: march}
+	other := &models.UserWorkSchedule{UserID: 2, WorkScheduleID: schedule.ID, ValidFrom: january}
+	for _, assignment := range []*models.UserWorkSchedule{current, past, other} {
+		if err := repo.Create(assignment); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	tests := []struct {
+		name     string
+		from     time.Time
+		to       time.Time
+		expected []uint
+	}{
+		{name: "both", from: january.AddDate(0, 1, 0), to: march, expected: []uint{past.ID, current.ID}},
+		{name: "past only", from: january, to: january.AddDate(0, 0, 10), expected: []uint{past.ID}},
+		{name: "open end", from: march.AddDate(1, 0, 0), to: march.AddDate(1, 0, 0), expected: []uint{current.ID}},
+		{name: "before", from: january.AddDate(-1, 0, 0), to: january.AddDate(0, 0, -1)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assignments, err := repo.GetByUserIDBetween(1, tt.from, tt.to)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if len(assignments) != len(tt.expected) {
+				t.Fatalf("expected %d assignments, got %d", len(tt.expected), len(assignments))
+			}
+			for i, assignment := range assignments {
+				if assignment.ID != tt.expected[i] || assignment.WorkSchedule.ID != schedule.ID {
+					t.Errorf("unexpected assignment %+v", assignment)
+				}
+			}
+		})
+	}
+
+	if count, _ := repo.CountByWorkScheduleID(schedule.ID); count != 3 {
+		t.Errorf("expected 3 assignments, got %d", count)
+	}
+}
@@ -0,0 +1,65 @@
+package audit
+
+import (
+	"github.com/r-52/embrace/models"
+	dto "github.com/r-52/embrace/models/dto/audit"
+	"github.com/r-52/embrace/repositories"
+	"gorm.io/gorm"
+)
+
+// DEFAULT_LIMIT is the number of audit logs List returns when the request sets no limit.
+const DEFAULT_LIMIT = 100
+
+// AuditLogService reads the audit log of a company, see models.AuditLog. The logs are written by the callbacks of
+// repositories.RegisterAuditCallbacks.
+type AuditLogService struct {
+	unitOfWork *repositories.UnitOfWork
+}
+
+type AuditLogServiceInterface interface {
+	List(req *dto.ListAuditLogsRequest) ([]models.AuditLog, error)
+	Export(req *dto.ListAuditLogsRequest) ([]models.AuditLog, error)
+}
+
+// NewAuditLogService creates an AuditLogService. The database should be scoped to the actor's company, see repositories.WithTenant.
+func NewAuditLogService(db *gorm.DB) *AuditLogService {
+	return NewAuditLogServiceWithUnitOfWork(repositories.NewUnitOfWork(db))
+}
+
+// NewAuditLogServiceWithUnitOfWork creates an AuditLogService whose repositories join the given unit of work.
+func NewAuditLogServiceWithUnitOfWork(uow *repositories.UnitOfWork) *AuditLogService {
+	return &AuditLogService{
+		unitOfWork: uow,
+	}
+}
+
+// List returns the audit logs matching the request, newest first. Without a limit it returns DEFAULT_LIMIT logs.
+func (s *AuditLogService) List(req *dto.ListAuditLogsRequest) ([]models.AuditLog, error) {
+	filter := filterOf(req)
+	filter.NewestFirst = true
+	if filter.Limit == 0 {
+		filter.Limit = DEFAULT_LIMIT
+	}
+	return s.unitOfWork.AuditLogs().Find(filter)
+}
+
+// Export returns every audit log matching the request in the order they were recorded.
+func (s *AuditLogService) Export(req *dto.ListAuditLogsRequest) ([]models.AuditLog, error) {
+	return s.unitOfWork.AuditLogs().Find(filterOf(req))
+}
+
+func filterOf(req *dto.ListAuditLogsRequest) repositories.AuditLogFilter {
+	filter := repositories.AuditLogFilter{
+		Entity:    req.Entity,
+		EntityID:  req.EntityID,
+		ActorID:   req.ActorID,
+		Operation: req.Operation,
+		From:      req.From,
+		Limit:     req.Limit,
+		Offset:    req.Offset,
+	}
+	if !req.To.IsZero() {
+		filter.To = req.To.AddDate(0, 0, 1)
+	}
+	return filter
+}
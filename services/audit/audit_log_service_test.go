@@ -0,0 +1,69 @@
+package audit_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/r-52/embrace/models"
+	dto "github.com/r-52/embrace/models/dto/audit"
+	"github.com/r-52/embrace/repositories"
+	"github.com/r-52/embrace/services/audit"
+	"gorm.io/gorm"
+)
+
+func setupDb(t *testing.T) *gorm.DB {
+	db := repositories.GetDatabase()
+	if err := repositories.RegisterAuditCallbacks(db); err != nil {
+		t.Fatalf("failed to register audit callbacks: %v", err)
+	}
+	err := db.AutoMigrate(&models.Company{}, &models.UserRole{}, &models.RolePermission{}, &models.AuditLog{})
+	if err != nil {
+		t.Fatalf("failed to migrate database: %v", err)
+	}
+	return db
+}
+
+func TestAuditLogService_List_And_Export(t *testing.T) {
+	db := setupDb(t)
+	own := &models.Company{Name: "Own", PrimaryEmail: "own@company.com"}
+	foreign := &models.Company{Name: "Foreign", PrimaryEmail: "foreign@company.com"}
+	for _, company := range []*models.Company{own, foreign} {
+		if err := db.Create(company).Error; err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	tenant := repositories.WithActor(repositories.WithTenant(db, own.ID), 1)
+	roles := repositories.NewUserRoleRepository(tenant)
+	role := &models.UserRole{Name: "admin"}
+	if err := roles.Create(role); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	role.Name = "owner"
+	if err := roles.Update(role); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := repositories.NewUserRoleRepository(repositories.WithTenant(db, foreign.ID)).Create(&models.UserRole{Name: "admin"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	service := audit.NewAuditLogService(tenant)
+	auditLogs, err := service.List(&dto.ListAuditLogsRequest{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(auditLogs) != 3 || auditLogs[0].Operation != models.AUDIT_OPERATION_UPDATE || auditLogs[2].Entity != "companies" {
+		t.Errorf("expected the logs of the own company newest first, got %+v", auditLogs)
+	}
+
+	today := time.Now().UTC().Truncate(24 * time.Hour)
+	exported, err := service.Export(&dto.ListAuditLogsRequest{Entity: "user_roles", From: today, To: today})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(exported) != 2 || exported[0].Operation != models.AUDIT_OPERATION_CREATE || *exported[1].ActorID != 1 {
+		t.Errorf("expected the role's logs of today in the order they were recorded, got %+v", exported)
+	}
+	if exported, _ := service.Export(&dto.ListAuditLogsRequest{To: today.AddDate(0, 0, -1)}); len(exported) != 0 {
+		t.Errorf("expected no logs before today, got %+v", exported)
+	}
+}
@@ -0,0 +1,203 @@
+package auth
+
+import (
+	"errors"
+	"time"
+
+	"github.com/r-52/embrace/models"
+	dto "github.com/r-52/embrace/models/dto/auth"
+	"github.com/r-52/embrace/repositories"
+	"github.com/r-52/embrace/services/user"
+	"gorm.io/gorm"
+)
+
+type Authenticator struct {
+	unitOfWork   *repositories.UnitOfWork
+	tokenService *TokenService
+}
+
+type AuthenticatorInterface interface {
+	Login(req *dto.LoginRequest) (*dto.TokenResponse, error)
+	Refresh(req *dto.RefreshRequest) (*dto.TokenResponse, error)
+	Logout(req *dto.RefreshRequest) error
+	Authenticate(accessToken string) (*models.User, error)
+}
+
+func NewAuthenticator(db *gorm.DB, tokenService *TokenService) *Authenticator {
+	return &Authenticator{
+		unitOfWork:   repositories.NewUnitOfWork(db),
+		tokenService: tokenService,
+	}
+}
+
+// Login verifies the user's credentials and issues a new access and refresh token pair.
+// The email has to match exactly apart from case. The refresh token starts a new rotation family. Unknown emails and
+// wrong passwords both return ErrInvalidCredentials.
+func (a *Authenticator) Login(req *dto.LoginRequest) (*dto.TokenResponse, error) {
+	existingUser, err := a.unitOfWork.Users().GetByExactEmail(req.Email)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrInvalidCredentials
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	match, err := user.NewPasswordService(req.Password).ComparePassword(existingUser.Password)
+	if err != nil || !match {
+		return nil, ErrInvalidCredentials
+	}
+
+	familyID, err := NewTokenID()
+	if err != nil {
+		return nil, err
+	}
+
+	var response *dto.TokenResponse
+	err = a.unitOfWork.Transaction(func(uow *repositories.UnitOfWork) error {
+		response, _, err = a.issueTokens(uow, existingUser, familyID, time.Now())
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return response, nil
+}
+
+// Refresh rotates a refresh token: the presented token is revoked and a new token pair of the same family is issued.
+// Presenting a token that was already rotated or revoked, even by a concurrent refresh, is treated as token theft and
+// revokes the whole family.
+func (a *Authenticator) Refresh(req *dto.RefreshRequest) (*dto.TokenResponse, error) {
+	claims, err := a.tokenService.ParseToken(req.RefreshToken, TOKEN_TYPE_REFRESH)
+	if err != nil {
+		return nil, err
+	}
+
+	var response *dto.TokenResponse
+	var reused bool
+	err = a.unitOfWork.Transaction(func(uow *repositories.UnitOfWork) error {
+		now := time.Now()
+		stored, err := uow.RefreshTokens().GetByTokenID(claims.ID)
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrInvalidToken
+		}
+		if err != nil {
+			return err
+		}
+		if stored.RevokedAt.Valid {
+			reused = true
+			return ErrTokenRevoked
+		}
+		if !stored.IsActive(now) {
+			return ErrInvalidToken
+		}
+
+		existingUser, err := uow.Users().GetByID(stored.UserID)
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrInvalidToken
+		}
+		if err != nil {
+			return err
+		}
+
+		var issued *models.RefreshToken
+		response, issued, err = a.issueTokens(uow, existingUser, stored.FamilyID, now)
+		if err != nil {
+			return err
+		}
+
+		// The token is revoked only if it is still active, a concurrent refresh with the same token is a reuse.
+		err = uow.RefreshTokens().Rotate(stored.TokenID, issued.TokenID, now)
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			reused = true
+			return ErrTokenRevoked
+		}
+		return err
+	})
+	if reused {
+		if revokeErr := a.revokeFamily(claims.ID); revokeErr != nil {
+			return nil, revokeErr
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+	return response, nil
+}
+
+// Logout revokes the presented refresh token together with every token rotated from the same login.
+func (a *Authenticator) Logout(req *dto.RefreshRequest) error {
+	claims, err := a.tokenService.ParseToken(req.RefreshToken, TOKEN_TYPE_REFRESH)
+	if err != nil {
+		return err
+	}
+	return a.revokeFamily(claims.ID)
+}
+
+// Authenticate verifies an access token and loads the user it was issued for, including company and role.
+func (a *Authenticator) Authenticate(accessToken string) (*models.User, error) {
+	claims, err := a.tokenService.ParseToken(accessToken, TOKEN_TYPE_ACCESS)
+	if err != nil {
+		return nil, err
+	}
+	userID, err := claims.UserID()
+	if err != nil {
+		return nil, err
+	}
+
+	currentUser, err := a.unitOfWork.Users().GetByIDWithCompanyAndRole(userID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrInvalidToken
+	}
+	if err != nil {
+		return nil, err
+	}
+	if currentUser.CompanyID != claims.CompanyID {
+		return nil, ErrInvalidToken
+	}
+	return currentUser, nil
+}
+
+func (a *Authenticator) revokeFamily(tokenID string) error {
+	stored, err := a.unitOfWork.RefreshTokens().GetByTokenID(tokenID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return ErrInvalidToken
+	}
+	if err != nil {
+		return err
+	}
+	return a.unitOfWork.RefreshTokens().RevokeFamily(stored.FamilyID, time.Now())
+}
+
+func (a *Authenticator) issueTokens(uow *repositories.UnitOfWork, tokenUser *models.User, familyID string, now time.Time) (*dto.TokenResponse, *models.RefreshToken, error) {
+	accessToken, accessExpiresAt, err := a.tokenService.IssueAccessToken(tokenUser, now)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	tokenID, err := NewTokenID()
+	if err != nil {
+		return nil, nil, err
+	}
+	refreshToken, refreshExpiresAt, err := a.tokenService.IssueRefreshToken(tokenUser, tokenID, now)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	stored := &models.RefreshToken{
+		TokenID:   tokenID,
+		FamilyID:  familyID,
+		UserID:    tokenUser.ID,
+		ExpiresAt: refreshExpiresAt,
+	}
+	if err := uow.RefreshTokens().Create(stored); err != nil {
+		return nil, nil, err
+	}
+
+	return &dto.TokenResponse{
+		AccessToken:           accessToken,
+		AccessTokenExpiresAt:  accessExpiresAt,
+		RefreshToken:          refreshToken,
+		RefreshTokenExpiresAt: refreshExpiresAt,
+		TokenType:             "Bearer",
+	}, stored, nil
+}
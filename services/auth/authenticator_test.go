@@ -0,0 +1,222 @@
+package auth_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/r-52/embrace/models"
+	dto "github.com/r-52/embrace/models/dto/auth"
+	"github.com/r-52/embrace/repositories"
+	"github.com/r-52/embrace/services/auth"
+	"github.com/r-52/embrace/services/user"
+	"gorm.io/gorm"
+)
+
+func setupDb(t *testing.T) *gorm.DB {
+	db := repositories.GetDatabase()
+	err := db.AutoMigrate(&models.Company{}, &models.User{}, &models.UserProfile{}, &models.UserRole{}, &models.RolePermission{}, &models.RefreshToken{})
+	if err != nil {
+		t.Fatalf("failed to migrate database: %v", err)
+	}
+	return db
+}
+
+func seedUser(t *testing.T, db *gorm.DB, email, password string) *models.User {
+	hash, err := user.NewPasswordService(password).HashPassword()
+	if err != nil {
+		t.Fatalf("failed to hash password: %v", err)
+	}
+	company := &models.Company{Name: "Company " + email, PrimaryEmail: email}
+	db.Create(company)
+	seeded := &models.User{
+		Email:       email,
+		Password:    hash,
+		CompanyID:   company.ID,
+		Role:        models.UserRole{Name: "admin " + email, CompanyID: company.ID},
+		UserProfile: models.UserProfile{Slug: email},
+	}
+	if err := db.Create(seeded).Error; err != nil {
+		t.Fatalf("failed to seed user: %v", err)
+	}
+	return seeded
+}
+
+func newAuthenticator(db *gorm.DB) *auth.Authenticator {
+	return auth.NewAuthenticator(db, auth.NewTokenService([]byte("secret")))
+}
+
+func TestAuthenticator_Login_With_Success(t *testing.T) {
+	db := setupDb(t)
+	seeded := seedUser(t, db, "login@test.com", "password")
+	authenticator := newAuthenticator(db)
+
+	tokens, err := authenticator.Login(&dto.LoginRequest{Email: "login@test.com", Password: "password"})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	current, err := authenticator.Authenticate(tokens.AccessToken)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if current.ID != seeded.ID || current.Company.ID != seeded.CompanyID || current.Role.ID != seeded.RoleID {
+		t.Errorf("expected user with company and role, got %+v", current)
+	}
+}
+
+func TestAuthenticator_Login_With_Invalid_Credentials(t *testing.T) {
+	db := setupDb(t)
+	seedUser(t, db, "wrong@test.com", "password")
+	authenticator := newAuthenticator(db)
+
+	_, err := authenticator.Login(&dto.LoginRequest{Email: "wrong@test.com", Password: "not-the-password"})
+	if !errors.Is(err, auth.ErrInvalidCredentials) {
+		t.Errorf("expected ErrInvalidCredentials, got %v", err)
+	}
+
+	_, err = authenticator.Login(&dto.LoginRequest{Email: "unknown@test.com", Password: "password"})
+	if !errors.Is(err, auth.ErrInvalidCredentials) {
+		t.Errorf("expected ErrInvalidCredentials, got %v", err)
+	}
+
+	// Wildcards do not match another user's email.
+	_, err = authenticator.Login(&dto.LoginRequest{Email: "wrong@%", Password: "password"})
+	if !errors.Is(err, auth.ErrInvalidCredentials) {
+		t.Errorf("expected ErrInvalidCredentials for a wildcard, got %v", err)
+	}
+}
+
+func TestAuthenticator_Login_Ignores_Case_Of_Email(t *testing.T) {
+	db := setupDb(t)
+	seedUser(t, db, "case@test.com", "password")
+	authenticator := newAuthenticator(db)
+
+	if _, err := authenticator.Login(&dto.LoginRequest{Email: "Case@Test.com", Password: "password"}); err != nil {
+		t.Errorf("expected no error, got %v", err)
+	}
+}
+
+func TestAuthenticator_Authenticate_Rejects_Refresh_Token(t *testing.T) {
+	db := setupDb(t)
+	seedUser(t, db, "type@test.com", "password")
+	authenticator := newAuthenticator(db)
+
+	tokens, err := authenticator.Login(&dto.LoginRequest{Email: "type@test.com", Password: "password"})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	_, err = authenticator.Authenticate(tokens.RefreshToken)
+	if !errors.Is(err, auth.ErrInvalidToken) {
+		t.Errorf("expected ErrInvalidToken, got %v", err)
+	}
+}
+
+func TestAuthenticator_Refresh_Rotates_Token(t *testing.T) {
+	db := setupDb(t)
+	seedUser(t, db, "rotate@test.com", "password")
+	authenticator := newAuthenticator(db)
+
+	tokens, err := authenticator.Login(&dto.LoginRequest{Email: "rotate@test.com", Password: "password"})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	rotated, err := authenticator.Refresh(&dto.RefreshRequest{RefreshToken: tokens.RefreshToken})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if rotated.RefreshToken == tokens.RefreshToken {
+		t.Errorf("expected a new refresh token")
+	}
+
+	// The rotated token can be used once more.
+	if _, err := authenticator.Refresh(&dto.RefreshRequest{RefreshToken: rotated.RefreshToken}); err != nil {
+		t.Errorf("expected no error, got %v", err)
+	}
+}
+
+func TestAuthenticator_Refresh_Reuse_Revokes_Family(t *testing.T) {
+	db := setupDb(t)
+	seedUser(t, db, "reuse@test.com", "password")
+	authenticator := newAuthenticator(db)
+
+	tokens, err := authenticator.Login(&dto.LoginRequest{Email: "reuse@test.com", Password: "password"})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	rotated, err := authenticator.Refresh(&dto.RefreshRequest{RefreshToken: tokens.RefreshToken})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	// Replaying the original token must fail and revoke the rotated one as well.
+	_, err = authenticator.Refresh(&dto.RefreshRequest{RefreshToken: tokens.RefreshToken})
+	if !errors.Is(err, auth.ErrTokenRevoked) {
+		t.Fatalf("expected ErrTokenRevoked, got %v", err)
+	}
+	_, err = authenticator.Refresh(&dto.RefreshRequest{RefreshToken: rotated.RefreshToken})
+	if !errors.Is(err, auth.ErrTokenRevoked) {
+		t.Errorf("expected ErrTokenRevoked, got %v", err)
+	}
+}
+
+func TestAuthenticator_Refresh_Concurrent_Reuse_Revokes_Family(t *testing.T) {
+	db := setupDb(t)
+	seedUser(t, db, "race@test.com", "password")
+	authenticator := newAuthenticator(db)
+
+	tokens, err := authenticator.Login(&dto.LoginRequest{Email: "race@test.com", Password: "password"})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	claims, err := auth.NewTokenService([]byte("secret")).ParseToken(tokens.RefreshToken, auth.TOKEN_TYPE_REFRESH)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	// A concurrent refresh revokes the token after it was read, but before it is rotated.
+	raced := false
+	err = db.Callback().Update().Before("gorm:update").Register("test:race", func(tx *gorm.DB) {
+		if raced || tx.Statement.Table != "refresh_tokens" {
+			return
+		}
+		raced = true
+		tx.Session(&gorm.Session{NewDB: true, SkipHooks: true}).Exec("UPDATE refresh_tokens SET revoked_at = CURRENT_TIMESTAMP WHERE token_id = ?", claims.ID)
+	})
+	if err != nil {
+		t.Fatalf("failed to register callback: %v", err)
+	}
+	defer db.Callback().Update().Remove("test:race")
+
+	_, err = authenticator.Refresh(&dto.RefreshRequest{RefreshToken: tokens.RefreshToken})
+	if !errors.Is(err, auth.ErrTokenRevoked) {
+		t.Errorf("expected ErrTokenRevoked, got %v", err)
+	}
+	var count int64
+	db.Model(&models.RefreshToken{}).Count(&count)
+	if count != 1 {
+		t.Errorf("expected the issued token to be rolled back, got %d tokens", count)
+	}
+}
+
+func TestAuthenticator_Logout_Revokes_Refresh_Token(t *testing.T) {
+	db := setupDb(t)
+	seedUser(t, db, "logout@test.com", "password")
+	authenticator := newAuthenticator(db)
+
+	tokens, err := authenticator.Login(&dto.LoginRequest{Email: "logout@test.com", Password: "password"})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if err := authenticator.Logout(&dto.RefreshRequest{RefreshToken: tokens.RefreshToken}); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	_, err = authenticator.Refresh(&dto.RefreshRequest{RefreshToken: tokens.RefreshToken})
+	if !errors.Is(err, auth.ErrTokenRevoked) {
+		t.Errorf("expected ErrTokenRevoked, got %v", err)
+	}
+}
@@ -0,0 +1,15 @@
+package auth
+
+import "errors"
+
+// ErrInvalidCredentials is returned when the email is unknown or the password does not match.
+var ErrInvalidCredentials = errors.New("E2000")
+
+// ErrInvalidToken is returned when a token is malformed, expired, of the wrong type or not signed by us.
+var ErrInvalidToken = errors.New("E2001")
+
+// ErrTokenRevoked is returned when a refresh token was revoked, rotated or logged out.
+var ErrTokenRevoked = errors.New("E2002")
+
+// ErrPermissionDenied is returned when the authenticated user's role lacks a required permission.
+var ErrPermissionDenied = errors.New("E2003")
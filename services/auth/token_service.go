@@ -0,0 +1,118 @@
+package auth
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/r-52/embrace/models"
+)
+
+const (
+	TOKEN_TYPE_ACCESS  = "access"
+	TOKEN_TYPE_REFRESH = "refresh"
+)
+
+const DEFAULT_ACCESS_TOKEN_TTL = 15 * time.Minute
+const DEFAULT_REFRESH_TOKEN_TTL = 7 * 24 * time.Hour
+
+// Claims are the JWT claims of both access and refresh tokens.
+type Claims struct {
+	jwt.RegisteredClaims
+	CompanyID uint   `json:"cid"`
+	TokenType string `json:"typ"`
+}
+
+// UserID returns the ID of the user the token was issued for.
+func (c *Claims) UserID() (uint, error) {
+	id, err := strconv.ParseUint(c.Subject, 10, 64)
+	if err != nil {
+		return 0, ErrInvalidToken
+	}
+	return uint(id), nil
+}
+
+type TokenService struct {
+	secret          []byte
+	accessTokenTTL  time.Duration
+	refreshTokenTTL time.Duration
+}
+
+type TokenServiceInterface interface {
+	IssueAccessToken(user *models.User, now time.Time) (string, time.Time, error)
+	IssueRefreshToken(user *models.User, tokenID string, now time.Time) (string, time.Time, error)
+	ParseToken(token string, tokenType string) (*Claims, error)
+}
+
+// NewTokenService creates a TokenService that signs tokens with HMAC-SHA256 using the given secret.
+func NewTokenService(secret []byte) *TokenService {
+	return &TokenService{
+		secret:          secret,
+		accessTokenTTL:  DEFAULT_ACCESS_TOKEN_TTL,
+		refreshTokenTTL: DEFAULT_REFRESH_TOKEN_TTL,
+	}
+}
+
+// IssueAccessToken signs a short-lived access token for the user.
+// It returns the signed token and its expiry.
+func (s *TokenService) IssueAccessToken(user *models.User, now time.Time) (string, time.Time, error) {
+	tokenID, err := NewTokenID()
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	return s.sign(user, TOKEN_TYPE_ACCESS, tokenID, now, s.accessTokenTTL)
+}
+
+// IssueRefreshToken signs a refresh token for the user with the given token ID.
+// The token ID is stored server side so the token can be rotated and revoked.
+func (s *TokenService) IssueRefreshToken(user *models.User, tokenID string, now time.Time) (string, time.Time, error) {
+	return s.sign(user, TOKEN_TYPE_REFRESH, tokenID, now, s.refreshTokenTTL)
+}
+
+// ParseToken verifies the signature and expiry of a token and checks that it has the expected type.
+// It returns ErrInvalidToken for every token that cannot be trusted.
+func (s *TokenService) ParseToken(token string, tokenType string) (*Claims, error) {
+	claims := &Claims{}
+	_, err := jwt.ParseWithClaims(token, claims, func(t *jwt.Token) (interface{}, error) {
+		return s.secret, nil
+	}, jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}), jwt.WithExpirationRequired())
+	if err != nil {
+		return nil, errors.Join(ErrInvalidToken, err)
+	}
+	if claims.TokenType != tokenType || claims.ID == "" {
+		return nil, ErrInvalidToken
+	}
+	return claims, nil
+}
+
+func (s *TokenService) sign(user *models.User, tokenType string, tokenID string, now time.Time, ttl time.Duration) (string, time.Time, error) {
+	expiresAt := now.Add(ttl)
+	claims := Claims{
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        tokenID,
+			Subject:   strconv.FormatUint(uint64(user.ID), 10),
+			IssuedAt:  jwt.NewNumericDate(now),
+			NotBefore: jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(expiresAt),
+		},
+		CompanyID: user.CompanyID,
+		TokenType: tokenType,
+	}
+	signed, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(s.secret)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	return signed, expiresAt, nil
+}
+
+// NewTokenID returns a random identifier used for the `jti` claim and rotation families.
+func NewTokenID() (string, error) {
+	buffer := make([]byte, 16)
+	if _, err := rand.Read(buffer); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(buffer), nil
+}
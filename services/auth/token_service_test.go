@@ -0,0 +1,80 @@
+package auth_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/r-52/embrace/models"
+	"github.com/r-52/embrace/services/auth"
+)
+
+func TestTokenService_IssueAndParseAccessToken(t *testing.T) {
+	tokenService := auth.NewTokenService([]byte("secret"))
+	user := &models.User{CompanyID: 3}
+	user.ID = 7
+
+	token, expiresAt, err := tokenService.IssueAccessToken(user, time.Now())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !expiresAt.After(time.Now()) {
+		t.Errorf("expected expiry in the future, got %v", expiresAt)
+	}
+
+	claims, err := tokenService.ParseToken(token, auth.TOKEN_TYPE_ACCESS)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	userID, err := claims.UserID()
+	if err != nil || userID != 7 || claims.CompanyID != 3 {
+		t.Errorf("unexpected claims: %+v", claims)
+	}
+}
+
+func TestTokenService_ParseToken_Rejects_Wrong_Type(t *testing.T) {
+	tokenService := auth.NewTokenService([]byte("secret"))
+	user := &models.User{}
+	user.ID = 1
+
+	token, _, err := tokenService.IssueRefreshToken(user, "token-id", time.Now())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	_, err = tokenService.ParseToken(token, auth.TOKEN_TYPE_ACCESS)
+	if !errors.Is(err, auth.ErrInvalidToken) {
+		t.Errorf("expected ErrInvalidToken, got %v", err)
+	}
+}
+
+func TestTokenService_ParseToken_Rejects_Foreign_Signature(t *testing.T) {
+	user := &models.User{}
+	user.ID = 1
+
+	token, _, err := auth.NewTokenService([]byte("other")).IssueAccessToken(user, time.Now())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	_, err = auth.NewTokenService([]byte("secret")).ParseToken(token, auth.TOKEN_TYPE_ACCESS)
+	if !errors.Is(err, auth.ErrInvalidToken) {
+		t.Errorf("expected ErrInvalidToken, got %v", err)
+	}
+}
+
+func TestTokenService_ParseToken_Rejects_Expired_Token(t *testing.T) {
+	tokenService := auth.NewTokenService([]byte("secret"))
+	user := &models.User{}
+	user.ID = 1
+
+	token, _, err := tokenService.IssueAccessToken(user, time.Now().Add(-time.Hour))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	_, err = tokenService.ParseToken(token, auth.TOKEN_TYPE_ACCESS)
+	if !errors.Is(err, auth.ErrInvalidToken) {
+		t.Errorf("expected ErrInvalidToken, got %v", err)
+	}
+}
@@ -1,49 +1,116 @@
 package company
 
 import (
+	"errors"
+
 	"github.com/r-52/embrace/models"
 	"github.com/r-52/embrace/models/dto/company"
+	users "github.com/r-52/embrace/models/dto/user"
 	"github.com/r-52/embrace/repositories"
+	"github.com/r-52/embrace/services/role"
 	"github.com/r-52/embrace/services/user"
 	"gorm.io/gorm"
 )
 
 type CompanyCreator struct {
-	companyRepository *repositories.CompanyRepository
-	userCreator       user.UserCreator
+	unitOfWork *repositories.UnitOfWork
 }
 
 type CompanyCreatorInterface interface {
-	CreateCompany(req *company.CreateCompanyRequest) (*models.Company, error)
+	CreateCompany(req *company.CreateCompanyRequest) (*company.CreateCompanyResponse, error)
 }
 
 func NewCompanyCreator(db *gorm.DB) *CompanyCreator {
 	return &CompanyCreator{
-		companyRepository: repositories.NewCompanyRepository(db),
-		userCreator:       *user.NewUserCreator(db),
+		unitOfWork: repositories.NewUnitOfWork(db),
 	}
 }
 
-// CreateCompany creates a new company in the database.
-// It takes a pointer to a `company.CreateCompanyRequest` instance as input and returns a pointer to a `models.Company` instance and an error.
-// If the creation fails, it returns a non-nil error.
-func (c *CompanyCreator) CreateCompany(req *company.CreateCompanyRequest) (*models.Company, error) {
-	company := &models.Company{
-		Name:         req.Name,
-		Description:  req.Description,
-		Website:      req.Website,
-		PrimaryEmail: req.User.Email,
-	}
-	err := c.companyRepository.Create(company)
-	if err != nil {
-		return nil, err
-	}
-	req.User.CompanyID = company.ID
+// CreateCompany creates a new company together with its first admin user.
+// It takes a pointer to a `company.CreateCompanyRequest` instance as input and returns a pointer to a `company.CreateCompanyResponse` instance and an error.
+// The company's default roles are seeded first and the user is attached to its admin role.
+// The company, the roles, the user and its profile are written in one transaction, so either all of them are stored or none.
+// If a company with the same name exists it returns ErrCompanyAlreadyExists, if the admin email is taken it returns user.ErrEmailAlreadyExists.
+func (c *CompanyCreator) CreateCompany(req *company.CreateCompanyRequest) (*company.CreateCompanyResponse, error) {
+	var response *company.CreateCompanyResponse
+	err := c.unitOfWork.Transaction(func(uow *repositories.UnitOfWork) error {
+		_, err := uow.Companies().GetByName(req.Name)
+		if err == nil {
+			return ErrCompanyAlreadyExists
+		}
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			return err
+		}
+		_, err = uow.Users().GetByEmail(req.User.Email)
+		if err == nil {
+			return user.ErrEmailAlreadyExists
+		}
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			return err
+		}
+
+		newCompany := &models.Company{
+			Name:         req.Name,
+			Description:  req.Description,
+			Website:      req.Website,
+			PrimaryEmail: req.User.Email,
+
+			Timezone:           req.Timezone,
+			AllowNegativeQuota: req.AllowNegativeQuota,
+
+			FullTimeWorkingDays: req.FullTimeWorkingDays,
+			QuotaRounding:       req.QuotaRounding,
+		}
+		if newCompany.Timezone == "" {
+			newCompany.Timezone = "UTC"
+		}
+		if newCompany.FullTimeWorkingDays == 0 {
+			newCompany.FullTimeWorkingDays = 5
+		}
+		if newCompany.QuotaRounding == "" {
+			newCompany.QuotaRounding = models.QUOTA_ROUNDING_NEAREST
+		}
+		err = uow.Companies().Create(newCompany)
+		if err != nil {
+			return err
+		}
+		err = role.NewRoleServiceWithUnitOfWork(uow).SeedDefaultRoles(newCompany.ID)
+		if err != nil {
+			return err
+		}
+		adminRole, err := uow.UserRoles().GetByCompanyIDAndName(newCompany.ID, role.DEFAULT_ROLE_ADMIN)
+		if err != nil {
+			return err
+		}
+		req.User.CompanyID = newCompany.ID
+		req.User.RoleID = adminRole.ID
+
+		var createdUser *users.CreateUserResponse
+		createdUser, err = user.NewUserCreatorWithUnitOfWork(uow).CreateUser(req.User)
+		if err != nil {
+			return err
+		}
+
+		response = &company.CreateCompanyResponse{
+			ID:          newCompany.ID,
+			Name:        newCompany.Name,
+			Description: newCompany.Description,
+			Website:     newCompany.Website,
+			Email:       newCompany.PrimaryEmail,
+
+			Timezone:           newCompany.Timezone,
+			AllowNegativeQuota: newCompany.AllowNegativeQuota,
+
+			FullTimeWorkingDays: newCompany.FullTimeWorkingDays,
+			QuotaRounding:       newCompany.QuotaRounding,
 
-	_, err = c.userCreator.CreateUser(req.User)
+			User: createdUser,
+		}
+		return nil
+	})
 	if err != nil {
 		return nil, err
 	}
 
-	return company, nil
+	return response, nil
 }
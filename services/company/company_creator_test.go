@@ -1,6 +1,7 @@
 package company_test
 
 import (
+	"errors"
 	"testing"
 
 	"github.com/r-52/embrace/models"
@@ -8,12 +9,14 @@ import (
 	"github.com/r-52/embrace/models/dto/user"
 	"github.com/r-52/embrace/repositories"
 	srv "github.com/r-52/embrace/services/company"
+	"github.com/r-52/embrace/services/role"
+	usersrv "github.com/r-52/embrace/services/user"
 	"gorm.io/gorm"
 )
 
 func setupDb() *gorm.DB {
 	db := repositories.GetDatabase()
-	db.AutoMigrate(&models.Company{}, &models.User{}, &models.UserProfile{}, &models.UserRole{})
+	db.AutoMigrate(&models.Company{}, &models.User{}, &models.UserProfile{}, &models.UserRole{}, &models.RolePermission{})
 	return db
 }
 
@@ -43,3 +46,136 @@ func TestCompanyCreator_Create_Company_With_Success(t *testing.T) {
 		t.Errorf("expected company to be created, got nil")
 	}
 }
+
+func newCreateCompanyRequest(name, email string) *dto.CreateCompanyRequest {
+	return &dto.CreateCompanyRequest{
+		Name: name,
+		User: &user.CreateUserRequest{
+			Email:     email,
+			Password:  "password",
+			FirstName: "Test",
+			LastName:  "User",
+		},
+	}
+}
+
+func TestCompanyCreator_Create_Company_Returns_Admin_User(t *testing.T) {
+	db := setupDb()
+	companyCreator := srv.NewCompanyCreator(db)
+	company, err := companyCreator.CreateCompany(newCreateCompanyRequest("Admin Company", "admin@admin.com"))
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if company.User == nil || company.User.ID == 0 {
+		t.Fatalf("expected admin user to be created, got %+v", company.User)
+	}
+	if company.User.CompanyID != company.ID {
+		t.Errorf("expected admin user to belong to company %d, got %d", company.ID, company.User.CompanyID)
+	}
+}
+
+func TestCompanyCreator_Create_Company_Defaults_Settings(t *testing.T) {
+	db := setupDb()
+	companyCreator := srv.NewCompanyCreator(db)
+	company, err := companyCreator.CreateCompany(newCreateCompanyRequest("Timezone Company", "tz@tz.com"))
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if company.Timezone != "UTC" {
+		t.Errorf("expected timezone UTC, got %q", company.Timezone)
+	}
+	if company.FullTimeWorkingDays != 5 || company.QuotaRounding != "nearest" {
+		t.Errorf("expected 5 full-time days rounded to nearest, got %d and %q", company.FullTimeWorkingDays, company.QuotaRounding)
+	}
+
+	req := newCreateCompanyRequest("Berlin Company", "berlin@berlin.com")
+	req.Timezone = "Europe/Berlin"
+	company, err = companyCreator.CreateCompany(req)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if company.Timezone != "Europe/Berlin" {
+		t.Errorf("expected timezone Europe/Berlin, got %q", company.Timezone)
+	}
+}
+
+func TestCompanyCreator_Create_Company_With_Duplicate_Name(t *testing.T) {
+	db := setupDb()
+	companyCreator := srv.NewCompanyCreator(db)
+	_, err := companyCreator.CreateCompany(newCreateCompanyRequest("Duplicate Company", "first@test.com"))
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	_, err = companyCreator.CreateCompany(newCreateCompanyRequest("Duplicate Company", "second@test.com"))
+	if !errors.Is(err, srv.ErrCompanyAlreadyExists) {
+		t.Errorf("expected ErrCompanyAlreadyExists, got %v", err)
+	}
+}
+
+func TestCompanyCreator_Create_Company_With_Duplicate_Email(t *testing.T) {
+	db := setupDb()
+	companyCreator := srv.NewCompanyCreator(db)
+	_, err := companyCreator.CreateCompany(newCreateCompanyRequest("First Company", "taken@test.com"))
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	_, err = companyCreator.CreateCompany(newCreateCompanyRequest("Second Company", "taken@test.com"))
+	if !errors.Is(err, usersrv.ErrEmailAlreadyExists) {
+		t.Errorf("expected ErrEmailAlreadyExists, got %v", err)
+	}
+}
+
+func TestCompanyCreator_Create_Company_Rolls_Back_When_User_Fails(t *testing.T) {
+	db := setupDb()
+	failure := errors.New("user insert failed")
+	db.Callback().Create().Before("gorm:create").Register("test:fail_users", func(tx *gorm.DB) {
+		if tx.Statement.Table == "users" {
+			tx.AddError(failure)
+		}
+	})
+	companyCreator := srv.NewCompanyCreator(db)
+
+	_, err := companyCreator.CreateCompany(newCreateCompanyRequest("Orphan Company", "orphan@test.com"))
+	if !errors.Is(err, failure) {
+		t.Fatalf("expected user insert failure, got %v", err)
+	}
+
+	for _, table := range []interface{}{&models.Company{}, &models.UserProfile{}, &models.UserRole{}, &models.User{}} {
+		var count int64
+		db.Model(table).Count(&count)
+		if count != 0 {
+			t.Errorf("expected %T rows to be rolled back, got %d", table, count)
+		}
+	}
+}
+
+func TestCompanyCreator_Create_Companies_With_Own_Admin_Roles(t *testing.T) {
+	db := setupDb()
+	companyCreator := srv.NewCompanyCreator(db)
+
+	first, err := companyCreator.CreateCompany(newCreateCompanyRequest("First Tenant", "first@tenant.com"))
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	second, err := companyCreator.CreateCompany(newCreateCompanyRequest("Second Tenant", "second@tenant.com"))
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	roles := repositories.NewUserRoleRepository(db)
+	for _, created := range []*dto.CreateCompanyResponse{first, second} {
+		admin, err := roles.GetByCompanyIDAndName(created.ID, role.DEFAULT_ROLE_ADMIN)
+		if err != nil {
+			t.Fatalf("expected admin role for company %d, got %v", created.ID, err)
+		}
+		if created.User.RoleID != admin.ID {
+			t.Errorf("expected user of company %d to have admin role %d, got %d", created.ID, admin.ID, created.User.RoleID)
+		}
+		count, _ := roles.CountByCompanyID(created.ID)
+		if count != int64(len(role.DEFAULT_ROLES)) {
+			t.Errorf("expected %d roles for company %d, got %d", len(role.DEFAULT_ROLES), created.ID, count)
+		}
+	}
+}
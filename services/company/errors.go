@@ -0,0 +1,6 @@
+package company
+
+import "errors"
+
+// ErrCompanyAlreadyExists is returned when a company with the requested name is already registered.
+var ErrCompanyAlreadyExists = errors.New("E1001")
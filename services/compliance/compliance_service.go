@@ -0,0 +1,257 @@
+package compliance
+
+import (
+	"errors"
+	"slices"
+	"sort"
+	"time"
+
+	"github.com/r-52/embrace/models"
+	dto "github.com/r-52/embrace/models/dto/compliance"
+	"github.com/r-52/embrace/repositories"
+	"github.com/r-52/embrace/services/holiday"
+	"gorm.io/gorm"
+)
+
+// MAX_COMPLIANCE_REPORT_DAYS is the number of calendar days a single compliance report may cover at most.
+const MAX_COMPLIANCE_REPORT_DAYS = 366
+
+// ComplianceService checks the time entries of users against the labor law rules of their company.
+type ComplianceService struct {
+	unitOfWork *repositories.UnitOfWork
+	engine     *Engine
+}
+
+type ComplianceServiceInterface interface {
+	GetRules(companyID uint) ([]models.ComplianceRule, error)
+	UpdateRules(companyID uint, req *dto.ComplianceRuleSetRequest) ([]models.ComplianceRule, error)
+	Check(entry *models.TimeEntry) error
+	Report(companyID uint, req *dto.ComplianceReportRequest) ([]models.ComplianceViolation, error)
+}
+
+// NewComplianceService creates a ComplianceService with the DefaultRules. The database should be scoped to the company,
+// see repositories.WithTenant.
+func NewComplianceService(db *gorm.DB) *ComplianceService {
+	return NewComplianceServiceWithUnitOfWork(repositories.NewUnitOfWork(db))
+}
+
+// NewComplianceServiceWithUnitOfWork creates a ComplianceService with the DefaultRules whose repositories join the given unit of work.
+func NewComplianceServiceWithUnitOfWork(uow *repositories.UnitOfWork) *ComplianceService {
+	return &ComplianceService{
+		unitOfWork: uow,
+		engine:     NewEngine(DefaultRules()...),
+	}
+}
+
+// GetRules returns the configuration of every rule for the company, including the defaults of rules it did not configure.
+func (s *ComplianceService) GetRules(companyID uint) ([]models.ComplianceRule, error) {
+	ruleSet, err := ruleSetOf(s.unitOfWork, companyID)
+	if err != nil {
+		return nil, err
+	}
+	return s.engine.Configure(ruleSet), nil
+}
+
+// UpdateRules replaces the rule set of the company. It returns ErrUnknownComplianceRule if it configures a rule
+// the engine does not know. A rule configured twice keeps the last configuration.
+func (s *ComplianceService) UpdateRules(companyID uint, req *dto.ComplianceRuleSetRequest) ([]models.ComplianceRule, error) {
+	var rules []models.ComplianceRule
+	err := s.unitOfWork.Transaction(func(uow *repositories.UnitOfWork) error {
+		configured := map[string]int{}
+		var ruleConfigs []models.ComplianceRule
+		for _, rule := range req.Rules {
+			if !s.engine.Knows(rule.Rule) {
+				return ErrUnknownComplianceRule
+			}
+			config := models.ComplianceRule{Rule: rule.Rule, Enabled: rule.Enabled, Severity: rule.Severity}
+			if i, ok := configured[rule.Rule]; ok {
+				ruleConfigs[i] = config
+				continue
+			}
+			configured[rule.Rule] = len(ruleConfigs)
+			ruleConfigs = append(ruleConfigs, config)
+		}
+
+		ruleSet, err := uow.ComplianceRuleSets().GetByCompanyID(companyID)
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			ruleSet = &models.ComplianceRuleSet{CompanyID: companyID, Rules: ruleConfigs}
+			err = uow.ComplianceRuleSets().Create(ruleSet)
+		} else if err == nil {
+			ruleSet.Rules = ruleConfigs
+			err = uow.ComplianceRuleSets().Update(ruleSet)
+		}
+		if err != nil {
+			return err
+		}
+		rules = s.engine.Configure(ruleSet)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return rules, nil
+}
+
+// Check evaluates the rules of the company against the work around a time entry that was just booked or changed.
+// Only violations the entry is involved in count. Blocking ones are returned as a *ViolationError, warnings
+// are set as the entry's Warnings. Entries that are not work, such as leave, are not checked.
+func (s *ComplianceService) Check(entry *models.TimeEntry) error {
+	entry.Warnings = nil
+	if entry.IsRunning() {
+		return nil
+	}
+	workTypes, err := workTypesOf(s.unitOfWork, entry.CompanyID)
+	if err != nil {
+		return err
+	}
+	if !workTypes[entry.TimeEntryTypeID] {
+		return nil
+	}
+	user, err := s.unitOfWork.Users().GetByID(entry.UserID)
+	if err != nil {
+		return err
+	}
+	company, err := s.unitOfWork.Companies().GetByID(user.CompanyID)
+	if err != nil {
+		return err
+	}
+	ruleSet, err := ruleSetOf(s.unitOfWork, user.CompanyID)
+	if err != nil {
+		return err
+	}
+
+	// Two days around the entry cover its whole shift and the shifts before and after it.
+	work, err := workOf(s.unitOfWork, user, company, workTypes, entry.StartTime.AddDate(0, 0, -2), entry.EndTime.Time.AddDate(0, 0, 2))
+	if err != nil {
+		return err
+	}
+	var blocking []models.ComplianceViolation
+	for _, violation := range s.engine.Evaluate(ruleSet, work) {
+		if !slices.Contains(violation.EntryIDs, entry.ID) {
+			continue
+		}
+		if violation.IsBlocking() {
+			blocking = append(blocking, violation)
+		} else {
+			entry.Warnings = append(entry.Warnings, violation)
+		}
+	}
+	if len(blocking) > 0 {
+		return &ViolationError{Violations: blocking}
+	}
+	return nil
+}
+
+// Report returns the violations of the rules of the company from `From` to `To` of the request, ordered by user
+// and date. It covers one user of the company or all of them. Violations of disabled rules are not reported.
+// It fails with ErrInvalidCompliancePeriod if the period ends before it starts or is longer than MAX_COMPLIANCE_REPORT_DAYS.
+func (s *ComplianceService) Report(companyID uint, req *dto.ComplianceReportRequest) ([]models.ComplianceViolation, error) {
+	if req.To.Before(req.From) || !req.To.Before(req.From.AddDate(0, 0, MAX_COMPLIANCE_REPORT_DAYS)) {
+		return nil, ErrInvalidCompliancePeriod
+	}
+	company, err := s.unitOfWork.Companies().GetByID(companyID)
+	if err != nil {
+		return nil, err
+	}
+	var users []*models.User
+	if req.UserID != 0 {
+		user, err := s.unitOfWork.Users().GetByID(req.UserID)
+		if err != nil {
+			return nil, err
+		}
+		if user.CompanyID != companyID {
+			return nil, gorm.ErrRecordNotFound
+		}
+		users = append(users, user)
+	} else {
+		users, err = s.unitOfWork.Users().GetUsersByCompanyID(companyID)
+		if err != nil {
+			return nil, err
+		}
+	}
+	ruleSet, err := ruleSetOf(s.unitOfWork, companyID)
+	if err != nil {
+		return nil, err
+	}
+	workTypes, err := workTypesOf(s.unitOfWork, companyID)
+	if err != nil {
+		return nil, err
+	}
+
+	location := company.Location()
+	// The day before the period holds the shift a rest period is measured from, the day after it
+	// the end of shifts that run past midnight.
+	start := time.Date(req.From.Year(), req.From.Month(), req.From.Day()-1, 0, 0, 0, 0, location)
+	end := time.Date(req.To.Year(), req.To.Month(), req.To.Day()+2, 0, 0, 0, 0, location)
+	violations := []models.ComplianceViolation{}
+	for _, user := range users {
+		work, err := workOf(s.unitOfWork, user, company, workTypes, start, end)
+		if err != nil {
+			return nil, err
+		}
+		userViolations := s.engine.Evaluate(ruleSet, work)
+		sort.SliceStable(userViolations, func(i, j int) bool {
+			return userViolations[i].Date.Before(userViolations[j].Date)
+		})
+		for _, violation := range userViolations {
+			if !violation.Date.Before(req.From) && !violation.Date.After(req.To) {
+				violations = append(violations, violation)
+			}
+		}
+	}
+	return violations, nil
+}
+
+// ruleSetOf returns the rule set of a company, or nil if it has none.
+func ruleSetOf(uow *repositories.UnitOfWork, companyID uint) (*models.ComplianceRuleSet, error) {
+	ruleSet, err := uow.ComplianceRuleSets().GetByCompanyID(companyID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	return ruleSet, err
+}
+
+// workTypesOf returns the IDs of the time entry types of a company whose entries are work: the types that count as
+// worked time for overtime, see models.OvertimePolicy, except for time off in lieu.
+func workTypesOf(uow *repositories.UnitOfWork, companyID uint) (map[uint]bool, error) {
+	policy, err := uow.OvertimePolicies().GetByCompanyID(companyID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		policy = &models.OvertimePolicy{}
+	} else if err != nil {
+		return nil, err
+	}
+	timeEntryTypes, err := uow.TimeEntryTypes().GetByCompanyID(companyID)
+	if err != nil {
+		return nil, err
+	}
+	workTypes := map[uint]bool{}
+	for i := range timeEntryTypes {
+		workTypes[timeEntryTypes[i].ID] = !timeEntryTypes[i].IsQuotaRelevant && policy.CountsAsWorkTime(&timeEntryTypes[i])
+	}
+	return workTypes, nil
+}
+
+// workOf returns the work of a user on entries that start from `start` until before `end`.
+func workOf(uow *repositories.UnitOfWork, user *models.User, company *models.Company, workTypes map[uint]bool, start, end time.Time) (*Work, error) {
+	entries, err := uow.TimeEntries().GetByUserIDAndDateRange(user.ID, start, end)
+	if err != nil {
+		return nil, err
+	}
+	var workEntries []models.TimeEntry
+	for _, entry := range entries {
+		if workTypes[entry.TimeEntryTypeID] {
+			workEntries = append(workEntries, entry)
+		}
+	}
+
+	var calendar *models.HolidayCalendar
+	if user.HolidayCalendarID != nil {
+		calendar, err = uow.HolidayCalendars().GetByID(*user.HolidayCalendarID)
+		if err != nil {
+			return nil, err
+		}
+	}
+	location := company.Location()
+	holidays := holiday.Holidays(calendar, dayOf(start, location), dayOf(end, location))
+	return NewWork(user.ID, workEntries, location, holidays), nil
+}
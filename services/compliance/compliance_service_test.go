@@ -0,0 +1,187 @@
+package compliance_test
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/r-52/embrace/models"
+	dto "github.com/r-52/embrace/models/dto/compliance"
+	"github.com/r-52/embrace/repositories"
+	"github.com/r-52/embrace/services/compliance"
+	"github.com/r-52/embrace/services/role"
+	"github.com/r-52/embrace/services/servicetest"
+	"gorm.io/gorm"
+)
+
+type fixture struct {
+	db        *gorm.DB
+	companyID uint
+	employee  *models.User
+	colleague *models.User
+	work      *models.TimeEntryType
+	vacation  *models.TimeEntryType
+}
+
+func setupFixture(t *testing.T) *fixture {
+	db := servicetest.Database(t, &models.Company{}, &models.User{}, &models.UserProfile{}, &models.UserRole{}, &models.RolePermission{},
+		&models.TimeEntryType{}, &models.TimeEntry{}, &models.HolidayCalendar{}, &models.Holiday{},
+		&models.OvertimePolicy{}, &models.OvertimeWorkTimeType{}, &models.ComplianceRuleSet{}, &models.ComplianceRule{})
+
+	f := &fixture{db: db}
+	f.companyID = servicetest.Company(t, db, "acme").ID
+	f.employee = servicetest.User(t, db, f.companyID, role.DEFAULT_ROLE_EMPLOYEE, "employee", nil)
+	f.colleague = servicetest.User(t, db, f.companyID, role.DEFAULT_ROLE_EMPLOYEE, "colleague", nil)
+	f.work = &models.TimeEntryType{Name: "Work", Color: "#0000ff", CompanyID: f.companyID}
+	servicetest.MustCreate(t, db, f.work)
+	f.vacation = &models.TimeEntryType{Name: "Vacation", Color: "#00ff00", CompanyID: f.companyID, IsQuotaRelevant: true, QuotaName: "vacation"}
+	servicetest.MustCreate(t, db, f.vacation)
+	return f
+}
+
+// book stores a closed time entry without any checks.
+func (f *fixture) book(t *testing.T, user *models.User, timeEntryType *models.TimeEntryType, start time.Time, hours float64) *models.TimeEntry {
+	entry := &models.TimeEntry{StartTime: start, CompanyID: f.companyID, UserID: user.ID, TimeEntryTypeID: timeEntryType.ID}
+	entry.Close(start.Add(time.Duration(hours * float64(time.Hour))))
+	servicetest.MustCreate(t, f.db, entry)
+	return entry
+}
+
+func (f *fixture) service() *compliance.ComplianceService {
+	return compliance.NewComplianceService(repositories.WithTenant(f.db, f.companyID))
+}
+
+func TestComplianceService_UpdateRules(t *testing.T) {
+	f := setupFixture(t)
+
+	rules, err := f.service().GetRules(f.companyID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(rules) != 5 {
+		t.Fatalf("expected every default rule, got %+v", rules)
+	}
+	for _, rule := range rules {
+		if !rule.Enabled || rule.Severity != models.COMPLIANCE_SEVERITY_WARNING {
+			t.Errorf("expected rules to be enabled warnings by default, got %+v", rule)
+		}
+	}
+
+	_, err = f.service().UpdateRules(f.companyID, &dto.ComplianceRuleSetRequest{Rules: []dto.ComplianceRuleRequest{
+		{Rule: "four_day_week", Enabled: true, Severity: models.COMPLIANCE_SEVERITY_BLOCKING},
+	}})
+	if !errors.Is(err, compliance.ErrUnknownComplianceRule) {
+		t.Errorf("expected ErrUnknownComplianceRule, got %v", err)
+	}
+
+	for _, severity := range []string{models.COMPLIANCE_SEVERITY_WARNING, models.COMPLIANCE_SEVERITY_BLOCKING} {
+		_, err = f.service().UpdateRules(f.companyID, &dto.ComplianceRuleSetRequest{Rules: []dto.ComplianceRuleRequest{
+			{Rule: models.COMPLIANCE_RULE_SUNDAY_WORK, Enabled: false, Severity: models.COMPLIANCE_SEVERITY_WARNING},
+			{Rule: models.COMPLIANCE_RULE_BREAKS, Enabled: true, Severity: severity},
+		}})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	rules, _ = f.service().GetRules(f.companyID)
+	configured := map[string]models.ComplianceRule{}
+	for _, rule := range rules {
+		configured[rule.Rule] = rule
+	}
+	if len(rules) != 5 || configured[models.COMPLIANCE_RULE_SUNDAY_WORK].Enabled ||
+		configured[models.COMPLIANCE_RULE_BREAKS].Severity != models.COMPLIANCE_SEVERITY_BLOCKING ||
+		!configured[models.COMPLIANCE_RULE_REST_PERIOD].Enabled {
+		t.Errorf("expected the rule set to be replaced, got %+v", rules)
+	}
+}
+
+func TestComplianceService_Check(t *testing.T) {
+	f := setupFixture(t)
+	tuesday := time.Date(2024, 3, 5, 8, 0, 0, 0, time.UTC)
+
+	previous := f.book(t, f.employee, f.work, tuesday.Add(-12*time.Hour), 2)
+	entry := f.book(t, f.employee, f.work, tuesday, 7)
+	if err := f.service().Check(entry); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(entry.Warnings) != 2 || entry.Warnings[0].Rule != models.COMPLIANCE_RULE_BREAKS || entry.Warnings[1].Rule != models.COMPLIANCE_RULE_REST_PERIOD {
+		t.Errorf("expected warnings about breaks and rest, got %+v", entry.Warnings)
+	}
+	if err := f.service().Check(previous); err != nil || len(previous.Warnings) != 1 {
+		t.Errorf("expected the previous entry to be involved in the rest period only, got %v %+v", err, previous.Warnings)
+	}
+
+	vacation := f.book(t, f.employee, f.vacation, tuesday.AddDate(0, 0, 1), 11)
+	if err := f.service().Check(vacation); err != nil || len(vacation.Warnings) != 0 {
+		t.Errorf("expected leave not to be checked, got %v %+v", err, vacation.Warnings)
+	}
+
+	_, err := f.service().UpdateRules(f.companyID, &dto.ComplianceRuleSetRequest{Rules: []dto.ComplianceRuleRequest{
+		{Rule: models.COMPLIANCE_RULE_BREAKS, Enabled: true, Severity: models.COMPLIANCE_SEVERITY_BLOCKING},
+	}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	err = f.service().Check(entry)
+	var violationErr *compliance.ViolationError
+	if !errors.As(err, &violationErr) || !errors.Is(err, compliance.ErrComplianceViolation) {
+		t.Fatalf("expected a ViolationError, got %v", err)
+	}
+	if len(violationErr.Violations) != 1 || violationErr.Violations[0].Rule != models.COMPLIANCE_RULE_BREAKS {
+		t.Errorf("expected the breaks to block, got %+v", violationErr.Violations)
+	}
+	if len(entry.Warnings) != 1 || entry.Warnings[0].Rule != models.COMPLIANCE_RULE_REST_PERIOD {
+		t.Errorf("expected the rest period to still warn, got %+v", entry.Warnings)
+	}
+}
+
+func TestComplianceService_Report(t *testing.T) {
+	f := setupFixture(t)
+	saturday := time.Date(2024, 3, 9, 8, 0, 0, 0, time.UTC)
+
+	f.book(t, f.employee, f.work, saturday.AddDate(0, 0, -1), 7)
+	sunday := f.book(t, f.employee, f.work, saturday.AddDate(0, 0, 1), 4)
+	f.book(t, f.employee, f.work, saturday.AddDate(0, 0, 2), 4)
+	colleague := f.book(t, f.colleague, f.work, saturday, 11)
+
+	req := &dto.ComplianceReportRequest{From: time.Date(2024, 3, 9, 0, 0, 0, 0, time.UTC), To: time.Date(2024, 3, 10, 0, 0, 0, 0, time.UTC)}
+	violations, err := f.service().Report(f.companyID, req)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var actual []string
+	for _, violation := range violations {
+		actual = append(actual, fmt.Sprintf("%d %s %s %v", violation.UserID, violation.Date.Format(time.DateOnly), violation.Rule, violation.EntryIDs))
+	}
+	expected := []string{
+		fmt.Sprintf("%d 2024-03-10 %s [%d]", f.employee.ID, models.COMPLIANCE_RULE_SUNDAY_WORK, sunday.ID),
+		fmt.Sprintf("%d 2024-03-09 %s [%d]", f.colleague.ID, models.COMPLIANCE_RULE_BREAKS, colleague.ID),
+		fmt.Sprintf("%d 2024-03-09 %s [%d]", f.colleague.ID, models.COMPLIANCE_RULE_DAILY_MAXIMUM, colleague.ID),
+	}
+	if fmt.Sprint(actual) != fmt.Sprint(expected) {
+		t.Errorf("expected %v, got %v", expected, actual)
+	}
+
+	req.UserID = f.colleague.ID
+	if violations, err := f.service().Report(f.companyID, req); err != nil || len(violations) != 2 {
+		t.Errorf("expected the violations of the colleague only, got %v %+v", err, violations)
+	}
+	req.UserID = 999
+	if _, err := f.service().Report(f.companyID, req); !errors.Is(err, gorm.ErrRecordNotFound) {
+		t.Errorf("expected ErrRecordNotFound, got %v", err)
+	}
+
+	year := &dto.ComplianceReportRequest{From: req.From, To: req.From.AddDate(0, 0, compliance.MAX_COMPLIANCE_REPORT_DAYS-1)}
+	if _, err := f.service().Report(f.companyID, year); err != nil {
+		t.Errorf("unexpected error for a period of MAX_COMPLIANCE_REPORT_DAYS: %v", err)
+	}
+	year.To = year.To.AddDate(0, 0, 1)
+	if _, err := f.service().Report(f.companyID, year); !errors.Is(err, compliance.ErrInvalidCompliancePeriod) {
+		t.Errorf("expected ErrInvalidCompliancePeriod for a longer period, got %v", err)
+	}
+	backwards := &dto.ComplianceReportRequest{From: req.To, To: req.From}
+	if _, err := f.service().Report(f.companyID, backwards); !errors.Is(err, compliance.ErrInvalidCompliancePeriod) {
+		t.Errorf("expected ErrInvalidCompliancePeriod for a period that ends before it starts, got %v", err)
+	}
+}
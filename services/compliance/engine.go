@@ -0,0 +1,67 @@
+package compliance
+
+import "github.com/r-52/embrace/models"
+
+// Engine evaluates labor law rules. Which rules run and how severe their violations are is configured
+// per company by a models.ComplianceRuleSet. Rules the rule set does not list run as warnings.
+type Engine struct {
+	rules []Rule
+}
+
+// NewEngine creates an Engine that knows the given rules. Names must be unique.
+func NewEngine(rules ...Rule) *Engine {
+	return &Engine{
+		rules: rules,
+	}
+}
+
+// Rules returns the names of the rules the engine knows, in the order they are evaluated.
+func (e *Engine) Rules() []string {
+	names := make([]string, 0, len(e.rules))
+	for _, rule := range e.rules {
+		names = append(names, rule.Name())
+	}
+	return names
+}
+
+// Knows reports whether the engine has a rule of that name.
+func (e *Engine) Knows(name string) bool {
+	for _, rule := range e.rules {
+		if rule.Name() == name {
+			return true
+		}
+	}
+	return false
+}
+
+// Configure returns the configuration of every rule the engine knows: the one of the rule set or, for rules
+// it does not list, an enabled warning. The rule set may be nil.
+func (e *Engine) Configure(ruleSet *models.ComplianceRuleSet) []models.ComplianceRule {
+	configured := make([]models.ComplianceRule, 0, len(e.rules))
+	for _, rule := range e.rules {
+		if ruleSet != nil {
+			if config := ruleSet.Rule(rule.Name()); config != nil {
+				configured = append(configured, *config)
+				continue
+			}
+		}
+		configured = append(configured, models.ComplianceRule{Rule: rule.Name(), Enabled: true, Severity: models.COMPLIANCE_SEVERITY_WARNING})
+	}
+	return configured
+}
+
+// Evaluate runs the rules enabled by the rule set over the work and returns their violations
+// with the configured severity. The rule set may be nil.
+func (e *Engine) Evaluate(ruleSet *models.ComplianceRuleSet, work *Work) []models.ComplianceViolation {
+	var violations []models.ComplianceViolation
+	for i, config := range e.Configure(ruleSet) {
+		if !config.Enabled {
+			continue
+		}
+		for _, violation := range e.rules[i].Evaluate(work) {
+			violation.Severity = config.Severity
+			violations = append(violations, violation)
+		}
+	}
+	return violations
+}
@@ -0,0 +1,30 @@
+package compliance
+
+import (
+	"errors"
+
+	"github.com/r-52/embrace/models"
+)
+
+// ErrComplianceViolation is returned when a time entry violates a labor law rule that the company made blocking.
+var ErrComplianceViolation = errors.New("E9000")
+
+// ErrUnknownComplianceRule is returned when a rule set configures a rule the engine does not know.
+var ErrUnknownComplianceRule = errors.New("E9001")
+
+// ErrInvalidCompliancePeriod is returned when a compliance report ends before it starts or is longer than MAX_COMPLIANCE_REPORT_DAYS.
+var ErrInvalidCompliancePeriod = errors.New("E9002")
+
+// ViolationError is returned when a time entry violates blocking labor law rules.
+// It matches ErrComplianceViolation with errors.Is and lists the blocking violations.
+type ViolationError struct {
+	Violations []models.ComplianceViolation
+}
+
+func (e *ViolationError) Error() string {
+	return ErrComplianceViolation.Error()
+}
+
+func (e *ViolationError) Is(target error) bool {
+	return target == ErrComplianceViolation
+}
@@ -0,0 +1,206 @@
+package compliance
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/r-52/embrace/models"
+)
+
+// The limits of the German working time act (Arbeitszeitgesetz) that the default rules check.
+const (
+	// MIN_BREAK is the shortest interruption that counts as a break.
+	MIN_BREAK = 15 * time.Minute
+	// MAX_WORK_WITHOUT_BREAK is the longest time that may be worked without a break.
+	MAX_WORK_WITHOUT_BREAK = 6 * time.Hour
+	// LONG_SHIFT is the worked time from which the longer LONG_SHIFT_BREAK is required instead of SHIFT_BREAK.
+	LONG_SHIFT       = 9 * time.Hour
+	SHIFT_BREAK      = 30 * time.Minute
+	LONG_SHIFT_BREAK = 45 * time.Minute
+	// MIN_REST_PERIOD is the shortest rest between two shifts.
+	MIN_REST_PERIOD = 11 * time.Hour
+	// MAX_DAILY_WORK is the most time that may be worked in a shift.
+	MAX_DAILY_WORK = 10 * time.Hour
+)
+
+// Rule is a labor law rule. It evaluates the work of a single user and returns the violations it finds,
+// see Engine. Rules leave the severity of their violations to the engine.
+type Rule interface {
+	// Name identifies the rule in rule sets and violations, see the models.COMPLIANCE_RULE constants.
+	Name() string
+	Evaluate(work *Work) []models.ComplianceViolation
+}
+
+// DefaultRules returns the rules of the German working time act.
+func DefaultRules() []Rule {
+	return []Rule{BreakRule{}, RestPeriodRule{}, DailyMaximumRule{}, SundayWorkRule{}, HolidayWorkRule{}}
+}
+
+// BreakRule requires breaks of SHIFT_BREAK in shifts longer than MAX_WORK_WITHOUT_BREAK and of LONG_SHIFT_BREAK
+// in shifts longer than LONG_SHIFT. Only breaks of at least MIN_BREAK count, and no more than MAX_WORK_WITHOUT_BREAK
+// may be worked without one.
+type BreakRule struct{}
+
+func (BreakRule) Name() string {
+	return models.COMPLIANCE_RULE_BREAKS
+}
+
+func (r BreakRule) Evaluate(work *Work) []models.ComplianceViolation {
+	var violations []models.ComplianceViolation
+	for i := range work.Shifts {
+		shift := &work.Shifts[i]
+		worked := shift.Worked()
+		var breaks, stretch, longestStretch time.Duration
+		end := shift.Entries[0].StartTime
+		for _, entry := range shift.Entries {
+			if gap := entry.StartTime.Sub(end); gap >= MIN_BREAK {
+				breaks += gap
+				stretch = 0
+			}
+			stretch += entry.EndTime.Time.Sub(entry.StartTime)
+			longestStretch = max(longestStretch, stretch)
+			if entry.EndTime.Time.After(end) {
+				end = entry.EndTime.Time
+			}
+		}
+
+		required := time.Duration(0)
+		if worked > LONG_SHIFT {
+			required = LONG_SHIFT_BREAK
+		} else if worked > MAX_WORK_WITHOUT_BREAK {
+			required = SHIFT_BREAK
+		}
+		var message string
+		if breaks < required {
+			message = fmt.Sprintf("%s worked with %s of breaks, %s are required", formatDuration(worked), formatDuration(breaks), formatDuration(required))
+		} else if longestStretch > MAX_WORK_WITHOUT_BREAK {
+			message = fmt.Sprintf("%s worked without a break, at most %s are allowed", formatDuration(longestStretch), formatDuration(MAX_WORK_WITHOUT_BREAK))
+		} else {
+			continue
+		}
+		violations = append(violations, newViolation(r, work, shift.Date(work.Location), message, shift.EntryIDs()))
+	}
+	return violations
+}
+
+// RestPeriodRule requires a rest of MIN_REST_PERIOD between the end of a shift and the start of the next one.
+type RestPeriodRule struct{}
+
+func (RestPeriodRule) Name() string {
+	return models.COMPLIANCE_RULE_REST_PERIOD
+}
+
+func (r RestPeriodRule) Evaluate(work *Work) []models.ComplianceViolation {
+	var violations []models.ComplianceViolation
+	for i := 1; i < len(work.Shifts); i++ {
+		previous, shift := &work.Shifts[i-1], &work.Shifts[i]
+		rest := shift.Start().Sub(previous.End())
+		if rest >= MIN_REST_PERIOD {
+			continue
+		}
+		message := fmt.Sprintf("%s of rest after the previous shift, %s are required", formatDuration(rest), formatDuration(MIN_REST_PERIOD))
+		entryIDs := []uint{previous.Entries[len(previous.Entries)-1].ID, shift.Entries[0].ID}
+		violations = append(violations, newViolation(r, work, shift.Date(work.Location), message, entryIDs))
+	}
+	return violations
+}
+
+// DailyMaximumRule limits the time worked in a shift to MAX_DAILY_WORK.
+type DailyMaximumRule struct{}
+
+func (DailyMaximumRule) Name() string {
+	return models.COMPLIANCE_RULE_DAILY_MAXIMUM
+}
+
+func (r DailyMaximumRule) Evaluate(work *Work) []models.ComplianceViolation {
+	var violations []models.ComplianceViolation
+	for i := range work.Shifts {
+		shift := &work.Shifts[i]
+		if worked := shift.Worked(); worked > MAX_DAILY_WORK {
+			message := fmt.Sprintf("%s worked, at most %s are allowed", formatDuration(worked), formatDuration(MAX_DAILY_WORK))
+			violations = append(violations, newViolation(r, work, shift.Date(work.Location), message, shift.EntryIDs()))
+		}
+	}
+	return violations
+}
+
+// SundayWorkRule flags work on Sundays.
+type SundayWorkRule struct{}
+
+func (SundayWorkRule) Name() string {
+	return models.COMPLIANCE_RULE_SUNDAY_WORK
+}
+
+func (r SundayWorkRule) Evaluate(work *Work) []models.ComplianceViolation {
+	return flagDays(r, work, func(day time.Time) (string, bool) {
+		return "work on a Sunday", day.Weekday() == time.Sunday
+	})
+}
+
+// HolidayWorkRule flags work on the holidays of the user's calendar. Half-day holidays are working days and not flagged.
+type HolidayWorkRule struct{}
+
+func (HolidayWorkRule) Name() string {
+	return models.COMPLIANCE_RULE_HOLIDAY_WORK
+}
+
+func (r HolidayWorkRule) Evaluate(work *Work) []models.ComplianceViolation {
+	return flagDays(r, work, func(day time.Time) (string, bool) {
+		holiday, ok := work.Holidays[day.Format(time.DateOnly)]
+		return "work on " + holiday.Name, ok && !holiday.HalfDay
+	})
+}
+
+// flagDays returns a violation for every day that `flagged` reports and that entries of the work reach into,
+// naming those entries.
+func flagDays(rule Rule, work *Work, flagged func(day time.Time) (string, bool)) []models.ComplianceViolation {
+	var violations []models.ComplianceViolation
+	for _, shift := range work.Shifts {
+		var days []time.Time
+		entryIDs := map[time.Time][]uint{}
+		messages := map[time.Time]string{}
+		for _, entry := range shift.Entries {
+			// The end is exclusive, an entry ending at midnight does not reach into the next day.
+			last := dayOf(entry.EndTime.Time.Add(-time.Nanosecond), work.Location)
+			for day := dayOf(entry.StartTime, work.Location); !day.After(last); day = day.AddDate(0, 0, 1) {
+				message, ok := flagged(day)
+				if !ok {
+					continue
+				}
+				if _, seen := entryIDs[day]; !seen {
+					days = append(days, day)
+					messages[day] = message
+				}
+				entryIDs[day] = append(entryIDs[day], entry.ID)
+			}
+		}
+		for _, day := range days {
+			violations = append(violations, newViolation(rule, work, day, messages[day], entryIDs[day]))
+		}
+	}
+	return violations
+}
+
+func newViolation(rule Rule, work *Work, date time.Time, message string, entryIDs []uint) models.ComplianceViolation {
+	return models.ComplianceViolation{
+		Rule:     rule.Name(),
+		UserID:   work.UserID,
+		Date:     date,
+		Message:  message,
+		EntryIDs: entryIDs,
+	}
+}
+
+// formatDuration formats a duration in hours and minutes, such as 9h45m.
+func formatDuration(d time.Duration) string {
+	d = d.Truncate(time.Minute)
+	if d < time.Minute {
+		return "0m"
+	}
+	formatted := strings.TrimSuffix(d.String(), "0s")
+	if strings.HasSuffix(formatted, "h0m") {
+		formatted = strings.TrimSuffix(formatted, "0m")
+	}
+	return formatted
+}
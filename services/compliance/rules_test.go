@@ -0,0 +1,144 @@
+package compliance_test
+
+import (
+	"database/sql"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/r-52/embrace/models"
+	"github.com/r-52/embrace/services/compliance"
+)
+
+// span is a closed time entry from `start` to `end`, both "2006-01-02 15:04" in UTC.
+func span(id uint, start, end string) models.TimeEntry {
+	startTime, _ := time.Parse(time.DateTime, start+":00")
+	endTime, _ := time.Parse(time.DateTime, end+":00")
+	entry := models.TimeEntry{StartTime: startTime, EndTime: sql.NullTime{Time: endTime, Valid: true}}
+	entry.ID = id
+	return entry
+}
+
+type found struct {
+	rule     string
+	date     string
+	entryIDs []uint
+}
+
+func TestDefaultRules(t *testing.T) {
+	goodFriday := models.Holiday{Date: time.Date(2024, 3, 29, 0, 0, 0, 0, time.UTC), Name: "Good Friday"}
+	christmasEve := models.Holiday{Date: time.Date(2024, 12, 24, 0, 0, 0, 0, time.UTC), Name: "Christmas Eve", HalfDay: true}
+
+	tests := []struct {
+		name     string
+		entries  []models.TimeEntry
+		expected []found
+	}{
+		{name: "six hours without a break", entries: []models.TimeEntry{span(1, "2024-03-04 08:00", "2024-03-04 14:00")}},
+		{
+			name:     "seven hours without a break",
+			entries:  []models.TimeEntry{span(1, "2024-03-04 08:00", "2024-03-04 15:00")},
+			expected: []found{{models.COMPLIANCE_RULE_BREAKS, "2024-03-04", []uint{1}}},
+		},
+		{
+			name:    "eight hours with half an hour of break",
+			entries: []models.TimeEntry{span(1, "2024-03-04 08:00", "2024-03-04 12:00"), span(2, "2024-03-04 12:30", "2024-03-04 16:30")},
+		},
+		{
+			name:     "short interruptions are no breaks",
+			entries:  []models.TimeEntry{span(1, "2024-03-04 08:00", "2024-03-04 12:00"), span(2, "2024-03-04 12:10", "2024-03-04 16:00")},
+			expected: []found{{models.COMPLIANCE_RULE_BREAKS, "2024-03-04", []uint{1, 2}}},
+		},
+		{
+			name:     "more than nine hours with half an hour of break",
+			entries:  []models.TimeEntry{span(1, "2024-03-04 08:00", "2024-03-04 13:00"), span(2, "2024-03-04 13:30", "2024-03-04 18:00")},
+			expected: []found{{models.COMPLIANCE_RULE_BREAKS, "2024-03-04", []uint{1, 2}}},
+		},
+		{
+			name:     "seven hours in a row after an early break",
+			entries:  []models.TimeEntry{span(1, "2024-03-04 07:00", "2024-03-04 07:30"), span(2, "2024-03-04 08:00", "2024-03-04 15:00")},
+			expected: []found{{models.COMPLIANCE_RULE_BREAKS, "2024-03-04", []uint{1, 2}}},
+		},
+		{
+			name:     "eleven hours",
+			entries:  []models.TimeEntry{span(1, "2024-03-04 07:00", "2024-03-04 12:00"), span(2, "2024-03-04 12:45", "2024-03-04 18:45")},
+			expected: []found{{models.COMPLIANCE_RULE_DAILY_MAXIMUM, "2024-03-04", []uint{1, 2}}},
+		},
+		{
+			name:     "nine hours of rest",
+			entries:  []models.TimeEntry{span(1, "2024-03-04 15:00", "2024-03-04 21:00"), span(2, "2024-03-05 06:00", "2024-03-05 12:00")},
+			expected: []found{{models.COMPLIANCE_RULE_REST_PERIOD, "2024-03-05", []uint{1, 2}}},
+		},
+		{
+			name:    "night shift",
+			entries: []models.TimeEntry{span(1, "2024-03-04 22:00", "2024-03-05 02:00"), span(2, "2024-03-05 02:30", "2024-03-05 06:00")},
+		},
+		{
+			name:     "Sunday",
+			entries:  []models.TimeEntry{span(1, "2024-03-09 20:00", "2024-03-10 01:00"), span(2, "2024-03-10 01:30", "2024-03-10 02:00")},
+			expected: []found{{models.COMPLIANCE_RULE_SUNDAY_WORK, "2024-03-10", []uint{1, 2}}},
+		},
+		{
+			name:    "until midnight before a Sunday",
+			entries: []models.TimeEntry{span(1, "2024-03-09 20:00", "2024-03-10 00:00")},
+		},
+		{
+			name:     "holiday",
+			entries:  []models.TimeEntry{span(1, "2024-03-29 08:00", "2024-03-29 12:00")},
+			expected: []found{{models.COMPLIANCE_RULE_HOLIDAY_WORK, "2024-03-29", []uint{1}}},
+		},
+		{name: "half-day holiday", entries: []models.TimeEntry{span(1, "2024-12-24 08:00", "2024-12-24 12:00")}},
+	}
+
+	engine := compliance.NewEngine(compliance.DefaultRules()...)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			work := compliance.NewWork(7, tt.entries, time.UTC, []models.Holiday{goodFriday, christmasEve})
+			var actual []found
+			for _, violation := range engine.Evaluate(nil, work) {
+				if violation.UserID != 7 || violation.Severity != models.COMPLIANCE_SEVERITY_WARNING || violation.Message == "" {
+					t.Errorf("unexpected violation: %+v", violation)
+				}
+				actual = append(actual, found{violation.Rule, violation.Date.Format(time.DateOnly), violation.EntryIDs})
+			}
+			if !reflect.DeepEqual(actual, tt.expected) {
+				t.Errorf("expected %v, got %v", tt.expected, actual)
+			}
+		})
+	}
+}
+
+func TestEngine_Evaluate_Applies_Rule_Set(t *testing.T) {
+	engine := compliance.NewEngine(compliance.DefaultRules()...)
+	ruleSet := &models.ComplianceRuleSet{Rules: []models.ComplianceRule{
+		{Rule: models.COMPLIANCE_RULE_DAILY_MAXIMUM, Enabled: true, Severity: models.COMPLIANCE_SEVERITY_BLOCKING},
+		{Rule: models.COMPLIANCE_RULE_SUNDAY_WORK, Enabled: false, Severity: models.COMPLIANCE_SEVERITY_WARNING},
+	}}
+	// Eleven hours without a break on a Sunday.
+	work := compliance.NewWork(7, []models.TimeEntry{span(1, "2024-03-10 07:00", "2024-03-10 18:00")}, time.UTC, nil)
+
+	severities := map[string]string{}
+	for _, violation := range engine.Evaluate(ruleSet, work) {
+		severities[violation.Rule] = violation.Severity
+	}
+	expected := map[string]string{
+		models.COMPLIANCE_RULE_BREAKS:        models.COMPLIANCE_SEVERITY_WARNING,
+		models.COMPLIANCE_RULE_DAILY_MAXIMUM: models.COMPLIANCE_SEVERITY_BLOCKING,
+	}
+	if !reflect.DeepEqual(severities, expected) {
+		t.Errorf("expected %v, got %v", expected, severities)
+	}
+}
+
+func TestNewWork_Uses_Company_Timezone(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Fatalf("failed to load location: %v", err)
+	}
+	// 23:30 UTC on Saturday is half past midnight on Sunday in Berlin.
+	work := compliance.NewWork(7, []models.TimeEntry{span(1, "2024-03-09 23:30", "2024-03-10 01:00")}, berlin, nil)
+	violations := compliance.SundayWorkRule{}.Evaluate(work)
+	if len(violations) != 1 || violations[0].Date.Format(time.DateOnly) != "2024-03-10" {
+		t.Errorf("expected work on Sunday the 10th, got %+v", violations)
+	}
+}
@@ -0,0 +1,98 @@
+package compliance
+
+import (
+	"time"
+
+	"github.com/r-52/embrace/models"
+)
+
+// MAX_SHIFT_INTERRUPTION is the longest interruption that continues a shift on the next day,
+// so that night shifts are not split at midnight.
+const MAX_SHIFT_INTERRUPTION = 3 * time.Hour
+
+// Work is what the rules evaluate: the shifts of a single user in order and the holidays of their calendar.
+type Work struct {
+	UserID   uint
+	Location *time.Location
+	Shifts   []Shift
+	// Holidays are the holidays of the user's calendar keyed by date.
+	Holidays map[string]models.Holiday
+}
+
+// Shift is the work of a user between two rest periods, a sequence of closed time entries ordered by start.
+// The gaps between the entries are breaks.
+type Shift struct {
+	Entries []models.TimeEntry
+}
+
+// NewWork groups the closed time entries of a user into shifts. Entries belong to the shift of the previous entry
+// if they start on the day the shift started or less than MAX_SHIFT_INTERRUPTION after the previous entry ended,
+// both in `location`.
+func NewWork(userID uint, entries []models.TimeEntry, location *time.Location, holidays []models.Holiday) *Work {
+	work := &Work{
+		UserID:   userID,
+		Location: location,
+		Holidays: map[string]models.Holiday{},
+	}
+	for _, holiday := range holidays {
+		work.Holidays[holiday.Date.Format(time.DateOnly)] = holiday
+	}
+
+	var shift *Shift
+	for _, entry := range entries {
+		if entry.IsRunning() {
+			continue
+		}
+		if shift == nil || (!dayOf(entry.StartTime, location).Equal(shift.Date(location)) && entry.StartTime.Sub(shift.End()) >= MAX_SHIFT_INTERRUPTION) {
+			work.Shifts = append(work.Shifts, Shift{})
+			shift = &work.Shifts[len(work.Shifts)-1]
+		}
+		shift.Entries = append(shift.Entries, entry)
+	}
+	return work
+}
+
+// Start returns when the first entry of the shift starts.
+func (s *Shift) Start() time.Time {
+	return s.Entries[0].StartTime
+}
+
+// End returns when the last entry of the shift ends.
+func (s *Shift) End() time.Time {
+	end := s.Entries[0].EndTime.Time
+	for _, entry := range s.Entries[1:] {
+		if entry.EndTime.Time.After(end) {
+			end = entry.EndTime.Time
+		}
+	}
+	return end
+}
+
+// Date returns the day the shift started in `location`, at midnight UTC.
+func (s *Shift) Date(location *time.Location) time.Time {
+	return dayOf(s.Start(), location)
+}
+
+// Worked returns the time worked in the shift.
+func (s *Shift) Worked() time.Duration {
+	var worked time.Duration
+	for _, entry := range s.Entries {
+		worked += entry.EndTime.Time.Sub(entry.StartTime)
+	}
+	return worked
+}
+
+// EntryIDs returns the IDs of the entries of the shift.
+func (s *Shift) EntryIDs() []uint {
+	entryIDs := make([]uint, 0, len(s.Entries))
+	for _, entry := range s.Entries {
+		entryIDs = append(entryIDs, entry.ID)
+	}
+	return entryIDs
+}
+
+// dayOf returns the day of t in `location`, at midnight UTC.
+func dayOf(t time.Time, location *time.Location) time.Time {
+	t = t.In(location)
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
@@ -0,0 +1,9 @@
+package holiday
+
+import "errors"
+
+// ErrUnknownRegion is returned when a holiday calendar should generate the public holidays of a region that is not supported.
+var ErrUnknownRegion = errors.New("E6000")
+
+// ErrHolidayCalendarInUse is returned when a holiday calendar that is still assigned to users should be deleted.
+var ErrHolidayCalendarInUse = errors.New("E6001")
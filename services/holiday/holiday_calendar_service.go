@@ -0,0 +1,173 @@
+package holiday
+
+import (
+	"time"
+
+	"github.com/r-52/embrace/models"
+	dto "github.com/r-52/embrace/models/dto/holiday"
+	"github.com/r-52/embrace/repositories"
+	"gorm.io/gorm"
+)
+
+// HolidayCalendarService manages the holiday calendars of a company and their custom holidays.
+type HolidayCalendarService struct {
+	unitOfWork *repositories.UnitOfWork
+}
+
+type HolidayCalendarServiceInterface interface {
+	ListCalendars(companyID uint) ([]models.HolidayCalendar, error)
+	GetCalendar(companyID, calendarID uint) (*models.HolidayCalendar, error)
+	CreateCalendar(companyID uint, req *dto.HolidayCalendarRequest) (*models.HolidayCalendar, error)
+	UpdateCalendar(companyID, calendarID uint, req *dto.HolidayCalendarRequest) (*models.HolidayCalendar, error)
+	DeleteCalendar(companyID, calendarID uint) error
+	AddHoliday(companyID, calendarID uint, req *dto.HolidayRequest) (*models.Holiday, error)
+	DeleteHoliday(companyID, calendarID, holidayID uint) error
+	ListHolidays(companyID, calendarID uint, year int) ([]models.Holiday, error)
+}
+
+// NewHolidayCalendarService creates a HolidayCalendarService. The database should be scoped to the company, see repositories.WithTenant.
+func NewHolidayCalendarService(db *gorm.DB) *HolidayCalendarService {
+	return NewHolidayCalendarServiceWithUnitOfWork(repositories.NewUnitOfWork(db))
+}
+
+// NewHolidayCalendarServiceWithUnitOfWork creates a HolidayCalendarService whose repositories join the given unit of work.
+func NewHolidayCalendarServiceWithUnitOfWork(uow *repositories.UnitOfWork) *HolidayCalendarService {
+	return &HolidayCalendarService{
+		unitOfWork: uow,
+	}
+}
+
+// ListCalendars returns the holiday calendars of the company without their holidays.
+func (s *HolidayCalendarService) ListCalendars(companyID uint) ([]models.HolidayCalendar, error) {
+	return s.unitOfWork.HolidayCalendars().GetByCompanyID(companyID)
+}
+
+// GetCalendar returns a holiday calendar of the company with its custom holidays.
+// Calendars of other companies are reported as gorm.ErrRecordNotFound.
+func (s *HolidayCalendarService) GetCalendar(companyID, calendarID uint) (*models.HolidayCalendar, error) {
+	return getCompanyCalendar(s.unitOfWork, companyID, calendarID)
+}
+
+// CreateCalendar creates a holiday calendar for the company. It returns ErrUnknownRegion for regions that are not in REGIONS.
+func (s *HolidayCalendarService) CreateCalendar(companyID uint, req *dto.HolidayCalendarRequest) (*models.HolidayCalendar, error) {
+	if req.Region != "" && !IsKnownRegion(req.Region) {
+		return nil, ErrUnknownRegion
+	}
+	calendar := &models.HolidayCalendar{
+		CompanyID: companyID,
+		Name:      req.Name,
+		Region:    req.Region,
+	}
+	if err := s.unitOfWork.HolidayCalendars().Create(calendar); err != nil {
+		return nil, err
+	}
+	return calendar, nil
+}
+
+// UpdateCalendar renames a holiday calendar and changes its region. It returns ErrUnknownRegion for regions that are not in REGIONS.
+func (s *HolidayCalendarService) UpdateCalendar(companyID, calendarID uint, req *dto.HolidayCalendarRequest) (*models.HolidayCalendar, error) {
+	if req.Region != "" && !IsKnownRegion(req.Region) {
+		return nil, ErrUnknownRegion
+	}
+
+	var updated *models.HolidayCalendar
+	err := s.unitOfWork.Transaction(func(uow *repositories.UnitOfWork) error {
+		calendar, err := getCompanyCalendar(uow, companyID, calendarID)
+		if err != nil {
+			return err
+		}
+		calendar.Name = req.Name
+		calendar.Region = req.Region
+		if err := uow.HolidayCalendars().Update(calendar); err != nil {
+			return err
+		}
+		updated = calendar
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return updated, nil
+}
+
+// DeleteCalendar deletes a holiday calendar that is not assigned to any user, together with its custom holidays.
+// It returns ErrHolidayCalendarInUse while users are still assigned.
+func (s *HolidayCalendarService) DeleteCalendar(companyID, calendarID uint) error {
+	return s.unitOfWork.Transaction(func(uow *repositories.UnitOfWork) error {
+		calendar, err := getCompanyCalendar(uow, companyID, calendarID)
+		if err != nil {
+			return err
+		}
+		count, err := uow.Users().CountByHolidayCalendarID(calendar.ID)
+		if err != nil {
+			return err
+		}
+		if count > 0 {
+			return ErrHolidayCalendarInUse
+		}
+		return uow.HolidayCalendars().Delete(calendar.ID)
+	})
+}
+
+// AddHoliday adds a custom holiday to a holiday calendar of the company.
+func (s *HolidayCalendarService) AddHoliday(companyID, calendarID uint, req *dto.HolidayRequest) (*models.Holiday, error) {
+	date, err := time.Parse(time.DateOnly, req.Date)
+	if err != nil {
+		return nil, err
+	}
+
+	var created *models.Holiday
+	err = s.unitOfWork.Transaction(func(uow *repositories.UnitOfWork) error {
+		calendar, err := getCompanyCalendar(uow, companyID, calendarID)
+		if err != nil {
+			return err
+		}
+		holiday := &models.Holiday{
+			HolidayCalendarID: calendar.ID,
+			Date:              date,
+			Name:              req.Name,
+			HalfDay:           req.HalfDay,
+			Recurring:         req.Recurring,
+		}
+		if err := uow.HolidayCalendars().CreateHoliday(holiday); err != nil {
+			return err
+		}
+		created = holiday
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return created, nil
+}
+
+// DeleteHoliday removes a custom holiday from a holiday calendar of the company.
+func (s *HolidayCalendarService) DeleteHoliday(companyID, calendarID, holidayID uint) error {
+	return s.unitOfWork.Transaction(func(uow *repositories.UnitOfWork) error {
+		calendar, err := getCompanyCalendar(uow, companyID, calendarID)
+		if err != nil {
+			return err
+		}
+		return uow.HolidayCalendars().DeleteHoliday(calendar.ID, holidayID)
+	})
+}
+
+// ListHolidays returns the public and custom holidays of a holiday calendar in a year, ordered by date.
+func (s *HolidayCalendarService) ListHolidays(companyID, calendarID uint, year int) ([]models.Holiday, error) {
+	calendar, err := getCompanyCalendar(s.unitOfWork, companyID, calendarID)
+	if err != nil {
+		return nil, err
+	}
+	return Holidays(calendar, time.Date(year, time.January, 1, 0, 0, 0, 0, time.UTC), time.Date(year, time.December, 31, 0, 0, 0, 0, time.UTC)), nil
+}
+
+func getCompanyCalendar(uow *repositories.UnitOfWork, companyID, calendarID uint) (*models.HolidayCalendar, error) {
+	calendar, err := uow.HolidayCalendars().GetByID(calendarID)
+	if err != nil {
+		return nil, err
+	}
+	if calendar.CompanyID != companyID {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return calendar, nil
+}
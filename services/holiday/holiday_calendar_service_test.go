@@ -0,0 +1,94 @@
+package holiday_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/r-52/embrace/models"
+	dto "github.com/r-52/embrace/models/dto/holiday"
+	"github.com/r-52/embrace/repositories"
+	"github.com/r-52/embrace/services/holiday"
+	"gorm.io/gorm"
+)
+
+func setupDb(t *testing.T) *gorm.DB {
+	db := repositories.GetDatabase()
+	err := db.AutoMigrate(&models.Company{}, &models.User{}, &models.UserProfile{}, &models.HolidayCalendar{}, &models.Holiday{})
+	if err != nil {
+		t.Fatalf("failed to migrate database: %v", err)
+	}
+	return db
+}
+
+func TestHolidayCalendarService_Calendars_And_Holidays(t *testing.T) {
+	db := setupDb(t)
+	service := holiday.NewHolidayCalendarService(repositories.WithTenant(db, 1))
+
+	if _, err := service.CreateCalendar(1, &dto.HolidayCalendarRequest{Name: "Atlantis", Region: "XX"}); !errors.Is(err, holiday.ErrUnknownRegion) {
+		t.Errorf("expected ErrUnknownRegion, got %v", err)
+	}
+	calendar, err := service.CreateCalendar(1, &dto.HolidayCalendarRequest{Name: "Munich", Region: holiday.REGION_BAVARIA})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	christmasEve, err := service.AddHoliday(1, calendar.ID, &dto.HolidayRequest{Date: "2024-12-24", Name: "Heiligabend", HalfDay: true, Recurring: true})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	holidays, err := service.ListHolidays(1, calendar.ID, 2024)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(holidays) != 13 {
+		t.Errorf("expected 12 public holidays and Christmas Eve, got %d", len(holidays))
+	}
+
+	// Moving the calendar to Berlin changes the generated holidays but keeps the custom ones.
+	if _, err := service.UpdateCalendar(1, calendar.ID, &dto.HolidayCalendarRequest{Name: "Berlin", Region: holiday.REGION_BERLIN}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if holidays, _ := service.ListHolidays(1, calendar.ID, 2024); len(holidays) != 11 {
+		t.Errorf("expected 10 public holidays and Christmas Eve, got %d", len(holidays))
+	}
+	if err := service.DeleteHoliday(1, calendar.ID, christmasEve.ID); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := service.DeleteHoliday(1, calendar.ID, christmasEve.ID); !errors.Is(err, gorm.ErrRecordNotFound) {
+		t.Errorf("expected ErrRecordNotFound, got %v", err)
+	}
+
+	// Calendars of other companies do not exist.
+	foreign := holiday.NewHolidayCalendarService(repositories.WithTenant(db, 2))
+	if _, err := foreign.ListHolidays(2, calendar.ID, 2024); !errors.Is(err, gorm.ErrRecordNotFound) {
+		t.Errorf("expected ErrRecordNotFound, got %v", err)
+	}
+	if _, err := foreign.AddHoliday(2, calendar.ID, &dto.HolidayRequest{Date: "2024-06-14", Name: "Party"}); !errors.Is(err, gorm.ErrRecordNotFound) {
+		t.Errorf("expected ErrRecordNotFound, got %v", err)
+	}
+}
+
+func TestHolidayCalendarService_DeleteCalendar_In_Use(t *testing.T) {
+	db := setupDb(t)
+	service := holiday.NewHolidayCalendarService(repositories.WithTenant(db, 1))
+
+	calendar, err := service.CreateCalendar(1, &dto.HolidayCalendarRequest{Name: "Munich", Region: holiday.REGION_BAVARIA})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	user := &models.User{Email: "user@example.com", CompanyID: 1, HolidayCalendarID: &calendar.ID, UserProfile: models.UserProfile{Slug: "user"}}
+	if err := db.Create(user).Error; err != nil {
+		t.Fatalf("failed to create user: %v", err)
+	}
+	if err := service.DeleteCalendar(1, calendar.ID); !errors.Is(err, holiday.ErrHolidayCalendarInUse) {
+		t.Errorf("expected ErrHolidayCalendarInUse, got %v", err)
+	}
+
+	db.Model(user).Update("holiday_calendar_id", nil)
+	if err := service.DeleteCalendar(1, calendar.ID); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if calendars, _ := service.ListCalendars(1); len(calendars) != 0 {
+		t.Errorf("expected no calendars, got %v", calendars)
+	}
+}
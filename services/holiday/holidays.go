@@ -0,0 +1,62 @@
+package holiday
+
+import (
+	"sort"
+	"time"
+
+	"github.com/r-52/embrace/models"
+)
+
+// Holidays returns the holidays of a calendar from `from` to `to`, both days at midnight UTC and inclusive, ordered by date.
+// They are the public holidays of the calendar's region and its custom holidays, with recurring ones repeated every year.
+// When several holidays fall on the same day, a full day off takes precedence over a half one. A nil calendar has no holidays.
+func Holidays(calendar *models.HolidayCalendar, from, to time.Time) []models.Holiday {
+	if calendar == nil || to.Before(from) {
+		return nil
+	}
+
+	byDay := map[string]models.Holiday{}
+	add := func(holiday models.Holiday) {
+		if holiday.Date.Before(from) || holiday.Date.After(to) {
+			return
+		}
+		key := holiday.Date.Format(time.DateOnly)
+		if existing, ok := byDay[key]; ok && (!existing.HalfDay || holiday.HalfDay) {
+			return
+		}
+		byDay[key] = holiday
+	}
+
+	for year := from.Year(); year <= to.Year(); year++ {
+		for _, holiday := range PublicHolidays(calendar.Region, year) {
+			add(holiday)
+		}
+		for _, holiday := range calendar.Holidays {
+			if !holiday.Recurring {
+				continue
+			}
+			date := time.Date(year, holiday.Date.Month(), holiday.Date.Day(), 0, 0, 0, 0, time.UTC)
+			if !holiday.OccursOn(date) || date.Before(holiday.Date) {
+				// The 29th of February only recurs in leap years, and no holiday recurs before it was added.
+				continue
+			}
+			holiday.Date = date
+			add(holiday)
+		}
+	}
+	for _, holiday := range calendar.Holidays {
+		if !holiday.Recurring {
+			holiday.Date = time.Date(holiday.Date.Year(), holiday.Date.Month(), holiday.Date.Day(), 0, 0, 0, 0, time.UTC)
+			add(holiday)
+		}
+	}
+
+	holidays := make([]models.Holiday, 0, len(byDay))
+	for _, holiday := range byDay {
+		holidays = append(holidays, holiday)
+	}
+	sort.Slice(holidays, func(i, j int) bool {
+		return holidays[i].Date.Before(holidays[j].Date)
+	})
+	return holidays
+}
@@ -0,0 +1,62 @@
+package holiday_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/r-52/embrace/models"
+	"github.com/r-52/embrace/services/holiday"
+	"gorm.io/gorm"
+)
+
+func day(value string) time.Time {
+	date, _ := time.Parse(time.DateOnly, value)
+	return date
+}
+
+func TestHolidays(t *testing.T) {
+	calendar := &models.HolidayCalendar{
+		Region: holiday.REGION_BERLIN,
+		Holidays: []models.Holiday{
+			{Model: gorm.Model{ID: 1}, Date: day("2023-12-24"), Name: "Heiligabend", HalfDay: true, Recurring: true},
+			{Model: gorm.Model{ID: 2}, Date: day("2024-06-14"), Name: "Company party"},
+			{Model: gorm.Model{ID: 3}, Date: day("2024-02-29"), Name: "Leap day", Recurring: true},
+			// A half day does not shorten a public holiday.
+			{Model: gorm.Model{ID: 4}, Date: day("2024-12-25"), Name: "Half Christmas", HalfDay: true},
+		},
+	}
+
+	holidays := holiday.Holidays(calendar, day("2024-01-01"), day("2025-12-31"))
+	byDay := map[string]models.Holiday{}
+	for _, h := range holidays {
+		byDay[h.Date.Format(time.DateOnly)] = h
+	}
+	if len(holidays) != 2*10+2+1+1 {
+		t.Errorf("expected 24 holidays, got %d: %v", len(holidays), holidays)
+	}
+	if h, ok := byDay["2024-12-24"]; !ok || !h.HalfDay {
+		t.Errorf("expected a recurring half day on 2024-12-24, got %+v", h)
+	}
+	if _, ok := byDay["2025-12-24"]; !ok {
+		t.Errorf("expected the half day to recur in 2025")
+	}
+	if _, ok := byDay["2024-02-29"]; !ok {
+		t.Errorf("expected the leap day in 2024")
+	}
+	if _, ok := byDay["2025-03-01"]; ok {
+		t.Errorf("expected the leap day not to move to March in 2025")
+	}
+	if h := byDay["2024-12-25"]; h.HalfDay || h.Name != "1. Weihnachtstag" {
+		t.Errorf("expected the full public holiday on 2024-12-25, got %+v", h)
+	}
+	if _, ok := byDay["2024-06-14"]; !ok {
+		t.Errorf("expected the company party")
+	}
+
+	if holidays := holiday.Holidays(calendar, day("2023-12-01"), day("2023-12-31")); len(holidays) != 3 {
+		t.Errorf("expected the half day and two public holidays in December 2023, got %v", holidays)
+	}
+	if holidays := holiday.Holidays(nil, day("2024-01-01"), day("2024-12-31")); len(holidays) != 0 {
+		t.Errorf("expected no holidays without a calendar, got %v", holidays)
+	}
+}
@@ -0,0 +1,176 @@
+package holiday
+
+import (
+	"sort"
+	"time"
+
+	"github.com/r-52/embrace/models"
+)
+
+const REGION_GERMANY = "DE"
+const REGION_BADEN_WUERTTEMBERG = "DE-BW"
+const REGION_BAVARIA = "DE-BY"
+const REGION_BERLIN = "DE-BE"
+const REGION_BRANDENBURG = "DE-BB"
+const REGION_BREMEN = "DE-HB"
+const REGION_HAMBURG = "DE-HH"
+const REGION_HESSE = "DE-HE"
+const REGION_MECKLENBURG_WESTERN_POMERANIA = "DE-MV"
+const REGION_LOWER_SAXONY = "DE-NI"
+const REGION_NORTH_RHINE_WESTPHALIA = "DE-NW"
+const REGION_RHINELAND_PALATINATE = "DE-RP"
+const REGION_SAARLAND = "DE-SL"
+const REGION_SAXONY = "DE-SN"
+const REGION_SAXONY_ANHALT = "DE-ST"
+const REGION_SCHLESWIG_HOLSTEIN = "DE-SH"
+const REGION_THURINGIA = "DE-TH"
+
+// REGIONS lists the regions whose public holidays can be generated. DE only has the nationwide holidays of Germany,
+// the federal states add their own. Holidays of single municipalities, such as Assumption Day in parts of Bavaria,
+// are not generated and can be added to a calendar as custom holidays.
+var REGIONS = []string{
+	REGION_GERMANY,
+	REGION_BADEN_WUERTTEMBERG,
+	REGION_BAVARIA,
+	REGION_BERLIN,
+	REGION_BRANDENBURG,
+	REGION_BREMEN,
+	REGION_HAMBURG,
+	REGION_HESSE,
+	REGION_MECKLENBURG_WESTERN_POMERANIA,
+	REGION_LOWER_SAXONY,
+	REGION_NORTH_RHINE_WESTPHALIA,
+	REGION_RHINELAND_PALATINATE,
+	REGION_SAARLAND,
+	REGION_SAXONY,
+	REGION_SAXONY_ANHALT,
+	REGION_SCHLESWIG_HOLSTEIN,
+	REGION_THURINGIA,
+}
+
+// publicHoliday is a rule that generates a public holiday every year from `from` until `until`, a zero year being open.
+// A holiday without regions is observed in every region.
+type publicHoliday struct {
+	name    string
+	date    func(year int) time.Time
+	regions []string
+	from    int
+	until   int
+}
+
+var publicHolidays = []publicHoliday{
+	{name: "Neujahr", date: fixed(time.January, 1)},
+	{name: "Heilige Drei Könige", date: fixed(time.January, 6), regions: []string{REGION_BADEN_WUERTTEMBERG, REGION_BAVARIA, REGION_SAXONY_ANHALT}},
+	{name: "Internationaler Frauentag", date: fixed(time.March, 8), regions: []string{REGION_BERLIN}, from: 2019},
+	{name: "Internationaler Frauentag", date: fixed(time.March, 8), regions: []string{REGION_MECKLENBURG_WESTERN_POMERANIA}, from: 2023},
+	{name: "Karfreitag", date: easterOffset(-2)},
+	{name: "Ostersonntag", date: easterOffset(0), regions: []string{REGION_BRANDENBURG}},
+	{name: "Ostermontag", date: easterOffset(1)},
+	{name: "Tag der Arbeit", date: fixed(time.May, 1)},
+	{name: "Christi Himmelfahrt", date: easterOffset(39)},
+	{name: "Pfingstsonntag", date: easterOffset(49), regions: []string{REGION_BRANDENBURG}},
+	{name: "Pfingstmontag", date: easterOffset(50)},
+	{name: "Fronleichnam", date: easterOffset(60), regions: []string{REGION_BADEN_WUERTTEMBERG, REGION_BAVARIA, REGION_HESSE, REGION_NORTH_RHINE_WESTPHALIA, REGION_RHINELAND_PALATINATE, REGION_SAARLAND}},
+	{name: "Mariä Himmelfahrt", date: fixed(time.August, 15), regions: []string{REGION_SAARLAND}},
+	{name: "Weltkindertag", date: fixed(time.September, 20), regions: []string{REGION_THURINGIA}, from: 2019},
+	{name: "Tag der Deutschen Einheit", date: fixed(time.October, 3)},
+	{name: "Reformationstag", date: fixed(time.October, 31), regions: []string{REGION_BRANDENBURG, REGION_MECKLENBURG_WESTERN_POMERANIA, REGION_SAXONY, REGION_SAXONY_ANHALT, REGION_THURINGIA}},
+	{name: "Reformationstag", date: fixed(time.October, 31), regions: []string{REGION_BREMEN, REGION_HAMBURG, REGION_LOWER_SAXONY, REGION_SCHLESWIG_HOLSTEIN}, from: 2018},
+	// The 500th anniversary of the Reformation was a public holiday everywhere.
+	{name: "Reformationstag", date: fixed(time.October, 31), from: 2017, until: 2017},
+	{name: "Allerheiligen", date: fixed(time.November, 1), regions: []string{REGION_BADEN_WUERTTEMBERG, REGION_BAVARIA, REGION_NORTH_RHINE_WESTPHALIA, REGION_RHINELAND_PALATINATE, REGION_SAARLAND}},
+	{name: "Buß- und Bettag", date: dayOfRepentance, regions: []string{REGION_SAXONY}},
+	{name: "1. Weihnachtstag", date: fixed(time.December, 25)},
+	{name: "2. Weihnachtstag", date: fixed(time.December, 26)},
+}
+
+// IsKnownRegion reports whether the public holidays of the region can be generated.
+func IsKnownRegion(region string) bool {
+	for _, known := range REGIONS {
+		if known == region {
+			return true
+		}
+	}
+	return false
+}
+
+// PublicHolidays returns the public holidays of a region in a year, ordered by date. The holidays are not stored
+// and their dates are at midnight UTC. Unknown regions have no public holidays.
+func PublicHolidays(region string, year int) []models.Holiday {
+	if !IsKnownRegion(region) {
+		return nil
+	}
+
+	var holidays []models.Holiday
+	seen := map[time.Time]bool{}
+	for _, rule := range publicHolidays {
+		if !rule.observedIn(region, year) {
+			continue
+		}
+		date := rule.date(year)
+		if seen[date] {
+			continue
+		}
+		seen[date] = true
+		holidays = append(holidays, models.Holiday{Date: date, Name: rule.name})
+	}
+	sort.Slice(holidays, func(i, j int) bool {
+		return holidays[i].Date.Before(holidays[j].Date)
+	})
+	return holidays
+}
+
+func (h *publicHoliday) observedIn(region string, year int) bool {
+	if (h.from != 0 && year < h.from) || (h.until != 0 && year > h.until) {
+		return false
+	}
+	if len(h.regions) == 0 {
+		return true
+	}
+	for _, observed := range h.regions {
+		if observed == region {
+			return true
+		}
+	}
+	return false
+}
+
+func fixed(month time.Month, day int) func(year int) time.Time {
+	return func(year int) time.Time {
+		return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+	}
+}
+
+func easterOffset(days int) func(year int) time.Time {
+	return func(year int) time.Time {
+		return easterSunday(year).AddDate(0, 0, days)
+	}
+}
+
+// easterSunday computes Easter Sunday of the Gregorian calendar with the anonymous Gregorian algorithm.
+func easterSunday(year int) time.Time {
+	a := year % 19
+	b := year / 100
+	c := year % 100
+	d := b / 4
+	e := b % 4
+	f := (b + 8) / 25
+	g := (b - f + 1) / 3
+	h := (19*a + b - d - g + 15) % 30
+	i := c / 4
+	k := c % 4
+	l := (32 + 2*e + 2*i - h - k) % 7
+	m := (a + 11*h + 22*l) / 451
+	month := (h + l - 7*m + 114) / 31
+	day := (h+l-7*m+114)%31 + 1
+	return time.Date(year, time.Month(month), day, 0, 0, 0, 0, time.UTC)
+}
+
+// dayOfRepentance is the last Wednesday before the 23rd of November.
+func dayOfRepentance(year int) time.Time {
+	day := time.Date(year, time.November, 22, 0, 0, 0, 0, time.UTC)
+	for day.Weekday() != time.Wednesday {
+		day = day.AddDate(0, 0, -1)
+	}
+	return day
+}
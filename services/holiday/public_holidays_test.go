@@ -0,0 +1,64 @@
+package holiday
+
+import (
+	"testing"
+	"time"
+)
+
+func TestEasterSunday(t *testing.T) {
+	tests := map[int]string{
+		2000: "2000-04-23",
+		2019: "2019-04-21",
+		2024: "2024-03-31",
+		2025: "2025-04-20",
+		2038: "2038-04-25",
+	}
+	for year, expected := range tests {
+		if actual := easterSunday(year).Format(time.DateOnly); actual != expected {
+			t.Errorf("%d: expected %s, got %s", year, expected, actual)
+		}
+	}
+}
+
+func TestPublicHolidays(t *testing.T) {
+	tests := []struct {
+		name     string
+		region   string
+		year     int
+		expected int
+		includes string
+		excludes string
+	}{
+		{name: "nationwide", region: REGION_GERMANY, year: 2024, expected: 9, includes: "2024-05-09", excludes: "2024-01-06"},
+		{name: "bavaria", region: REGION_BAVARIA, year: 2024, expected: 12, includes: "2024-05-30", excludes: "2024-10-31"},
+		{name: "berlin", region: REGION_BERLIN, year: 2024, expected: 10, includes: "2024-03-08"},
+		{name: "berlin before the women's day", region: REGION_BERLIN, year: 2018, expected: 9, excludes: "2018-03-08"},
+		{name: "brandenburg", region: REGION_BRANDENBURG, year: 2024, expected: 12, includes: "2024-03-31"},
+		{name: "saxony", region: REGION_SAXONY, year: 2024, expected: 11, includes: "2024-11-20"},
+		{name: "lower saxony in the anniversary year", region: REGION_LOWER_SAXONY, year: 2017, expected: 10, includes: "2017-10-31"},
+		{name: "bavaria in the anniversary year", region: REGION_BAVARIA, year: 2017, expected: 13, includes: "2017-10-31"},
+		{name: "lower saxony before the reformation day", region: REGION_LOWER_SAXONY, year: 2016, expected: 9, excludes: "2016-10-31"},
+		{name: "unknown region", region: "XX", year: 2024},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			holidays := PublicHolidays(tt.region, tt.year)
+			if len(holidays) != tt.expected {
+				t.Errorf("expected %d holidays, got %d: %v", tt.expected, len(holidays), holidays)
+			}
+			dates := map[string]bool{}
+			for i, holiday := range holidays {
+				dates[holiday.Date.Format(time.DateOnly)] = true
+				if i > 0 && !holidays[i-1].Date.Before(holiday.Date) {
+					t.Errorf("expected holidays ordered by date, got %v after %v", holiday.Date, holidays[i-1].Date)
+				}
+			}
+			if tt.includes != "" && !dates[tt.includes] {
+				t.Errorf("expected %s to be a holiday", tt.includes)
+			}
+			if tt.excludes != "" && dates[tt.excludes] {
+				t.Errorf("expected %s not to be a holiday", tt.excludes)
+			}
+		})
+	}
+}
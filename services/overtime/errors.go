@@ -0,0 +1,19 @@
+package overtime
+
+import "errors"
+
+// ErrUnknownWorkTimeType is returned when an overtime policy counts a time entry type that does not exist in the company.
+var ErrUnknownWorkTimeType = errors.New("E8000")
+
+// ErrOvertimeNotConfigured is returned when overtime should be booked in a company without an overtime policy.
+var ErrOvertimeNotConfigured = errors.New("E8001")
+
+// ErrPayoutExceedsBalance is returned when more overtime should be paid out than the overtime account holds.
+var ErrPayoutExceedsBalance = errors.New("E8002")
+
+// ErrOvertimeQuotaConflict is returned when the company has a quota named like the overtime quota that is not measured
+// in hours or is reset, so it cannot hold overtime accounts.
+var ErrOvertimeQuotaConflict = errors.New("E8003")
+
+// ErrInvalidOvertimePeriod is returned when an overtime breakdown ends before it starts or is longer than MAX_OVERTIME_DAYS.
+var ErrInvalidOvertimePeriod = errors.New("E8004")
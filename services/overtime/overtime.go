@@ -0,0 +1,119 @@
+package overtime
+
+import (
+	"errors"
+	"math"
+	"time"
+
+	"github.com/r-52/embrace/models"
+	"github.com/r-52/embrace/repositories"
+	"github.com/r-52/embrace/services/schedule"
+	"gorm.io/gorm"
+)
+
+// overtimeDay are the hours a user worked on a calendar day and the hours they were supposed to work.
+type overtimeDay struct {
+	date   time.Time
+	worked float64
+	target float64
+}
+
+// overtimeDays returns the worked and the target hours of a user for every day from `from` to `to`, both calendar
+// days and inclusive. Worked hours are the durations of the user's entries on types that count as worked time,
+// split at midnight in the company's timezone in proportion to the booked times. Running timers count once they are stopped.
+func overtimeDays(uow *repositories.UnitOfWork, user *models.User, company *models.Company, policy *models.OvertimePolicy, from, to time.Time) ([]overtimeDay, error) {
+	targets, err := schedule.NewTargetHoursServiceWithUnitOfWork(uow).TargetHoursOf(user, from, to)
+	if err != nil {
+		return nil, err
+	}
+	timeEntryTypes, err := uow.TimeEntryTypes().GetByCompanyID(user.CompanyID)
+	if err != nil {
+		return nil, err
+	}
+	counted := map[uint]bool{}
+	for i := range timeEntryTypes {
+		counted[timeEntryTypes[i].ID] = policy.CountsAsWorkTime(&timeEntryTypes[i])
+	}
+
+	location := company.Location()
+	start := time.Date(from.Year(), from.Month(), from.Day(), 0, 0, 0, 0, location)
+	end := time.Date(to.Year(), to.Month(), to.Day()+1, 0, 0, 0, 0, location)
+	// Entries that started the day before may reach into the period.
+	entries, err := uow.TimeEntries().GetByUserIDAndDateRange(user.ID, start.AddDate(0, 0, -1), end)
+	if err != nil {
+		return nil, err
+	}
+	worked := map[string]float64{}
+	for i := range entries {
+		if entries[i].IsRunning() || !counted[entries[i].TimeEntryTypeID] {
+			continue
+		}
+		span := entries[i].EndTime.Time.Sub(entries[i].StartTime).Hours()
+		if span <= 0 {
+			continue
+		}
+		// The duration policy may count less or more than the booked times, which spreads over the days alike.
+		share := entries[i].Duration.Float64 / span
+		entryStart := latest(entries[i].StartTime.In(location), start)
+		entryEnd := earliest(entries[i].EndTime.Time.In(location), end)
+		for day := time.Date(entryStart.Year(), entryStart.Month(), entryStart.Day(), 0, 0, 0, 0, location); day.Before(entryEnd); day = day.AddDate(0, 0, 1) {
+			worked[day.Format(time.DateOnly)] += earliest(entryEnd, day.AddDate(0, 0, 1)).Sub(latest(entryStart, day)).Hours() * share
+		}
+	}
+
+	days := make([]overtimeDay, 0, len(targets.Days))
+	for _, target := range targets.Days {
+		date, err := time.Parse(time.DateOnly, target.Date)
+		if err != nil {
+			return nil, err
+		}
+		days = append(days, overtimeDay{date: date, worked: worked[target.Date], target: target.TargetHours})
+	}
+	return days, nil
+}
+
+// sumDays returns the worked and the target hours of the days.
+func sumDays(days []overtimeDay) (worked, target float64) {
+	for _, day := range days {
+		worked += day.worked
+		target += day.target
+	}
+	return roundHours(worked), roundHours(target)
+}
+
+// firstUnsettledMonth returns the first day of the first month of a user's overtime account that was not settled yet,
+// as midnight UTC. Accounts accrue overtime from the month they were opened in, in the company's timezone.
+func firstUnsettledMonth(uow *repositories.UnitOfWork, company *models.Company, account *models.UserQuota) (time.Time, error) {
+	latestSettlement, err := uow.OvertimeSettlements().GetLatestByUserID(account.UserID)
+	if err == nil {
+		return latestSettlement.Month.AddDate(0, 1, 0), nil
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return time.Time{}, err
+	}
+	return monthOf(account.CreatedAt.In(company.Location())), nil
+}
+
+// monthOf returns the first day of the month of t as midnight UTC.
+func monthOf(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC)
+}
+
+// roundHours rounds a sum of hours to hundredths, which hides the imprecision of adding up fractions of hours.
+func roundHours(hours float64) float64 {
+	return math.Round(hours*100) / 100
+}
+
+func latest(a, b time.Time) time.Time {
+	if a.After(b) {
+		return a
+	}
+	return b
+}
+
+func earliest(a, b time.Time) time.Time {
+	if a.Before(b) {
+		return a
+	}
+	return b
+}
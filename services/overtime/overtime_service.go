@@ -0,0 +1,281 @@
+package overtime
+
+import (
+	"errors"
+	"time"
+
+	"github.com/r-52/embrace/models"
+	dto "github.com/r-52/embrace/models/dto/overtime"
+	"github.com/r-52/embrace/repositories"
+	"github.com/r-52/embrace/services/quota"
+	"gorm.io/gorm"
+)
+
+// MAX_OVERTIME_DAYS is the number of calendar days a single overtime breakdown may cover at most.
+const MAX_OVERTIME_DAYS = 366
+
+// OvertimeService computes the overtime of users and manages the overtime rules of a company, see models.OvertimePolicy.
+type OvertimeService struct {
+	unitOfWork *repositories.UnitOfWork
+}
+
+type OvertimeServiceInterface interface {
+	GetPolicy(companyID uint) (*models.OvertimePolicy, error)
+	UpdatePolicy(companyID uint, req *dto.OvertimePolicyRequest) (*models.OvertimePolicy, error)
+	Overtime(actor *models.User, userID uint, req *dto.OvertimeRequest, now time.Time) (*dto.OvertimeResponse, error)
+	Payout(actor *models.User, userID uint, req *dto.PayoutRequest, now time.Time) (*models.UserQuotaTransaction, error)
+}
+
+// NewOvertimeService creates an OvertimeService. The database should be scoped to the company, see repositories.WithTenant.
+func NewOvertimeService(db *gorm.DB) *OvertimeService {
+	return NewOvertimeServiceWithUnitOfWork(repositories.NewUnitOfWork(db))
+}
+
+// NewOvertimeServiceWithUnitOfWork creates an OvertimeService whose repositories join the given unit of work.
+func NewOvertimeServiceWithUnitOfWork(uow *repositories.UnitOfWork) *OvertimeService {
+	return &OvertimeService{
+		unitOfWork: uow,
+	}
+}
+
+// GetPolicy returns the overtime policy of the company, or gorm.ErrRecordNotFound if it has none.
+func (s *OvertimeService) GetPolicy(companyID uint) (*models.OvertimePolicy, error) {
+	return s.unitOfWork.OvertimePolicies().GetByCompanyID(companyID)
+}
+
+// UpdatePolicy sets the overtime policy of the company. The first policy creates the quota of the overtime accounts,
+// which are then assigned to users like any other quota. It returns ErrUnknownWorkTimeType if a counted type does not
+// exist in the company and ErrOvertimeQuotaConflict if the company has a quota of that name that cannot hold overtime.
+func (s *OvertimeService) UpdatePolicy(companyID uint, req *dto.OvertimePolicyRequest) (*models.OvertimePolicy, error) {
+	var updated *models.OvertimePolicy
+	err := s.unitOfWork.Transaction(func(uow *repositories.UnitOfWork) error {
+		var workTimeTypes []models.OvertimeWorkTimeType
+		seen := map[uint]bool{}
+		for _, id := range req.WorkTimeTypeIDs {
+			timeEntryType, err := uow.TimeEntryTypes().GetByID(id)
+			if errors.Is(err, gorm.ErrRecordNotFound) || (err == nil && timeEntryType.CompanyID != companyID) {
+				return ErrUnknownWorkTimeType
+			}
+			if err != nil {
+				return err
+			}
+			if !seen[id] {
+				seen[id] = true
+				workTimeTypes = append(workTimeTypes, models.OvertimeWorkTimeType{TimeEntryTypeID: id})
+			}
+		}
+		overtimeQuota, err := overtimeQuotaOf(uow, companyID)
+		if err != nil {
+			return err
+		}
+
+		policy, err := uow.OvertimePolicies().GetByCompanyID(companyID)
+		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			return err
+		}
+		if policy == nil {
+			policy = &models.OvertimePolicy{CompanyID: companyID}
+		}
+		policy.QuotaID = overtimeQuota.ID
+		policy.WorkTimeTypes = workTimeTypes
+		policy.MonthlyCap = req.MonthlyCap
+		policy.BalanceCap = req.BalanceCap
+		if policy.ID == 0 {
+			err = uow.OvertimePolicies().Create(policy)
+		} else {
+			err = uow.OvertimePolicies().Update(policy)
+		}
+		if err != nil {
+			return err
+		}
+		updated, err = uow.OvertimePolicies().GetByCompanyID(companyID)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return updated, nil
+}
+
+// Overtime breaks down the overtime of a user from `from` to `to`, both calendar days and inclusive, by day, week or month.
+// Users with an overtime account also get its running balance: what was booked on it so far plus the overtime of the
+// months that were not settled yet, until `now`. The actor needs the permission to read the user's time entries.
+// It fails with ErrInvalidOvertimePeriod if the period ends before it starts or is longer than MAX_OVERTIME_DAYS.
+func (s *OvertimeService) Overtime(actor *models.User, userID uint, req *dto.OvertimeRequest, now time.Time) (*dto.OvertimeResponse, error) {
+	if req.To.Before(req.From) || !req.To.Before(req.From.AddDate(0, 0, MAX_OVERTIME_DAYS)) {
+		return nil, ErrInvalidOvertimePeriod
+	}
+	user, err := s.unitOfWork.Users().GetByID(userID)
+	if err != nil {
+		return nil, err
+	}
+	if !actor.CanAccess(user, models.PERMISSION_TIME_ENTRIES_READ) {
+		return nil, gorm.ErrRecordNotFound
+	}
+	company, err := s.unitOfWork.Companies().GetByID(user.CompanyID)
+	if err != nil {
+		return nil, err
+	}
+	policy, err := s.unitOfWork.OvertimePolicies().GetByCompanyID(user.CompanyID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		policy = &models.OvertimePolicy{}
+	} else if err != nil {
+		return nil, err
+	}
+
+	days, err := overtimeDays(s.unitOfWork, user, company, policy, req.From, req.To)
+	if err != nil {
+		return nil, err
+	}
+	groupBy := req.GroupBy
+	if groupBy == "" {
+		groupBy = dto.OVERTIME_GROUP_BY_DAY
+	}
+	response := &dto.OvertimeResponse{
+		UserID:  user.ID,
+		GroupBy: groupBy,
+		Periods: []*dto.OvertimePeriodResponse{},
+	}
+	if len(days) > 0 {
+		response.From = days[0].date.Format(time.DateOnly)
+		response.To = days[len(days)-1].date.Format(time.DateOnly)
+	}
+	var period *dto.OvertimePeriodResponse
+	var periodStart time.Time
+	for _, day := range days {
+		if start := startOfGroup(day.date, groupBy); period == nil || !start.Equal(periodStart) {
+			period = &dto.OvertimePeriodResponse{Start: day.date.Format(time.DateOnly)}
+			periodStart = start
+			response.Periods = append(response.Periods, period)
+		}
+		period.End = day.date.Format(time.DateOnly)
+		period.WorkedHours += day.worked
+		period.TargetHours += day.target
+	}
+	cumulative := 0.0
+	for _, period := range response.Periods {
+		period.WorkedHours = roundHours(period.WorkedHours)
+		period.TargetHours = roundHours(period.TargetHours)
+		period.Overtime = roundHours(period.WorkedHours - period.TargetHours)
+		cumulative += period.Overtime
+		period.CumulativeOvertime = roundHours(cumulative)
+	}
+	response.WorkedHours, response.TargetHours = sumDays(days)
+	response.Overtime = roundHours(response.WorkedHours - response.TargetHours)
+
+	if policy.ID != 0 {
+		response.Account, err = s.account(user, company, policy, now)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return response, nil
+}
+
+// account returns the running balance of a user's overtime account, or nil if they have none.
+func (s *OvertimeService) account(user *models.User, company *models.Company, policy *models.OvertimePolicy, now time.Time) (*dto.OvertimeAccountResponse, error) {
+	userQuota, err := s.unitOfWork.UserQuotas().GetByUserIDAndQuotaID(user.ID, policy.QuotaID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	account := &dto.OvertimeAccountResponse{
+		QuotaID: policy.QuotaID,
+		Balance: userQuota.Count,
+	}
+
+	month, err := firstUnsettledMonth(s.unitOfWork, company, userQuota)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := s.unitOfWork.OvertimeSettlements().GetLatestByUserID(user.ID); err == nil {
+		account.SettledUntil = month.AddDate(0, 0, -1).Format(time.DateOnly)
+	}
+	today := now.In(company.Location())
+	if today = time.Date(today.Year(), today.Month(), today.Day(), 0, 0, 0, 0, time.UTC); !today.Before(month) {
+		days, err := overtimeDays(s.unitOfWork, user, company, policy, month, today)
+		if err != nil {
+			return nil, err
+		}
+		worked, target := sumDays(days)
+		account.Unsettled = models.NewQuotaAmount(worked - target)
+	}
+	account.RunningBalance = account.Balance + account.Unsettled
+	return account, nil
+}
+
+// Payout pays out hours of a user's overtime account. It returns ErrOvertimeNotConfigured if the company has no
+// overtime policy, quota.ErrQuotaNotAssigned if the user has no overtime account and ErrPayoutExceedsBalance if the
+// account holds less than the hours. The payout is recorded on the account by the actor.
+func (s *OvertimeService) Payout(actor *models.User, userID uint, req *dto.PayoutRequest, now time.Time) (*models.UserQuotaTransaction, error) {
+	var transaction *models.UserQuotaTransaction
+	err := s.unitOfWork.Transaction(func(uow *repositories.UnitOfWork) error {
+		user, err := uow.Users().GetByID(userID)
+		if err != nil {
+			return err
+		}
+		policy, err := uow.OvertimePolicies().GetByCompanyID(user.CompanyID)
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrOvertimeNotConfigured
+		}
+		if err != nil {
+			return err
+		}
+		account, err := uow.UserQuotas().GetByUserIDAndQuotaID(user.ID, policy.QuotaID)
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return quota.ErrQuotaNotAssigned
+		}
+		if err != nil {
+			return err
+		}
+		if account.Count < req.Hours {
+			return ErrPayoutExceedsBalance
+		}
+
+		transaction, err = quota.NewQuotaLedgerWithUnitOfWork(uow).Book(actor, user.ID, policy.QuotaID, models.QUOTA_TRANSACTION_PAYOUT, -req.Hours, req.Reason, now)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return transaction, nil
+}
+
+// overtimeQuotaOf returns the quota of the overtime accounts of a company and creates it if it does not exist yet.
+func overtimeQuotaOf(uow *repositories.UnitOfWork, companyID uint) (*models.Quota, error) {
+	overtimeQuota, err := uow.Quotas().GetByCompanyIDAndName(companyID, models.OVERTIME_QUOTA_NAME)
+	if err == nil {
+		if overtimeQuota.Unit != models.QUOTA_UNIT_HOURS || overtimeQuota.QuotaResetAt != models.QUOTA_RESET_NEVER {
+			return nil, ErrOvertimeQuotaConflict
+		}
+		return overtimeQuota, nil
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+
+	overtimeQuota = &models.Quota{
+		Name:         models.OVERTIME_QUOTA_NAME,
+		CompanyID:    companyID,
+		QuotaResetAt: models.QUOTA_RESET_NEVER,
+		Unit:         models.QUOTA_UNIT_HOURS,
+	}
+	if err := uow.Quotas().Create(overtimeQuota); err != nil {
+		return nil, err
+	}
+	return overtimeQuota, nil
+}
+
+// startOfGroup returns the first day of the day, week or month a day belongs to. Weeks start on Monday.
+func startOfGroup(day time.Time, groupBy string) time.Time {
+	switch groupBy {
+	case dto.OVERTIME_GROUP_BY_WEEK:
+		return day.AddDate(0, 0, -(int(day.Weekday())+6)%7)
+	case dto.OVERTIME_GROUP_BY_MONTH:
+		return monthOf(day)
+	default:
+		return day
+	}
+}
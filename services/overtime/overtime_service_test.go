@@ -0,0 +1,199 @@
+package overtime_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/r-52/embrace/models"
+	dto "github.com/r-52/embrace/models/dto/overtime"
+	"github.com/r-52/embrace/repositories"
+	"github.com/r-52/embrace/services/overtime"
+	"github.com/r-52/embrace/services/quota"
+	"github.com/r-52/embrace/services/role"
+	"github.com/r-52/embrace/services/servicetest"
+	"gorm.io/gorm"
+)
+
+type fixture struct {
+	db            *gorm.DB
+	companyID     uint
+	admin         *models.User
+	employee      *models.User
+	colleague     *models.User
+	work          *models.TimeEntryType
+	travel        *models.TimeEntryType
+	timeOffInLieu *models.TimeEntryType
+}
+
+func setupFixture(t *testing.T) *fixture {
+	db := servicetest.Database(t, &models.Company{}, &models.User{}, &models.UserProfile{}, &models.UserRole{}, &models.RolePermission{},
+		&models.TimeEntryType{}, &models.TimeEntry{}, &models.Quota{}, &models.UserQuota{}, &models.UserQuotaCarryOver{},
+		&models.UserQuotaTransaction{}, &models.LeaveRequest{}, &models.HolidayCalendar{}, &models.Holiday{}, &models.WorkSchedule{},
+		&models.WorkScheduleDay{}, &models.UserWorkSchedule{}, &models.OvertimePolicy{}, &models.OvertimeWorkTimeType{}, &models.OvertimeSettlement{})
+
+	f := &fixture{db: db}
+	f.companyID = servicetest.Company(t, db, "acme").ID
+	f.admin = servicetest.User(t, db, f.companyID, role.DEFAULT_ROLE_ADMIN, "admin", nil)
+	f.employee = servicetest.User(t, db, f.companyID, role.DEFAULT_ROLE_EMPLOYEE, "employee", nil)
+	f.colleague = servicetest.User(t, db, f.companyID, role.DEFAULT_ROLE_EMPLOYEE, "colleague", nil)
+	f.work = &models.TimeEntryType{Name: "Work", Color: "#0000ff", CompanyID: f.companyID}
+	servicetest.MustCreate(t, db, f.work)
+	f.travel = &models.TimeEntryType{Name: "Travel", Color: "#00ffff", CompanyID: f.companyID}
+	servicetest.MustCreate(t, db, f.travel)
+	f.timeOffInLieu = &models.TimeEntryType{Name: "Time off in lieu", Color: "#ff0000", CompanyID: f.companyID,
+		IsQuotaRelevant: true, QuotaName: models.OVERTIME_QUOTA_NAME}
+	servicetest.MustCreate(t, db, f.timeOffInLieu)
+	return f
+}
+
+func (f *fixture) service() *overtime.OvertimeService {
+	return overtime.NewOvertimeService(repositories.WithTenant(f.db, f.companyID))
+}
+
+// book creates a closed entry of the employee and books it on their quotas like the time entry service does.
+func (f *fixture) book(t *testing.T, timeEntryType *models.TimeEntryType, start time.Time, hours float64) {
+	entry := &models.TimeEntry{CompanyID: f.companyID, UserID: f.employee.ID, TimeEntryTypeID: timeEntryType.ID, StartTime: start}
+	entry.Close(start.Add(time.Duration(hours * float64(time.Hour))))
+	servicetest.MustCreate(t, f.db, entry)
+	if err := quota.NewQuotaLedger(repositories.WithTenant(f.db, f.companyID)).Apply(f.admin, nil, entry); err != nil {
+		t.Fatalf("failed to book entry: %v", err)
+	}
+}
+
+// openAccount sets up the overtime policy and opens the overtime account of the employee at `openedAt`.
+func (f *fixture) openAccount(t *testing.T, req *dto.OvertimePolicyRequest, openedAt time.Time) *models.OvertimePolicy {
+	policy, err := f.service().UpdatePolicy(f.companyID, req)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	account, err := quota.NewQuotaAllocator(repositories.WithTenant(f.db, f.companyID)).Assign(f.admin, f.employee.ID, policy.QuotaID, openedAt)
+	if err != nil {
+		t.Fatalf("failed to assign overtime account: %v", err)
+	}
+	f.db.Model(account).Update("created_at", openedAt)
+	return policy
+}
+
+func (f *fixture) balance(t *testing.T, policy *models.OvertimePolicy) float64 {
+	account, err := repositories.NewUserQuotaRepository(f.db).GetByUserIDAndQuotaID(f.employee.ID, policy.QuotaID)
+	if err != nil {
+		t.Fatalf("failed to load overtime account: %v", err)
+	}
+	return account.Count.Units()
+}
+
+var monday = time.Date(2024, 3, 4, 8, 0, 0, 0, time.UTC)
+
+func TestOvertimeService_UpdatePolicy(t *testing.T) {
+	f := setupFixture(t)
+	service := f.service()
+
+	if _, err := service.GetPolicy(f.companyID); !errors.Is(err, gorm.ErrRecordNotFound) {
+		t.Errorf("expected ErrRecordNotFound, got %v", err)
+	}
+	if _, err := service.UpdatePolicy(f.companyID, &dto.OvertimePolicyRequest{WorkTimeTypeIDs: []uint{999}}); !errors.Is(err, overtime.ErrUnknownWorkTimeType) {
+		t.Errorf("expected ErrUnknownWorkTimeType, got %v", err)
+	}
+	policy, err := service.UpdatePolicy(f.companyID, &dto.OvertimePolicyRequest{WorkTimeTypeIDs: []uint{f.work.ID, f.work.ID}, MonthlyCap: models.QuotaUnits(20)})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if policy.Quota.Name != models.OVERTIME_QUOTA_NAME || policy.Quota.Unit != models.QUOTA_UNIT_HOURS || policy.Quota.QuotaResetAt != models.QUOTA_RESET_NEVER ||
+		len(policy.WorkTimeTypes) != 1 || policy.MonthlyCap != models.QuotaUnits(20) {
+		t.Errorf("expected the policy with an overtime quota, got %+v", policy)
+	}
+
+	updated, err := service.UpdatePolicy(f.companyID, &dto.OvertimePolicyRequest{WorkTimeTypeIDs: []uint{f.work.ID, f.travel.ID}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if updated.ID != policy.ID || updated.QuotaID != policy.QuotaID || len(updated.WorkTimeTypes) != 2 || updated.MonthlyCap != 0 {
+		t.Errorf("expected the policy to be updated, got %+v", updated)
+	}
+
+	// A quota that happens to be named like the overtime quota cannot hold overtime.
+	f.db.Model(&models.Quota{}).Where("id = ?", policy.QuotaID).Update("unit", models.QUOTA_UNIT_DAYS)
+	if _, err := service.UpdatePolicy(f.companyID, &dto.OvertimePolicyRequest{}); !errors.Is(err, overtime.ErrOvertimeQuotaConflict) {
+		t.Errorf("expected ErrOvertimeQuotaConflict, got %v", err)
+	}
+}
+
+func TestOvertimeService_Overtime(t *testing.T) {
+	f := setupFixture(t)
+
+	// The week of March 4, 2024 with 8 hour days from Monday to Friday.
+	f.book(t, f.work, monday, 10)
+	f.book(t, f.work, monday.AddDate(0, 0, 1), 9)
+	f.book(t, f.work, monday.AddDate(0, 0, 2), 6)
+	f.book(t, f.travel, monday.AddDate(0, 0, 2).Add(6*time.Hour), 2)
+	f.book(t, f.work, monday.AddDate(0, 0, 3), 8)
+	f.book(t, f.work, monday.AddDate(0, 0, 4), 8)
+	// Saturday night until Sunday morning is split at midnight.
+	f.book(t, f.work, monday.AddDate(0, 0, 5).Add(14*time.Hour), 4)
+
+	week := &dto.OvertimeRequest{From: monday, To: monday.AddDate(0, 0, 6)}
+	response, err := f.service().Overtime(f.employee, f.employee.ID, week, monday.AddDate(0, 1, 0))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if response.WorkedHours != 47 || response.TargetHours != 40 || response.Overtime != 7 || response.Account != nil {
+		t.Errorf("expected 7 hours of overtime without an account, got %+v", response)
+	}
+	expected := []float64{2, 1, 0, 0, 0, 2, 2}
+	if len(response.Periods) != len(expected) {
+		t.Fatalf("expected %d days, got %d", len(expected), len(response.Periods))
+	}
+	for i, period := range response.Periods {
+		if period.Overtime != expected[i] {
+			t.Errorf("%s: expected %v hours of overtime, got %v", period.Start, expected[i], period.Overtime)
+		}
+	}
+	if response.Periods[6].CumulativeOvertime != 7 {
+		t.Errorf("expected 7 hours of cumulative overtime, got %v", response.Periods[6].CumulativeOvertime)
+	}
+
+	// Only work counts once the policy lists it.
+	f.openAccount(t, &dto.OvertimePolicyRequest{WorkTimeTypeIDs: []uint{f.work.ID}}, time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC))
+	weeks := &dto.OvertimeRequest{From: monday, To: monday.AddDate(0, 0, 13), GroupBy: dto.OVERTIME_GROUP_BY_WEEK}
+	response, err = f.service().Overtime(f.admin, f.employee.ID, weeks, monday.AddDate(0, 0, 7).Add(4*time.Hour))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(response.Periods) != 2 || response.Periods[0].Overtime != 5 || response.Periods[1].Overtime != -40 ||
+		response.Periods[1].Start != "2024-03-11" || response.Periods[1].CumulativeOvertime != -35 {
+		t.Errorf("expected 5 hours of overtime in the first week and 40 missing in the second, got %+v %+v", response.Periods[0], response.Periods[1])
+	}
+	// The account accrues from March 1, a Friday, until the Monday after the week.
+	if response.Account == nil || response.Account.Balance != 0 || response.Account.SettledUntil != "" || response.Account.Unsettled != models.QuotaUnits(-11) ||
+		response.Account.RunningBalance != models.QuotaUnits(-11) {
+		t.Errorf("expected an unsettled balance of -11 hours, got %+v", response.Account)
+	}
+
+	months := &dto.OvertimeRequest{From: monday, To: monday.AddDate(0, 1, 0), GroupBy: dto.OVERTIME_GROUP_BY_MONTH}
+	response, err = f.service().Overtime(f.admin, f.employee.ID, months, monday)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(response.Periods) != 2 || response.Periods[0].Start != "2024-03-04" || response.Periods[0].End != "2024-03-31" ||
+		response.Periods[1].Start != "2024-04-01" || response.Periods[1].End != "2024-04-04" {
+		t.Errorf("expected the months to be cut off at the period, got %+v %+v", response.Periods[0], response.Periods[1])
+	}
+
+	if _, err := f.service().Overtime(f.colleague, f.employee.ID, week, monday); !errors.Is(err, gorm.ErrRecordNotFound) {
+		t.Errorf("expected ErrRecordNotFound, got %v", err)
+	}
+
+	year := &dto.OvertimeRequest{From: monday, To: monday.AddDate(0, 0, overtime.MAX_OVERTIME_DAYS-1)}
+	if _, err := f.service().Overtime(f.admin, f.employee.ID, year, monday); err != nil {
+		t.Errorf("unexpected error for a period of MAX_OVERTIME_DAYS: %v", err)
+	}
+	year.To = year.To.AddDate(0, 0, 1)
+	if _, err := f.service().Overtime(f.admin, f.employee.ID, year, monday); !errors.Is(err, overtime.ErrInvalidOvertimePeriod) {
+		t.Errorf("expected ErrInvalidOvertimePeriod for a longer period, got %v", err)
+	}
+	backwards := &dto.OvertimeRequest{From: monday, To: monday.AddDate(0, 0, -1)}
+	if _, err := f.service().Overtime(f.admin, f.employee.ID, backwards, monday); !errors.Is(err, overtime.ErrInvalidOvertimePeriod) {
+		t.Errorf("expected ErrInvalidOvertimePeriod for a period that ends before it starts, got %v", err)
+	}
+}
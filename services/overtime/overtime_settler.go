@@ -0,0 +1,239 @@
+package overtime
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/r-52/embrace/models"
+	"github.com/r-52/embrace/repositories"
+	"github.com/r-52/embrace/services/quota"
+	"gorm.io/gorm"
+)
+
+var errAlreadySettled = errors.New("overtime month already settled")
+
+// OvertimeSettler credits the overtime of every user with an overtime account once a month has ended, in the timezone
+// of the company. The overtime is capped as the company's policy says, booked on the account and recorded as
+// a settlement, and a month is never settled twice. Missing hours are debited. Time entries of a settled month that
+// are corrected later, e.g. before the timesheet is signed off, have the difference booked on the next run.
+type OvertimeSettler struct {
+	unitOfWork *repositories.UnitOfWork
+}
+
+type OvertimeSettlerInterface interface {
+	SettleDue(now time.Time) ([]models.OvertimeSettlement, error)
+}
+
+// NewOvertimeSettler creates an OvertimeSettler. It works across companies, so the database must not be scoped to a tenant.
+func NewOvertimeSettler(db *gorm.DB) *OvertimeSettler {
+	return NewOvertimeSettlerWithUnitOfWork(repositories.NewUnitOfWork(db))
+}
+
+// NewOvertimeSettlerWithUnitOfWork creates an OvertimeSettler whose repositories join the given unit of work.
+func NewOvertimeSettlerWithUnitOfWork(uow *repositories.UnitOfWork) *OvertimeSettler {
+	return &OvertimeSettler{
+		unitOfWork: uow,
+	}
+}
+
+// SettleDue settles every ended month of every overtime account that was not settled yet, corrects the settled months
+// whose time entries changed since and returns the settlements it made or corrected. A failing account does not stop
+// the others, all failures are returned together.
+func (s *OvertimeSettler) SettleDue(now time.Time) ([]models.OvertimeSettlement, error) {
+	companies, err := s.unitOfWork.Companies().GetAll()
+	if err != nil {
+		return nil, err
+	}
+
+	var settlements []models.OvertimeSettlement
+	var errs []error
+	for _, company := range companies {
+		tenant := s.unitOfWork.ForTenant(company.ID)
+		policy, err := tenant.OvertimePolicies().GetByCompanyID(company.ID)
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			continue
+		}
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		accounts, err := tenant.UserQuotas().GetByQuotaID(policy.QuotaID)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+
+		currentMonth := monthOf(now.In(company.Location()))
+		for i := range accounts {
+			settled, err := settleAccount(tenant, &company, policy, &accounts[i], currentMonth, now)
+			settlements = append(settlements, settled...)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("user quota %d: %w", accounts[i].ID, err))
+			}
+		}
+	}
+	return settlements, errors.Join(errs...)
+}
+
+// settleAccount corrects the settled months of an overtime account and settles the months before currentMonth that
+// were not settled yet.
+func settleAccount(uow *repositories.UnitOfWork, company *models.Company, policy *models.OvertimePolicy, account *models.UserQuota, currentMonth time.Time, now time.Time) ([]models.OvertimeSettlement, error) {
+	user, err := uow.Users().GetByID(account.UserID)
+	if err != nil {
+		return nil, err
+	}
+	settled, err := uow.OvertimeSettlements().GetByUserID(user.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	var settlements []models.OvertimeSettlement
+	for i := range settled {
+		corrected, err := correctMonth(uow, company, policy, user, &settled[i], now)
+		if errors.Is(err, errAlreadySettled) {
+			continue
+		}
+		if err != nil {
+			return settlements, err
+		}
+		if corrected != nil {
+			settlements = append(settlements, *corrected)
+		}
+	}
+
+	month, err := firstUnsettledMonth(uow, company, account)
+	if err != nil {
+		return settlements, err
+	}
+	for ; month.Before(currentMonth); month = month.AddDate(0, 1, 0) {
+		settlement, err := settleMonth(uow, company, policy, user, month, now)
+		if errors.Is(err, errAlreadySettled) {
+			continue
+		}
+		if err != nil {
+			return settlements, err
+		}
+		settlements = append(settlements, *settlement)
+	}
+	return settlements, nil
+}
+
+// settleMonth credits the overtime of a user in the month starting at `month` to their overtime account.
+// It returns errAlreadySettled if the month was settled before, also by a concurrent run.
+func settleMonth(uow *repositories.UnitOfWork, company *models.Company, policy *models.OvertimePolicy, user *models.User, month time.Time, now time.Time) (*models.OvertimeSettlement, error) {
+	var settlement *models.OvertimeSettlement
+	err := uow.Transaction(func(uow *repositories.UnitOfWork) error {
+		days, err := overtimeDays(uow, user, company, policy, month, month.AddDate(0, 1, -1))
+		if err != nil {
+			return err
+		}
+		worked, target := sumDays(days)
+		account, err := uow.UserQuotas().GetByUserIDAndQuotaID(user.ID, policy.QuotaID)
+		if err != nil {
+			return err
+		}
+
+		overtime := models.NewQuotaAmount(worked - target)
+		credited := capOvertime(policy, overtime, account.Count)
+		settlement = &models.OvertimeSettlement{
+			CompanyID:   user.CompanyID,
+			UserID:      user.ID,
+			Month:       month,
+			WorkedHours: worked,
+			TargetHours: target,
+			Overtime:    overtime,
+			Credited:    credited,
+			Forfeited:   overtime - credited,
+		}
+		if credited != 0 {
+			transaction, err := quota.NewQuotaLedgerWithUnitOfWork(uow).Book(nil, user.ID, policy.QuotaID, models.QUOTA_TRANSACTION_OVERTIME, credited,
+				"overtime of "+month.Format("2006-01"), now)
+			if err != nil {
+				return err
+			}
+			settlement.UserQuotaTransactionID = &transaction.ID
+		}
+
+		// A concurrent run that settled the month first makes this one fail on the unique index and roll back.
+		err = uow.OvertimeSettlements().Create(settlement)
+		if errors.Is(err, gorm.ErrDuplicatedKey) {
+			return errAlreadySettled
+		}
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return settlement, nil
+}
+
+// correctMonth books the difference of a settled month whose time entries were created, changed or deleted since it
+// was settled or last corrected, and records the recomputed hours on the settlement. It returns nil if the entries did
+// not change or the overtime stayed the same, and errAlreadySettled if a concurrent run corrected the month first.
+func correctMonth(uow *repositories.UnitOfWork, company *models.Company, policy *models.OvertimePolicy, user *models.User, settlement *models.OvertimeSettlement, now time.Time) (*models.OvertimeSettlement, error) {
+	month := settlement.Month.UTC()
+	start := time.Date(month.Year(), month.Month(), 1, 0, 0, 0, 0, company.Location())
+	// Entries that started the day before may reach into the month.
+	changed, err := uow.TimeEntries().CountChangedSince(user.ID, start.AddDate(0, 0, -1), start.AddDate(0, 1, 0), settlement.UpdatedAt)
+	if err != nil || changed == 0 {
+		return nil, err
+	}
+
+	corrected := *settlement
+	err = uow.Transaction(func(uow *repositories.UnitOfWork) error {
+		days, err := overtimeDays(uow, user, company, policy, month, month.AddDate(0, 1, -1))
+		if err != nil {
+			return err
+		}
+		worked, target := sumDays(days)
+		account, err := uow.UserQuotas().GetByUserIDAndQuotaID(user.ID, policy.QuotaID)
+		if err != nil {
+			return err
+		}
+
+		corrected.WorkedHours = worked
+		corrected.TargetHours = target
+		corrected.Overtime = models.NewQuotaAmount(worked - target)
+		// What was credited for the month before does not count against the balance cap.
+		corrected.Credited = capOvertime(policy, corrected.Overtime, account.Count-settlement.Credited)
+		corrected.Forfeited = corrected.Overtime - corrected.Credited
+		if difference := corrected.Credited - settlement.Credited; difference != 0 {
+			transaction, err := quota.NewQuotaLedgerWithUnitOfWork(uow).Book(nil, user.ID, policy.QuotaID, models.QUOTA_TRANSACTION_OVERTIME, difference,
+				"overtime correction of "+month.Format("2006-01"), now)
+			if err != nil {
+				return err
+			}
+			corrected.UserQuotaTransactionID = &transaction.ID
+		}
+
+		// Correcting also marks the month as checked, even if the overtime stayed the same.
+		err = uow.OvertimeSettlements().Correct(&corrected, settlement.Overtime, settlement.Credited)
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return errAlreadySettled
+		}
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	if corrected.Overtime == settlement.Overtime && corrected.Credited == settlement.Credited {
+		return nil, nil
+	}
+	return &corrected, nil
+}
+
+// capOvertime returns the part of a month's overtime that is credited to an account holding `balance`.
+func capOvertime(policy *models.OvertimePolicy, overtime, balance models.QuotaAmount) models.QuotaAmount {
+	if overtime <= 0 {
+		return overtime
+	}
+	credited := overtime
+	if policy.MonthlyCap > 0 {
+		credited = min(credited, policy.MonthlyCap)
+	}
+	if policy.BalanceCap > 0 {
+		credited = max(min(credited, policy.BalanceCap-balance), 0)
+	}
+	return credited
+}
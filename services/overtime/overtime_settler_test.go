@@ -0,0 +1,179 @@
+package overtime_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/r-52/embrace/models"
+	dto "github.com/r-52/embrace/models/dto/overtime"
+	"github.com/r-52/embrace/repositories"
+	"github.com/r-52/embrace/services/overtime"
+	"github.com/r-52/embrace/services/quota"
+)
+
+// workMonth books `hours` of work on every weekday of the month starting at `month`, except on the given days.
+func (f *fixture) workMonth(t *testing.T, month time.Time, hours float64, except ...int) {
+	for day := month; day.Month() == month.Month(); day = day.AddDate(0, 0, 1) {
+		skip := day.Weekday() == time.Saturday || day.Weekday() == time.Sunday
+		for _, d := range except {
+			skip = skip || day.Day() == d
+		}
+		if !skip {
+			f.book(t, f.work, day.Add(8*time.Hour), hours)
+		}
+	}
+}
+
+func TestOvertimeSettler_SettleDue(t *testing.T) {
+	f := setupFixture(t)
+	march := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	april := march.AddDate(0, 1, 0)
+	policy := f.openAccount(t, &dto.OvertimePolicyRequest{MonthlyCap: models.QuotaUnits(10), BalanceCap: models.QuotaUnits(11)}, march)
+	settler := overtime.NewOvertimeSettler(f.db)
+
+	// 21 days of 9 hours in March are 21 hours of overtime, capped at 10 for the month.
+	f.workMonth(t, march, 9)
+	settlements, err := settler.SettleDue(april.Add(time.Hour))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(settlements) != 1 || settlements[0].WorkedHours != 189 || settlements[0].TargetHours != 168 ||
+		settlements[0].Credited != models.QuotaUnits(10) || settlements[0].Forfeited != models.QuotaUnits(11) {
+		t.Fatalf("expected 10 hours credited and 11 forfeited, got %+v", settlements)
+	}
+
+	// Time off in lieu takes from the account right away and counts as worked time in the month it is taken.
+	f.book(t, f.timeOffInLieu, april.Add(8*time.Hour), 8)
+	if balance := f.balance(t, policy); balance != 2 {
+		t.Errorf("expected 2 hours left after time off in lieu, got %v", balance)
+	}
+	f.workMonth(t, april, 9, 1)
+	settlements, err = settler.SettleDue(april.AddDate(0, 1, 1))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(settlements) != 1 || settlements[0].Overtime != models.QuotaUnits(21) || settlements[0].Credited != models.QuotaUnits(9) {
+		t.Fatalf("expected 9 of 21 hours credited up to the balance cap, got %+v", settlements)
+	}
+	if balance := f.balance(t, policy); balance != 11 {
+		t.Errorf("expected a balance of 11 hours, got %v", balance)
+	}
+
+	// Settled months are not settled again, and the ledger records every booking.
+	if settlements, err := settler.SettleDue(april.AddDate(0, 1, 2)); err != nil || len(settlements) != 0 {
+		t.Errorf("expected nothing to settle, got %+v, %v", settlements, err)
+	}
+	account, err := repositories.NewUserQuotaRepository(f.db).GetByUserIDAndQuotaID(f.employee.ID, policy.QuotaID)
+	if err != nil {
+		t.Fatalf("failed to load overtime account: %v", err)
+	}
+	transactions, err := repositories.NewUserQuotaTransactionRepository(f.db).GetByUserQuotaID(account.ID, time.Time{}, time.Time{})
+	if err != nil {
+		t.Fatalf("failed to load quota transactions: %v", err)
+	}
+	kinds := map[string]int{}
+	for _, transaction := range transactions {
+		kinds[transaction.Kind]++
+	}
+	if len(transactions) != 3 || kinds[models.QUOTA_TRANSACTION_CONSUMPTION] != 1 || kinds[models.QUOTA_TRANSACTION_OVERTIME] != 2 {
+		t.Errorf("expected the time off in lieu and two overtime bookings, got %v", kinds)
+	}
+
+	response, err := f.service().Overtime(f.employee, f.employee.ID, &dto.OvertimeRequest{From: april, To: april}, april.AddDate(0, 1, 2))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if response.Account == nil || response.Account.SettledUntil != "2024-04-30" || response.Account.Balance != models.QuotaUnits(11) {
+		t.Errorf("expected the account to be settled until April 30, got %+v", response.Account)
+	}
+}
+
+func TestOvertimeSettler_SettleDue_Corrects_Settled_Months(t *testing.T) {
+	f := setupFixture(t)
+	march := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	april := march.AddDate(0, 1, 0)
+	policy := f.openAccount(t, &dto.OvertimePolicyRequest{}, march)
+	settler := overtime.NewOvertimeSettler(f.db)
+
+	// An entry that ends when it starts adds nothing.
+	f.workMonth(t, march, 8)
+	f.book(t, f.work, march.AddDate(0, 0, 3).Add(18*time.Hour), 0)
+	settlements, err := settler.SettleDue(april.Add(time.Hour))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(settlements) != 1 || settlements[0].WorkedHours != 168 || settlements[0].Overtime != 0 {
+		t.Fatalf("expected March to be settled without overtime, got %+v", settlements)
+	}
+
+	// Work booked on a Saturday after the month was settled is credited on the next run.
+	f.book(t, f.work, march.AddDate(0, 0, 1).Add(8*time.Hour), 5)
+	settlements, err = settler.SettleDue(april.Add(2 * time.Hour))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(settlements) != 1 || settlements[0].WorkedHours != 173 || settlements[0].Credited != models.QuotaUnits(5) {
+		t.Fatalf("expected 5 hours to be credited for March, got %+v", settlements)
+	}
+	if balance := f.balance(t, policy); balance != 5 {
+		t.Errorf("expected a balance of 5 hours, got %v", balance)
+	}
+
+	// Deleting a working day debits it.
+	entries, err := repositories.NewTimeEntryRepository(f.db).GetByUserIDAndDateRange(f.employee.ID, march.AddDate(0, 0, 4), march.AddDate(0, 0, 5))
+	if err != nil || len(entries) != 1 {
+		t.Fatalf("failed to load the entry of March 5: %v", err)
+	}
+	if err := repositories.NewTimeEntryRepository(f.db).Delete(entries[0].ID); err != nil {
+		t.Fatalf("failed to delete entry: %v", err)
+	}
+	settlements, err = settler.SettleDue(april.Add(3 * time.Hour))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(settlements) != 1 || settlements[0].Overtime != models.QuotaUnits(-3) || settlements[0].Credited != models.QuotaUnits(-3) {
+		t.Fatalf("expected March to end 3 hours short, got %+v", settlements)
+	}
+	if balance := f.balance(t, policy); balance != -3 {
+		t.Errorf("expected a balance of -3 hours, got %v", balance)
+	}
+
+	// Unchanged months are left alone.
+	if settlements, err := settler.SettleDue(april.Add(4 * time.Hour)); err != nil || len(settlements) != 0 {
+		t.Errorf("expected nothing to correct, got %+v, %v", settlements, err)
+	}
+	history, err := repositories.NewOvertimeSettlementRepository(f.db).GetByUserID(f.employee.ID)
+	if err != nil || len(history) != 1 || history[0].Credited != models.QuotaUnits(-3) || history[0].UserQuotaTransactionID == nil {
+		t.Errorf("expected a single corrected settlement, got %+v, %v", history, err)
+	}
+}
+
+func TestOvertimeService_Payout(t *testing.T) {
+	f := setupFixture(t)
+	service := f.service()
+	payout := &dto.PayoutRequest{Hours: models.QuotaUnits(5), Reason: "paid with the May salary"}
+
+	if _, err := service.Payout(f.admin, f.employee.ID, payout, monday); !errors.Is(err, overtime.ErrOvertimeNotConfigured) {
+		t.Errorf("expected ErrOvertimeNotConfigured, got %v", err)
+	}
+	policy := f.openAccount(t, &dto.OvertimePolicyRequest{}, monday)
+	if _, err := service.Payout(f.admin, f.colleague.ID, payout, monday); !errors.Is(err, quota.ErrQuotaNotAssigned) {
+		t.Errorf("expected ErrQuotaNotAssigned, got %v", err)
+	}
+	if _, err := service.Payout(f.admin, f.employee.ID, payout, monday); !errors.Is(err, overtime.ErrPayoutExceedsBalance) {
+		t.Errorf("expected ErrPayoutExceedsBalance, got %v", err)
+	}
+
+	if _, err := quota.NewQuotaLedger(repositories.WithTenant(f.db, f.companyID)).Adjust(f.admin, f.employee.ID, policy.QuotaID, models.QuotaUnits(8), "migrated", monday); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	transaction, err := service.Payout(f.admin, f.employee.ID, payout, monday)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if transaction.Kind != models.QUOTA_TRANSACTION_PAYOUT || transaction.Amount != models.QuotaUnits(-5) || transaction.Balance != models.QuotaUnits(3) ||
+		*transaction.ActorID != f.admin.ID {
+		t.Errorf("expected a payout of 5 hours leaving 3, got %+v", transaction)
+	}
+}
@@ -0,0 +1,53 @@
+package overtime
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// SettlementScheduler runs the OvertimeSettler in the background of the server process.
+// It settles due months when it starts, so months that ended while the server was down
+// are caught up, and then once every interval.
+type SettlementScheduler struct {
+	settler  *OvertimeSettler
+	interval time.Duration
+}
+
+// NewSettlementScheduler creates a SettlementScheduler. The database must not be scoped to a tenant.
+func NewSettlementScheduler(db *gorm.DB, interval time.Duration) *SettlementScheduler {
+	return &SettlementScheduler{
+		settler:  NewOvertimeSettler(db),
+		interval: interval,
+	}
+}
+
+// Start runs the scheduler until the context is cancelled. It returns immediately.
+func (s *SettlementScheduler) Start(ctx context.Context) {
+	go func() {
+		ticker := time.NewTicker(s.interval)
+		defer ticker.Stop()
+
+		s.tick(time.Now())
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case now := <-ticker.C:
+				s.tick(now)
+			}
+		}
+	}()
+}
+
+func (s *SettlementScheduler) tick(now time.Time) {
+	settlements, err := s.settler.SettleDue(now)
+	for _, settlement := range settlements {
+		log.Printf("overtime of user %d for %s settled, %s credited", settlement.UserID, settlement.Month.Format("2006-01"), settlement.Credited)
+	}
+	if err != nil {
+		log.Printf("overtime settlement failed: %v", err)
+	}
+}
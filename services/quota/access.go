@@ -0,0 +1,15 @@
+package quota
+
+import (
+	"github.com/r-52/embrace/models"
+	"gorm.io/gorm"
+)
+
+// authorizeRead checks that the actor may read the quotas of the owner: their own, those of a user they manage,
+// or anyone's. Quotas the actor may not read are reported as gorm.ErrRecordNotFound, so callers cannot probe for users.
+func authorizeRead(actor *models.User, owner *models.User) error {
+	if !actor.CanAccess(owner, models.PERMISSION_QUOTAS_READ) && !actor.Role.HasPermission(models.PERMISSION_QUOTAS_MANAGE) {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
@@ -0,0 +1,72 @@
+package quota
+
+import (
+	"time"
+
+	"github.com/r-52/embrace/models"
+	"github.com/r-52/embrace/repositories"
+)
+
+// Carried over quota only covers bookings before it expires. Bookings use it up before the
+// rest of the balance, the buckets expiring first first, and refunds go back in reverse order.
+
+// usableAt reports whether a carry-over bucket covers a booking that starts at `at`.
+func usableAt(carryOver *models.UserQuotaCarryOver, at time.Time) bool {
+	return !carryOver.ExpiresAt.Valid || at.Before(carryOver.ExpiresAt.Time)
+}
+
+// consumeCarryOvers takes up to `amount` from the carry-over buckets of a UserQuota that cover a booking at `at`.
+func consumeCarryOvers(uow *repositories.UnitOfWork, userQuotaID uint, amount models.QuotaAmount, at time.Time) error {
+	carryOvers, err := uow.UserQuotaCarryOvers().GetOpenByUserQuotaID(userQuotaID)
+	if err != nil {
+		return err
+	}
+	for i := 0; i < len(carryOvers) && amount > 0; i++ {
+		carryOver := &carryOvers[i]
+		if carryOver.Remaining <= 0 || !usableAt(carryOver, at) {
+			continue
+		}
+		taken := min(amount, carryOver.Remaining)
+		if err := uow.UserQuotaCarryOvers().SetRemaining(carryOver.ID, carryOver.Remaining-taken); err != nil {
+			return err
+		}
+		amount -= taken
+	}
+	return nil
+}
+
+// refundCarryOvers gives up to `amount` back to the used carry-over buckets of a UserQuota that cover a booking at `at`.
+func refundCarryOvers(uow *repositories.UnitOfWork, userQuotaID uint, amount models.QuotaAmount, at time.Time) error {
+	carryOvers, err := uow.UserQuotaCarryOvers().GetOpenByUserQuotaID(userQuotaID)
+	if err != nil {
+		return err
+	}
+	for i := len(carryOvers) - 1; i >= 0 && amount > 0; i-- {
+		carryOver := &carryOvers[i]
+		if carryOver.Remaining >= carryOver.Carried || !usableAt(carryOver, at) {
+			continue
+		}
+		given := min(amount, carryOver.Carried-carryOver.Remaining)
+		if err := uow.UserQuotaCarryOvers().SetRemaining(carryOver.ID, carryOver.Remaining+given); err != nil {
+			return err
+		}
+		amount -= given
+	}
+	return nil
+}
+
+// unusableCarryOver returns the carried over quota of a UserQuota that is still part of its balance
+// but has expired by `at`, because the scheduler did not remove it yet.
+func unusableCarryOver(uow *repositories.UnitOfWork, userQuotaID uint, at time.Time) (models.QuotaAmount, error) {
+	carryOvers, err := uow.UserQuotaCarryOvers().GetOpenByUserQuotaID(userQuotaID)
+	if err != nil {
+		return 0, err
+	}
+	var unusable models.QuotaAmount
+	for i := range carryOvers {
+		if !usableAt(&carryOvers[i], at) {
+			unusable += carryOvers[i].Remaining
+		}
+	}
+	return unusable, nil
+}
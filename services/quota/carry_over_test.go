@@ -0,0 +1,206 @@
+package quota_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/r-52/embrace/models"
+	"github.com/r-52/embrace/repositories"
+	"github.com/r-52/embrace/services/quota"
+)
+
+var newYear = time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+// carryOverFixture resets a vacation quota of `count` days per year into 2025, with a balance of
+// `leftover` days at the end of 2024 and up to five days carried over until March 31.
+func carryOverFixture(t *testing.T, count, leftover int) *fixture {
+	f := setupFixture(t, leftover)
+	q := f.quota(t)
+	backdate(t, f.db, q, time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	f.db.Model(q).Updates(map[string]interface{}{"count": models.QuotaUnits(count), "carry_over_max": models.QuotaUnits(5), "carry_over_expiry": "03-31"})
+
+	if _, err := quota.NewQuotaResetter(f.db).ResetDue(newYear); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	return f
+}
+
+func (f *fixture) carryOver(t *testing.T) *models.UserQuotaCarryOver {
+	carryOvers, err := repositories.NewUserQuotaCarryOverRepository(f.db).GetByUserQuotaID(f.userQuota.ID)
+	if err != nil {
+		t.Fatalf("failed to load carry-overs: %v", err)
+	}
+	if len(carryOvers) != 1 {
+		t.Fatalf("expected 1 carry-over, got %v", len(carryOvers))
+	}
+	return &carryOvers[0]
+}
+
+func TestQuotaResetter_ResetDue_Carries_Over_Leftover(t *testing.T) {
+	f := carryOverFixture(t, 30, 8)
+
+	if balance := f.balance(t); balance != 35 {
+		t.Errorf("expected balance 35 with 5 days carried over, got %v", balance)
+	}
+	carryOver := f.carryOver(t)
+	if carryOver.Carried.Units() != 5 || carryOver.Remaining.Units() != 5 {
+		t.Errorf("expected 5 days carried over, got %+v", carryOver)
+	}
+	if expected := time.Date(2025, 4, 1, 0, 0, 0, 0, time.UTC); !carryOver.ExpiresAt.Valid || !carryOver.ExpiresAt.Time.Equal(expected) {
+		t.Errorf("expected the carry-over to expire at %v, got %v", expected, carryOver.ExpiresAt)
+	}
+
+	resets, _ := repositories.NewQuotaResetRepository(f.db).GetByQuotaID(f.userQuota.QuotaID)
+	if len(resets) != 1 || resets[0].Entries[0].PreviousCount.Units() != 8 || resets[0].Entries[0].Carried.Units() != 5 {
+		t.Errorf("expected the carry-over to be recorded, got %v", resets)
+	}
+	transactions := f.transactions(t)
+	expected := []struct {
+		kind            string
+		amount, balance float64
+	}{
+		{models.QUOTA_TRANSACTION_RESET, -8, 0},
+		{models.QUOTA_TRANSACTION_ALLOCATION, 30, 30},
+		{models.QUOTA_TRANSACTION_CARRY_OVER, 5, 35},
+	}
+	if len(transactions) != len(expected) {
+		t.Fatalf("expected %d transactions, got %+v", len(expected), transactions)
+	}
+	for i, transaction := range transactions {
+		if transaction.Kind != expected[i].kind || transaction.Amount.Units() != expected[i].amount || transaction.Balance.Units() != expected[i].balance ||
+			*transaction.QuotaResetID != resets[0].ID || transaction.ActorID != nil {
+			t.Errorf("transaction %d: expected %s of %v leaving %v, got %+v", i, expected[i].kind, expected[i].amount, expected[i].balance, transaction)
+		}
+	}
+
+	// The next reset absorbs what is left of the carry-over.
+	if _, err := quota.NewQuotaResetter(f.db).ResetDue(newYear.AddDate(1, 0, 0)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	carryOvers, _ := repositories.NewUserQuotaCarryOverRepository(f.db).GetOpenByUserQuotaID(f.userQuota.ID)
+	if len(carryOvers) != 1 || carryOvers[0].Carried.Units() != 5 || carryOvers[0].QuotaResetID == carryOver.QuotaResetID {
+		t.Errorf("expected only the new carry-over to be open, got %v", carryOvers)
+	}
+}
+
+func TestQuotaResetter_ResetDue_Without_Carry_Over(t *testing.T) {
+	f := setupFixture(t, 8)
+	backdate(t, f.db, f.quota(t), time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+
+	if _, err := quota.NewQuotaResetter(f.db).ResetDue(newYear); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if balance := f.balance(t); balance != 30 {
+		t.Errorf("expected balance 30, got %v", balance)
+	}
+	carryOvers, _ := repositories.NewUserQuotaCarryOverRepository(f.db).GetByUserQuotaID(f.userQuota.ID)
+	if len(carryOvers) != 0 {
+		t.Errorf("expected no carry-over, got %v", carryOvers)
+	}
+}
+
+func TestQuotaLedger_Apply_Uses_Carry_Over_First(t *testing.T) {
+	f := carryOverFixture(t, 30, 8)
+	ledger := quota.NewQuotaLedger(repositories.WithTenant(f.db, f.company.ID))
+
+	february := f.entry(f.vacation, time.Date(2025, 2, 3, 8, 0, 0, 0, time.UTC), 56)
+	if err := ledger.Apply(f.user, nil, february); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if balance, remaining := f.balance(t), f.carryOver(t).Remaining.Units(); balance != 32 || remaining != 2 {
+		t.Errorf("expected balance 32 with 2 days carried over left, got %v and %v", balance, remaining)
+	}
+
+	// Carried over days do not cover bookings after they expire.
+	april := f.entry(f.vacation, time.Date(2025, 4, 7, 8, 0, 0, 0, time.UTC), 8)
+	if err := ledger.Apply(f.user, nil, april); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if balance, remaining := f.balance(t), f.carryOver(t).Remaining.Units(); balance != 31 || remaining != 2 {
+		t.Errorf("expected balance 31 with 2 days carried over left, got %v and %v", balance, remaining)
+	}
+
+	if err := ledger.Apply(f.user, february, nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if balance, remaining := f.balance(t), f.carryOver(t).Remaining.Units(); balance != 34 || remaining != 5 {
+		t.Errorf("expected balance 34 with 5 days carried over left, got %v and %v", balance, remaining)
+	}
+}
+
+func TestQuotaLedger_Apply_Rejects_Expired_Carry_Over(t *testing.T) {
+	f := carryOverFixture(t, 2, 8)
+	uow := repositories.NewUnitOfWork(repositories.WithTenant(f.db, f.company.ID))
+	apply := func(entry *models.TimeEntry) error {
+		return uow.Transaction(func(uow *repositories.UnitOfWork) error {
+			return quota.NewQuotaLedgerWithUnitOfWork(uow).Apply(f.user, nil, entry)
+		})
+	}
+
+	// Of the balance of 7 days, the 5 carried over ones expire before April.
+	err := apply(f.entry(f.vacation, time.Date(2025, 4, 7, 8, 0, 0, 0, time.UTC), 56))
+	if !errors.Is(err, quota.ErrQuotaExceeded) {
+		t.Errorf("expected ErrQuotaExceeded, got %v", err)
+	}
+	if balance := f.balance(t); balance != 7 {
+		t.Errorf("expected the balance to stay at 7, got %v", balance)
+	}
+
+	if err := apply(f.entry(f.vacation, time.Date(2025, 4, 7, 8, 0, 0, 0, time.UTC), 32)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if balance := f.balance(t); balance != 5 {
+		t.Errorf("expected balance 5, got %v", balance)
+	}
+}
+
+func TestQuotaResetter_ExpireDue(t *testing.T) {
+	f := carryOverFixture(t, 30, 8)
+	ledger := quota.NewQuotaLedger(repositories.WithTenant(f.db, f.company.ID))
+	if err := ledger.Apply(f.user, nil, f.entry(f.vacation, time.Date(2025, 2, 3, 8, 0, 0, 0, time.UTC), 8)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	resetter := quota.NewQuotaResetter(f.db)
+	expired, err := resetter.ExpireDue(time.Date(2025, 3, 31, 23, 0, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(expired) != 0 {
+		t.Errorf("expected nothing to expire before April, got %v", expired)
+	}
+
+	expired, err = resetter.ExpireDue(time.Date(2025, 4, 1, 0, 0, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(expired) != 1 || expired[0].Expired.Units() != 4 {
+		t.Fatalf("expected 4 days to expire, got %v", expired)
+	}
+	if balance := f.balance(t); balance != 30 {
+		t.Errorf("expected balance 30, got %v", balance)
+	}
+	carryOver := f.carryOver(t)
+	if carryOver.IsOpen() || carryOver.Remaining.Units() != 0 || carryOver.Expired.Units() != 4 {
+		t.Errorf("expected the carry-over to be closed, got %+v", carryOver)
+	}
+	var expiries []models.UserQuotaTransaction
+	for _, transaction := range f.transactions(t) {
+		if transaction.Kind == models.QUOTA_TRANSACTION_EXPIRY {
+			expiries = append(expiries, transaction)
+		}
+	}
+	if len(expiries) != 1 || expiries[0].Amount.Units() != -4 || expiries[0].Balance.Units() != 30 || *expiries[0].UserQuotaCarryOverID != carryOver.ID {
+		t.Errorf("expected the expiry to be recorded, got %+v", expiries)
+	}
+
+	// A restarted scheduler does not expire the carry-over twice.
+	expired, err = resetter.ExpireDue(time.Date(2025, 4, 2, 0, 0, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(expired) != 0 || f.balance(t) != 30 {
+		t.Errorf("expected nothing to expire again, got %v and balance %v", expired, f.balance(t))
+	}
+}
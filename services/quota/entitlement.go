@@ -0,0 +1,106 @@
+package quota
+
+import (
+	"math"
+	"time"
+
+	"github.com/r-52/embrace/models"
+)
+
+// Entitlement returns the quota a user is entitled to in the period of `quota` that contains `now`:
+// Quota.Count pro-rated to the part of the period the user is employed in and scaled by their share
+// of full-time working days. A pro-rated entitlement is rounded to whole units of the quota as the company configured.
+// Periods are pro-rated by month. Every month weighs the same and partial months count by their days,
+// so someone joining on July 1 gets half of a yearly quota. Weekly periods are pro-rated by day.
+// Quotas that are never reset are only scaled.
+func Entitlement(quota *models.Quota, company *models.Company, user *models.User, now time.Time) models.QuotaAmount {
+	share := workingDaysShare(company, user)
+	if periodStart, ok := quota.PeriodStart(now.In(company.Location())); ok {
+		share *= employedShare(quota, user, periodStart)
+	}
+	if share == 1 {
+		return quota.Count
+	}
+	return models.QuotaUnits(round(quota.Count.Units()*share, company.QuotaRounding))
+}
+
+// workingDaysShare returns the share of a full-time week the user works.
+func workingDaysShare(company *models.Company, user *models.User) float64 {
+	fullTime := company.FullTimeWorkingDays
+	if fullTime <= 0 {
+		fullTime = 5
+	}
+	if user.WorkingDaysPerWeek <= 0 {
+		return 1
+	}
+	return float64(user.WorkingDaysPerWeek) / float64(fullTime)
+}
+
+// employedShare returns the share of the period starting at periodStart in which the user is employed.
+func employedShare(quota *models.Quota, user *models.User, periodStart time.Time) float64 {
+	start := calendarDay(periodStart)
+	end := calendarDay(quota.NextPeriodStart(periodStart))
+	from, to := start, end
+	if user.EmploymentStart.Valid && calendarDay(user.EmploymentStart.Time).After(from) {
+		from = calendarDay(user.EmploymentStart.Time)
+	}
+	if user.EmploymentEnd.Valid {
+		if last := calendarDay(user.EmploymentEnd.Time).AddDate(0, 0, 1); last.Before(to) {
+			to = last
+		}
+	}
+	if !from.Before(to) {
+		return 0
+	}
+
+	if quota.QuotaResetAt == models.QUOTA_RESET_FIRST_OF_WEEK {
+		return days(from, to) / days(start, end)
+	}
+	share := 0.0
+	months := 0
+	for month := start; month.Before(end); month = month.AddDate(0, 1, 0) {
+		next := month.AddDate(0, 1, 0)
+		if covered := days(latest(from, month), earliest(to, next)); covered > 0 {
+			share += covered / days(month, next)
+		}
+		months++
+	}
+	return share / float64(months)
+}
+
+// round rounds an entitlement as described by one of the models.QUOTA_ROUNDING constants.
+func round(value float64, rounding string) int {
+	// Shares like 3/5 are not exact, so the value is cut to a precision far below a day first.
+	value = math.Round(value*1e6) / 1e6
+	switch rounding {
+	case models.QUOTA_ROUNDING_UP:
+		return int(math.Ceil(value))
+	case models.QUOTA_ROUNDING_DOWN:
+		return int(math.Floor(value))
+	default:
+		return int(math.Round(value))
+	}
+}
+
+// calendarDay returns the calendar day of t as midnight UTC, so days can be counted without daylight saving gaps.
+func calendarDay(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+func days(from, to time.Time) float64 {
+	return to.Sub(from).Hours() / 24
+}
+
+func latest(a, b time.Time) time.Time {
+	if a.After(b) {
+		return a
+	}
+	return b
+}
+
+func earliest(a, b time.Time) time.Time {
+	if a.Before(b) {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,53 @@
+package quota_test
+
+import (
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/r-52/embrace/models"
+	"github.com/r-52/embrace/services/quota"
+)
+
+func day(year int, month time.Month, d int) sql.NullTime {
+	return sql.NullTime{Time: time.Date(year, month, d, 0, 0, 0, 0, time.UTC), Valid: true}
+}
+
+func TestEntitlement(t *testing.T) {
+	now := time.Date(2024, 9, 1, 12, 0, 0, 0, time.UTC)
+	yearly := &models.Quota{Count: models.QuotaUnits(30), QuotaResetAt: models.QUOTA_RESET_FIRST_OF_YEAR}
+	monthly := &models.Quota{Count: models.QuotaUnits(4), QuotaResetAt: models.QUOTA_RESET_FIRST_OF_MONTH}
+	weekly := &models.Quota{Count: models.QuotaUnits(7), QuotaResetAt: models.QUOTA_RESET_FIRST_OF_WEEK}
+	never := &models.Quota{Count: models.QuotaUnits(10)}
+
+	tests := []struct {
+		name     string
+		quota    *models.Quota
+		user     models.User
+		rounding string
+		expected float64
+	}{
+		{name: "full year", quota: yearly, user: models.User{WorkingDaysPerWeek: 5}, expected: 30},
+		{name: "joined before the period", quota: yearly, user: models.User{WorkingDaysPerWeek: 5, EmploymentStart: day(2019, 5, 1)}, expected: 30},
+		{name: "joined on July 1", quota: yearly, user: models.User{WorkingDaysPerWeek: 5, EmploymentStart: day(2024, 7, 1)}, expected: 15},
+		{name: "joined mid July", quota: yearly, user: models.User{WorkingDaysPerWeek: 5, EmploymentStart: day(2024, 7, 15)}, expected: 14},
+		{name: "part-time", quota: yearly, user: models.User{WorkingDaysPerWeek: 3}, expected: 18},
+		{name: "part-time joiner", quota: yearly, user: models.User{WorkingDaysPerWeek: 3, EmploymentStart: day(2024, 7, 1)}, expected: 9},
+		{name: "leaver rounded to nearest", quota: yearly, user: models.User{WorkingDaysPerWeek: 5, EmploymentEnd: day(2024, 3, 31)}, expected: 8},
+		{name: "leaver rounded down", quota: yearly, user: models.User{WorkingDaysPerWeek: 5, EmploymentEnd: day(2024, 3, 31)}, rounding: models.QUOTA_ROUNDING_DOWN, expected: 7},
+		{name: "part-time rounded up", quota: yearly, user: models.User{WorkingDaysPerWeek: 4, EmploymentEnd: day(2024, 3, 31)}, rounding: models.QUOTA_ROUNDING_UP, expected: 6},
+		{name: "left before the period", quota: yearly, user: models.User{WorkingDaysPerWeek: 5, EmploymentEnd: day(2023, 12, 31)}, expected: 0},
+		{name: "joins after the period", quota: yearly, user: models.User{WorkingDaysPerWeek: 5, EmploymentStart: day(2025, 1, 1)}, expected: 0},
+		{name: "half a month", quota: monthly, user: models.User{WorkingDaysPerWeek: 5, EmploymentStart: day(2024, 9, 16)}, expected: 2},
+		{name: "part of a week", quota: weekly, user: models.User{WorkingDaysPerWeek: 5, EmploymentStart: day(2024, 8, 29)}, expected: 4},
+		{name: "never reset", quota: never, user: models.User{WorkingDaysPerWeek: 4, EmploymentStart: day(2024, 7, 1)}, expected: 8},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			company := &models.Company{FullTimeWorkingDays: 5, QuotaRounding: tt.rounding}
+			if got := quota.Entitlement(tt.quota, company, &tt.user, now); got.Units() != tt.expected {
+				t.Errorf("expected %v, got %v", tt.expected, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,15 @@
+package quota
+
+import "errors"
+
+// ErrQuotaExceeded is returned when a booking needs more quota than the user has left and the company does not allow negative balances.
+var ErrQuotaExceeded = errors.New("E5000")
+
+// ErrUnknownQuota is returned when a quota relevant time entry type refers to a quota that does not exist in the company.
+var ErrUnknownQuota = errors.New("E5001")
+
+// ErrQuotaNotAssigned is returned when a user books on a quota that was not assigned to them.
+var ErrQuotaNotAssigned = errors.New("E5002")
+
+// ErrQuotaAlreadyAssigned is returned when a quota is assigned to a user who already has it.
+var ErrQuotaAlreadyAssigned = errors.New("E5003")
@@ -0,0 +1,149 @@
+package quota
+
+import (
+	"errors"
+	"time"
+
+	"github.com/r-52/embrace/models"
+	"github.com/r-52/embrace/repositories"
+	"gorm.io/gorm"
+)
+
+// QuotaAllocator allocates the entitlements of users to their quotas, see Entitlement.
+type QuotaAllocator struct {
+	unitOfWork *repositories.UnitOfWork
+}
+
+type QuotaAllocatorInterface interface {
+	Assign(actor *models.User, userID, quotaID uint, now time.Time) (*models.UserQuota, error)
+	Recalculate(actor *models.User, userID uint, now time.Time) ([]models.UserQuota, error)
+}
+
+// NewQuotaAllocator creates a QuotaAllocator. The database should be scoped to the company of the users, see repositories.WithTenant.
+func NewQuotaAllocator(db *gorm.DB) *QuotaAllocator {
+	return NewQuotaAllocatorWithUnitOfWork(repositories.NewUnitOfWork(db))
+}
+
+// NewQuotaAllocatorWithUnitOfWork creates a QuotaAllocator whose repositories join the given unit of work.
+func NewQuotaAllocatorWithUnitOfWork(uow *repositories.UnitOfWork) *QuotaAllocator {
+	return &QuotaAllocator{
+		unitOfWork: uow,
+	}
+}
+
+// Assign assigns a quota to a user, starting with the entitlement for the current period,
+// which is recorded as an allocation by the actor. It fails with ErrUnknownQuota if the quota does not exist in the user's company
+// and with ErrQuotaAlreadyAssigned if the user has the quota already. The balance and its allocation are stored in one transaction.
+func (a *QuotaAllocator) Assign(actor *models.User, userID, quotaID uint, now time.Time) (*models.UserQuota, error) {
+	var assigned *models.UserQuota
+	err := a.unitOfWork.Transaction(func(uow *repositories.UnitOfWork) error {
+		user, company, err := employment(uow, userID)
+		if err != nil {
+			return err
+		}
+		quota, err := uow.Quotas().GetByID(quotaID)
+		if errors.Is(err, gorm.ErrRecordNotFound) || (err == nil && quota.CompanyID != company.ID) {
+			return ErrUnknownQuota
+		}
+		if err != nil {
+			return err
+		}
+
+		_, err = uow.UserQuotas().GetByUserIDAndQuotaID(userID, quotaID)
+		if err == nil {
+			return ErrQuotaAlreadyAssigned
+		}
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			return err
+		}
+
+		entitlement := Entitlement(quota, company, user, now)
+		userQuota := &models.UserQuota{
+			UserID:      userID,
+			QuotaID:     quotaID,
+			Count:       entitlement,
+			Entitlement: entitlement,
+		}
+		err = uow.UserQuotas().Create(userQuota)
+		if errors.Is(err, gorm.ErrDuplicatedKey) {
+			return ErrQuotaAlreadyAssigned
+		}
+		if err != nil {
+			return err
+		}
+		if entitlement != 0 {
+			transaction := newTransaction(models.QUOTA_TRANSACTION_ALLOCATION, userQuota, company.ID, entitlement, actor, now)
+			transaction.Reason = "quota assigned"
+			if err := record(uow, transaction); err != nil {
+				return err
+			}
+		}
+		userQuota.Quota = *quota
+		assigned = userQuota
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return assigned, nil
+}
+
+// Recalculate recalculates the entitlements of a user for the current periods of their quotas, after their
+// employment data changed. The difference to the previous entitlement is added to the balance,
+// so what the user booked stays consumed, and recorded as an allocation by the actor in the same transaction.
+// It returns the updated quotas of the user.
+func (a *QuotaAllocator) Recalculate(actor *models.User, userID uint, now time.Time) ([]models.UserQuota, error) {
+	var userQuotas []models.UserQuota
+	err := a.unitOfWork.Transaction(func(uow *repositories.UnitOfWork) error {
+		user, company, err := employment(uow, userID)
+		if err != nil {
+			return err
+		}
+		userQuotas, err = uow.UserQuotas().GetByUserID(userID)
+		if err != nil {
+			return err
+		}
+
+		for i := range userQuotas {
+			quota, err := uow.Quotas().GetByID(userQuotas[i].QuotaID)
+			if err != nil {
+				return err
+			}
+			entitlement := Entitlement(quota, company, user, now)
+			if entitlement != userQuotas[i].Entitlement {
+				if err := uow.UserQuotas().Reallocate(userQuotas[i].ID, entitlement); err != nil {
+					return err
+				}
+				transaction := newTransaction(models.QUOTA_TRANSACTION_ALLOCATION, &userQuotas[i], company.ID, entitlement-userQuotas[i].Entitlement, actor, now)
+				transaction.Reason = "entitlement recalculated"
+				if err := record(uow, transaction); err != nil {
+					return err
+				}
+				reallocated, err := uow.UserQuotas().GetByID(userQuotas[i].ID)
+				if err != nil {
+					return err
+				}
+				userQuotas[i] = *reallocated
+			}
+			userQuotas[i].Quota = *quota
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return userQuotas, nil
+}
+
+// employment loads a user together with their company.
+func employment(uow *repositories.UnitOfWork, userID uint) (*models.User, *models.Company, error) {
+	user, err := uow.Users().GetByID(userID)
+	if err != nil {
+		return nil, nil, err
+	}
+	company, err := uow.Companies().GetByID(user.CompanyID)
+	if err != nil {
+		return nil, nil, err
+	}
+	return user, company, nil
+}
@@ -0,0 +1,84 @@
+package quota_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/r-52/embrace/models"
+	"github.com/r-52/embrace/repositories"
+	"github.com/r-52/embrace/services/quota"
+	"github.com/r-52/embrace/services/servicetest"
+)
+
+var september = time.Date(2024, 9, 1, 12, 0, 0, 0, time.UTC)
+
+func TestQuotaAllocator_Assign(t *testing.T) {
+	f := setupFixture(t, 30)
+	f.db.Model(f.user).Updates(map[string]interface{}{"employment_start": day(2024, 7, 1), "working_days_per_week": 3})
+	overtime := &models.Quota{Name: "overtime", CompanyID: f.company.ID, Count: models.QuotaUnits(20), QuotaResetAt: models.QUOTA_RESET_FIRST_OF_YEAR}
+	servicetest.MustCreate(t, f.db, overtime)
+	allocator := quota.NewQuotaAllocator(repositories.WithTenant(f.db, f.company.ID))
+
+	userQuota, err := allocator.Assign(f.user, f.user.ID, overtime.ID, september)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if userQuota.Count.Units() != 6 || userQuota.Entitlement.Units() != 6 || userQuota.Quota.Name != "overtime" {
+		t.Errorf("expected an entitlement of 6, got %+v", userQuota)
+	}
+	transactions, _ := repositories.NewUserQuotaTransactionRepository(f.db).GetByUserQuotaID(userQuota.ID, time.Time{}, time.Time{})
+	if len(transactions) != 1 || transactions[0].Kind != models.QUOTA_TRANSACTION_ALLOCATION || transactions[0].Amount.Units() != 6 || *transactions[0].ActorID != f.user.ID {
+		t.Errorf("expected the allocation to be recorded, got %+v", transactions)
+	}
+
+	if _, err := allocator.Assign(f.user, f.user.ID, overtime.ID, september); !errors.Is(err, quota.ErrQuotaAlreadyAssigned) {
+		t.Errorf("expected ErrQuotaAlreadyAssigned, got %v", err)
+	}
+	if _, err := allocator.Assign(f.user, f.user.ID, 999, september); !errors.Is(err, quota.ErrUnknownQuota) {
+		t.Errorf("expected ErrUnknownQuota, got %v", err)
+	}
+
+	foreignCompany := &models.Company{Name: "other", PrimaryEmail: "other@example.com"}
+	servicetest.MustCreate(t, f.db, foreignCompany)
+	foreign := &models.Quota{Name: "vacation", CompanyID: foreignCompany.ID, Count: models.QuotaUnits(30)}
+	servicetest.MustCreate(t, f.db, foreign)
+	if _, err := allocator.Assign(f.user, f.user.ID, foreign.ID, september); !errors.Is(err, quota.ErrUnknownQuota) {
+		t.Errorf("expected ErrUnknownQuota, got %v", err)
+	}
+}
+
+func TestQuotaAllocator_Recalculate_Keeps_Consumption(t *testing.T) {
+	f := setupFixture(t, 28)
+	f.db.Model(f.userQuota).Update("entitlement", models.QuotaUnits(30))
+	f.db.Model(f.user).Update("employment_start", day(2024, 7, 1))
+
+	userQuotas, err := quota.NewQuotaAllocator(repositories.WithTenant(f.db, f.company.ID)).Recalculate(f.user, f.user.ID, september)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(userQuotas) != 1 || userQuotas[0].Entitlement.Units() != 15 || userQuotas[0].Count.Units() != 13 {
+		t.Errorf("expected an entitlement of 15 with 2 days consumed, got %+v", userQuotas)
+	}
+	if balance := f.balance(t); balance != 13 {
+		t.Errorf("expected balance 13, got %v", balance)
+	}
+}
+
+func TestQuotaResetter_ResetDue_Allocates_Entitlement(t *testing.T) {
+	f := setupFixture(t, 0)
+	backdate(t, f.db, f.quota(t), time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	f.db.Model(f.user).Update("employment_end", day(2025, 6, 30))
+
+	resets, err := quota.NewQuotaResetter(f.db).ResetDue(newYear)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(resets) != 1 || resets[0].Entries[0].Count.Units() != 15 {
+		t.Fatalf("expected the leaver to be reset to 15, got %v", resets)
+	}
+	userQuota, _ := repositories.NewUserQuotaRepository(f.db).GetByID(f.userQuota.ID)
+	if userQuota.Count.Units() != 15 || userQuota.Entitlement.Units() != 15 {
+		t.Errorf("expected count and entitlement 15, got %v and %v", userQuota.Count, userQuota.Entitlement)
+	}
+}
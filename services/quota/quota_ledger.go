@@ -0,0 +1,237 @@
+package quota
+
+import (
+	"errors"
+	"math"
+	"time"
+
+	"github.com/r-52/embrace/models"
+	quotas "github.com/r-52/embrace/models/dto/quota"
+	"github.com/r-52/embrace/repositories"
+	"gorm.io/gorm"
+)
+
+// QuotaLedger keeps the UserQuota balances in line with the time entries booked on quota relevant types.
+// It has to run in the same unit of work as the change of the entries, so both commit or roll back together.
+type QuotaLedger struct {
+	unitOfWork *repositories.UnitOfWork
+}
+
+type QuotaLedgerInterface interface {
+	Apply(actor *models.User, before, after *models.TimeEntry) error
+	Adjust(actor *models.User, userID, quotaID uint, amount models.QuotaAmount, reason string, now time.Time) (*models.UserQuotaTransaction, error)
+	Book(actor *models.User, userID, quotaID uint, kind string, amount models.QuotaAmount, reason string, now time.Time) (*models.UserQuotaTransaction, error)
+	Statement(actor *models.User, userID, quotaID uint, from, to time.Time) (*quotas.QuotaStatementResponse, error)
+}
+
+// NewQuotaLedger creates a QuotaLedger. The database should be scoped to the company of the entries, see repositories.WithTenant.
+func NewQuotaLedger(db *gorm.DB) *QuotaLedger {
+	return NewQuotaLedgerWithUnitOfWork(repositories.NewUnitOfWork(db))
+}
+
+// NewQuotaLedgerWithUnitOfWork creates a QuotaLedger whose repositories join the given unit of work.
+func NewQuotaLedgerWithUnitOfWork(uow *repositories.UnitOfWork) *QuotaLedger {
+	return &QuotaLedger{
+		unitOfWork: uow,
+	}
+}
+
+// Apply books the change of a time entry from `before` to `after` on the quotas of its user. `before` is nil
+// for created entries and `after` is nil for deleted ones. The old state is credited and the new one debited.
+// A debit that exceeds the balance fails with ErrQuotaExceeded unless the company allows negative balances.
+// Carried over quota is used up first and refunded last, see consumeCarryOvers.
+// Every change of a balance is recorded as a consumption by the actor.
+func (l *QuotaLedger) Apply(actor *models.User, before, after *models.TimeEntry) error {
+	deltas := map[uint]models.QuotaAmount{}
+	userQuotas := map[uint]*models.UserQuota{}
+	var order []uint
+	var company *models.Company
+	add := func(entry *models.TimeEntry, sign models.QuotaAmount) error {
+		if entry == nil {
+			return nil
+		}
+		userQuota, quota, err := l.userQuotaFor(entry)
+		if err != nil || userQuota == nil {
+			return err
+		}
+		user, err := l.unitOfWork.Users().GetByID(entry.UserID)
+		if err != nil {
+			return err
+		}
+		if company == nil {
+			if company, err = l.unitOfWork.Companies().GetByID(entry.CompanyID); err != nil {
+				return err
+			}
+		}
+		amount := Consumption(entry, quota, company, user)
+		if amount == 0 {
+			return nil
+		}
+		if _, ok := deltas[userQuota.ID]; !ok {
+			order = append(order, userQuota.ID)
+			userQuotas[userQuota.ID] = userQuota
+		}
+		deltas[userQuota.ID] += sign * amount
+		if sign > 0 {
+			return refundCarryOvers(l.unitOfWork, userQuota.ID, amount, entry.StartTime)
+		}
+		return consumeCarryOvers(l.unitOfWork, userQuota.ID, amount, entry.StartTime)
+	}
+	if err := add(before, 1); err != nil {
+		return err
+	}
+	if err := add(after, -1); err != nil {
+		return err
+	}
+
+	for _, userQuotaID := range order {
+		delta := deltas[userQuotaID]
+		if delta == 0 {
+			continue
+		}
+		// Only debits are bound by the balance.
+		allowNegative := delta > 0 || company.AllowNegativeQuota
+
+		err := l.unitOfWork.UserQuotas().Adjust(userQuotaID, delta, allowNegative)
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrQuotaExceeded
+		}
+		if err != nil {
+			return err
+		}
+		if !allowNegative {
+			if err := l.checkExpiredCarryOver(userQuotaID, after.StartTime); err != nil {
+				return err
+			}
+		}
+
+		entry, reason := after, "time entry changed"
+		if before == nil {
+			reason = "time entry booked"
+		} else if after == nil {
+			entry, reason = before, "time entry deleted"
+		}
+		transaction := newTransaction(models.QUOTA_TRANSACTION_CONSUMPTION, userQuotas[userQuotaID], entry.CompanyID, delta, actor, time.Now())
+		transaction.TimeEntryID = &entry.ID
+		transaction.Reason = reason
+		if err := record(l.unitOfWork, transaction); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Adjust books a manual correction of `amount` on the quota of a user, e.g. leave granted outside of the system.
+// The balance may become negative. It fails with ErrQuotaNotAssigned if the user does not have the quota.
+func (l *QuotaLedger) Adjust(actor *models.User, userID, quotaID uint, amount models.QuotaAmount, reason string, now time.Time) (*models.UserQuotaTransaction, error) {
+	return l.Book(actor, userID, quotaID, models.QUOTA_TRANSACTION_ADJUSTMENT, amount, reason, now)
+}
+
+// Book books `amount` of the given kind, one of the QUOTA_TRANSACTION constants, on the quota of a user,
+// e.g. settled overtime or a payout. The balance may become negative.
+// It fails with ErrQuotaNotAssigned if the user does not have the quota.
+func (l *QuotaLedger) Book(actor *models.User, userID, quotaID uint, kind string, amount models.QuotaAmount, reason string, now time.Time) (*models.UserQuotaTransaction, error) {
+	var transaction *models.UserQuotaTransaction
+	err := l.unitOfWork.Transaction(func(uow *repositories.UnitOfWork) error {
+		user, err := uow.Users().GetByID(userID)
+		if err != nil {
+			return err
+		}
+		userQuota, err := uow.UserQuotas().GetByUserIDAndQuotaID(userID, quotaID)
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrQuotaNotAssigned
+		}
+		if err != nil {
+			return err
+		}
+
+		if err := uow.UserQuotas().Adjust(userQuota.ID, amount, true); err != nil {
+			return err
+		}
+		transaction = newTransaction(kind, userQuota, user.CompanyID, amount, actor, now)
+		transaction.Reason = reason
+		return record(uow, transaction)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return transaction, nil
+}
+
+// checkExpiredCarryOver fails with ErrQuotaExceeded if a debit at `at` was only covered by carried over quota that has expired by then.
+func (l *QuotaLedger) checkExpiredCarryOver(userQuotaID uint, at time.Time) error {
+	unusable, err := unusableCarryOver(l.unitOfWork, userQuotaID, at)
+	if err != nil || unusable == 0 {
+		return err
+	}
+	userQuota, err := l.unitOfWork.UserQuotas().GetByID(userQuotaID)
+	if err != nil {
+		return err
+	}
+	if userQuota.Count < unusable {
+		return ErrQuotaExceeded
+	}
+	return nil
+}
+
+// userQuotaFor returns the UserQuota an entry is booked on together with its Quota, or nil if its type is not quota relevant.
+func (l *QuotaLedger) userQuotaFor(entry *models.TimeEntry) (*models.UserQuota, *models.Quota, error) {
+	timeEntryType, err := l.unitOfWork.TimeEntryTypes().GetByID(entry.TimeEntryTypeID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if !timeEntryType.IsQuotaRelevant {
+		return nil, nil, nil
+	}
+
+	quota, err := l.unitOfWork.Quotas().GetByCompanyIDAndName(entry.CompanyID, timeEntryType.QuotaName)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil, ErrUnknownQuota
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+
+	userQuota, err := l.unitOfWork.UserQuotas().GetByUserIDAndQuotaName(entry.UserID, timeEntryType.QuotaName)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil, ErrQuotaNotAssigned
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	return userQuota, quota, nil
+}
+
+// Consumption returns the quota a time entry uses up, in the unit of the quota. Quotas measured in hours
+// or minutes use up the time the entry lasts. Quotas measured in days use up the share of the user's
+// daily working hours the entry covers on every calendar day, at most a day per calendar day, and quotas
+// measured in half days the same share rounded up to whole half days. Calendar days begin in the timezone
+// of the company. Running entries use up nothing until they are closed.
+func Consumption(entry *models.TimeEntry, quota *models.Quota, company *models.Company, user *models.User) models.QuotaAmount {
+	if !entry.EndTime.Valid || !entry.EndTime.Time.After(entry.StartTime) {
+		return 0
+	}
+	location := company.Location()
+	start := entry.StartTime.In(location)
+	end := entry.EndTime.Time.In(location)
+	switch quota.Unit {
+	case models.QUOTA_UNIT_HOURS:
+		return models.NewQuotaAmount(end.Sub(start).Hours())
+	case models.QUOTA_UNIT_MINUTES:
+		return models.NewQuotaAmount(end.Sub(start).Minutes())
+	}
+
+	dailyHours := user.WorkingHoursPerDay()
+	var amount models.QuotaAmount
+	// An entry that ends at midnight does not cover the following day.
+	for day := time.Date(start.Year(), start.Month(), start.Day(), 0, 0, 0, 0, location); day.Before(end); day = day.AddDate(0, 0, 1) {
+		hours := earliest(end, day.AddDate(0, 0, 1)).Sub(latest(start, day)).Hours()
+		share := math.Min(hours/dailyHours, 1)
+		if quota.Unit == models.QUOTA_UNIT_HALF_DAYS {
+			amount += models.QuotaUnits(int(math.Ceil(share*2 - 1e-9)))
+		} else {
+			amount += models.NewQuotaAmount(share)
+		}
+	}
+	return amount
+}
@@ -0,0 +1,257 @@
+package quota_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/r-52/embrace/models"
+	"github.com/r-52/embrace/repositories"
+	"github.com/r-52/embrace/services/quota"
+	"github.com/r-52/embrace/services/role"
+	"github.com/r-52/embrace/services/servicetest"
+	"gorm.io/gorm"
+)
+
+var monday = time.Date(2024, 3, 4, 8, 0, 0, 0, time.UTC)
+
+type fixture struct {
+	db        *gorm.DB
+	company   *models.Company
+	user      *models.User
+	vacation  *models.TimeEntryType
+	work      *models.TimeEntryType
+	userQuota *models.UserQuota
+}
+
+func setupFixture(t *testing.T, count int) *fixture {
+	db := servicetest.Database(t, &models.Company{}, &models.User{}, &models.UserProfile{}, &models.UserRole{}, &models.RolePermission{},
+		&models.TimeEntryType{}, &models.TimeEntry{}, &models.Quota{}, &models.UserQuota{},
+		&models.QuotaReset{}, &models.UserQuotaReset{}, &models.UserQuotaCarryOver{}, &models.UserQuotaTransaction{})
+
+	f := &fixture{db: db}
+	f.company = servicetest.Company(t, db, "acme")
+	f.user = servicetest.User(t, db, f.company.ID, role.DEFAULT_ROLE_EMPLOYEE, "user", nil)
+	f.vacation = &models.TimeEntryType{Name: "Vacation", Color: "#00ff00", CompanyID: f.company.ID, IsQuotaRelevant: true, QuotaName: "vacation"}
+	servicetest.MustCreate(t, db, f.vacation)
+	f.work = &models.TimeEntryType{Name: "Work", Color: "#0000ff", CompanyID: f.company.ID}
+	servicetest.MustCreate(t, db, f.work)
+	vacationQuota := &models.Quota{Name: "vacation", CompanyID: f.company.ID, Count: models.QuotaUnits(30), QuotaResetAt: models.QUOTA_RESET_FIRST_OF_YEAR}
+	servicetest.MustCreate(t, db, vacationQuota)
+	f.userQuota = &models.UserQuota{UserID: f.user.ID, QuotaID: vacationQuota.ID, Count: models.QuotaUnits(count)}
+	servicetest.MustCreate(t, db, f.userQuota)
+	return f
+}
+
+func (f *fixture) entry(timeEntryType *models.TimeEntryType, start time.Time, hours int) *models.TimeEntry {
+	entry := &models.TimeEntry{CompanyID: f.company.ID, UserID: f.user.ID, TimeEntryTypeID: timeEntryType.ID, StartTime: start}
+	entry.Close(start.Add(time.Duration(hours) * time.Hour))
+	return entry
+}
+
+func (f *fixture) balance(t *testing.T) float64 {
+	userQuota, err := repositories.NewUserQuotaRepository(f.db).GetByID(f.userQuota.ID)
+	if err != nil {
+		t.Fatalf("failed to load user quota: %v", err)
+	}
+	return userQuota.Count.Units()
+}
+
+func (f *fixture) transactions(t *testing.T) []models.UserQuotaTransaction {
+	transactions, err := repositories.NewUserQuotaTransactionRepository(f.db).GetByUserQuotaID(f.userQuota.ID, time.Time{}, time.Time{})
+	if err != nil {
+		t.Fatalf("failed to load quota transactions: %v", err)
+	}
+	return transactions
+}
+
+func TestConsumption(t *testing.T) {
+	midnight := time.Date(2024, 3, 5, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name     string
+		unit     string
+		start    time.Time
+		end      time.Time
+		expected float64
+	}{
+		{name: "working day", unit: models.QUOTA_UNIT_DAYS, start: monday, end: monday.Add(8 * time.Hour), expected: 1},
+		{name: "half a working day", unit: models.QUOTA_UNIT_DAYS, start: monday, end: monday.Add(4 * time.Hour), expected: 0.5},
+		{name: "until midnight", unit: models.QUOTA_UNIT_DAYS, start: monday, end: midnight, expected: 1},
+		{name: "whole day", unit: models.QUOTA_UNIT_DAYS, start: midnight, end: midnight.AddDate(0, 0, 1), expected: 1},
+		{name: "across midnight", unit: models.QUOTA_UNIT_DAYS, start: monday.Add(14 * time.Hour), end: monday.Add(20 * time.Hour), expected: 0.75},
+		{name: "two working days", unit: models.QUOTA_UNIT_DAYS, start: monday, end: monday.Add(32 * time.Hour), expected: 2},
+		{name: "half days rounded up", unit: models.QUOTA_UNIT_HALF_DAYS, start: monday, end: monday.Add(3 * time.Hour), expected: 1},
+		{name: "half days of a working day", unit: models.QUOTA_UNIT_HALF_DAYS, start: monday, end: monday.Add(8 * time.Hour), expected: 2},
+		{name: "hours", unit: models.QUOTA_UNIT_HOURS, start: monday, end: monday.Add(90 * time.Minute), expected: 1.5},
+		{name: "hours across days", unit: models.QUOTA_UNIT_HOURS, start: monday, end: monday.Add(32 * time.Hour), expected: 32},
+		{name: "minutes", unit: models.QUOTA_UNIT_MINUTES, start: monday, end: monday.Add(90 * time.Minute), expected: 90},
+	}
+	company := &models.Company{}
+	user := &models.User{DailyWorkingHours: 8}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			entry := &models.TimeEntry{StartTime: tt.start}
+			entry.Close(tt.end)
+			if got := quota.Consumption(entry, &models.Quota{Unit: tt.unit}, company, user); got.Units() != tt.expected {
+				t.Errorf("expected %v, got %v", tt.expected, got)
+			}
+		})
+	}
+
+	partTime := &models.User{DailyWorkingHours: 6}
+	entry := &models.TimeEntry{StartTime: monday}
+	entry.Close(monday.Add(3 * time.Hour))
+	if got := quota.Consumption(entry, &models.Quota{Unit: models.QUOTA_UNIT_DAYS}, company, partTime); got.Units() != 0.5 {
+		t.Errorf("expected half a day of a 6 hour working day, got %v", got)
+	}
+
+	if got := quota.Consumption(&models.TimeEntry{StartTime: monday}, &models.Quota{}, company, user); got != 0 {
+		t.Errorf("expected a running entry to consume nothing, got %v", got)
+	}
+
+	// 20:00 to 23:30 UTC lies on one calendar day in UTC, but on two in Berlin.
+	evening := &models.TimeEntry{StartTime: monday.Add(12 * time.Hour)}
+	evening.Close(monday.Add(15*time.Hour + 30*time.Minute))
+	if got := quota.Consumption(evening, &models.Quota{Unit: models.QUOTA_UNIT_HALF_DAYS}, company, user); got.Units() != 1 {
+		t.Errorf("expected one half day in UTC, got %v", got)
+	}
+	berlin := &models.Company{Timezone: "Europe/Berlin"}
+	if got := quota.Consumption(evening, &models.Quota{Unit: models.QUOTA_UNIT_HALF_DAYS}, berlin, user); got.Units() != 2 {
+		t.Errorf("expected a half day on each calendar day of the company, got %v", got)
+	}
+}
+
+func TestQuotaLedger_Apply(t *testing.T) {
+	f := setupFixture(t, 2)
+	ledger := quota.NewQuotaLedger(repositories.WithTenant(f.db, f.company.ID))
+
+	first := f.entry(f.vacation, monday, 8)
+	if err := ledger.Apply(f.user, nil, first); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if balance := f.balance(t); balance != 1 {
+		t.Errorf("expected balance 1 after booking, got %v", balance)
+	}
+
+	if err := ledger.Apply(f.user, nil, f.entry(f.work, monday.AddDate(0, 0, 1), 8)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if balance := f.balance(t); balance != 1 {
+		t.Errorf("expected work to leave the balance at 1, got %v", balance)
+	}
+
+	// Moving the entry to span two working days needs one more day.
+	moved := *first
+	moved.Close(monday.Add(32 * time.Hour))
+	if err := ledger.Apply(f.user, first, &moved); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if balance := f.balance(t); balance != 0 {
+		t.Errorf("expected balance 0 after moving, got %v", balance)
+	}
+
+	if err := ledger.Apply(f.user, nil, f.entry(f.vacation, monday.AddDate(0, 0, 2), 8)); !errors.Is(err, quota.ErrQuotaExceeded) {
+		t.Errorf("expected ErrQuotaExceeded, got %v", err)
+	}
+
+	// Switching to a type that is not quota relevant refunds everything.
+	switched := moved
+	switched.TimeEntryTypeID = f.work.ID
+	if err := ledger.Apply(f.user, &moved, &switched); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if balance := f.balance(t); balance != 2 {
+		t.Errorf("expected balance 2 after switching the type, got %v", balance)
+	}
+}
+
+func TestQuotaLedger_Apply_Allows_Negative_Balances(t *testing.T) {
+	f := setupFixture(t, 0)
+	f.db.Model(f.company).Update("allow_negative_quota", true)
+	ledger := quota.NewQuotaLedger(repositories.WithTenant(f.db, f.company.ID))
+
+	entry := f.entry(f.vacation, monday, 8)
+	if err := ledger.Apply(f.user, nil, entry); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if balance := f.balance(t); balance != -1 {
+		t.Errorf("expected balance -1, got %v", balance)
+	}
+	if err := ledger.Apply(f.user, entry, nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if balance := f.balance(t); balance != 0 {
+		t.Errorf("expected balance 0 after deleting, got %v", balance)
+	}
+}
+
+func TestQuotaLedger_Apply_Records_Consumption(t *testing.T) {
+	f := setupFixture(t, 10)
+	ledger := quota.NewQuotaLedger(repositories.WithTenant(f.db, f.company.ID))
+
+	entry := f.entry(f.vacation, monday, 8)
+	servicetest.MustCreate(t, f.db, entry)
+	if err := ledger.Apply(f.user, nil, entry); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	shortened := *entry
+	shortened.Close(monday.Add(4 * time.Hour))
+	if err := ledger.Apply(f.user, entry, &shortened); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := ledger.Apply(f.user, &shortened, nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	transactions := f.transactions(t)
+	expected := []struct{ amount, balance float64 }{{-1, 9}, {0.5, 9.5}, {0.5, 10}}
+	if len(transactions) != len(expected) {
+		t.Fatalf("expected %d transactions, got %+v", len(expected), transactions)
+	}
+	for i, transaction := range transactions {
+		if transaction.Kind != models.QUOTA_TRANSACTION_CONSUMPTION || transaction.Amount.Units() != expected[i].amount ||
+			transaction.Balance.Units() != expected[i].balance || *transaction.ActorID != f.user.ID ||
+			*transaction.TimeEntryID != entry.ID || transaction.CompanyID != f.company.ID {
+			t.Errorf("transaction %d: expected a consumption of %v leaving %v, got %+v", i, expected[i].amount, expected[i].balance, transaction)
+		}
+	}
+}
+
+func TestQuotaLedger_Adjust(t *testing.T) {
+	f := setupFixture(t, 10)
+	ledger := quota.NewQuotaLedger(repositories.WithTenant(f.db, f.company.ID))
+
+	transaction, err := ledger.Adjust(f.user, f.user.ID, f.userQuota.QuotaID, models.NewQuotaAmount(-10.5), "paid out", monday)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if balance := f.balance(t); balance != -0.5 {
+		t.Errorf("expected an adjustment to allow a negative balance, got %v", balance)
+	}
+	if transaction.Kind != models.QUOTA_TRANSACTION_ADJUSTMENT || transaction.Balance.Units() != -0.5 ||
+		transaction.Reason != "paid out" || !transaction.BookedAt.Equal(monday) {
+		t.Errorf("expected the adjustment to be recorded, got %+v", transaction)
+	}
+
+	if _, err := ledger.Adjust(f.user, f.user.ID, 999, models.QuotaUnits(1), "typo", monday); !errors.Is(err, quota.ErrQuotaNotAssigned) {
+		t.Errorf("expected ErrQuotaNotAssigned, got %v", err)
+	}
+	if _, err := ledger.Adjust(f.user, 999, f.userQuota.QuotaID, models.QuotaUnits(1), "typo", monday); !errors.Is(err, gorm.ErrRecordNotFound) {
+		t.Errorf("expected gorm.ErrRecordNotFound for an unknown user, got %v", err)
+	}
+}
+
+func TestQuotaLedger_Apply_Requires_Quota(t *testing.T) {
+	f := setupFixture(t, 10)
+	ledger := quota.NewQuotaLedger(repositories.WithTenant(f.db, f.company.ID))
+
+	f.db.Delete(f.userQuota)
+	if err := ledger.Apply(f.user, nil, f.entry(f.vacation, monday, 8)); !errors.Is(err, quota.ErrQuotaNotAssigned) {
+		t.Errorf("expected ErrQuotaNotAssigned, got %v", err)
+	}
+
+	f.db.Model(f.vacation).Update("quota_name", "sabbatical")
+	if err := ledger.Apply(f.user, nil, f.entry(f.vacation, monday, 8)); !errors.Is(err, quota.ErrUnknownQuota) {
+		t.Errorf("expected ErrUnknownQuota, got %v", err)
+	}
+}
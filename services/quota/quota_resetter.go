@@ -0,0 +1,340 @@
+package quota
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/r-52/embrace/models"
+	"github.com/r-52/embrace/repositories"
+	"gorm.io/gorm"
+)
+
+var errAlreadyReset = errors.New("quota period already reset")
+
+var errAlreadyExpired = errors.New("carry-over already expired")
+
+// QuotaResetter resets the balances of quotas when a new period begins, see Quota.QuotaResetAt.
+// Periods begin in the timezone of the company. Every reset is recorded together with the
+// balances it changed, and a period is never reset twice. Unused quota up to Quota.CarryOverMax
+// is carried over into the new period, until it expires.
+type QuotaResetter struct {
+	unitOfWork *repositories.UnitOfWork
+}
+
+type QuotaResetterInterface interface {
+	ResetDue(now time.Time) ([]models.QuotaReset, error)
+	ExpireDue(now time.Time) ([]models.UserQuotaCarryOver, error)
+}
+
+// NewQuotaResetter creates a QuotaResetter. It works across companies, so the database must not be scoped to a tenant.
+func NewQuotaResetter(db *gorm.DB) *QuotaResetter {
+	return NewQuotaResetterWithUnitOfWork(repositories.NewUnitOfWork(db))
+}
+
+// NewQuotaResetterWithUnitOfWork creates a QuotaResetter whose repositories join the given unit of work.
+func NewQuotaResetterWithUnitOfWork(uow *repositories.UnitOfWork) *QuotaResetter {
+	return &QuotaResetter{
+		unitOfWork: uow,
+	}
+}
+
+// ResetDue resets every quota whose current period has not been reset yet and returns the resets it made.
+// Quotas created within the current period keep their balances until the next one begins.
+// A failing quota does not stop the others, all failures are returned together.
+func (r *QuotaResetter) ResetDue(now time.Time) ([]models.QuotaReset, error) {
+	companies, err := r.unitOfWork.Companies().GetAll()
+	if err != nil {
+		return nil, err
+	}
+
+	var resets []models.QuotaReset
+	var errs []error
+	for _, company := range companies {
+		tenant := r.unitOfWork.ForTenant(company.ID)
+		quotas, err := tenant.Quotas().GetByCompanyID(company.ID)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+
+		for i := range quotas {
+			periodStart, ok := quotas[i].PeriodStart(now.In(company.Location()))
+			if !ok || !periodStart.After(quotas[i].CreatedAt) {
+				continue
+			}
+			reset, err := resetQuota(tenant, &company, &quotas[i], periodStart, now)
+			if errors.Is(err, errAlreadyReset) {
+				continue
+			}
+			if err != nil {
+				errs = append(errs, fmt.Errorf("quota %d: %w", quotas[i].ID, err))
+				continue
+			}
+			resets = append(resets, *reset)
+		}
+	}
+	return resets, errors.Join(errs...)
+}
+
+// resetQuota sets the balances of a quota to the entitlements of its users for the period starting at periodStart,
+// plus what is carried over. Entries of the new period that were booked in advance were debited from the previous
+// balance, so they do not reduce the carry-over and are debited from the new balance again.
+// It returns errAlreadyReset if the period was reset before, also by a concurrent run.
+func resetQuota(uow *repositories.UnitOfWork, company *models.Company, quota *models.Quota, periodStart time.Time, now time.Time) (*models.QuotaReset, error) {
+	expiresAt, expires := quota.CarryOverExpiresAt(periodStart)
+	periodStart = periodStart.UTC()
+	_, err := uow.QuotaResets().GetByQuotaIDAndPeriodStart(quota.ID, periodStart)
+	if err == nil {
+		return nil, errAlreadyReset
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+
+	reset := &models.QuotaReset{
+		QuotaID:     quota.ID,
+		PeriodStart: periodStart,
+		ExecutedAt:  now,
+		CompanyID:   quota.CompanyID,
+	}
+	err = uow.Transaction(func(uow *repositories.UnitOfWork) error {
+		userQuotas, err := uow.UserQuotas().GetByQuotaID(quota.ID)
+		if err != nil {
+			return err
+		}
+		timeEntryTypeIDs, err := timeEntryTypesOf(uow, quota)
+		if err != nil {
+			return err
+		}
+		entitlements := make([]models.QuotaAmount, len(userQuotas))
+		consumptions := make([][]consumption, len(userQuotas))
+		for i, userQuota := range userQuotas {
+			user, err := uow.Users().GetByID(userQuota.UserID)
+			if err != nil {
+				return err
+			}
+			consumptions[i], err = bookedInAdvance(uow, quota, company, user, timeEntryTypeIDs, periodStart)
+			if err != nil {
+				return err
+			}
+			var consumed models.QuotaAmount
+			for _, c := range consumptions[i] {
+				consumed += c.amount
+			}
+			entitlements[i] = Entitlement(quota, company, user, periodStart)
+			carried := min(max(userQuota.Count+consumed, 0), quota.CarryOverMax)
+			reset.Entries = append(reset.Entries, models.UserQuotaReset{
+				UserQuotaID:   userQuota.ID,
+				UserID:        userQuota.UserID,
+				PreviousCount: userQuota.Count,
+				Count:         entitlements[i] + carried - consumed,
+				Carried:       carried,
+			})
+		}
+
+		// Recording the reset first claims the period, a concurrent run fails on the unique index.
+		err = uow.QuotaResets().Create(reset)
+		if errors.Is(err, gorm.ErrDuplicatedKey) {
+			return errAlreadyReset
+		}
+		if err != nil {
+			return err
+		}
+
+		for i := range userQuotas {
+			entry := reset.Entries[i]
+			// What is left of earlier carry-overs is part of the previous count, so the reset absorbs them.
+			carryOvers, err := uow.UserQuotaCarryOvers().GetOpenByUserQuotaID(userQuotas[i].ID)
+			if err != nil {
+				return err
+			}
+			for _, carryOver := range carryOvers {
+				if err := uow.UserQuotaCarryOvers().Close(carryOver.ID, 0, now); err != nil {
+					return err
+				}
+			}
+
+			userQuotas[i].Count = entry.Count
+			userQuotas[i].Entitlement = entitlements[i]
+			if err := uow.UserQuotas().Update(&userQuotas[i]); err != nil {
+				return err
+			}
+			var carryOver *models.UserQuotaCarryOver
+			if entry.Carried != 0 {
+				carryOver = &models.UserQuotaCarryOver{
+					UserQuotaID:  userQuotas[i].ID,
+					QuotaResetID: reset.ID,
+					CompanyID:    quota.CompanyID,
+					Carried:      entry.Carried,
+					Remaining:    entry.Carried,
+					ExpiresAt:    sql.NullTime{Time: expiresAt.UTC(), Valid: expires},
+				}
+				if err := uow.UserQuotaCarryOvers().Create(carryOver); err != nil {
+					return err
+				}
+			}
+			for _, c := range consumptions[i] {
+				if err := consumeCarryOvers(uow, userQuotas[i].ID, c.amount, c.entry.StartTime); err != nil {
+					return err
+				}
+			}
+			if err := recordReset(uow, reset, &userQuotas[i], &entry, entitlements[i], carryOver, consumptions[i]); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return reset, nil
+}
+
+// consumption is the quota a time entry uses up.
+type consumption struct {
+	entry  *models.TimeEntry
+	amount models.QuotaAmount
+}
+
+// timeEntryTypesOf returns the IDs of the quota relevant time entry types that are booked on a quota.
+func timeEntryTypesOf(uow *repositories.UnitOfWork, quota *models.Quota) ([]uint, error) {
+	timeEntryTypes, err := uow.TimeEntryTypes().GetByCompanyID(quota.CompanyID)
+	if err != nil {
+		return nil, err
+	}
+	var ids []uint
+	for _, timeEntryType := range timeEntryTypes {
+		if timeEntryType.IsQuotaRelevant && timeEntryType.QuotaName == quota.Name {
+			ids = append(ids, timeEntryType.ID)
+		}
+	}
+	return ids, nil
+}
+
+// bookedInAdvance returns what the entries of a user on the given types that start at or after periodStart use up of the quota.
+func bookedInAdvance(uow *repositories.UnitOfWork, quota *models.Quota, company *models.Company, user *models.User, timeEntryTypeIDs []uint, periodStart time.Time) ([]consumption, error) {
+	var consumptions []consumption
+	for _, timeEntryTypeID := range timeEntryTypeIDs {
+		entries, err := uow.TimeEntries().Find(repositories.TimeEntryFilter{UserIDs: []uint{user.ID}, TimeEntryTypeID: timeEntryTypeID, From: periodStart})
+		if err != nil {
+			return nil, err
+		}
+		for i := range entries {
+			if amount := Consumption(&entries[i], quota, company, user); amount != 0 {
+				consumptions = append(consumptions, consumption{entry: &entries[i], amount: amount})
+			}
+		}
+	}
+	return consumptions, nil
+}
+
+// recordReset records how a reset replaced the balance of a UserQuota: the previous balance is cleared,
+// the entitlement allocated, the carried over quota added back on top of it and the entries booked in advance debited again.
+func recordReset(uow *repositories.UnitOfWork, reset *models.QuotaReset, userQuota *models.UserQuota, entry *models.UserQuotaReset, entitlement models.QuotaAmount, carryOver *models.UserQuotaCarryOver, consumptions []consumption) error {
+	var transactions []*models.UserQuotaTransaction
+	balance := entry.PreviousCount
+	if entry.PreviousCount != 0 {
+		transaction := newTransaction(models.QUOTA_TRANSACTION_RESET, userQuota, reset.CompanyID, -entry.PreviousCount, nil, reset.ExecutedAt)
+		transaction.Reason = "period ended"
+		transactions = append(transactions, transaction)
+	}
+	if entitlement != 0 {
+		transaction := newTransaction(models.QUOTA_TRANSACTION_ALLOCATION, userQuota, reset.CompanyID, entitlement, nil, reset.ExecutedAt)
+		transaction.Reason = "period started"
+		transactions = append(transactions, transaction)
+	}
+	if carryOver != nil {
+		transaction := newTransaction(models.QUOTA_TRANSACTION_CARRY_OVER, userQuota, reset.CompanyID, carryOver.Carried, nil, reset.ExecutedAt)
+		transaction.Reason = "unused quota carried over"
+		transaction.UserQuotaCarryOverID = &carryOver.ID
+		transactions = append(transactions, transaction)
+	}
+	for _, c := range consumptions {
+		transaction := newTransaction(models.QUOTA_TRANSACTION_CONSUMPTION, userQuota, reset.CompanyID, -c.amount, nil, reset.ExecutedAt)
+		transaction.Reason = "time entry booked in advance"
+		transaction.TimeEntryID = &c.entry.ID
+		transactions = append(transactions, transaction)
+	}
+	for _, transaction := range transactions {
+		balance += transaction.Amount
+		transaction.Balance = balance
+		transaction.QuotaResetID = &reset.ID
+		if err := uow.UserQuotaTransactions().Create(transaction); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ExpireDue removes the carried over quota that is left when it expires from the balances
+// and returns the carry-overs it closed. Carry-overs closed before are skipped.
+func (r *QuotaResetter) ExpireDue(now time.Time) ([]models.UserQuotaCarryOver, error) {
+	carryOvers, err := r.unitOfWork.UserQuotaCarryOvers().GetDue(now)
+	if err != nil {
+		return nil, err
+	}
+
+	var expired []models.UserQuotaCarryOver
+	var errs []error
+	for _, carryOver := range carryOvers {
+		closed, err := expireCarryOver(r.unitOfWork, carryOver.ID, now)
+		if errors.Is(err, errAlreadyExpired) {
+			continue
+		}
+		if err != nil {
+			errs = append(errs, fmt.Errorf("carry-over %d: %w", carryOver.ID, err))
+			continue
+		}
+		expired = append(expired, *closed)
+	}
+	return expired, errors.Join(errs...)
+}
+
+// expireCarryOver closes a carry-over and debits what is left of it. It returns errAlreadyExpired if the carry-over was closed before.
+func expireCarryOver(uow *repositories.UnitOfWork, id uint, now time.Time) (*models.UserQuotaCarryOver, error) {
+	var carryOver *models.UserQuotaCarryOver
+	err := uow.Transaction(func(uow *repositories.UnitOfWork) error {
+		var err error
+		// Bookings may have used the carry-over since it was listed.
+		carryOver, err = uow.UserQuotaCarryOvers().GetByID(id)
+		if err != nil {
+			return err
+		}
+		if !carryOver.IsOpen() {
+			return errAlreadyExpired
+		}
+
+		err = uow.UserQuotaCarryOvers().Close(carryOver.ID, carryOver.Remaining, now)
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return errAlreadyExpired
+		}
+		if err != nil {
+			return err
+		}
+		if carryOver.Remaining <= 0 {
+			return nil
+		}
+		if err := uow.UserQuotas().Adjust(carryOver.UserQuotaID, -carryOver.Remaining, true); err != nil {
+			return err
+		}
+		transaction := &models.UserQuotaTransaction{
+			UserQuotaID:          carryOver.UserQuotaID,
+			CompanyID:            carryOver.CompanyID,
+			Kind:                 models.QUOTA_TRANSACTION_EXPIRY,
+			Amount:               -carryOver.Remaining,
+			BookedAt:             now.UTC(),
+			Reason:               "carried over quota expired",
+			UserQuotaCarryOverID: &carryOver.ID,
+		}
+		return record(uow, transaction)
+	})
+	if err != nil {
+		return nil, err
+	}
+	carryOver.Expired = carryOver.Remaining
+	carryOver.Remaining = 0
+	carryOver.ExpiredAt = sql.NullTime{Time: now, Valid: true}
+	return carryOver, nil
+}
@@ -0,0 +1,180 @@
+package quota_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/r-52/embrace/models"
+	"github.com/r-52/embrace/repositories"
+	"github.com/r-52/embrace/services/quota"
+	"github.com/r-52/embrace/services/servicetest"
+	"gorm.io/gorm"
+)
+
+func (f *fixture) quota(t *testing.T) *models.Quota {
+	q, err := repositories.NewQuotaRepository(f.db).GetByID(f.userQuota.QuotaID)
+	if err != nil {
+		t.Fatalf("failed to load quota: %v", err)
+	}
+	return q
+}
+
+func backdate(t *testing.T, db *gorm.DB, q *models.Quota, createdAt time.Time) {
+	if err := db.Model(q).Update("created_at", createdAt).Error; err != nil {
+		t.Fatalf("failed to backdate quota: %v", err)
+	}
+}
+
+func TestQuotaResetter_ResetDue(t *testing.T) {
+	f := setupFixture(t, 4)
+	backdate(t, f.db, f.quota(t), time.Date(2023, 6, 1, 0, 0, 0, 0, time.UTC))
+
+	resets, err := quota.NewQuotaResetter(f.db).ResetDue(monday)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(resets) != 1 {
+		t.Fatalf("expected 1 reset, got %v", len(resets))
+	}
+	if !resets[0].PeriodStart.Equal(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("expected the period to start on new year, got %v", resets[0].PeriodStart)
+	}
+	if len(resets[0].Entries) != 1 || resets[0].Entries[0].PreviousCount.Units() != 4 || resets[0].Entries[0].Count.Units() != 30 {
+		t.Errorf("expected the balance change to be recorded, got %v", resets[0].Entries)
+	}
+	if balance := f.balance(t); balance != 30 {
+		t.Errorf("expected balance 30, got %v", balance)
+	}
+}
+
+func TestQuotaResetter_ResetDue_Is_Idempotent(t *testing.T) {
+	f := setupFixture(t, 4)
+	backdate(t, f.db, f.quota(t), time.Date(2023, 6, 1, 0, 0, 0, 0, time.UTC))
+
+	resetter := quota.NewQuotaResetter(f.db)
+	if _, err := resetter.ResetDue(monday); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	f.db.Model(f.userQuota).Update("count", models.QuotaUnits(12))
+
+	// A restarted scheduler runs again within the same period
+	resets, err := quota.NewQuotaResetter(f.db).ResetDue(monday.Add(time.Hour))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(resets) != 0 {
+		t.Errorf("expected no reset, got %v", len(resets))
+	}
+	if balance := f.balance(t); balance != 12 {
+		t.Errorf("expected balance 12, got %v", balance)
+	}
+
+	// The next period is reset again
+	resets, err = resetter.ResetDue(time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(resets) != 1 || f.balance(t) != 30 {
+		t.Errorf("expected the next period to be reset, got %v resets and balance %v", len(resets), f.balance(t))
+	}
+	history, _ := repositories.NewQuotaResetRepository(f.db).GetByQuotaID(f.userQuota.QuotaID)
+	if len(history) != 2 {
+		t.Errorf("expected 2 recorded resets, got %v", len(history))
+	}
+}
+
+func TestQuotaResetter_ResetDue_Skips_Quotas_Created_In_Current_Period(t *testing.T) {
+	f := setupFixture(t, 4)
+	backdate(t, f.db, f.quota(t), time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC))
+
+	resets, err := quota.NewQuotaResetter(f.db).ResetDue(monday)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(resets) != 0 || f.balance(t) != 4 {
+		t.Errorf("expected no reset, got %v resets and balance %v", len(resets), f.balance(t))
+	}
+}
+
+func TestQuotaResetter_ResetDue_Uses_Company_Timezone(t *testing.T) {
+	f := setupFixture(t, 4)
+	backdate(t, f.db, f.quota(t), time.Date(2023, 6, 1, 0, 0, 0, 0, time.UTC))
+	lateNewYearsEve := time.Date(2024, 12, 31, 23, 30, 0, 0, time.UTC)
+
+	// In UTC the year 2024 has begun, but not yet 2025
+	resetter := quota.NewQuotaResetter(f.db)
+	if _, err := resetter.ResetDue(time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	f.db.Model(f.userQuota).Update("count", models.QuotaUnits(4))
+	resets, _ := resetter.ResetDue(lateNewYearsEve)
+	if len(resets) != 0 {
+		t.Errorf("expected no reset before new year in UTC, got %v", len(resets))
+	}
+
+	// In Berlin it is already 2025
+	f.db.Model(f.company).Update("timezone", "Europe/Berlin")
+	resets, err := resetter.ResetDue(lateNewYearsEve)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(resets) != 1 || f.balance(t) != 30 {
+		t.Fatalf("expected a reset after new year in Berlin, got %v resets and balance %v", len(resets), f.balance(t))
+	}
+	if expected := time.Date(2024, 12, 31, 23, 0, 0, 0, time.UTC); !resets[0].PeriodStart.Equal(expected) {
+		t.Errorf("expected the period to start at %v, got %v", expected, resets[0].PeriodStart)
+	}
+}
+
+func TestQuotaResetter_ResetDue_Debits_Entries_Booked_In_Advance(t *testing.T) {
+	f := setupFixture(t, 10)
+	q := f.quota(t)
+	backdate(t, f.db, q, time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	f.db.Model(q).Update("carry_over_max", models.QuotaUnits(5))
+	ledger := quota.NewQuotaLedger(repositories.WithTenant(f.db, f.company.ID))
+
+	// January leave approved in December is debited from the old year.
+	leave := f.entry(f.vacation, time.Date(2025, 1, 6, 8, 0, 0, 0, time.UTC), 8)
+	servicetest.MustCreate(t, f.db, leave)
+	if err := ledger.Apply(f.user, nil, leave); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if balance := f.balance(t); balance != 9 {
+		t.Fatalf("expected balance 9 after booking, got %v", balance)
+	}
+
+	resets, err := quota.NewQuotaResetter(f.db).ResetDue(newYear)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	// The leave does not reduce the carry-over and is debited from the new year.
+	if len(resets) != 1 || resets[0].Entries[0].Carried.Units() != 5 || resets[0].Entries[0].Count.Units() != 34 {
+		t.Fatalf("expected 5 days carried over and the leave debited, got %+v", resets)
+	}
+	if balance := f.balance(t); balance != 34 {
+		t.Errorf("expected balance 34 after the reset, got %v", balance)
+	}
+	if remaining := f.carryOver(t).Remaining.Units(); remaining != 4 {
+		t.Errorf("expected the leave to use up the carry-over first, got %v remaining", remaining)
+	}
+	var rebooked []models.UserQuotaTransaction
+	for _, transaction := range f.transactions(t) {
+		if transaction.QuotaResetID != nil && transaction.Kind == models.QUOTA_TRANSACTION_CONSUMPTION {
+			rebooked = append(rebooked, transaction)
+		}
+	}
+	if len(rebooked) != 1 || rebooked[0].Amount.Units() != -1 || *rebooked[0].TimeEntryID != leave.ID || rebooked[0].Balance.Units() != 34 {
+		t.Errorf("expected the leave to be recorded as consumption of the reset, got %+v", rebooked)
+	}
+
+	// Deleting the leave refunds what the new year was debited.
+	if err := ledger.Apply(f.user, leave, nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if balance := f.balance(t); balance != 35 {
+		t.Errorf("expected balance 35 after deleting, got %v", balance)
+	}
+	if remaining := f.carryOver(t).Remaining.Units(); remaining != 5 {
+		t.Errorf("expected the carry-over to be refunded, got %v remaining", remaining)
+	}
+}
@@ -0,0 +1,72 @@
+package quota
+
+import (
+	"errors"
+	"time"
+
+	"github.com/r-52/embrace/models"
+	quotas "github.com/r-52/embrace/models/dto/quota"
+	"gorm.io/gorm"
+)
+
+// Statement lists the transactions on the quota of a user booked from the start of the day `from` until the end
+// of the day `to`, in the timezone of the company. The opening and closing balances are derived from the ledger
+// and the current balance is reconciled against it. It fails with ErrQuotaNotAssigned if the user does not have the quota.
+func (l *QuotaLedger) Statement(actor *models.User, userID, quotaID uint, from, to time.Time) (*quotas.QuotaStatementResponse, error) {
+	user, err := l.unitOfWork.Users().GetByID(userID)
+	if err != nil {
+		return nil, err
+	}
+	if err := authorizeRead(actor, user); err != nil {
+		return nil, err
+	}
+	company, err := l.unitOfWork.Companies().GetByID(user.CompanyID)
+	if err != nil {
+		return nil, err
+	}
+	userQuota, err := l.unitOfWork.UserQuotas().GetByUserIDAndQuotaID(userID, quotaID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrQuotaNotAssigned
+	}
+	if err != nil {
+		return nil, err
+	}
+	quota, err := l.unitOfWork.Quotas().GetByID(quotaID)
+	if err != nil {
+		return nil, err
+	}
+
+	start := time.Date(from.Year(), from.Month(), from.Day(), 0, 0, 0, 0, company.Location())
+	end := time.Date(to.Year(), to.Month(), to.Day()+1, 0, 0, 0, 0, company.Location())
+	opening, err := l.unitOfWork.UserQuotaTransactions().SumBefore(userQuota.ID, start)
+	if err != nil {
+		return nil, err
+	}
+	transactions, err := l.unitOfWork.UserQuotaTransactions().GetByUserQuotaID(userQuota.ID, start, end)
+	if err != nil {
+		return nil, err
+	}
+	total, err := l.unitOfWork.UserQuotaTransactions().SumBefore(userQuota.ID, time.Time{})
+	if err != nil {
+		return nil, err
+	}
+
+	statement := &quotas.QuotaStatementResponse{
+		UserID:         userID,
+		QuotaID:        quotaID,
+		Name:           quota.Name,
+		Unit:           quota.Unit,
+		From:           start.Format(time.DateOnly),
+		To:             end.AddDate(0, 0, -1).Format(time.DateOnly),
+		OpeningBalance: opening,
+		ClosingBalance: opening,
+		Transactions:   []*quotas.QuotaTransactionResponse{},
+		Balance:        userQuota.Count,
+		Reconciled:     total == userQuota.Count,
+	}
+	for i := range transactions {
+		statement.ClosingBalance += transactions[i].Amount
+		statement.Transactions = append(statement.Transactions, quotas.NewQuotaTransactionResponse(&transactions[i]))
+	}
+	return statement, nil
+}
@@ -0,0 +1,83 @@
+package quota_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/r-52/embrace/models"
+	"github.com/r-52/embrace/repositories"
+	"github.com/r-52/embrace/services/quota"
+	"github.com/r-52/embrace/services/servicetest"
+	"gorm.io/gorm"
+)
+
+func withPermissions(user *models.User, permissions ...string) *models.User {
+	actor := *user
+	actor.Role.Permissions = nil
+	for _, permission := range permissions {
+		actor.Role.Permissions = append(actor.Role.Permissions, models.RolePermission{Permission: permission})
+	}
+	return &actor
+}
+
+func TestQuotaLedger_Statement(t *testing.T) {
+	f := setupFixture(t, 0)
+	ledger := quota.NewQuotaLedger(repositories.WithTenant(f.db, f.company.ID))
+	admin := withPermissions(f.user, models.PERMISSION_QUOTAS_MANAGE)
+
+	for _, adjustment := range []struct {
+		amount float64
+		at     time.Time
+	}{{5, time.Date(2025, 1, 10, 9, 0, 0, 0, time.UTC)}, {-1, time.Date(2025, 2, 10, 9, 0, 0, 0, time.UTC)}, {2, time.Date(2025, 3, 10, 9, 0, 0, 0, time.UTC)}} {
+		if _, err := ledger.Adjust(admin, f.user.ID, f.userQuota.QuotaID, models.NewQuotaAmount(adjustment.amount), "correction", adjustment.at); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	from := time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2025, 2, 28, 0, 0, 0, 0, time.UTC)
+	statement, err := ledger.Statement(withPermissions(f.user, models.PERMISSION_QUOTAS_READ_OWN), f.user.ID, f.userQuota.QuotaID, from, to)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if statement.From != "2025-02-01" || statement.To != "2025-02-28" || statement.Name != "vacation" {
+		t.Errorf("expected the february statement of the vacation quota, got %+v", statement)
+	}
+	if statement.OpeningBalance.Units() != 5 || statement.ClosingBalance.Units() != 4 || len(statement.Transactions) != 1 || statement.Transactions[0].Amount.Units() != -1 {
+		t.Errorf("expected february to go from 5 to 4, got %+v", statement)
+	}
+	if statement.Balance.Units() != 6 || !statement.Reconciled {
+		t.Errorf("expected the current balance 6 to reconcile with the ledger, got %+v", statement)
+	}
+
+	// A balance changed behind the back of the ledger no longer reconciles.
+	f.db.Model(f.userQuota).Update("count", models.QuotaUnits(7))
+	statement, err = ledger.Statement(admin, f.user.ID, f.userQuota.QuotaID, from, to)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if statement.Reconciled {
+		t.Errorf("expected the statement not to reconcile, got %+v", statement)
+	}
+}
+
+func TestQuotaLedger_Statement_Requires_Permission(t *testing.T) {
+	f := setupFixture(t, 0)
+	ledger := quota.NewQuotaLedger(repositories.WithTenant(f.db, f.company.ID))
+	colleague := &models.User{Email: "colleague@example.com", Password: "secret", CompanyID: f.company.ID, UserProfile: models.UserProfile{Slug: "colleague"}}
+	servicetest.MustCreate(t, f.db, colleague)
+
+	_, err := ledger.Statement(withPermissions(colleague, models.PERMISSION_QUOTAS_READ_TEAM), f.user.ID, f.userQuota.QuotaID, monday, monday)
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		t.Errorf("expected gorm.ErrRecordNotFound for a user outside the team, got %v", err)
+	}
+
+	f.db.Model(f.user).Update("manager_id", colleague.ID)
+	if _, err := ledger.Statement(withPermissions(colleague, models.PERMISSION_QUOTAS_READ_TEAM), f.user.ID, f.userQuota.QuotaID, monday, monday); err != nil {
+		t.Errorf("expected the manager to read the statement, got %v", err)
+	}
+	if _, err := ledger.Statement(withPermissions(f.user, models.PERMISSION_QUOTAS_READ_OWN), f.user.ID, 999, monday, monday); !errors.Is(err, quota.ErrQuotaNotAssigned) {
+		t.Errorf("expected ErrQuotaNotAssigned, got %v", err)
+	}
+}
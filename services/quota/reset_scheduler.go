@@ -0,0 +1,62 @@
+package quota
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// ResetScheduler runs the QuotaResetter in the background of the server process.
+// It checks for due expiries and resets when it starts, so boundaries passed while the server was down
+// are caught up, and then once every interval.
+type ResetScheduler struct {
+	resetter *QuotaResetter
+	interval time.Duration
+}
+
+// NewResetScheduler creates a ResetScheduler. The database must not be scoped to a tenant.
+func NewResetScheduler(db *gorm.DB, interval time.Duration) *ResetScheduler {
+	return &ResetScheduler{
+		resetter: NewQuotaResetter(db),
+		interval: interval,
+	}
+}
+
+// Start runs the scheduler until the context is cancelled. It returns immediately.
+func (s *ResetScheduler) Start(ctx context.Context) {
+	go func() {
+		ticker := time.NewTicker(s.interval)
+		defer ticker.Stop()
+
+		s.tick(time.Now())
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case now := <-ticker.C:
+				s.tick(now)
+			}
+		}
+	}()
+}
+
+func (s *ResetScheduler) tick(now time.Time) {
+	// Carry-overs expiring at the start of a period expire before the reset absorbs them.
+	carryOvers, err := s.resetter.ExpireDue(now)
+	for _, carryOver := range carryOvers {
+		log.Printf("carry-over %d of user quota %d expired, %s removed", carryOver.ID, carryOver.UserQuotaID, carryOver.Expired)
+	}
+	if err != nil {
+		log.Printf("carry-over expiry failed: %v", err)
+	}
+
+	resets, err := s.resetter.ResetDue(now)
+	for _, reset := range resets {
+		log.Printf("quota %d reset for the period starting %s, %d balances changed", reset.QuotaID, reset.PeriodStart.Format(time.RFC3339), len(reset.Entries))
+	}
+	if err != nil {
+		log.Printf("quota reset failed: %v", err)
+	}
+}
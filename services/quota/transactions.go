@@ -0,0 +1,38 @@
+package quota
+
+import (
+	"time"
+
+	"github.com/r-52/embrace/models"
+	"github.com/r-52/embrace/repositories"
+)
+
+// Every change of a UserQuota balance is appended to the ledger of quota transactions, see models.UserQuotaTransaction.
+
+// record appends a movement that was already applied to the balance of its UserQuota, together with the balance it left.
+func record(uow *repositories.UnitOfWork, transaction *models.UserQuotaTransaction) error {
+	userQuota, err := uow.UserQuotas().GetByID(transaction.UserQuotaID)
+	if err != nil {
+		return err
+	}
+	transaction.UserID = userQuota.UserID
+	transaction.Balance = userQuota.Count
+	return uow.UserQuotaTransactions().Create(transaction)
+}
+
+// newTransaction returns a transaction of the given kind on a UserQuota, booked at `at` by the actor.
+// A nil actor books for the system.
+func newTransaction(kind string, userQuota *models.UserQuota, companyID uint, amount models.QuotaAmount, actor *models.User, at time.Time) *models.UserQuotaTransaction {
+	transaction := &models.UserQuotaTransaction{
+		UserQuotaID: userQuota.ID,
+		UserID:      userQuota.UserID,
+		CompanyID:   companyID,
+		Kind:        kind,
+		Amount:      amount,
+		BookedAt:    at.UTC(),
+	}
+	if actor != nil {
+		transaction.ActorID = &actor.ID
+	}
+	return transaction
+}
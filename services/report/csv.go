@@ -0,0 +1,30 @@
+package report
+
+import (
+	"encoding/csv"
+	"io"
+)
+
+// WriteCSV writes the report as CSV with the delimiter of its locale. The file starts with a byte order mark, so
+// spreadsheet applications detect the UTF-8 encoding.
+func WriteCSV(w io.Writer, report *TimesheetReport) error {
+	if _, err := io.WriteString(w, "\uFEFF"); err != nil {
+		return err
+	}
+	writer := csv.NewWriter(w)
+	writer.Comma = report.Locale.CSVDelimiter
+	if err := writer.Write(report.headers()); err != nil {
+		return err
+	}
+	for i := range report.Rows {
+		record := make([]string, 0, len(report.Columns))
+		for _, column := range report.Columns {
+			record = append(record, report.text(&report.Rows[i], column))
+		}
+		if err := writer.Write(record); err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
@@ -0,0 +1,15 @@
+package report
+
+import "errors"
+
+// ErrUnknownColumn is returned when a timesheet export should contain a column that does not exist.
+var ErrUnknownColumn = errors.New("E10000")
+
+// ErrUnknownLocale is returned when a timesheet export should be formatted for a locale that is not supported.
+var ErrUnknownLocale = errors.New("E10001")
+
+// ErrInvalidReportPeriod is returned when a timesheet export ends before it starts or is longer than MAX_REPORT_DAYS.
+var ErrInvalidReportPeriod = errors.New("E10002")
+
+// ErrReportTooLarge is returned when a timesheet export covers more than MAX_REPORT_USER_DAYS days of all its users together.
+var ErrReportTooLarge = errors.New("E10003")
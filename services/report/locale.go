@@ -0,0 +1,140 @@
+package report
+
+import (
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Locale is the formatting of a timesheet export: how dates, times and numbers are written, how CSV fields are
+// separated and what the headers and summary rows are called. XLSX cells keep dates and numbers as values and
+// only take the display formats from the locale.
+type Locale struct {
+	DateLayout       string
+	TimeLayout       string
+	DecimalSeparator string
+	CSVDelimiter     rune
+	XLSXDateFormat   string
+	XLSXTimeFormat   string
+	Yes              string
+	No               string
+	Headers          map[string]string
+	DailySubtotal    string
+	WeeklySubtotal   string
+	// Overtime labels the overtime summary row and takes the worked and the target hours.
+	Overtime string
+}
+
+const DEFAULT_LOCALE = "en"
+
+// LOCALES are the supported locales by name.
+var LOCALES = map[string]*Locale{
+	"en": {
+		DateLayout:       "2006-01-02",
+		TimeLayout:       "15:04",
+		DecimalSeparator: ".",
+		CSVDelimiter:     ',',
+		XLSXDateFormat:   "yyyy-mm-dd",
+		XLSXTimeFormat:   "hh:mm",
+		Yes:              "yes",
+		No:               "no",
+		Headers:          englishHeaders,
+		DailySubtotal:    "Daily subtotal",
+		WeeklySubtotal:   "Weekly subtotal",
+		Overtime:         "Overtime (worked %s h, target %s h)",
+	},
+	"en-US": {
+		DateLayout:       "01/02/2006",
+		TimeLayout:       "3:04 PM",
+		DecimalSeparator: ".",
+		CSVDelimiter:     ',',
+		XLSXDateFormat:   "mm/dd/yyyy",
+		XLSXTimeFormat:   "h:mm AM/PM",
+		Yes:              "yes",
+		No:               "no",
+		Headers:          englishHeaders,
+		DailySubtotal:    "Daily subtotal",
+		WeeklySubtotal:   "Weekly subtotal",
+		Overtime:         "Overtime (worked %s h, target %s h)",
+	},
+	"de": {
+		DateLayout:       "02.01.2006",
+		TimeLayout:       "15:04",
+		DecimalSeparator: ",",
+		CSVDelimiter:     ';',
+		XLSXDateFormat:   "dd.mm.yyyy",
+		XLSXTimeFormat:   "hh:mm",
+		Yes:              "ja",
+		No:               "nein",
+		Headers: map[string]string{
+			COLUMN_DATE:     "Datum",
+			COLUMN_USER:     "Mitarbeiter",
+			COLUMN_TYPE:     "Art",
+			COLUMN_BILLABLE: "Abrechenbar",
+			COLUMN_START:    "Beginn",
+			COLUMN_END:      "Ende",
+			COLUMN_DURATION: "Dauer (h)",
+			COLUMN_NOTE:     "Notiz",
+		},
+		DailySubtotal:  "Tagessumme",
+		WeeklySubtotal: "Wochensumme",
+		Overtime:       "Überstunden (gearbeitet %s h, Soll %s h)",
+	},
+	"fr": {
+		DateLayout:       "02/01/2006",
+		TimeLayout:       "15:04",
+		DecimalSeparator: ",",
+		CSVDelimiter:     ';',
+		XLSXDateFormat:   "dd/mm/yyyy",
+		XLSXTimeFormat:   "hh:mm",
+		Yes:              "oui",
+		No:               "non",
+		Headers: map[string]string{
+			COLUMN_DATE:     "Date",
+			COLUMN_USER:     "Employé",
+			COLUMN_TYPE:     "Type",
+			COLUMN_BILLABLE: "Facturable",
+			COLUMN_START:    "Début",
+			COLUMN_END:      "Fin",
+			COLUMN_DURATION: "Durée (h)",
+			COLUMN_NOTE:     "Note",
+		},
+		DailySubtotal:  "Sous-total journalier",
+		WeeklySubtotal: "Sous-total hebdomadaire",
+		Overtime:       "Heures supplémentaires (travaillées %s h, prévues %s h)",
+	},
+}
+
+var englishHeaders = map[string]string{
+	COLUMN_DATE:     "Date",
+	COLUMN_USER:     "User",
+	COLUMN_TYPE:     "Type",
+	COLUMN_BILLABLE: "Billable",
+	COLUMN_START:    "Start",
+	COLUMN_END:      "End",
+	COLUMN_DURATION: "Duration (h)",
+	COLUMN_NOTE:     "Note",
+}
+
+// FormatDate writes the calendar day of t.
+func (l *Locale) FormatDate(t time.Time) string {
+	return t.Format(l.DateLayout)
+}
+
+// FormatTime writes the time of day of t.
+func (l *Locale) FormatTime(t time.Time) string {
+	return t.Format(l.TimeLayout)
+}
+
+// FormatHours writes hours with two decimals.
+func (l *Locale) FormatHours(hours float64) string {
+	return strings.Replace(strconv.FormatFloat(hours, 'f', 2, 64), ".", l.DecimalSeparator, 1)
+}
+
+// FormatBool writes yes or no.
+func (l *Locale) FormatBool(value bool) string {
+	if value {
+		return l.Yes
+	}
+	return l.No
+}
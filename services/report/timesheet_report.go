@@ -0,0 +1,135 @@
+package report
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// COLUMN constants are the columns of a timesheet export.
+const COLUMN_DATE = "date"
+const COLUMN_USER = "user"
+const COLUMN_TYPE = "type"
+const COLUMN_BILLABLE = "billable"
+const COLUMN_START = "start"
+const COLUMN_END = "end"
+const COLUMN_DURATION = "duration"
+const COLUMN_NOTE = "note"
+
+// ALL_COLUMNS lists the columns of a timesheet export in their default order.
+var ALL_COLUMNS = []string{COLUMN_DATE, COLUMN_USER, COLUMN_TYPE, COLUMN_BILLABLE, COLUMN_START, COLUMN_END, COLUMN_DURATION, COLUMN_NOTE}
+
+// ROW constants are the kinds of TimesheetRow.
+const ROW_ENTRY = "entry"
+const ROW_DAILY_SUBTOTAL = "daily_subtotal"
+const ROW_WEEKLY_SUBTOTAL = "weekly_subtotal"
+const ROW_OVERTIME = "overtime"
+
+// TimesheetRow is a row of a timesheet export: a time entry, the subtotal of a day or a week, or the overtime
+// summary of a user. Date is the day of the entry or the subtotal, the Monday of a weekly subtotal and the first day
+// of the export for the overtime summary. Hours are the duration of an entry, the sum of the durations of a
+// subtotal and the overtime of a summary, which also has the WorkedHours and TargetHours it was computed from.
+type TimesheetRow struct {
+	Kind        string
+	Date        time.Time
+	User        string
+	Type        string
+	Billable    bool
+	Start       time.Time
+	End         *time.Time
+	Hours       *float64
+	Note        string
+	WorkedHours float64
+	TargetHours float64
+}
+
+// TimesheetReport is a timesheet export before it is written as CSV or XLSX. Times are in the company's timezone.
+type TimesheetReport struct {
+	From    time.Time
+	To      time.Time
+	Format  string
+	Columns []string
+	Locale  *Locale
+	Rows    []TimesheetRow
+}
+
+// headers returns the names of the columns of the report in its locale.
+func (r *TimesheetReport) headers() []string {
+	headers := make([]string, 0, len(r.Columns))
+	for _, column := range r.Columns {
+		headers = append(headers, r.Locale.Headers[column])
+	}
+	return headers
+}
+
+// text returns the cell of a row in a column as text formatted for the locale of the report.
+func (r *TimesheetReport) text(row *TimesheetRow, column string) string {
+	locale := r.Locale
+	switch column {
+	case COLUMN_DATE:
+		return locale.FormatDate(row.Date)
+	case COLUMN_USER:
+		return escapeFormula(row.User)
+	case COLUMN_DURATION:
+		if row.Hours == nil {
+			return ""
+		}
+		return locale.FormatHours(*row.Hours)
+	}
+	if row.Kind != ROW_ENTRY {
+		if column == r.labelColumn() {
+			return r.label(row)
+		}
+		return ""
+	}
+	switch column {
+	case COLUMN_TYPE:
+		return escapeFormula(row.Type)
+	case COLUMN_BILLABLE:
+		return locale.FormatBool(row.Billable)
+	case COLUMN_START:
+		return locale.FormatTime(row.Start)
+	case COLUMN_END:
+		if row.End == nil {
+			return ""
+		}
+		return locale.FormatTime(*row.End)
+	case COLUMN_NOTE:
+		return escapeFormula(row.Note)
+	}
+	return ""
+}
+
+// labelColumn returns the column that names subtotal and summary rows: the type if it is exported, or else the
+// first exported column they have no other value in. Without such a column the rows are not named.
+func (r *TimesheetReport) labelColumn() string {
+	for _, candidate := range []string{COLUMN_TYPE, COLUMN_NOTE, COLUMN_BILLABLE, COLUMN_START, COLUMN_END} {
+		for _, column := range r.Columns {
+			if column == candidate {
+				return column
+			}
+		}
+	}
+	return ""
+}
+
+// label returns the name of a subtotal or summary row in the locale of the report.
+func (r *TimesheetReport) label(row *TimesheetRow) string {
+	switch row.Kind {
+	case ROW_DAILY_SUBTOTAL:
+		return r.Locale.DailySubtotal
+	case ROW_WEEKLY_SUBTOTAL:
+		return r.Locale.WeeklySubtotal
+	case ROW_OVERTIME:
+		return fmt.Sprintf(r.Locale.Overtime, r.Locale.FormatHours(row.WorkedHours), r.Locale.FormatHours(row.TargetHours))
+	}
+	return ""
+}
+
+// escapeFormula keeps spreadsheet applications from evaluating text that users entered as a formula.
+func escapeFormula(text string) string {
+	if text != "" && strings.ContainsRune("=+-@\t\r", rune(text[0])) {
+		return "'" + text
+	}
+	return text
+}
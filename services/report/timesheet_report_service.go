@@ -0,0 +1,258 @@
+package report
+
+import (
+	"errors"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/r-52/embrace/models"
+	overtimedto "github.com/r-52/embrace/models/dto/overtime"
+	dto "github.com/r-52/embrace/models/dto/report"
+	"github.com/r-52/embrace/repositories"
+	"github.com/r-52/embrace/services/overtime"
+	"gorm.io/gorm"
+)
+
+// MAX_REPORT_DAYS is the number of calendar days a single timesheet export may cover at most.
+const MAX_REPORT_DAYS = 366
+
+// MAX_REPORT_USER_DAYS limits the days of all users of a timesheet export together, a year for a team of 50.
+const MAX_REPORT_USER_DAYS = 50 * MAX_REPORT_DAYS
+
+// TimesheetReportService exports the time entries of users for the accounting.
+type TimesheetReportService struct {
+	unitOfWork *repositories.UnitOfWork
+}
+
+type TimesheetReportServiceInterface interface {
+	Report(actor *models.User, req *dto.TimesheetReportRequest, now time.Time) (*TimesheetReport, error)
+}
+
+// NewTimesheetReportService creates a TimesheetReportService. The database should be scoped to the actor's company, see repositories.WithTenant.
+func NewTimesheetReportService(db *gorm.DB) *TimesheetReportService {
+	return NewTimesheetReportServiceWithUnitOfWork(repositories.NewUnitOfWork(db))
+}
+
+// NewTimesheetReportServiceWithUnitOfWork creates a TimesheetReportService whose repositories join the given unit of work.
+func NewTimesheetReportServiceWithUnitOfWork(uow *repositories.UnitOfWork) *TimesheetReportService {
+	return &TimesheetReportService{
+		unitOfWork: uow,
+	}
+}
+
+// Report collects the time entries of the requested users that start within the period, ordered by user and start
+// time. The entries of every day are followed by their daily subtotal, the last day of a week by the weekly
+// subtotal and the entries of a user by the overtime summary of the period, see overtime.OvertimeService.
+// Running timers are listed without end and duration. Users the actor may not read are reported as
+// gorm.ErrRecordNotFound. It returns ErrUnknownColumn or ErrUnknownLocale for columns or locales that do not exist,
+// ErrInvalidReportPeriod for periods longer than MAX_REPORT_DAYS and ErrReportTooLarge for teams whose days
+// exceed MAX_REPORT_USER_DAYS.
+func (s *TimesheetReportService) Report(actor *models.User, req *dto.TimesheetReportRequest, now time.Time) (*TimesheetReport, error) {
+	from := time.Date(req.From.Year(), req.From.Month(), req.From.Day(), 0, 0, 0, 0, time.UTC)
+	to := time.Date(req.To.Year(), req.To.Month(), req.To.Day(), 0, 0, 0, 0, time.UTC)
+	if to.Before(from) || !to.Before(from.AddDate(0, 0, MAX_REPORT_DAYS)) {
+		return nil, ErrInvalidReportPeriod
+	}
+	columns, err := columnsOf(req.Columns)
+	if err != nil {
+		return nil, err
+	}
+	locale, err := localeOf(req.Locale)
+	if err != nil {
+		return nil, err
+	}
+	users, err := s.usersOf(actor, req)
+	if err != nil {
+		return nil, err
+	}
+	days := int(to.Sub(from).Hours()/24) + 1
+	if len(users)*days > MAX_REPORT_USER_DAYS {
+		return nil, ErrReportTooLarge
+	}
+	company, err := s.unitOfWork.Companies().GetByID(actor.CompanyID)
+	if err != nil {
+		return nil, err
+	}
+	timeEntryTypes, err := s.unitOfWork.TimeEntryTypes().GetByCompanyID(actor.CompanyID)
+	if err != nil {
+		return nil, err
+	}
+	typesByID := map[uint]*models.TimeEntryType{}
+	for i := range timeEntryTypes {
+		typesByID[timeEntryTypes[i].ID] = &timeEntryTypes[i]
+	}
+
+	format := req.Format
+	if format == "" {
+		format = dto.TIMESHEET_FORMAT_CSV
+	}
+	location := company.Location()
+	report := &TimesheetReport{
+		From:    time.Date(req.From.Year(), req.From.Month(), req.From.Day(), 0, 0, 0, 0, location),
+		To:      time.Date(req.To.Year(), req.To.Month(), req.To.Day(), 0, 0, 0, 0, location),
+		Format:  format,
+		Columns: columns,
+		Locale:  locale,
+		Rows:    []TimesheetRow{},
+	}
+	for _, user := range users {
+		name, err := s.nameOf(user)
+		if err != nil {
+			return nil, err
+		}
+		entries, err := s.unitOfWork.TimeEntries().GetByUserIDAndDateRange(user.ID, report.From, report.To.AddDate(0, 0, 1))
+		if err != nil {
+			return nil, err
+		}
+		report.Rows = append(report.Rows, entryRows(entries, name, typesByID, location)...)
+
+		overtimeResponse, err := overtime.NewOvertimeServiceWithUnitOfWork(s.unitOfWork).
+			Overtime(actor, user.ID, &overtimedto.OvertimeRequest{From: req.From, To: req.To}, now)
+		if err != nil {
+			return nil, err
+		}
+		report.Rows = append(report.Rows, TimesheetRow{
+			Kind:        ROW_OVERTIME,
+			Date:        report.From,
+			User:        name,
+			Hours:       &overtimeResponse.Overtime,
+			WorkedHours: overtimeResponse.WorkedHours,
+			TargetHours: overtimeResponse.TargetHours,
+		})
+	}
+	return report, nil
+}
+
+// usersOf returns the users of the export: the requested user, the team of the requested manager or the actor.
+func (s *TimesheetReportService) usersOf(actor *models.User, req *dto.TimesheetReportRequest) ([]*models.User, error) {
+	var users []*models.User
+	switch {
+	case req.ManagerID != 0:
+		if _, err := s.unitOfWork.Users().GetByID(req.ManagerID); err != nil {
+			return nil, err
+		}
+		team, err := s.unitOfWork.Users().GetByManagerID(req.ManagerID)
+		if err != nil {
+			return nil, err
+		}
+		users = team
+	case req.UserID != 0:
+		user, err := s.unitOfWork.Users().GetByID(req.UserID)
+		if err != nil {
+			return nil, err
+		}
+		users = []*models.User{user}
+	default:
+		users = []*models.User{actor}
+	}
+	for _, user := range users {
+		if !actor.CanAccess(user, models.PERMISSION_TIME_ENTRIES_READ) {
+			return nil, gorm.ErrRecordNotFound
+		}
+	}
+	return users, nil
+}
+
+// nameOf returns the full name of a user, or their email address if their profile has no name.
+func (s *TimesheetReportService) nameOf(user *models.User) (string, error) {
+	profile, err := s.unitOfWork.UserProfiles().GetByID(user.UserProfileID)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return "", err
+	}
+	if profile != nil {
+		if name := strings.TrimSpace(profile.FirstName + " " + profile.LastName); name != "" {
+			return name, nil
+		}
+	}
+	return user.Email, nil
+}
+
+// entryRows returns the rows of the entries of a user ordered by start time with the daily and weekly subtotals.
+// Days and weeks are taken in the company's timezone, weeks start on Monday.
+func entryRows(entries []models.TimeEntry, name string, typesByID map[uint]*models.TimeEntryType, location *time.Location) []TimesheetRow {
+	rows := []TimesheetRow{}
+	var day, week time.Time
+	var dayHours, weekHours float64
+	closeDay := func() {
+		hours := roundHours(dayHours)
+		rows = append(rows, TimesheetRow{Kind: ROW_DAILY_SUBTOTAL, Date: day, User: name, Hours: &hours})
+		dayHours = 0
+	}
+	closeWeek := func() {
+		hours := roundHours(weekHours)
+		rows = append(rows, TimesheetRow{Kind: ROW_WEEKLY_SUBTOTAL, Date: week, User: name, Hours: &hours})
+		weekHours = 0
+	}
+
+	for _, entry := range entries {
+		start := entry.StartTime.In(location)
+		entryDay := time.Date(start.Year(), start.Month(), start.Day(), 0, 0, 0, 0, location)
+		entryWeek := entryDay.AddDate(0, 0, -(int(entryDay.Weekday())+6)%7)
+		if !day.IsZero() && !entryDay.Equal(day) {
+			closeDay()
+			if !entryWeek.Equal(week) {
+				closeWeek()
+			}
+		}
+		day, week = entryDay, entryWeek
+
+		row := TimesheetRow{Kind: ROW_ENTRY, Date: entryDay, User: name, Start: start, Note: entry.Note}
+		if timeEntryType, ok := typesByID[entry.TimeEntryTypeID]; ok {
+			row.Type = timeEntryType.Name
+			row.Billable = timeEntryType.IsBillable
+		}
+		if entry.EndTime.Valid {
+			end := entry.EndTime.Time.In(location)
+			row.End = &end
+		}
+		if entry.Duration.Valid {
+			hours := entry.Duration.Float64
+			row.Hours = &hours
+			dayHours += hours
+			weekHours += hours
+		}
+		rows = append(rows, row)
+	}
+	if !day.IsZero() {
+		closeDay()
+		closeWeek()
+	}
+	return rows
+}
+
+// columnsOf parses a comma separated list of columns. An empty list selects ALL_COLUMNS.
+func columnsOf(list string) ([]string, error) {
+	if strings.TrimSpace(list) == "" {
+		return ALL_COLUMNS, nil
+	}
+	columns := []string{}
+	for _, column := range strings.Split(list, ",") {
+		column = strings.TrimSpace(column)
+		known := false
+		for _, candidate := range ALL_COLUMNS {
+			known = known || candidate == column
+		}
+		if !known {
+			return nil, ErrUnknownColumn
+		}
+		columns = append(columns, column)
+	}
+	return columns, nil
+}
+
+// localeOf returns the locale with the name, or the DEFAULT_LOCALE for an empty name.
+func localeOf(name string) (*Locale, error) {
+	if name == "" {
+		name = DEFAULT_LOCALE
+	}
+	locale, ok := LOCALES[name]
+	if !ok {
+		return nil, ErrUnknownLocale
+	}
+	return locale, nil
+}
+
+func roundHours(hours float64) float64 {
+	return math.Round(hours*100) / 100
+}
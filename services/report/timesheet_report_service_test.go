@@ -0,0 +1,194 @@
+package report_test
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/r-52/embrace/models"
+	dto "github.com/r-52/embrace/models/dto/report"
+	"github.com/r-52/embrace/repositories"
+	"github.com/r-52/embrace/services/report"
+	"github.com/r-52/embrace/services/role"
+	"github.com/r-52/embrace/services/servicetest"
+	"gorm.io/gorm"
+)
+
+type fixture struct {
+	db        *gorm.DB
+	companyID uint
+	manager   *models.User
+	employee  *models.User
+	colleague *models.User
+	work      *models.TimeEntryType
+}
+
+func setupFixture(t *testing.T) *fixture {
+	db := servicetest.Database(t, &models.Company{}, &models.User{}, &models.UserProfile{}, &models.UserRole{}, &models.RolePermission{},
+		&models.TimeEntryType{}, &models.TimeEntry{}, &models.HolidayCalendar{}, &models.Holiday{}, &models.WorkSchedule{},
+		&models.WorkScheduleDay{}, &models.UserWorkSchedule{}, &models.LeaveRequest{}, &models.OvertimePolicy{}, &models.OvertimeWorkTimeType{})
+
+	f := &fixture{db: db}
+	f.companyID = servicetest.Company(t, db, "acme").ID
+	f.manager = servicetest.User(t, db, f.companyID, role.DEFAULT_ROLE_MANAGER, "manager", nil)
+	f.employee = servicetest.User(t, db, f.companyID, role.DEFAULT_ROLE_EMPLOYEE, "employee", &f.manager.ID)
+	f.colleague = servicetest.User(t, db, f.companyID, role.DEFAULT_ROLE_EMPLOYEE, "colleague", nil)
+	f.work = &models.TimeEntryType{Name: "Work", Color: "#0000ff", CompanyID: f.companyID, IsBillable: true}
+	servicetest.MustCreate(t, db, f.work)
+	return f
+}
+
+func (f *fixture) service() *report.TimesheetReportService {
+	return report.NewTimesheetReportService(repositories.WithTenant(f.db, f.companyID))
+}
+
+// book creates an entry of the employee, a running one without hours.
+func (f *fixture) book(t *testing.T, start time.Time, hours float64, note string) {
+	entry := &models.TimeEntry{CompanyID: f.companyID, UserID: f.employee.ID, TimeEntryTypeID: f.work.ID, StartTime: start, Note: note}
+	if hours > 0 {
+		entry.Close(start.Add(time.Duration(hours * float64(time.Hour))))
+	}
+	servicetest.MustCreate(t, f.db, entry)
+}
+
+var monday = time.Date(2024, 3, 4, 8, 0, 0, 0, time.UTC)
+
+// bookTwoWeeks books 7.5 hours on Monday, 8 on Tuesday, 2 on the following Monday and a running timer the day after.
+func (f *fixture) bookTwoWeeks(t *testing.T) {
+	f.book(t, monday, 4, "=SUM(A1)")
+	f.book(t, monday.Add(5*time.Hour), 3.5, "afternoon")
+	f.book(t, monday.AddDate(0, 0, 1), 8, "")
+	f.book(t, monday.AddDate(0, 0, 7), 2, "")
+	f.book(t, monday.AddDate(0, 0, 8), 0, "running")
+}
+
+func twoWeeks() *dto.TimesheetReportRequest {
+	return &dto.TimesheetReportRequest{From: monday.Truncate(24 * time.Hour), To: monday.AddDate(0, 0, 8).Truncate(24 * time.Hour)}
+}
+
+func TestTimesheetReportService_Report(t *testing.T) {
+	f := setupFixture(t)
+	f.bookTwoWeeks(t)
+
+	timesheet, err := f.service().Report(f.employee, twoWeeks(), monday)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if timesheet.Format != dto.TIMESHEET_FORMAT_CSV || len(timesheet.Columns) != len(report.ALL_COLUMNS) || timesheet.Locale != report.LOCALES["en"] {
+		t.Errorf("unexpected defaults: %+v", timesheet)
+	}
+	expected := []struct {
+		kind  string
+		day   int
+		hours float64
+	}{
+		{report.ROW_ENTRY, 4, 4},
+		{report.ROW_ENTRY, 4, 3.5},
+		{report.ROW_DAILY_SUBTOTAL, 4, 7.5},
+		{report.ROW_ENTRY, 5, 8},
+		{report.ROW_DAILY_SUBTOTAL, 5, 8},
+		{report.ROW_WEEKLY_SUBTOTAL, 4, 15.5},
+		{report.ROW_ENTRY, 11, 2},
+		{report.ROW_DAILY_SUBTOTAL, 11, 2},
+		{report.ROW_ENTRY, 12, -1},
+		{report.ROW_DAILY_SUBTOTAL, 12, 0},
+		{report.ROW_WEEKLY_SUBTOTAL, 11, 2},
+		{report.ROW_OVERTIME, 4, -1},
+	}
+	if len(timesheet.Rows) != len(expected) {
+		t.Fatalf("expected %d rows, got %+v", len(expected), timesheet.Rows)
+	}
+	for i, row := range timesheet.Rows {
+		if row.Kind != expected[i].kind || row.Date.Day() != expected[i].day || row.User != "Erika employee" {
+			t.Errorf("unexpected row %d: %+v", i, row)
+		}
+		if expected[i].hours >= 0 && (row.Hours == nil || *row.Hours != expected[i].hours) {
+			t.Errorf("expected row %d to have %v hours, got %+v", i, expected[i].hours, row)
+		}
+	}
+	if entry := timesheet.Rows[0]; entry.Type != "Work" || !entry.Billable || entry.End == nil || entry.Note != "=SUM(A1)" {
+		t.Errorf("unexpected entry: %+v", entry)
+	}
+	if running := timesheet.Rows[8]; running.End != nil || running.Hours != nil {
+		t.Errorf("expected the running timer without end and hours, got %+v", running)
+	}
+	if summary := timesheet.Rows[11]; *summary.Hours != summary.WorkedHours-summary.TargetHours || summary.TargetHours == 0 {
+		t.Errorf("unexpected overtime summary: %+v", summary)
+	}
+}
+
+func TestTimesheetReportService_Report_Users(t *testing.T) {
+	f := setupFixture(t)
+	f.bookTwoWeeks(t)
+
+	req := twoWeeks()
+	req.ManagerID = f.manager.ID
+	team, err := f.service().Report(f.manager, req, monday)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(team.Rows) != 12 || team.Rows[0].User != "Erika employee" {
+		t.Errorf("expected the rows of the manager's team, got %+v", team.Rows)
+	}
+	if _, err := f.service().Report(f.colleague, req, monday); !errors.Is(err, gorm.ErrRecordNotFound) {
+		t.Errorf("expected ErrRecordNotFound for another team, got %v", err)
+	}
+
+	req = twoWeeks()
+	req.UserID = f.employee.ID
+	if _, err := f.service().Report(f.colleague, req, monday); !errors.Is(err, gorm.ErrRecordNotFound) {
+		t.Errorf("expected ErrRecordNotFound for a colleague, got %v", err)
+	}
+	own, err := f.service().Report(f.colleague, twoWeeks(), monday)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(own.Rows) != 1 || own.Rows[0].Kind != report.ROW_OVERTIME || own.Rows[0].User != "Erika colleague" {
+		t.Errorf("expected only the overtime summary of the colleague, got %+v", own.Rows)
+	}
+
+	req = twoWeeks()
+	req.Columns = "date,hours"
+	if _, err := f.service().Report(f.employee, req, monday); !errors.Is(err, report.ErrUnknownColumn) {
+		t.Errorf("expected ErrUnknownColumn, got %v", err)
+	}
+	req = twoWeeks()
+	req.Locale = "xx"
+	if _, err := f.service().Report(f.employee, req, monday); !errors.Is(err, report.ErrUnknownLocale) {
+		t.Errorf("expected ErrUnknownLocale, got %v", err)
+	}
+}
+
+func TestTimesheetReportService_Report_Limits(t *testing.T) {
+	f := setupFixture(t)
+
+	req := twoWeeks()
+	req.To = req.From.AddDate(0, 0, report.MAX_REPORT_DAYS-1)
+	if _, err := f.service().Report(f.employee, req, monday); err != nil {
+		t.Fatalf("unexpected error for a period of MAX_REPORT_DAYS: %v", err)
+	}
+	req.To = req.To.AddDate(0, 0, 1)
+	if _, err := f.service().Report(f.employee, req, monday); !errors.Is(err, report.ErrInvalidReportPeriod) {
+		t.Errorf("expected ErrInvalidReportPeriod for a longer period, got %v", err)
+	}
+	req.To = req.From.AddDate(0, 0, -1)
+	if _, err := f.service().Report(f.employee, req, monday); !errors.Is(err, report.ErrInvalidReportPeriod) {
+		t.Errorf("expected ErrInvalidReportPeriod for a period that ends before it starts, got %v", err)
+	}
+
+	// A year of the manager's team exceeds the limit once it has more than 50 members.
+	for i := 0; i < report.MAX_REPORT_USER_DAYS/report.MAX_REPORT_DAYS; i++ {
+		servicetest.User(t, f.db, f.companyID, role.DEFAULT_ROLE_EMPLOYEE, fmt.Sprintf("member%d", i), &f.manager.ID)
+	}
+	req = twoWeeks()
+	req.ManagerID = f.manager.ID
+	req.To = req.From.AddDate(0, 0, report.MAX_REPORT_DAYS-1)
+	if _, err := f.service().Report(f.manager, req, monday); !errors.Is(err, report.ErrReportTooLarge) {
+		t.Errorf("expected ErrReportTooLarge, got %v", err)
+	}
+	req.To = req.From.AddDate(0, 0, 13)
+	if _, err := f.service().Report(f.manager, req, monday); err != nil {
+		t.Errorf("unexpected error for two weeks of the team: %v", err)
+	}
+}
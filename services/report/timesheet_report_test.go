@@ -0,0 +1,88 @@
+package report_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/r-52/embrace/services/report"
+	"github.com/xuri/excelize/v2"
+)
+
+func TestWriteCSV(t *testing.T) {
+	f := setupFixture(t)
+	f.bookTwoWeeks(t)
+	req := twoWeeks()
+	req.Columns = "date, type, duration, note"
+	req.Locale = "de"
+	timesheet, err := f.service().Report(f.employee, req, monday)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var out bytes.Buffer
+	if err := report.WriteCSV(&out, timesheet); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	lines := strings.Split(strings.TrimSuffix(out.String(), "\n"), "\n")
+	expected := []string{
+		"\uFEFFDatum;Art;Dauer (h);Notiz",
+		"04.03.2024;Work;4,00;'=SUM(A1)",
+		"04.03.2024;Work;3,50;afternoon",
+		"04.03.2024;Tagessumme;7,50;",
+		"05.03.2024;Work;8,00;",
+		"05.03.2024;Tagessumme;8,00;",
+		"04.03.2024;Wochensumme;15,50;",
+	}
+	if len(lines) != len(timesheet.Rows)+1 {
+		t.Fatalf("expected a line per row, got %q", lines)
+	}
+	for i, line := range expected {
+		if lines[i] != line {
+			t.Errorf("expected line %d to be %q, got %q", i, line, lines[i])
+		}
+	}
+	if summary := lines[len(lines)-1]; !strings.HasPrefix(summary, "04.03.2024;Überstunden (gearbeitet ") {
+		t.Errorf("expected the overtime summary last, got %q", summary)
+	}
+}
+
+func TestWriteXLSX(t *testing.T) {
+	f := setupFixture(t)
+	f.bookTwoWeeks(t)
+	req := twoWeeks()
+	req.Format = "xlsx"
+	timesheet, err := f.service().Report(f.employee, req, monday)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var out bytes.Buffer
+	if err := report.WriteXLSX(&out, timesheet); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	workbook, err := excelize.OpenReader(&out)
+	if err != nil {
+		t.Fatalf("failed to open workbook: %v", err)
+	}
+	defer workbook.Close()
+
+	for cell, expected := range map[string]string{
+		"A1": "Date", "G1": "Duration (h)",
+		"A2": "2024-03-04", "C2": "Work", "D2": "yes", "E2": "08:00", "F2": "12:00", "G2": "4.00", "H2": "'=SUM(A1)",
+		"C4": "Daily subtotal", "G4": "7.50",
+		"F10": "", "G10": "",
+	} {
+		value, err := workbook.GetCellValue("Timesheet", cell)
+		if err != nil {
+			t.Fatalf("failed to read %s: %v", cell, err)
+		}
+		if value != expected {
+			t.Errorf("expected %s to be %q, got %q", cell, expected, value)
+		}
+	}
+	raw, err := workbook.GetCellValue("Timesheet", "G7", excelize.Options{RawCellValue: true})
+	if err != nil || raw != "15.5" {
+		t.Errorf("expected the weekly subtotal as a number, got %q, %v", raw, err)
+	}
+}
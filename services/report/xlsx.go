@@ -0,0 +1,128 @@
+package report
+
+import (
+	"io"
+	"time"
+
+	"github.com/xuri/excelize/v2"
+)
+
+const xlsxSheet = "Timesheet"
+
+// WriteXLSX writes the report as a workbook with a single sheet. Dates, times and hours are stored as values in the
+// display formats of the locale of the report, so they can be calculated with. Subtotal and summary rows are bold.
+func WriteXLSX(w io.Writer, report *TimesheetReport) error {
+	f := excelize.NewFile()
+	defer f.Close()
+	if err := f.SetSheetName(f.GetSheetName(0), xlsxSheet); err != nil {
+		return err
+	}
+	styles, err := newXLSXStyles(f, report.Locale)
+	if err != nil {
+		return err
+	}
+
+	headers := report.headers()
+	for i, header := range headers {
+		if err := setXLSXCell(f, i+1, 1, header, styles.text[true]); err != nil {
+			return err
+		}
+	}
+	for i := range report.Rows {
+		row := &report.Rows[i]
+		bold := row.Kind != ROW_ENTRY
+		for j, column := range report.Columns {
+			value, style := xlsxValue(report, row, column, styles)
+			if err := setXLSXCell(f, j+1, i+2, value, style[bold]); err != nil {
+				return err
+			}
+		}
+	}
+	if len(headers) > 0 {
+		last, err := excelize.ColumnNumberToName(len(headers))
+		if err != nil {
+			return err
+		}
+		if err := f.SetColWidth(xlsxSheet, "A", last, 16); err != nil {
+			return err
+		}
+	}
+	return f.Write(w)
+}
+
+// xlsxStyles are the cell styles of a workbook by whether they are bold.
+type xlsxStyles struct {
+	text  map[bool]int
+	date  map[bool]int
+	time  map[bool]int
+	hours map[bool]int
+}
+
+func newXLSXStyles(f *excelize.File, locale *Locale) (*xlsxStyles, error) {
+	hoursFormat := "0.00"
+	styles := &xlsxStyles{}
+	for _, style := range []struct {
+		target *map[bool]int
+		format *string
+	}{
+		{&styles.text, nil},
+		{&styles.date, &locale.XLSXDateFormat},
+		{&styles.time, &locale.XLSXTimeFormat},
+		{&styles.hours, &hoursFormat},
+	} {
+		*style.target = map[bool]int{}
+		for _, bold := range []bool{false, true} {
+			id, err := f.NewStyle(&excelize.Style{Font: &excelize.Font{Bold: bold}, CustomNumFmt: style.format})
+			if err != nil {
+				return nil, err
+			}
+			(*style.target)[bold] = id
+		}
+	}
+	return styles, nil
+}
+
+// xlsxValue returns the value of a row in a column and its style. Dates, times and hours are kept as values, the
+// remaining cells are the text of the CSV export.
+func xlsxValue(report *TimesheetReport, row *TimesheetRow, column string, styles *xlsxStyles) (interface{}, map[bool]int) {
+	switch column {
+	case COLUMN_DATE:
+		return wallClock(row.Date), styles.date
+	case COLUMN_DURATION:
+		if row.Hours == nil {
+			return nil, styles.hours
+		}
+		return *row.Hours, styles.hours
+	}
+	if row.Kind == ROW_ENTRY {
+		switch column {
+		case COLUMN_START:
+			return wallClock(row.Start), styles.time
+		case COLUMN_END:
+			if row.End == nil {
+				return nil, styles.time
+			}
+			return wallClock(*row.End), styles.time
+		}
+	}
+	return report.text(row, column), styles.text
+}
+
+func setXLSXCell(f *excelize.File, column, row int, value interface{}, style int) error {
+	cell, err := excelize.CoordinatesToCellName(column, row)
+	if err != nil {
+		return err
+	}
+	if value != nil {
+		if err := f.SetCellValue(xlsxSheet, cell, value); err != nil {
+			return err
+		}
+	}
+	return f.SetCellStyle(xlsxSheet, cell, cell, style)
+}
+
+// wallClock returns the time shown by a clock in the timezone of t as UTC. Spreadsheets have no timezones and
+// excelize converts times from UTC.
+func wallClock(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), time.UTC)
+}
@@ -0,0 +1,43 @@
+package role
+
+import "github.com/r-52/embrace/models"
+
+const DEFAULT_ROLE_ADMIN = "admin"
+const DEFAULT_ROLE_MANAGER = "manager"
+const DEFAULT_ROLE_EMPLOYEE = "employee"
+
+// DefaultRole describes a role every company is seeded with.
+type DefaultRole struct {
+	Name          string
+	InternalUsage int
+	Permissions   []string
+}
+
+// DEFAULT_ROLES are created for every company. Their permissions can be changed, their names cannot.
+var DEFAULT_ROLES = []DefaultRole{
+	{
+		Name:          DEFAULT_ROLE_ADMIN,
+		InternalUsage: models.ROLE_INTERNAL_USAGE_ADMIN,
+		Permissions:   models.ALL_PERMISSIONS,
+	},
+	{
+		Name:          DEFAULT_ROLE_MANAGER,
+		InternalUsage: models.ROLE_INTERNAL_USAGE_MANAGER,
+		Permissions: []string{
+			models.PERMISSION_TIME_ENTRIES_READ_TEAM,
+			models.PERMISSION_TIME_ENTRIES_WRITE_TEAM,
+			models.PERMISSION_TIME_ENTRIES_APPROVE_TEAM,
+			models.PERMISSION_QUOTAS_READ_TEAM,
+			models.PERMISSION_USERS_READ,
+		},
+	},
+	{
+		Name:          DEFAULT_ROLE_EMPLOYEE,
+		InternalUsage: models.ROLE_INTERNAL_USAGE_EMPLOYEE,
+		Permissions: []string{
+			models.PERMISSION_TIME_ENTRIES_READ_OWN,
+			models.PERMISSION_TIME_ENTRIES_WRITE_OWN,
+			models.PERMISSION_QUOTAS_READ_OWN,
+		},
+	},
+}
@@ -0,0 +1,18 @@
+package role
+
+import "errors"
+
+// ErrUnknownPermission is returned when a role should be granted a permission that does not exist.
+var ErrUnknownPermission = errors.New("E3000")
+
+// ErrRoleAlreadyExists is returned when the company already has a role with the requested name.
+var ErrRoleAlreadyExists = errors.New("E3001")
+
+// ErrSystemRole is returned when a seeded default role should be renamed or deleted.
+var ErrSystemRole = errors.New("E3002")
+
+// ErrRoleInUse is returned when a role that is still assigned to users should be deleted.
+var ErrRoleInUse = errors.New("E3003")
+
+// ErrAdminRoleLocked is returned when the admin role should lose the permission to manage roles.
+var ErrAdminRoleLocked = errors.New("E3004")
@@ -0,0 +1,217 @@
+package role
+
+import (
+	"errors"
+	"slices"
+
+	"github.com/r-52/embrace/models"
+	dto "github.com/r-52/embrace/models/dto/role"
+	"github.com/r-52/embrace/repositories"
+	"gorm.io/gorm"
+)
+
+type RoleService struct {
+	unitOfWork *repositories.UnitOfWork
+}
+
+type RoleServiceInterface interface {
+	SeedDefaultRoles(companyID uint) error
+	ListRoles(companyID uint) ([]models.UserRole, error)
+	CreateRole(companyID uint, req *dto.RoleRequest) (*models.UserRole, error)
+	UpdateRole(companyID, roleID uint, req *dto.RoleRequest) (*models.UserRole, error)
+	DeleteRole(companyID, roleID uint) error
+}
+
+func NewRoleService(db *gorm.DB) *RoleService {
+	return NewRoleServiceWithUnitOfWork(repositories.NewUnitOfWork(db))
+}
+
+// NewRoleServiceWithUnitOfWork creates a RoleService whose repositories join the given unit of work.
+func NewRoleServiceWithUnitOfWork(uow *repositories.UnitOfWork) *RoleService {
+	return &RoleService{
+		unitOfWork: uow,
+	}
+}
+
+// SeedDefaultRoles makes sure the company has every role of DEFAULT_ROLES.
+// Missing roles are created with their default permissions, existing roles without any permission receive the defaults.
+// Roles that already have permissions are left untouched, so calling it repeatedly is safe.
+func (s *RoleService) SeedDefaultRoles(companyID uint) error {
+	return s.unitOfWork.Transaction(func(uow *repositories.UnitOfWork) error {
+		for _, defaultRole := range DEFAULT_ROLES {
+			existing, err := uow.UserRoles().GetByCompanyIDAndName(companyID, defaultRole.Name)
+			if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+				return err
+			}
+			if existing == nil {
+				existing = &models.UserRole{
+					Name:          defaultRole.Name,
+					InternalUsage: defaultRole.InternalUsage,
+					CompanyID:     companyID,
+				}
+				if err := uow.UserRoles().Create(existing); err != nil {
+					return err
+				}
+			}
+
+			existing, err = uow.UserRoles().GetByIDWithPermissions(existing.ID)
+			if err != nil {
+				return err
+			}
+			if len(existing.Permissions) > 0 {
+				continue
+			}
+			if err := uow.UserRoles().ReplacePermissions(existing.ID, defaultRole.Permissions); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// ListRoles returns all roles of the company with their permissions.
+func (s *RoleService) ListRoles(companyID uint) ([]models.UserRole, error) {
+	return s.unitOfWork.UserRoles().GetByCompanyID(companyID)
+}
+
+// CreateRole creates a custom role for the company.
+// It returns ErrRoleAlreadyExists if the name is taken and ErrUnknownPermission for permissions that do not exist.
+func (s *RoleService) CreateRole(companyID uint, req *dto.RoleRequest) (*models.UserRole, error) {
+	permissions, err := normalizePermissions(req.Permissions)
+	if err != nil {
+		return nil, err
+	}
+
+	var created *models.UserRole
+	err = s.unitOfWork.Transaction(func(uow *repositories.UnitOfWork) error {
+		_, err := uow.UserRoles().GetByCompanyIDAndName(companyID, req.Name)
+		if err == nil {
+			return ErrRoleAlreadyExists
+		}
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			return err
+		}
+
+		role := &models.UserRole{
+			Name:          req.Name,
+			InternalUsage: models.ROLE_INTERNAL_USAGE_CUSTOM,
+			CompanyID:     companyID,
+		}
+		if err := uow.UserRoles().Create(role); err != nil {
+			return err
+		}
+		if err := uow.UserRoles().ReplacePermissions(role.ID, permissions); err != nil {
+			return err
+		}
+
+		created, err = uow.UserRoles().GetByIDWithPermissions(role.ID)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return created, nil
+}
+
+// UpdateRole renames a role and replaces its permissions.
+// Default roles keep their name and return ErrSystemRole when a rename is requested.
+// The admin role keeps PERMISSION_ROLES_MANAGE and returns ErrAdminRoleLocked otherwise, so the company cannot lock
+// itself out of role management. Roles of other companies are reported as gorm.ErrRecordNotFound.
+func (s *RoleService) UpdateRole(companyID, roleID uint, req *dto.RoleRequest) (*models.UserRole, error) {
+	permissions, err := normalizePermissions(req.Permissions)
+	if err != nil {
+		return nil, err
+	}
+
+	var updated *models.UserRole
+	err = s.unitOfWork.Transaction(func(uow *repositories.UnitOfWork) error {
+		role, err := getCompanyRole(uow, companyID, roleID)
+		if err != nil {
+			return err
+		}
+
+		if role.Name != req.Name {
+			if role.InternalUsage != models.ROLE_INTERNAL_USAGE_CUSTOM {
+				return ErrSystemRole
+			}
+			_, err := uow.UserRoles().GetByCompanyIDAndName(companyID, req.Name)
+			if err == nil {
+				return ErrRoleAlreadyExists
+			}
+			if !errors.Is(err, gorm.ErrRecordNotFound) {
+				return err
+			}
+			role.Name = req.Name
+			if err := uow.UserRoles().Update(role); err != nil {
+				return err
+			}
+		}
+		if role.InternalUsage == models.ROLE_INTERNAL_USAGE_ADMIN && !slices.Contains(permissions, models.PERMISSION_ROLES_MANAGE) {
+			return ErrAdminRoleLocked
+		}
+		if err := uow.UserRoles().ReplacePermissions(role.ID, permissions); err != nil {
+			return err
+		}
+
+		updated, err = uow.UserRoles().GetByIDWithPermissions(role.ID)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return updated, nil
+}
+
+// DeleteRole deletes a custom role that is not assigned to any user.
+// It returns ErrSystemRole for default roles and ErrRoleInUse while users are still assigned.
+func (s *RoleService) DeleteRole(companyID, roleID uint) error {
+	return s.unitOfWork.Transaction(func(uow *repositories.UnitOfWork) error {
+		role, err := getCompanyRole(uow, companyID, roleID)
+		if err != nil {
+			return err
+		}
+		if role.InternalUsage != models.ROLE_INTERNAL_USAGE_CUSTOM {
+			return ErrSystemRole
+		}
+
+		count, err := uow.Users().CountByRoleID(role.ID)
+		if err != nil {
+			return err
+		}
+		if count > 0 {
+			return ErrRoleInUse
+		}
+
+		if err := uow.UserRoles().ReplacePermissions(role.ID, nil); err != nil {
+			return err
+		}
+		return uow.UserRoles().Delete(role.ID)
+	})
+}
+
+func getCompanyRole(uow *repositories.UnitOfWork, companyID, roleID uint) (*models.UserRole, error) {
+	role, err := uow.UserRoles().GetByID(roleID)
+	if err != nil {
+		return nil, err
+	}
+	if role.CompanyID != companyID {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return role, nil
+}
+
+func normalizePermissions(permissions []string) ([]string, error) {
+	seen := make(map[string]bool, len(permissions))
+	normalized := make([]string, 0, len(permissions))
+	for _, permission := range permissions {
+		if !models.IsKnownPermission(permission) {
+			return nil, ErrUnknownPermission
+		}
+		if seen[permission] {
+			continue
+		}
+		seen[permission] = true
+		normalized = append(normalized, permission)
+	}
+	return normalized, nil
+}
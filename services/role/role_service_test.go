@@ -0,0 +1,176 @@
+package role_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/r-52/embrace/models"
+	dto "github.com/r-52/embrace/models/dto/role"
+	"github.com/r-52/embrace/repositories"
+	"github.com/r-52/embrace/services/role"
+	"gorm.io/gorm"
+)
+
+func setupDb(t *testing.T) *gorm.DB {
+	db := repositories.GetDatabase()
+	err := db.AutoMigrate(&models.Company{}, &models.User{}, &models.UserRole{}, &models.RolePermission{})
+	if err != nil {
+		t.Fatalf("failed to migrate database: %v", err)
+	}
+	return db
+}
+
+func TestRoleService_SeedDefaultRoles(t *testing.T) {
+	db := setupDb(t)
+	roleService := role.NewRoleService(db)
+
+	if err := roleService.SeedDefaultRoles(1); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	// Seeding twice must not duplicate anything.
+	if err := roleService.SeedDefaultRoles(1); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	roles, err := roleService.ListRoles(1)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(roles) != len(role.DEFAULT_ROLES) {
+		t.Fatalf("expected %d roles, got %d", len(role.DEFAULT_ROLES), len(roles))
+	}
+	for _, r := range roles {
+		switch r.Name {
+		case role.DEFAULT_ROLE_ADMIN:
+			if !r.HasPermission(models.PERMISSION_ROLES_MANAGE) {
+				t.Errorf("expected admin to manage roles")
+			}
+		case role.DEFAULT_ROLE_MANAGER:
+			if !r.HasPermission(models.PERMISSION_TIME_ENTRIES_APPROVE_TEAM) || r.HasPermission(models.PERMISSION_QUOTAS_MANAGE) {
+				t.Errorf("unexpected manager permissions: %+v", r.Permissions)
+			}
+		case role.DEFAULT_ROLE_EMPLOYEE:
+			if !r.HasPermission(models.PERMISSION_TIME_ENTRIES_WRITE_OWN) || r.HasPermission(models.PERMISSION_TIME_ENTRIES_READ_TEAM) {
+				t.Errorf("unexpected employee permissions: %+v", r.Permissions)
+			}
+		default:
+			t.Errorf("unexpected role %q", r.Name)
+		}
+	}
+}
+
+func TestRoleService_CreateRole(t *testing.T) {
+	db := setupDb(t)
+	roleService := role.NewRoleService(db)
+
+	created, err := roleService.CreateRole(1, &dto.RoleRequest{
+		Name:        "accountant",
+		Permissions: []string{models.PERMISSION_TIME_ENTRIES_READ_ALL, models.PERMISSION_TIME_ENTRIES_READ_ALL},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(created.Permissions) != 1 || !created.HasPermission(models.PERMISSION_TIME_ENTRIES_READ_TEAM) {
+		t.Errorf("unexpected permissions: %+v", created.Permissions)
+	}
+
+	_, err = roleService.CreateRole(1, &dto.RoleRequest{Name: "accountant"})
+	if !errors.Is(err, role.ErrRoleAlreadyExists) {
+		t.Errorf("expected ErrRoleAlreadyExists, got %v", err)
+	}
+
+	_, err = roleService.CreateRole(1, &dto.RoleRequest{Name: "hacker", Permissions: []string{"everything:all"}})
+	if !errors.Is(err, role.ErrUnknownPermission) {
+		t.Errorf("expected ErrUnknownPermission, got %v", err)
+	}
+}
+
+func TestRoleService_UpdateRole(t *testing.T) {
+	db := setupDb(t)
+	roleService := role.NewRoleService(db)
+	if err := roleService.SeedDefaultRoles(1); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	custom, err := roleService.CreateRole(1, &dto.RoleRequest{Name: "auditor"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	updated, err := roleService.UpdateRole(1, custom.ID, &dto.RoleRequest{
+		Name:        "reviewer",
+		Permissions: []string{models.PERMISSION_TIME_ENTRIES_APPROVE_ALL},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if updated.Name != "reviewer" || !updated.HasPermission(models.PERMISSION_TIME_ENTRIES_APPROVE_TEAM) {
+		t.Errorf("unexpected role: %+v", updated)
+	}
+
+	// Roles of other companies are invisible.
+	_, err = roleService.UpdateRole(2, custom.ID, &dto.RoleRequest{Name: "reviewer"})
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		t.Errorf("expected ErrRecordNotFound, got %v", err)
+	}
+
+	// Default roles cannot be renamed.
+	employee, err := repositories.NewUserRoleRepository(db).GetByCompanyIDAndName(1, role.DEFAULT_ROLE_EMPLOYEE)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	_, err = roleService.UpdateRole(1, employee.ID, &dto.RoleRequest{Name: "staff"})
+	if !errors.Is(err, role.ErrSystemRole) {
+		t.Errorf("expected ErrSystemRole, got %v", err)
+	}
+
+	// The admin role keeps the permission to manage roles.
+	admin, err := repositories.NewUserRoleRepository(db).GetByCompanyIDAndName(1, role.DEFAULT_ROLE_ADMIN)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	_, err = roleService.UpdateRole(1, admin.ID, &dto.RoleRequest{Name: role.DEFAULT_ROLE_ADMIN})
+	if !errors.Is(err, role.ErrAdminRoleLocked) {
+		t.Errorf("expected ErrAdminRoleLocked, got %v", err)
+	}
+	admin, err = repositories.NewUserRoleRepository(db).GetByIDWithPermissions(admin.ID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !admin.HasPermission(models.PERMISSION_ROLES_MANAGE) {
+		t.Errorf("expected the admin role to keep its permissions, got %+v", admin.Permissions)
+	}
+	updated, err = roleService.UpdateRole(1, admin.ID, &dto.RoleRequest{
+		Name:        role.DEFAULT_ROLE_ADMIN,
+		Permissions: []string{models.PERMISSION_ROLES_MANAGE, models.PERMISSION_USERS_MANAGE},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(updated.Permissions) != 2 {
+		t.Errorf("expected the admin role to be narrowed, got %+v", updated.Permissions)
+	}
+}
+
+func TestRoleService_DeleteRole(t *testing.T) {
+	db := setupDb(t)
+	roleService := role.NewRoleService(db)
+	if err := roleService.SeedDefaultRoles(1); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	admin, _ := repositories.NewUserRoleRepository(db).GetByCompanyIDAndName(1, role.DEFAULT_ROLE_ADMIN)
+	if err := roleService.DeleteRole(1, admin.ID); !errors.Is(err, role.ErrSystemRole) {
+		t.Errorf("expected ErrSystemRole, got %v", err)
+	}
+
+	used, _ := roleService.CreateRole(1, &dto.RoleRequest{Name: "used"})
+	db.Create(&models.User{Email: "used@test.com", CompanyID: 1, RoleID: used.ID})
+	if err := roleService.DeleteRole(1, used.ID); !errors.Is(err, role.ErrRoleInUse) {
+		t.Errorf("expected ErrRoleInUse, got %v", err)
+	}
+
+	unused, _ := roleService.CreateRole(1, &dto.RoleRequest{Name: "unused"})
+	if err := roleService.DeleteRole(1, unused.ID); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
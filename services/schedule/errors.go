@@ -0,0 +1,23 @@
+package schedule
+
+import "errors"
+
+// ErrInvalidWorkSchedule is returned when the hours of a work schedule do not fit its kind,
+// e.g. a weekly schedule without weekly hours or a rotating schedule with days outside of its weeks.
+var ErrInvalidWorkSchedule = errors.New("E7000")
+
+// ErrUnknownWorkSchedule is returned when a user should be assigned a work schedule that does not exist in their company.
+var ErrUnknownWorkSchedule = errors.New("E7001")
+
+// ErrWorkScheduleOverlaps is returned when a work schedule assignment overlaps another one of the same user.
+var ErrWorkScheduleOverlaps = errors.New("E7002")
+
+// ErrWorkScheduleInUse is returned when a work schedule that is still assigned to users should be deleted.
+var ErrWorkScheduleInUse = errors.New("E7003")
+
+// ErrInvalidValidityPeriod is returned when a work schedule assignment would end before it starts.
+var ErrInvalidValidityPeriod = errors.New("E7004")
+
+// ErrInvalidTargetHoursPeriod is returned when target hours are requested for a period that ends before it starts
+// or is longer than MAX_TARGET_HOURS_DAYS.
+var ErrInvalidTargetHoursPeriod = errors.New("E7005")
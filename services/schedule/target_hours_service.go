@@ -0,0 +1,157 @@
+package schedule
+
+import (
+	"math"
+	"time"
+
+	"github.com/r-52/embrace/models"
+	dto "github.com/r-52/embrace/models/dto/schedule"
+	"github.com/r-52/embrace/repositories"
+	"github.com/r-52/embrace/services/holiday"
+	"gorm.io/gorm"
+)
+
+// MAX_TARGET_HOURS_DAYS is the number of calendar days target hours may be computed for at once.
+const MAX_TARGET_HOURS_DAYS = 366
+
+// TargetHoursService computes how many hours users are supposed to work. The hours of a day come from the work
+// schedule assigned to the user for it. Days without a schedule fall back to the user's contract: DailyWorkingHours
+// on WorkingDaysPerWeek weekdays from Monday on. Days outside of the employment have no hours. Holidays of the user's
+// calendar and approved leave reduce the scheduled hours, and half days reduce them by half.
+type TargetHoursService struct {
+	unitOfWork *repositories.UnitOfWork
+}
+
+type TargetHoursServiceInterface interface {
+	TargetHours(actor *models.User, userID uint, from, to time.Time) (*dto.TargetHoursResponse, error)
+	TargetHoursOf(user *models.User, from, to time.Time) (*dto.TargetHoursResponse, error)
+}
+
+// NewTargetHoursService creates a TargetHoursService. The database should be scoped to the actor's company, see repositories.WithTenant.
+func NewTargetHoursService(db *gorm.DB) *TargetHoursService {
+	return NewTargetHoursServiceWithUnitOfWork(repositories.NewUnitOfWork(db))
+}
+
+// NewTargetHoursServiceWithUnitOfWork creates a TargetHoursService whose repositories join the given unit of work.
+func NewTargetHoursServiceWithUnitOfWork(uow *repositories.UnitOfWork) *TargetHoursService {
+	return &TargetHoursService{
+		unitOfWork: uow,
+	}
+}
+
+// TargetHours returns the target hours of a user for every day from `from` to `to`, both calendar days and inclusive,
+// see TargetHoursOf. The actor needs the permission to read the user's time entries.
+func (s *TargetHoursService) TargetHours(actor *models.User, userID uint, from, to time.Time) (*dto.TargetHoursResponse, error) {
+	user, err := s.unitOfWork.Users().GetByID(userID)
+	if err != nil {
+		return nil, err
+	}
+	if !actor.CanAccess(user, models.PERMISSION_TIME_ENTRIES_READ) {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return s.TargetHoursOf(user, from, to)
+}
+
+// TargetHoursOf returns the target hours of a user for every day from `from` to `to` without checking permissions,
+// for services that already did. It fails with ErrInvalidTargetHoursPeriod if the period ends before it starts
+// or is longer than MAX_TARGET_HOURS_DAYS.
+func (s *TargetHoursService) TargetHoursOf(user *models.User, from, to time.Time) (*dto.TargetHoursResponse, error) {
+	from = time.Date(from.Year(), from.Month(), from.Day(), 0, 0, 0, 0, time.UTC)
+	to = time.Date(to.Year(), to.Month(), to.Day(), 0, 0, 0, 0, time.UTC)
+	if to.Before(from) || !to.Before(from.AddDate(0, 0, MAX_TARGET_HOURS_DAYS)) {
+		return nil, ErrInvalidTargetHoursPeriod
+	}
+
+	assignments, err := s.unitOfWork.UserWorkSchedules().GetByUserIDBetween(user.ID, from, to)
+	if err != nil {
+		return nil, err
+	}
+	holidays := map[string]models.Holiday{}
+	if user.HolidayCalendarID != nil {
+		calendar, err := s.unitOfWork.HolidayCalendars().GetByID(*user.HolidayCalendarID)
+		if err != nil {
+			return nil, err
+		}
+		for _, day := range holiday.Holidays(calendar, from, to) {
+			holidays[day.Date.Format(time.DateOnly)] = day
+		}
+	}
+	leave, err := s.unitOfWork.LeaveRequests().GetApprovedBetween(user.ID, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	response := &dto.TargetHoursResponse{
+		UserID: user.ID,
+		From:   from.Format(time.DateOnly),
+		To:     to.Format(time.DateOnly),
+		Days:   []*dto.TargetDayResponse{},
+	}
+	for day := from; !day.After(to); day = day.AddDate(0, 0, 1) {
+		target := &dto.TargetDayResponse{
+			Date:           day.Format(time.DateOnly),
+			ScheduledHours: scheduledHours(user, assignments, day),
+		}
+		if dayOff, ok := holidays[target.Date]; ok {
+			target.Holiday = dayOff.Name
+			target.HolidayHours = target.ScheduledHours
+			if dayOff.HalfDay {
+				target.HolidayHours /= 2
+			}
+		}
+		if absence := leaveOn(leave, day); absence != nil {
+			target.AbsenceHours = target.ScheduledHours - target.HolidayHours
+			if absence.HalfDay {
+				target.AbsenceHours = math.Min(target.AbsenceHours, target.ScheduledHours/2)
+			}
+		}
+		target.TargetHours = target.ScheduledHours - target.HolidayHours - target.AbsenceHours
+
+		response.ScheduledHours += target.ScheduledHours
+		response.HolidayHours += target.HolidayHours
+		response.AbsenceHours += target.AbsenceHours
+		response.TargetHours += target.TargetHours
+		response.Days = append(response.Days, target)
+	}
+	response.ScheduledHours = roundHours(response.ScheduledHours)
+	response.HolidayHours = roundHours(response.HolidayHours)
+	response.AbsenceHours = roundHours(response.AbsenceHours)
+	response.TargetHours = roundHours(response.TargetHours)
+	return response, nil
+}
+
+// scheduledHours returns the hours a user is scheduled to work on a day, before holidays and absences.
+func scheduledHours(user *models.User, assignments []models.UserWorkSchedule, day time.Time) float64 {
+	if (user.EmploymentStart.Valid && day.Before(user.EmploymentStart.Time)) || (user.EmploymentEnd.Valid && day.After(user.EmploymentEnd.Time)) {
+		return 0
+	}
+	for i := range assignments {
+		if assignments[i].AppliesOn(day) {
+			return assignments[i].WorkSchedule.HoursOn(day, assignments[i].ValidFrom)
+		}
+	}
+
+	workingDays := user.WorkingDaysPerWeek
+	if workingDays <= 0 {
+		workingDays = 5
+	}
+	if (int(day.Weekday())+6)%7 >= workingDays {
+		return 0
+	}
+	return user.WorkingHoursPerDay()
+}
+
+// leaveOn returns the approved leave on a day, or nil.
+func leaveOn(leave []models.LeaveRequest, day time.Time) *models.LeaveRequest {
+	for i := range leave {
+		if !day.Before(leave[i].StartDate) && !day.After(leave[i].EndDate) {
+			return &leave[i]
+		}
+	}
+	return nil
+}
+
+// roundHours rounds a sum of hours to hundredths, which hides the imprecision of adding up fractions of hours.
+func roundHours(hours float64) float64 {
+	return math.Round(hours*100) / 100
+}
@@ -0,0 +1,117 @@
+package schedule_test
+
+import (
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/r-52/embrace/models"
+	dto "github.com/r-52/embrace/models/dto/schedule"
+	"github.com/r-52/embrace/repositories"
+	"github.com/r-52/embrace/services/holiday"
+	"github.com/r-52/embrace/services/role"
+	"github.com/r-52/embrace/services/schedule"
+	"gorm.io/gorm"
+)
+
+// The week before Easter 2024, Good Friday being March 29.
+var (
+	easterWeekStart = time.Date(2024, 3, 25, 0, 0, 0, 0, time.UTC)
+	easterWeekEnd   = time.Date(2024, 3, 31, 0, 0, 0, 0, time.UTC)
+)
+
+func hoursOf(response *dto.TargetHoursResponse) []float64 {
+	hours := []float64{}
+	for _, day := range response.Days {
+		hours = append(hours, day.TargetHours)
+	}
+	return hours
+}
+
+func TestTargetHoursService_TargetHours(t *testing.T) {
+	db := setupDb(t)
+	companyID := createCompany(t, db, "acme")
+	admin := createUser(t, db, companyID, role.DEFAULT_ROLE_ADMIN, "admin")
+	employee := createUser(t, db, companyID, role.DEFAULT_ROLE_EMPLOYEE, "employee")
+	colleague := createUser(t, db, companyID, role.DEFAULT_ROLE_EMPLOYEE, "colleague")
+	tenantDb := repositories.WithTenant(db, companyID)
+	service := schedule.NewTargetHoursService(tenantDb)
+
+	// Without a schedule, calendar or leave the contract applies.
+	response, err := service.TargetHours(employee, employee.ID, easterWeekStart, easterWeekEnd)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if response.TargetHours != 40 || len(response.Days) != 7 {
+		t.Errorf("expected 40 hours in 7 days, got %+v", response)
+	}
+
+	calendar := &models.HolidayCalendar{CompanyID: companyID, Name: "Munich", Region: holiday.REGION_BAVARIA}
+	if err := db.Create(calendar).Error; err != nil {
+		t.Fatalf("failed to create holiday calendar: %v", err)
+	}
+	leave := &models.LeaveRequest{CompanyID: companyID, UserID: employee.ID, TimeEntryTypeID: 1, StartDate: easterWeekStart.AddDate(0, 0, 1),
+		EndDate: easterWeekStart.AddDate(0, 0, 1), HalfDay: true, Status: models.LEAVE_REQUEST_APPROVED}
+	if err := db.Create(leave).Error; err != nil {
+		t.Fatalf("failed to create leave request: %v", err)
+	}
+	db.Model(employee).Update("holiday_calendar_id", calendar.ID)
+	schedules := schedule.NewWorkScheduleService(tenantDb)
+	partTime, err := schedules.CreateSchedule(companyID, &dto.WorkScheduleRequest{Name: "Part time", Kind: models.WORK_SCHEDULE_WEEKLY, WeeklyHours: 20})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := schedules.AssignSchedule(employee.ID, &dto.AssignWorkScheduleRequest{WorkScheduleID: partTime.ID, ValidFrom: "2024-03-27"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// Part time from Wednesday on, half a day of leave on Tuesday and Good Friday off.
+	response, err = service.TargetHours(admin, employee.ID, easterWeekStart, easterWeekEnd)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if response.ScheduledHours != 28 || response.HolidayHours != 4 || response.AbsenceHours != 4 || response.TargetHours != 20 {
+		t.Errorf("expected 28 scheduled, 4 holiday, 4 absence and 20 target hours, got %+v", response)
+	}
+	expected := []float64{8, 4, 4, 4, 0, 0, 0}
+	for i, hours := range hoursOf(response) {
+		if hours != expected[i] {
+			t.Errorf("expected the target hours %v, got %v", expected, hoursOf(response))
+			break
+		}
+	}
+	if response.Days[4].Holiday != "Karfreitag" {
+		t.Errorf("expected Good Friday on %s, got %q", response.Days[4].Date, response.Days[4].Holiday)
+	}
+
+	// Days outside of the employment have no hours.
+	db.Model(employee).Update("employment_start", sql.NullTime{Time: easterWeekStart.AddDate(0, 0, 3), Valid: true})
+	response, err = service.TargetHours(admin, employee.ID, easterWeekStart, easterWeekEnd)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if response.TargetHours != 4 {
+		t.Errorf("expected 4 target hours from Thursday on, got %v", response.TargetHours)
+	}
+
+	// Users without the permission to read the time entries cannot see the target hours.
+	if _, err := service.TargetHours(colleague, employee.ID, easterWeekStart, easterWeekEnd); !errors.Is(err, gorm.ErrRecordNotFound) {
+		t.Errorf("expected ErrRecordNotFound, got %v", err)
+	}
+
+	// Periods are limited to MAX_TARGET_HOURS_DAYS.
+	response, err = service.TargetHours(admin, employee.ID, easterWeekStart, easterWeekStart.AddDate(0, 0, schedule.MAX_TARGET_HOURS_DAYS-1))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(response.Days) != schedule.MAX_TARGET_HOURS_DAYS {
+		t.Errorf("expected %d days, got %d", schedule.MAX_TARGET_HOURS_DAYS, len(response.Days))
+	}
+	if _, err := service.TargetHours(admin, employee.ID, easterWeekStart, easterWeekStart.AddDate(0, 0, schedule.MAX_TARGET_HOURS_DAYS)); !errors.Is(err, schedule.ErrInvalidTargetHoursPeriod) {
+		t.Errorf("expected ErrInvalidTargetHoursPeriod for a longer period, got %v", err)
+	}
+	if _, err := service.TargetHours(admin, employee.ID, easterWeekEnd, easterWeekStart); !errors.Is(err, schedule.ErrInvalidTargetHoursPeriod) {
+		t.Errorf("expected ErrInvalidTargetHoursPeriod for a period that ends before it starts, got %v", err)
+	}
+}
@@ -0,0 +1,264 @@
+package schedule
+
+import (
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/r-52/embrace/models"
+	dto "github.com/r-52/embrace/models/dto/schedule"
+	"github.com/r-52/embrace/repositories"
+	"gorm.io/gorm"
+)
+
+// WorkScheduleService manages the work schedules of a company and assigns them to its users.
+type WorkScheduleService struct {
+	unitOfWork *repositories.UnitOfWork
+}
+
+type WorkScheduleServiceInterface interface {
+	ListSchedules(companyID uint) ([]models.WorkSchedule, error)
+	GetSchedule(companyID, scheduleID uint) (*models.WorkSchedule, error)
+	CreateSchedule(companyID uint, req *dto.WorkScheduleRequest) (*models.WorkSchedule, error)
+	UpdateSchedule(companyID, scheduleID uint, req *dto.WorkScheduleRequest) (*models.WorkSchedule, error)
+	DeleteSchedule(companyID, scheduleID uint) error
+	ListAssignments(userID uint) ([]models.UserWorkSchedule, error)
+	AssignSchedule(userID uint, req *dto.AssignWorkScheduleRequest) (*models.UserWorkSchedule, error)
+	DeleteAssignment(userID, assignmentID uint) error
+}
+
+// NewWorkScheduleService creates a WorkScheduleService. The database should be scoped to the company, see repositories.WithTenant.
+func NewWorkScheduleService(db *gorm.DB) *WorkScheduleService {
+	return NewWorkScheduleServiceWithUnitOfWork(repositories.NewUnitOfWork(db))
+}
+
+// NewWorkScheduleServiceWithUnitOfWork creates a WorkScheduleService whose repositories join the given unit of work.
+func NewWorkScheduleServiceWithUnitOfWork(uow *repositories.UnitOfWork) *WorkScheduleService {
+	return &WorkScheduleService{
+		unitOfWork: uow,
+	}
+}
+
+// ListSchedules returns the work schedules of the company with their days.
+func (s *WorkScheduleService) ListSchedules(companyID uint) ([]models.WorkSchedule, error) {
+	return s.unitOfWork.WorkSchedules().GetByCompanyID(companyID)
+}
+
+// GetSchedule returns a work schedule of the company with its days.
+// Schedules of other companies are reported as gorm.ErrRecordNotFound.
+func (s *WorkScheduleService) GetSchedule(companyID, scheduleID uint) (*models.WorkSchedule, error) {
+	return getCompanySchedule(s.unitOfWork, companyID, scheduleID)
+}
+
+// CreateSchedule creates a work schedule for the company. It returns ErrInvalidWorkSchedule if the hours do not fit the kind.
+func (s *WorkScheduleService) CreateSchedule(companyID uint, req *dto.WorkScheduleRequest) (*models.WorkSchedule, error) {
+	schedule, err := newSchedule(req)
+	if err != nil {
+		return nil, err
+	}
+	schedule.CompanyID = companyID
+	if err := s.unitOfWork.WorkSchedules().Create(schedule); err != nil {
+		return nil, err
+	}
+	return schedule, nil
+}
+
+// UpdateSchedule replaces a work schedule of the company. The change applies to every user the schedule
+// is assigned to, for past days as well. It returns ErrInvalidWorkSchedule if the hours do not fit the kind.
+func (s *WorkScheduleService) UpdateSchedule(companyID, scheduleID uint, req *dto.WorkScheduleRequest) (*models.WorkSchedule, error) {
+	changed, err := newSchedule(req)
+	if err != nil {
+		return nil, err
+	}
+
+	var updated *models.WorkSchedule
+	err = s.unitOfWork.Transaction(func(uow *repositories.UnitOfWork) error {
+		schedule, err := getCompanySchedule(uow, companyID, scheduleID)
+		if err != nil {
+			return err
+		}
+		changed.Model = schedule.Model
+		changed.CompanyID = schedule.CompanyID
+		if err := uow.WorkSchedules().Update(changed); err != nil {
+			return err
+		}
+		updated, err = uow.WorkSchedules().GetByID(schedule.ID)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return updated, nil
+}
+
+// DeleteSchedule deletes a work schedule that was never assigned to a user. It returns ErrWorkScheduleInUse otherwise,
+// as past assignments still define the target hours of their users.
+func (s *WorkScheduleService) DeleteSchedule(companyID, scheduleID uint) error {
+	return s.unitOfWork.Transaction(func(uow *repositories.UnitOfWork) error {
+		schedule, err := getCompanySchedule(uow, companyID, scheduleID)
+		if err != nil {
+			return err
+		}
+		count, err := uow.UserWorkSchedules().CountByWorkScheduleID(schedule.ID)
+		if err != nil {
+			return err
+		}
+		if count > 0 {
+			return ErrWorkScheduleInUse
+		}
+		return uow.WorkSchedules().Delete(schedule.ID)
+	})
+}
+
+// ListAssignments returns the work schedules assigned to a user, ordered by validity.
+func (s *WorkScheduleService) ListAssignments(userID uint) ([]models.UserWorkSchedule, error) {
+	if _, err := s.unitOfWork.Users().GetByID(userID); err != nil {
+		return nil, err
+	}
+	return s.unitOfWork.UserWorkSchedules().GetByUserID(userID)
+}
+
+// AssignSchedule assigns a work schedule to a user for a validity period. An assignment without an end that started
+// before the new one ends the day before it. It returns ErrUnknownWorkSchedule if the schedule does not exist,
+// ErrInvalidValidityPeriod if the period ends before it starts and ErrWorkScheduleOverlaps if it overlaps another assignment.
+func (s *WorkScheduleService) AssignSchedule(userID uint, req *dto.AssignWorkScheduleRequest) (*models.UserWorkSchedule, error) {
+	from, err := time.Parse(time.DateOnly, req.ValidFrom)
+	if err != nil {
+		return nil, err
+	}
+	until := sql.NullTime{}
+	if req.ValidUntil != "" {
+		until.Time, err = time.Parse(time.DateOnly, req.ValidUntil)
+		if err != nil {
+			return nil, err
+		}
+		until.Valid = true
+		if until.Time.Before(from) {
+			return nil, ErrInvalidValidityPeriod
+		}
+	}
+
+	var created *models.UserWorkSchedule
+	err = s.unitOfWork.Transaction(func(uow *repositories.UnitOfWork) error {
+		user, err := uow.Users().GetByID(userID)
+		if err != nil {
+			return err
+		}
+		schedule, err := getCompanySchedule(uow, user.CompanyID, req.WorkScheduleID)
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrUnknownWorkSchedule
+		}
+		if err != nil {
+			return err
+		}
+
+		assignment := &models.UserWorkSchedule{
+			CompanyID:      user.CompanyID,
+			UserID:         user.ID,
+			WorkScheduleID: schedule.ID,
+			ValidFrom:      from,
+			ValidUntil:     until,
+		}
+		existing, err := uow.UserWorkSchedules().GetByUserID(user.ID)
+		if err != nil {
+			return err
+		}
+		for i := range existing {
+			if !overlaps(&existing[i], assignment) {
+				continue
+			}
+			if existing[i].ValidUntil.Valid || !existing[i].ValidFrom.Before(from) {
+				return ErrWorkScheduleOverlaps
+			}
+			existing[i].ValidUntil = sql.NullTime{Time: from.AddDate(0, 0, -1), Valid: true}
+			if err := uow.UserWorkSchedules().Update(&existing[i]); err != nil {
+				return err
+			}
+		}
+		if err := uow.UserWorkSchedules().Create(assignment); err != nil {
+			return err
+		}
+		assignment.WorkSchedule = *schedule
+		created = assignment
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return created, nil
+}
+
+// DeleteAssignment removes a work schedule assignment of a user.
+func (s *WorkScheduleService) DeleteAssignment(userID, assignmentID uint) error {
+	return s.unitOfWork.Transaction(func(uow *repositories.UnitOfWork) error {
+		assignment, err := uow.UserWorkSchedules().GetByID(assignmentID)
+		if err != nil {
+			return err
+		}
+		if assignment.UserID != userID {
+			return gorm.ErrRecordNotFound
+		}
+		return uow.UserWorkSchedules().Delete(assignment.ID)
+	})
+}
+
+func getCompanySchedule(uow *repositories.UnitOfWork, companyID, scheduleID uint) (*models.WorkSchedule, error) {
+	schedule, err := uow.WorkSchedules().GetByID(scheduleID)
+	if err != nil {
+		return nil, err
+	}
+	if schedule.CompanyID != companyID {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return schedule, nil
+}
+
+// newSchedule builds a work schedule from the request and checks that its hours fit its kind.
+func newSchedule(req *dto.WorkScheduleRequest) (*models.WorkSchedule, error) {
+	schedule := &models.WorkSchedule{
+		Name:          req.Name,
+		Kind:          req.Kind,
+		WorkingDays:   5,
+		RotationWeeks: 1,
+	}
+	switch req.Kind {
+	case models.WORK_SCHEDULE_WEEKLY:
+		if req.WeeklyHours <= 0 || len(req.Days) > 0 {
+			return nil, ErrInvalidWorkSchedule
+		}
+		schedule.WeeklyHours = req.WeeklyHours
+		if req.WorkingDays > 0 {
+			schedule.WorkingDays = req.WorkingDays
+		}
+		return schedule, nil
+	case models.WORK_SCHEDULE_ROTATING:
+		if req.RotationWeeks < 2 {
+			return nil, ErrInvalidWorkSchedule
+		}
+		schedule.RotationWeeks = req.RotationWeeks
+	case models.WORK_SCHEDULE_WEEKDAYS:
+	default:
+		return nil, ErrInvalidWorkSchedule
+	}
+
+	if len(req.Days) == 0 {
+		return nil, ErrInvalidWorkSchedule
+	}
+	seen := map[[2]int]bool{}
+	for _, day := range req.Days {
+		key := [2]int{day.Week, day.Weekday}
+		if day.Week >= schedule.RotationWeeks || seen[key] {
+			return nil, ErrInvalidWorkSchedule
+		}
+		seen[key] = true
+		schedule.Days = append(schedule.Days, models.WorkScheduleDay{Week: day.Week, Weekday: time.Weekday(day.Weekday), Hours: day.Hours})
+	}
+	return schedule, nil
+}
+
+// overlaps reports whether two assignments share a day.
+func overlaps(a, b *models.UserWorkSchedule) bool {
+	aEndsBefore := a.ValidUntil.Valid && a.ValidUntil.Time.Before(b.ValidFrom)
+	bEndsBefore := b.ValidUntil.Valid && b.ValidUntil.Time.Before(a.ValidFrom)
+	return !aEndsBefore && !bEndsBefore
+}
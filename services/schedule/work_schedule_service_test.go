@@ -0,0 +1,163 @@
+package schedule_test
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/r-52/embrace/models"
+	dto "github.com/r-52/embrace/models/dto/schedule"
+	"github.com/r-52/embrace/repositories"
+	"github.com/r-52/embrace/services/role"
+	"github.com/r-52/embrace/services/schedule"
+	"gorm.io/gorm"
+)
+
+func setupDb(t *testing.T) *gorm.DB {
+	db := repositories.GetDatabase()
+	err := db.AutoMigrate(&models.Company{}, &models.User{}, &models.UserProfile{}, &models.UserRole{}, &models.RolePermission{},
+		&models.TimeEntryType{}, &models.LeaveRequest{}, &models.HolidayCalendar{}, &models.Holiday{},
+		&models.WorkSchedule{}, &models.WorkScheduleDay{}, &models.UserWorkSchedule{})
+	if err != nil {
+		t.Fatalf("failed to migrate database: %v", err)
+	}
+	return db
+}
+
+func createCompany(t *testing.T, db *gorm.DB, name string) uint {
+	company := &models.Company{Name: name, PrimaryEmail: name + "@example.com"}
+	if err := db.Create(company).Error; err != nil {
+		t.Fatalf("failed to create company: %v", err)
+	}
+	if err := role.NewRoleService(db).SeedDefaultRoles(company.ID); err != nil {
+		t.Fatalf("failed to seed roles: %v", err)
+	}
+	return company.ID
+}
+
+func createUser(t *testing.T, db *gorm.DB, companyID uint, roleName, name string) *models.User {
+	userRole, err := repositories.NewUserRoleRepository(db).GetByCompanyIDAndName(companyID, roleName)
+	if err != nil {
+		t.Fatalf("failed to find role: %v", err)
+	}
+	user := &models.User{
+		Email:       fmt.Sprintf("%s-%d@example.com", name, companyID),
+		Password:    "secret",
+		CompanyID:   companyID,
+		RoleID:      userRole.ID,
+		UserProfile: models.UserProfile{Slug: fmt.Sprintf("%s-%d", name, companyID)},
+	}
+	if err := db.Create(user).Error; err != nil {
+		t.Fatalf("failed to create user: %v", err)
+	}
+	user, err = repositories.NewUserRepository(db).GetByIDWithCompanyAndRole(user.ID)
+	if err != nil {
+		t.Fatalf("failed to load user: %v", err)
+	}
+	return user
+}
+
+func TestWorkScheduleService_CreateSchedule_Validates_Kind(t *testing.T) {
+	db := setupDb(t)
+	service := schedule.NewWorkScheduleService(repositories.WithTenant(db, 1))
+
+	monday := dto.WorkScheduleDayRequest{Weekday: int(time.Monday), Hours: 8}
+	tests := []struct {
+		name string
+		req  *dto.WorkScheduleRequest
+		err  error
+	}{
+		{name: "weekly", req: &dto.WorkScheduleRequest{Name: "Full time", Kind: models.WORK_SCHEDULE_WEEKLY, WeeklyHours: 40}},
+		{name: "weekly without hours", req: &dto.WorkScheduleRequest{Name: "Full time", Kind: models.WORK_SCHEDULE_WEEKLY}, err: schedule.ErrInvalidWorkSchedule},
+		{name: "weekly with days", req: &dto.WorkScheduleRequest{Name: "Full time", Kind: models.WORK_SCHEDULE_WEEKLY, WeeklyHours: 40, Days: []dto.WorkScheduleDayRequest{monday}}, err: schedule.ErrInvalidWorkSchedule},
+		{name: "weekdays", req: &dto.WorkScheduleRequest{Name: "Mondays", Kind: models.WORK_SCHEDULE_WEEKDAYS, Days: []dto.WorkScheduleDayRequest{monday}}},
+		{name: "weekdays without days", req: &dto.WorkScheduleRequest{Name: "Mondays", Kind: models.WORK_SCHEDULE_WEEKDAYS}, err: schedule.ErrInvalidWorkSchedule},
+		{name: "duplicate day", req: &dto.WorkScheduleRequest{Name: "Mondays", Kind: models.WORK_SCHEDULE_WEEKDAYS, Days: []dto.WorkScheduleDayRequest{monday, monday}}, err: schedule.ErrInvalidWorkSchedule},
+		{name: "rotating", req: &dto.WorkScheduleRequest{Name: "Shifts", Kind: models.WORK_SCHEDULE_ROTATING, RotationWeeks: 2,
+			Days: []dto.WorkScheduleDayRequest{monday, {Week: 1, Weekday: int(time.Tuesday), Hours: 8}}}},
+		{name: "rotating single week", req: &dto.WorkScheduleRequest{Name: "Shifts", Kind: models.WORK_SCHEDULE_ROTATING, RotationWeeks: 1,
+			Days: []dto.WorkScheduleDayRequest{monday}}, err: schedule.ErrInvalidWorkSchedule},
+		{name: "week outside the rotation", req: &dto.WorkScheduleRequest{Name: "Shifts", Kind: models.WORK_SCHEDULE_ROTATING, RotationWeeks: 2,
+			Days: []dto.WorkScheduleDayRequest{{Week: 2, Weekday: int(time.Monday), Hours: 8}}}, err: schedule.ErrInvalidWorkSchedule},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			created, err := service.CreateSchedule(1, tt.req)
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("expected %v, got %v", tt.err, err)
+			}
+			if err == nil && (created.CompanyID != 1 || len(created.Days) != len(tt.req.Days)) {
+				t.Errorf("unexpected schedule %+v", created)
+			}
+		})
+	}
+}
+
+func TestWorkScheduleService_AssignSchedule(t *testing.T) {
+	db := setupDb(t)
+	companyID := createCompany(t, db, "acme")
+	user := createUser(t, db, companyID, role.DEFAULT_ROLE_EMPLOYEE, "employee")
+	service := schedule.NewWorkScheduleService(repositories.WithTenant(db, companyID))
+
+	fullTime, err := service.CreateSchedule(companyID, &dto.WorkScheduleRequest{Name: "Full time", Kind: models.WORK_SCHEDULE_WEEKLY, WeeklyHours: 40})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	partTime, err := service.CreateSchedule(companyID, &dto.WorkScheduleRequest{Name: "Part time", Kind: models.WORK_SCHEDULE_WEEKLY, WeeklyHours: 20})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if _, err := service.AssignSchedule(user.ID, &dto.AssignWorkScheduleRequest{WorkScheduleID: fullTime.ID, ValidFrom: "2024-03-01", ValidUntil: "2024-02-01"}); !errors.Is(err, schedule.ErrInvalidValidityPeriod) {
+		t.Errorf("expected ErrInvalidValidityPeriod, got %v", err)
+	}
+	if _, err := service.AssignSchedule(user.ID, &dto.AssignWorkScheduleRequest{WorkScheduleID: 999, ValidFrom: "2024-01-01"}); !errors.Is(err, schedule.ErrUnknownWorkSchedule) {
+		t.Errorf("expected ErrUnknownWorkSchedule, got %v", err)
+	}
+	first, err := service.AssignSchedule(user.ID, &dto.AssignWorkScheduleRequest{WorkScheduleID: fullTime.ID, ValidFrom: "2024-01-01"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// A later assignment ends the open one the day before.
+	second, err := service.AssignSchedule(user.ID, &dto.AssignWorkScheduleRequest{WorkScheduleID: partTime.ID, ValidFrom: "2024-07-01"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	assignments, err := service.ListAssignments(user.ID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(assignments) != 2 || assignments[0].ID != first.ID || !assignments[0].ValidUntil.Valid ||
+		assignments[0].ValidUntil.Time.Format(time.DateOnly) != "2024-06-30" || assignments[1].ID != second.ID {
+		t.Errorf("expected the first assignment to end on 2024-06-30, got %+v", assignments)
+	}
+
+	// Closed assignments and assignments starting later are not moved.
+	if _, err := service.AssignSchedule(user.ID, &dto.AssignWorkScheduleRequest{WorkScheduleID: partTime.ID, ValidFrom: "2024-03-01", ValidUntil: "2024-03-31"}); !errors.Is(err, schedule.ErrWorkScheduleOverlaps) {
+		t.Errorf("expected ErrWorkScheduleOverlaps, got %v", err)
+	}
+	if _, err := service.AssignSchedule(user.ID, &dto.AssignWorkScheduleRequest{WorkScheduleID: fullTime.ID, ValidFrom: "2024-07-01"}); !errors.Is(err, schedule.ErrWorkScheduleOverlaps) {
+		t.Errorf("expected ErrWorkScheduleOverlaps, got %v", err)
+	}
+
+	if err := service.DeleteSchedule(companyID, partTime.ID); !errors.Is(err, schedule.ErrWorkScheduleInUse) {
+		t.Errorf("expected ErrWorkScheduleInUse, got %v", err)
+	}
+	if err := service.DeleteAssignment(user.ID+1, second.ID); !errors.Is(err, gorm.ErrRecordNotFound) {
+		t.Errorf("expected ErrRecordNotFound, got %v", err)
+	}
+	if err := service.DeleteAssignment(user.ID, second.ID); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := service.DeleteSchedule(companyID, partTime.ID); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	// Schedules of other companies do not exist.
+	foreign := schedule.NewWorkScheduleService(repositories.WithTenant(db, companyID+1))
+	if _, err := foreign.GetSchedule(companyID+1, fullTime.ID); !errors.Is(err, gorm.ErrRecordNotFound) {
+		t.Errorf("expected ErrRecordNotFound, got %v", err)
+	}
+}
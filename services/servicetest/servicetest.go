@@ -0,0 +1,65 @@
+// Package servicetest provides the database fixtures shared by the service tests.
+package servicetest
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/r-52/embrace/models"
+	"github.com/r-52/embrace/repositories"
+	"github.com/r-52/embrace/services/role"
+	"gorm.io/gorm"
+)
+
+// Database returns an in-memory database with the tables of the given models.
+func Database(t *testing.T, tables ...interface{}) *gorm.DB {
+	return Migrate(t, repositories.GetDatabase(), tables...)
+}
+
+// Migrate creates the tables of the given models in the database and returns it.
+func Migrate(t *testing.T, db *gorm.DB, tables ...interface{}) *gorm.DB {
+	if err := db.AutoMigrate(tables...); err != nil {
+		t.Fatalf("failed to migrate database: %v", err)
+	}
+	return db
+}
+
+// MustCreate inserts a record and fails the test if that is not possible.
+func MustCreate(t *testing.T, db *gorm.DB, value interface{}) {
+	if err := db.Create(value).Error; err != nil {
+		t.Fatalf("failed to create %T: %v", value, err)
+	}
+}
+
+// Company creates a company with the default roles.
+func Company(t *testing.T, db *gorm.DB, name string) *models.Company {
+	company := &models.Company{Name: name, PrimaryEmail: name + "@example.com"}
+	MustCreate(t, db, company)
+	if err := role.NewRoleService(db).SeedDefaultRoles(company.ID); err != nil {
+		t.Fatalf("failed to seed roles: %v", err)
+	}
+	return company
+}
+
+// User creates a user of the company with one of its roles, optionally managed by another user, and returns them
+// loaded with their company and role.
+func User(t *testing.T, db *gorm.DB, companyID uint, roleName, name string, managerID *uint) *models.User {
+	userRole, err := repositories.NewUserRoleRepository(db).GetByCompanyIDAndName(companyID, roleName)
+	if err != nil {
+		t.Fatalf("failed to find role: %v", err)
+	}
+	user := &models.User{
+		Email:       fmt.Sprintf("%s@example.com", name),
+		Password:    "secret",
+		CompanyID:   companyID,
+		RoleID:      userRole.ID,
+		ManagerID:   managerID,
+		UserProfile: models.UserProfile{Slug: name, FirstName: "Erika", LastName: name},
+	}
+	MustCreate(t, db, user)
+	user, err = repositories.NewUserRepository(db).GetByIDWithCompanyAndRole(user.ID)
+	if err != nil {
+		t.Fatalf("failed to load user: %v", err)
+	}
+	return user
+}
@@ -0,0 +1,73 @@
+package timeentry
+
+import (
+	"errors"
+
+	"github.com/r-52/embrace/models"
+	"github.com/r-52/embrace/repositories"
+	"github.com/r-52/embrace/services/auth"
+	"gorm.io/gorm"
+)
+
+const ACTION_READ = "read"
+const ACTION_WRITE = "write"
+
+// scopeFor returns the permission scope the actor needs to access data of the owner:
+// their own data, data of a user they manage, or anyone else's data.
+func scopeFor(uow *repositories.UnitOfWork, actor *models.User, ownerID uint) (string, error) {
+	if ownerID == actor.ID {
+		return models.PERMISSION_SCOPE_OWN, nil
+	}
+	owner, err := uow.Users().GetByID(ownerID)
+	if err != nil {
+		return "", err
+	}
+	return models.PermissionScopeFor(actor, owner), nil
+}
+
+// authorize checks that the actor may perform the action on time entries of the owner.
+// Unknown owners are reported as gorm.ErrRecordNotFound, missing permissions as auth.ErrPermissionDenied.
+func authorize(uow *repositories.UnitOfWork, actor *models.User, ownerID uint, action string) error {
+	scope, err := scopeFor(uow, actor, ownerID)
+	if err != nil {
+		return err
+	}
+	if !actor.Role.HasPermission("time_entries:" + action + ":" + scope) {
+		return auth.ErrPermissionDenied
+	}
+	return nil
+}
+
+// readableUserIDs returns the users whose time entries the actor may read.
+// A nil slice means the actor may read the entries of the whole company.
+func readableUserIDs(uow *repositories.UnitOfWork, actor *models.User) ([]uint, error) {
+	if actor.Role.HasPermission(models.PERMISSION_TIME_ENTRIES_READ_ALL) {
+		return nil, nil
+	}
+	userIDs := []uint{}
+	if actor.Role.HasPermission(models.PERMISSION_TIME_ENTRIES_READ_OWN) {
+		userIDs = append(userIDs, actor.ID)
+	}
+	if actor.Role.HasPermission(models.PERMISSION_TIME_ENTRIES_READ_TEAM) {
+		team, err := uow.Users().GetByManagerID(actor.ID)
+		if err != nil {
+			return nil, err
+		}
+		for _, member := range team {
+			userIDs = append(userIDs, member.ID)
+		}
+	}
+	if len(userIDs) == 0 {
+		return nil, auth.ErrPermissionDenied
+	}
+	return userIDs, nil
+}
+
+// hideForbidden turns a missing read permission into gorm.ErrRecordNotFound,
+// so callers cannot probe for entries they are not allowed to see.
+func hideForbidden(err error) error {
+	if errors.Is(err, auth.ErrPermissionDenied) {
+		return gorm.ErrRecordNotFound
+	}
+	return err
+}
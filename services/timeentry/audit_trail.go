@@ -0,0 +1,139 @@
+package timeentry
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/r-52/embrace/models"
+	"github.com/r-52/embrace/repositories"
+	"gorm.io/gorm"
+)
+
+// Every change of a time entry is appended to the audit trail of its company, see models.TimeEntryAudit.
+
+// AuditBreak is a record at which the audit trail of a company is broken, because the record or one before it
+// was changed or removed.
+type AuditBreak struct {
+	CompanyID uint
+	Sequence  uint
+	AuditID   uint
+	Problem   string
+}
+
+func (b AuditBreak) String() string {
+	return fmt.Sprintf("company %d, record %d (sequence %d): %s", b.CompanyID, b.AuditID, b.Sequence, b.Problem)
+}
+
+// AuditHead identifies the last record of the audit trail of a company at some point in time.
+type AuditHead struct {
+	Sequence uint
+	Hash     string
+}
+
+func (h AuditHead) String() string {
+	return fmt.Sprintf("%d:%s", h.Sequence, h.Hash)
+}
+
+// ParseAuditHead parses an AuditHead in the form returned by its String method.
+func ParseAuditHead(value string) (AuditHead, error) {
+	var head AuditHead
+	if _, err := fmt.Sscanf(value, "%d:%s", &head.Sequence, &head.Hash); err != nil || head.Sequence == 0 {
+		return AuditHead{}, fmt.Errorf("invalid audit head %q, expected sequence:hash", value)
+	}
+	return head, nil
+}
+
+// recordAudit appends the change of a time entry from `before` to `after` by the actor to the audit trail.
+// `before` is nil for created entries and `after` is nil for deleted ones.
+func recordAudit(uow *repositories.UnitOfWork, actor *models.User, before, after *models.TimeEntry, reason string) error {
+	entry, operation := after, models.AUDIT_OPERATION_UPDATE
+	if before == nil {
+		operation = models.AUDIT_OPERATION_CREATE
+	} else if after == nil {
+		entry, operation = before, models.AUDIT_OPERATION_DELETE
+	}
+	beforeSnapshot, err := models.NewTimeEntrySnapshot(before)
+	if err != nil {
+		return err
+	}
+	afterSnapshot, err := models.NewTimeEntrySnapshot(after)
+	if err != nil {
+		return err
+	}
+
+	audit := &models.TimeEntryAudit{
+		CompanyID:   entry.CompanyID,
+		Sequence:    1,
+		TimeEntryID: entry.ID,
+		UserID:      entry.UserID,
+		Operation:   operation,
+		ActorID:     actor.ID,
+		Reason:      reason,
+		// Databases keep times to the microsecond at most, the hash has to match the stored time.
+		RecordedAt: time.Now().UTC().Truncate(time.Microsecond),
+		Before:     beforeSnapshot,
+		After:      afterSnapshot,
+	}
+	latest, err := uow.TimeEntryAudits().GetLatestByCompanyID(entry.CompanyID)
+	if err == nil {
+		audit.Sequence = latest.Sequence + 1
+		audit.PreviousHash = latest.Hash
+	} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return err
+	}
+	audit.Hash = audit.ComputeHash()
+	return uow.TimeEntryAudits().Create(audit)
+}
+
+// VerifyAuditTrail walks the audit trail of a company and returns where it is broken: records missing from the
+// sequence, records that do not reference the hash of the record before them and records whose content does not
+// match their hash. An intact trail has no breaks.
+//
+// Records removed from the end of the trail leave a shorter, intact trail behind. Only a head noted before, e.g.
+// the one printed by the verify-audit command, reveals them: if `head` is not nil, the trail must still contain it.
+func VerifyAuditTrail(uow *repositories.UnitOfWork, companyID uint, head *AuditHead) ([]AuditBreak, error) {
+	audits, err := uow.TimeEntryAudits().GetByCompanyID(companyID)
+	if err != nil {
+		return nil, err
+	}
+
+	breaks := []AuditBreak{}
+	var sequence uint
+	previousHash := ""
+	for _, audit := range audits {
+		report := func(problem string) {
+			breaks = append(breaks, AuditBreak{CompanyID: companyID, Sequence: audit.Sequence, AuditID: audit.ID, Problem: problem})
+		}
+		if audit.Sequence != sequence+1 {
+			report(fmt.Sprintf("expected sequence %d, records are missing", sequence+1))
+		}
+		if audit.PreviousHash != previousHash {
+			report("previous hash does not match the record before")
+		}
+		if audit.Hash != audit.ComputeHash() {
+			report("hash does not match the content")
+		}
+		if head != nil && audit.Sequence == head.Sequence && audit.Hash != head.Hash {
+			report("hash does not match the noted head")
+		}
+		sequence = audit.Sequence
+		previousHash = audit.Hash
+	}
+	if head != nil && sequence < head.Sequence {
+		breaks = append(breaks, AuditBreak{CompanyID: companyID, Sequence: head.Sequence, Problem: fmt.Sprintf("trail ends at sequence %d before the noted head, records were removed", sequence)})
+	}
+	return breaks, nil
+}
+
+// GetAuditHead returns the head of the audit trail of a company, or nil if the company has no records yet.
+func GetAuditHead(uow *repositories.UnitOfWork, companyID uint) (*AuditHead, error) {
+	latest, err := uow.TimeEntryAudits().GetLatestByCompanyID(companyID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &AuditHead{Sequence: latest.Sequence, Hash: latest.Hash}, nil
+}
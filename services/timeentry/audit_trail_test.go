@@ -0,0 +1,130 @@
+package timeentry_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/r-52/embrace/models"
+	dto "github.com/r-52/embrace/models/dto/timeentry"
+	"github.com/r-52/embrace/repositories"
+	"github.com/r-52/embrace/services/timeentry"
+	"gorm.io/gorm"
+)
+
+func TestTimeEntryService_Records_Audit_Trail(t *testing.T) {
+	f := setupFixture(t)
+	entry, err := serviceFor(f, f.employee).Create(f.employee, createRequest(f, 0, monday))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	update := &dto.UpdateTimeEntryRequest{StartTime: monday, EndTime: monday.Add(4 * time.Hour), TimeEntryTypeID: f.timeEntryType.ID, Reason: "left early"}
+	if _, err := serviceFor(f, f.manager).Update(f.manager, entry.ID, update); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := serviceFor(f, f.employee).Delete(f.employee, entry.ID, &dto.DeleteTimeEntryRequest{Reason: "booked twice"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	history, err := serviceFor(f, f.employee).History(f.employee, entry.ID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(history) != 3 {
+		t.Fatalf("expected 3 records, got %+v", history)
+	}
+	expected := []struct {
+		operation string
+		actorID   uint
+		reason    string
+	}{
+		{models.AUDIT_OPERATION_CREATE, f.employee.ID, ""},
+		{models.AUDIT_OPERATION_UPDATE, f.manager.ID, "left early"},
+		{models.AUDIT_OPERATION_DELETE, f.employee.ID, "booked twice"},
+	}
+	for i, audit := range history {
+		if audit.Operation != expected[i].operation || audit.ActorID != expected[i].actorID || audit.Reason != expected[i].reason || audit.Sequence != uint(i+1) {
+			t.Errorf("unexpected record %d: %+v", i, audit)
+		}
+		if i > 0 && audit.PreviousHash != history[i-1].Hash {
+			t.Errorf("expected record %d to reference the hash of the record before", i)
+		}
+	}
+	if history[0].Before != "" || history[0].After == "" || history[2].Before == "" || history[2].After != "" {
+		t.Errorf("unexpected snapshots: %+v", history)
+	}
+
+	if _, err := serviceFor(f, f.colleague).History(f.colleague, entry.ID); !errors.Is(err, gorm.ErrRecordNotFound) {
+		t.Errorf("expected ErrRecordNotFound for a colleague, got %v", err)
+	}
+	if _, err := serviceFor(f, f.foreignUser).History(f.foreignUser, entry.ID); !errors.Is(err, gorm.ErrRecordNotFound) {
+		t.Errorf("expected ErrRecordNotFound for another company, got %v", err)
+	}
+}
+
+func TestVerifyAuditTrail(t *testing.T) {
+	f := setupFixture(t)
+	for day := 0; day < 3; day++ {
+		if _, err := serviceFor(f, f.employee).Create(f.employee, createRequest(f, 0, monday.AddDate(0, 0, day))); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	uow := repositories.NewUnitOfWork(f.db)
+	breaks, err := timeentry.VerifyAuditTrail(uow, f.employee.CompanyID, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(breaks) != 0 {
+		t.Errorf("expected an intact trail, got %v", breaks)
+	}
+
+	if err := f.db.Model(&models.TimeEntryAudit{}).Where("sequence = ?", 2).Update("reason", "changed").Error; err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	breaks, _ = timeentry.VerifyAuditTrail(uow, f.employee.CompanyID, nil)
+	if len(breaks) != 1 || breaks[0].Sequence != 2 {
+		t.Errorf("expected the changed record to break the trail, got %v", breaks)
+	}
+
+	if err := f.db.Unscoped().Where("sequence = ?", 1).Delete(&models.TimeEntryAudit{}).Error; err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	breaks, _ = timeentry.VerifyAuditTrail(uow, f.employee.CompanyID, nil)
+	if len(breaks) != 3 {
+		t.Errorf("expected the removed record to break the trail, got %v", breaks)
+	}
+}
+
+func TestVerifyAuditTrail_Detects_Truncated_Tail(t *testing.T) {
+	f := setupFixture(t)
+	for day := 0; day < 3; day++ {
+		if _, err := serviceFor(f, f.employee).Create(f.employee, createRequest(f, 0, monday.AddDate(0, 0, day))); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	uow := repositories.NewUnitOfWork(f.db)
+	head, err := timeentry.GetAuditHead(uow, f.employee.CompanyID)
+	if err != nil || head == nil || head.Sequence != 3 {
+		t.Fatalf("expected the head at sequence 3, got %v, %v", head, err)
+	}
+	parsed, err := timeentry.ParseAuditHead(head.String())
+	if err != nil || parsed != *head {
+		t.Fatalf("expected the head to parse back, got %v, %v", parsed, err)
+	}
+	if breaks, _ := timeentry.VerifyAuditTrail(uow, f.employee.CompanyID, head); len(breaks) != 0 {
+		t.Errorf("expected an intact trail, got %v", breaks)
+	}
+
+	if err := f.db.Unscoped().Where("sequence = ?", 3).Delete(&models.TimeEntryAudit{}).Error; err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if breaks, _ := timeentry.VerifyAuditTrail(uow, f.employee.CompanyID, nil); len(breaks) != 0 {
+		t.Errorf("expected the shorter trail to be intact on its own, got %v", breaks)
+	}
+	if breaks, _ := timeentry.VerifyAuditTrail(uow, f.employee.CompanyID, head); len(breaks) != 1 || breaks[0].Sequence != 3 {
+		t.Errorf("expected the noted head to reveal the removed record, got %v", breaks)
+	}
+	if _, err := timeentry.ParseAuditHead("abc"); err == nil {
+		t.Errorf("expected an invalid head to be rejected")
+	}
+}
@@ -0,0 +1,94 @@
+package timeentry
+
+import (
+	"database/sql"
+	"errors"
+	"math"
+	"time"
+
+	"github.com/r-52/embrace/models"
+	"github.com/r-52/embrace/repositories"
+	"github.com/r-52/embrace/services/compliance"
+	"gorm.io/gorm"
+)
+
+// ComputeDuration returns the duration in hours of an entry on `timeEntryType` from `start` to `end` as the policy
+// says, and the hours of break it deducted. The times are rounded in `location` first, then the statutory break
+// is deducted and billable durations are rounded up to the increment. A nil policy keeps the booked times.
+func ComputeDuration(policy *models.DurationPolicy, timeEntryType *models.TimeEntryType, start, end time.Time, location *time.Location) (float64, float64) {
+	if policy == nil || timeEntryType.IsQuotaRelevant {
+		return end.Sub(start).Hours(), 0
+	}
+
+	worked := max(roundTime(end, policy.EndRounding, location).Sub(roundTime(start, policy.StartRounding, location)), 0)
+	var deducted time.Duration
+	if policy.DeductBreaks {
+		deducted = statutoryBreak(worked)
+		worked -= deducted
+	}
+	if increment := time.Duration(policy.BillableIncrement) * time.Minute; timeEntryType.IsBillable && increment > 0 && end.After(start) {
+		worked = max(time.Duration(math.Ceil(float64(worked)/float64(increment)))*increment, increment)
+	}
+	return worked.Hours(), deducted.Hours()
+}
+
+// statutoryBreak returns the break to deduct from `worked` time without a break. It takes up the time beyond
+// compliance.MAX_WORK_WITHOUT_BREAK until the short break is reached, and the time beyond compliance.LONG_SHIFT that
+// is left after it until the long break is reached, so the deduction never shortens the worked time below a limit.
+func statutoryBreak(worked time.Duration) time.Duration {
+	short := min(max(worked-compliance.MAX_WORK_WITHOUT_BREAK, 0), compliance.SHIFT_BREAK)
+	long := min(max(worked-compliance.LONG_SHIFT-compliance.SHIFT_BREAK, 0), compliance.LONG_SHIFT_BREAK-compliance.SHIFT_BREAK)
+	return short + long
+}
+
+// roundTime rounds t to a multiple of the increment after midnight in `location`.
+func roundTime(t time.Time, rounding models.TimeRounding, location *time.Location) time.Time {
+	increment := time.Duration(rounding.Increment) * time.Minute
+	if increment <= 0 {
+		return t
+	}
+	local := t.In(location)
+	midnight := time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, location)
+	sinceMidnight := local.Sub(midnight)
+	rounded := sinceMidnight.Truncate(increment)
+	switch rounding.Mode {
+	case models.TIME_ROUNDING_UP:
+		if rounded < sinceMidnight {
+			rounded += increment
+		}
+	case models.TIME_ROUNDING_DOWN:
+	default:
+		rounded = sinceMidnight.Round(increment)
+	}
+	return midnight.Add(rounded).In(t.Location())
+}
+
+// applyDurationPolicy sets the duration of a closed time entry as the duration policy of its company says.
+func applyDurationPolicy(uow *repositories.UnitOfWork, entry *models.TimeEntry) error {
+	if entry.IsRunning() {
+		return nil
+	}
+	timeEntryType, err := uow.TimeEntryTypes().GetByID(entry.TimeEntryTypeID)
+	if err != nil {
+		return err
+	}
+	policy, err := uow.DurationPolicies().GetByCompanyID(entry.CompanyID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		policy = nil
+	} else if err != nil {
+		return err
+	}
+	location := time.UTC
+	if policy != nil {
+		company, err := uow.Companies().GetByID(entry.CompanyID)
+		if err != nil {
+			return err
+		}
+		location = company.Location()
+	}
+
+	duration, deducted := ComputeDuration(policy, timeEntryType, entry.StartTime, entry.EndTime.Time, location)
+	entry.Duration = sql.NullFloat64{Float64: duration, Valid: true}
+	entry.DeductedBreak = deducted
+	return nil
+}
@@ -0,0 +1,127 @@
+package timeentry
+
+import (
+	"errors"
+	"time"
+
+	"github.com/r-52/embrace/models"
+	dto "github.com/r-52/embrace/models/dto/timeentry"
+	"github.com/r-52/embrace/repositories"
+	"gorm.io/gorm"
+)
+
+// DurationPolicyService manages how the durations of a company's time entries are computed, see models.DurationPolicy.
+type DurationPolicyService struct {
+	unitOfWork *repositories.UnitOfWork
+}
+
+type DurationPolicyServiceInterface interface {
+	GetPolicy(companyID uint) (*models.DurationPolicy, error)
+	UpdatePolicy(companyID uint, req *dto.DurationPolicyRequest) (*models.DurationPolicy, error)
+	Reapply(actor *models.User, req *dto.ReapplyDurationPolicyRequest) (int, error)
+}
+
+// NewDurationPolicyService creates a DurationPolicyService. The database should be scoped to the company, see repositories.WithTenant.
+func NewDurationPolicyService(db *gorm.DB) *DurationPolicyService {
+	return NewDurationPolicyServiceWithUnitOfWork(repositories.NewUnitOfWork(db))
+}
+
+// NewDurationPolicyServiceWithUnitOfWork creates a DurationPolicyService whose repositories join the given unit of work.
+func NewDurationPolicyServiceWithUnitOfWork(uow *repositories.UnitOfWork) *DurationPolicyService {
+	return &DurationPolicyService{
+		unitOfWork: uow,
+	}
+}
+
+// GetPolicy returns the duration policy of the company. Companies without one get a policy that keeps the booked times.
+func (s *DurationPolicyService) GetPolicy(companyID uint) (*models.DurationPolicy, error) {
+	policy, err := s.unitOfWork.DurationPolicies().GetByCompanyID(companyID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return &models.DurationPolicy{
+			CompanyID:     companyID,
+			StartRounding: models.TimeRounding{Mode: models.TIME_ROUNDING_NEAREST},
+			EndRounding:   models.TimeRounding{Mode: models.TIME_ROUNDING_NEAREST},
+		}, nil
+	}
+	return policy, err
+}
+
+// UpdatePolicy sets the duration policy of the company. It applies to entries written from now on,
+// existing entries keep their duration until the policy is applied to them again, see Reapply.
+func (s *DurationPolicyService) UpdatePolicy(companyID uint, req *dto.DurationPolicyRequest) (*models.DurationPolicy, error) {
+	var updated *models.DurationPolicy
+	err := s.unitOfWork.Transaction(func(uow *repositories.UnitOfWork) error {
+		policy, err := uow.DurationPolicies().GetByCompanyID(companyID)
+		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			return err
+		}
+		if policy == nil {
+			policy = &models.DurationPolicy{CompanyID: companyID}
+		}
+		policy.StartRounding = req.StartRounding.TimeRounding()
+		policy.EndRounding = req.EndRounding.TimeRounding()
+		policy.DeductBreaks = req.DeductBreaks
+		policy.BillableIncrement = req.BillableIncrement
+		if policy.ID == 0 {
+			err = uow.DurationPolicies().Create(policy)
+		} else {
+			err = uow.DurationPolicies().Update(policy)
+		}
+		if err != nil {
+			return err
+		}
+		updated = policy
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return updated, nil
+}
+
+// Reapply computes the durations of the closed time entries of the actor's company that start within the period
+// of the request again, from their booked times and the current policy. Entries in locked timesheet periods keep
+// their duration. It returns how many durations changed.
+func (s *DurationPolicyService) Reapply(actor *models.User, req *dto.ReapplyDurationPolicyRequest) (int, error) {
+	updated := 0
+	err := s.unitOfWork.Transaction(func(uow *repositories.UnitOfWork) error {
+		company, err := uow.Companies().GetByID(actor.CompanyID)
+		if err != nil {
+			return err
+		}
+		location := company.Location()
+		from := time.Date(req.From.Year(), req.From.Month(), req.From.Day(), 0, 0, 0, 0, location)
+		to := time.Date(req.To.Year(), req.To.Month(), req.To.Day()+1, 0, 0, 0, 0, location)
+		entries, err := uow.TimeEntries().GetByCompanyIDAndDateRange(actor.CompanyID, from, to)
+		if err != nil {
+			return err
+		}
+
+		for i := range entries {
+			entry := &entries[i]
+			locked, err := isLocked(uow, entry)
+			if err != nil {
+				return err
+			}
+			if entry.IsRunning() || locked {
+				continue
+			}
+			before := *entry
+			if err := applyDurationPolicy(uow, entry); err != nil {
+				return err
+			}
+			if entry.Duration == before.Duration && entry.DeductedBreak == before.DeductedBreak {
+				continue
+			}
+			if err := updateEntry(uow, actor, &before, entry, "duration policy reapplied"); err != nil {
+				return err
+			}
+			updated++
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return updated, nil
+}
@@ -0,0 +1,81 @@
+package timeentry_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/r-52/embrace/models"
+	dto "github.com/r-52/embrace/models/dto/timeentry"
+	"github.com/r-52/embrace/repositories"
+	"github.com/r-52/embrace/services/timeentry"
+)
+
+func durationPolicyFor(f *fixture, actor *models.User) *timeentry.DurationPolicyService {
+	return timeentry.NewDurationPolicyService(repositories.WithTenant(f.db, actor.CompanyID))
+}
+
+func TestDurationPolicyService_Applies_Policy(t *testing.T) {
+	f := setupFixture(t)
+	companyID := f.admin.CompanyID
+
+	policy, err := durationPolicyFor(f, f.admin).GetPolicy(companyID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if policy.ID != 0 || policy.DeductBreaks || policy.StartRounding.Increment != 0 {
+		t.Errorf("expected a policy that keeps the booked times, got %+v", policy)
+	}
+
+	booked, err := serviceFor(f, f.employee).Create(f.employee, createRequest(f, 0, monday.Add(7*time.Minute)))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	policy, err = durationPolicyFor(f, f.admin).UpdatePolicy(companyID, &dto.DurationPolicyRequest{
+		StartRounding: dto.TimeRoundingRequest{Increment: 15},
+		EndRounding:   dto.TimeRoundingRequest{Increment: 15, Mode: models.TIME_ROUNDING_DOWN},
+		DeductBreaks:  true,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if policy.StartRounding.Mode != models.TIME_ROUNDING_NEAREST || policy.EndRounding.Mode != models.TIME_ROUNDING_DOWN {
+		t.Errorf("unexpected policy: %+v", policy)
+	}
+
+	// 08:07 to 16:07 is rounded to 08:00 to 16:00, which loses half an hour of break.
+	entry, err := serviceFor(f, f.employee).Create(f.employee, createRequest(f, 0, monday.AddDate(0, 0, 1).Add(7*time.Minute)))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if entry.Duration.Float64 != 7.5 || entry.DeductedBreak != 0.5 || !entry.StartTime.Equal(monday.AddDate(0, 0, 1).Add(7*time.Minute)) {
+		t.Errorf("expected 7.5 hours from the booked times, got %+v", entry)
+	}
+
+	timer := timerFor(f, f.colleague)
+	if _, err := timer.ClockIn(f.colleague, &dto.ClockInRequest{TimeEntryTypeID: f.timeEntryType.ID}, monday.Add(2*time.Minute)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	stopped, err := timer.ClockOut(f.colleague, monday.Add(4*time.Hour+14*time.Minute))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if *stopped.Entry.Duration != 4 {
+		t.Errorf("expected the timer to be rounded to 4 hours, got %v", *stopped.Entry.Duration)
+	}
+
+	// The entry booked before the policy keeps its duration until the policy is applied again.
+	if stored, _ := serviceFor(f, f.employee).Get(f.employee, booked.ID); stored.Duration.Float64 != 8 {
+		t.Errorf("expected the earlier entry to keep 8 hours, got %v", stored.Duration.Float64)
+	}
+	updated, err := durationPolicyFor(f, f.admin).Reapply(f.admin, &dto.ReapplyDurationPolicyRequest{From: monday, To: monday.AddDate(0, 0, 1)})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if updated != 1 {
+		t.Errorf("expected only the earlier entry to change, got %d", updated)
+	}
+	if stored, _ := serviceFor(f, f.employee).Get(f.employee, booked.ID); stored.Duration.Float64 != 7.5 || !stored.StartTime.Equal(booked.StartTime) {
+		t.Errorf("expected the earlier entry to take 7.5 hours from its booked times, got %+v", stored)
+	}
+}
@@ -0,0 +1,55 @@
+package timeentry_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/r-52/embrace/models"
+	"github.com/r-52/embrace/services/timeentry"
+)
+
+func TestComputeDuration(t *testing.T) {
+	work := &models.TimeEntryType{}
+	billable := &models.TimeEntryType{IsBillable: true}
+	vacation := &models.TimeEntryType{IsQuotaRelevant: true, IsBillable: true}
+	quarterHours := models.TimeRounding{Increment: 15, Mode: models.TIME_ROUNDING_NEAREST}
+	at := func(clock string) time.Time {
+		value, _ := time.Parse(time.DateTime, "2024-03-04 "+clock+":00")
+		return value
+	}
+
+	tests := []struct {
+		name          string
+		policy        *models.DurationPolicy
+		timeEntryType *models.TimeEntryType
+		start, end    string
+		expected      float64
+		deducted      float64
+	}{
+		{name: "no policy", timeEntryType: work, start: "08:07", end: "08:52", expected: 0.75},
+		{name: "nearest", policy: &models.DurationPolicy{StartRounding: quarterHours, EndRounding: quarterHours}, timeEntryType: work, start: "08:07", end: "16:53", expected: 9},
+		{
+			name: "start up and end down", timeEntryType: work, start: "08:07", end: "16:53", expected: 8.5,
+			policy: &models.DurationPolicy{StartRounding: models.TimeRounding{Increment: 15, Mode: models.TIME_ROUNDING_UP},
+				EndRounding: models.TimeRounding{Increment: 15, Mode: models.TIME_ROUNDING_DOWN}},
+		},
+		{name: "five minutes up", policy: &models.DurationPolicy{EndRounding: models.TimeRounding{Increment: 5, Mode: models.TIME_ROUNDING_UP}}, timeEntryType: work, start: "08:00", end: "08:51", expected: 55.0 / 60},
+		{name: "six hours keep no break", policy: &models.DurationPolicy{DeductBreaks: true}, timeEntryType: work, start: "08:00", end: "14:00", expected: 6},
+		{name: "deduction stops at six hours", policy: &models.DurationPolicy{DeductBreaks: true}, timeEntryType: work, start: "08:00", end: "14:12", expected: 6, deducted: 0.2},
+		{name: "eight hours lose half an hour", policy: &models.DurationPolicy{DeductBreaks: true}, timeEntryType: work, start: "08:00", end: "16:00", expected: 7.5, deducted: 0.5},
+		{name: "ten hours lose three quarters", policy: &models.DurationPolicy{DeductBreaks: true}, timeEntryType: work, start: "08:00", end: "18:00", expected: 9.25, deducted: 0.75},
+		{name: "billable increment", policy: &models.DurationPolicy{BillableIncrement: 15}, timeEntryType: billable, start: "08:00", end: "08:20", expected: 0.5},
+		{name: "minimum billable increment", policy: &models.DurationPolicy{StartRounding: quarterHours, EndRounding: quarterHours, BillableIncrement: 15}, timeEntryType: billable, start: "08:01", end: "08:04", expected: 0.25},
+		{name: "increment only for billable types", policy: &models.DurationPolicy{BillableIncrement: 15}, timeEntryType: work, start: "08:00", end: "08:20", expected: 20.0 / 60},
+		{name: "quota relevant types keep their times", policy: &models.DurationPolicy{StartRounding: quarterHours, DeductBreaks: true}, timeEntryType: vacation, start: "00:05", end: "08:05", expected: 8},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			duration, deducted := timeentry.ComputeDuration(tt.policy, tt.timeEntryType, at(tt.start), at(tt.end), time.UTC)
+			if duration != tt.expected || deducted != tt.deducted {
+				t.Errorf("expected %v hours with %v deducted, got %v with %v", tt.expected, tt.deducted, duration, deducted)
+			}
+		})
+	}
+}
@@ -0,0 +1,97 @@
+package timeentry
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrUnknownTimeEntryType is returned when the time entry type does not exist in the user's company.
+var ErrUnknownTimeEntryType = errors.New("E4000")
+
+// ErrUnknownUser is returned when the time entry should be booked for a user that does not exist in the company.
+var ErrUnknownUser = errors.New("E4001")
+
+// ErrAlreadyClockedIn is returned when the user clocks in while a timer is already running.
+var ErrAlreadyClockedIn = errors.New("E4002")
+
+// ErrNotClockedIn is returned when the user clocks out or pauses without a running timer.
+var ErrNotClockedIn = errors.New("E4003")
+
+// ErrTimerPaused is returned when the user clocks in while a timer is paused. It has to be resumed or clocked out first.
+var ErrTimerPaused = errors.New("E4004")
+
+// ErrTimerNotPaused is returned when the user resumes without a paused timer.
+var ErrTimerNotPaused = errors.New("E4005")
+
+// ErrEndBeforeStart is returned when a time entry does not end after it starts.
+var ErrEndBeforeStart = errors.New("E4006")
+
+// ErrTimeEntryTooLong is returned when a time entry is longer than MAX_TIME_ENTRY_DURATION.
+var ErrTimeEntryTooLong = errors.New("E4007")
+
+// ErrTimeEntryOverlaps is returned when a time entry overlaps another entry of the same user.
+var ErrTimeEntryOverlaps = errors.New("E4008")
+
+// ErrQuotaRelevantTimer is returned when the user clocks in on a quota relevant time entry type.
+var ErrQuotaRelevantTimer = errors.New("E4009")
+
+// ErrInvalidLeavePeriod is returned when a leave request ends before it starts, is longer than MAX_LEAVE_DAYS,
+// covers no working day or requests half of more than one day.
+var ErrInvalidLeavePeriod = errors.New("E4010")
+
+// ErrLeaveRequestNotPending is returned when a leave request is approved or rejected that was decided before.
+var ErrLeaveRequestNotPending = errors.New("E4011")
+
+// ErrLeaveRequestClosed is returned when a leave request is cancelled that was rejected or cancelled before.
+var ErrLeaveRequestClosed = errors.New("E4012")
+
+// ErrTimesheetLocked is returned when a time entry is booked, changed or deleted that starts in a locked timesheet period.
+var ErrTimesheetLocked = errors.New("E4013")
+
+// ErrTimesheetNotOpen is returned when a timesheet period is submitted that was submitted before.
+var ErrTimesheetNotOpen = errors.New("E4014")
+
+// ErrTimesheetNotSubmitted is returned when a timesheet period is approved that was not submitted or approved before.
+var ErrTimesheetNotSubmitted = errors.New("E4015")
+
+// ErrTimesheetOpen is returned when a timesheet period is reopened that is open.
+var ErrTimesheetOpen = errors.New("E4016")
+
+// ErrLeaveTypeNotQuotaRelevant is returned when leave is requested on a time entry type that does not use up a quota.
+var ErrLeaveTypeNotQuotaRelevant = errors.New("E4017")
+
+// ErrQuotaRelevantEntry is returned when a time entry is booked on, or changed to, a quota relevant time entry type.
+// Leave is booked by approving a leave request.
+var ErrQuotaRelevantEntry = errors.New("E4018")
+
+// ErrLeaveEntry is returned when a time entry booked for a leave request is changed or deleted. The leave request
+// has to be cancelled instead.
+var ErrLeaveEntry = errors.New("E4019")
+
+// ImportError names the entry of an import that failed. It unwraps to the error of that entry.
+type ImportError struct {
+	Index int
+	Err   error
+}
+
+func (e *ImportError) Error() string {
+	return fmt.Sprintf("entries[%d]: %s", e.Index, e.Err.Error())
+}
+
+func (e *ImportError) Unwrap() error {
+	return e.Err
+}
+
+// OverlapError is returned when a time entry overlaps other entries of the same user.
+// It matches ErrTimeEntryOverlaps with errors.Is and names the conflicting entries.
+type OverlapError struct {
+	EntryIDs []uint
+}
+
+func (e *OverlapError) Error() string {
+	return ErrTimeEntryOverlaps.Error()
+}
+
+func (e *OverlapError) Is(target error) bool {
+	return target == ErrTimeEntryOverlaps
+}
@@ -0,0 +1,331 @@
+package timeentry
+
+import (
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/r-52/embrace/models"
+	dto "github.com/r-52/embrace/models/dto/timeentry"
+	"github.com/r-52/embrace/repositories"
+	"github.com/r-52/embrace/services/auth"
+	"github.com/r-52/embrace/services/schedule"
+	"gorm.io/gorm"
+)
+
+const ACTION_APPROVE = "approve"
+
+// MAX_LEAVE_DAYS is the number of calendar days a single leave request may cover at most.
+const MAX_LEAVE_DAYS = 366
+
+// LeaveRequestService implements requesting leave and deciding on it. A request is approved or
+// rejected by the user's manager or anyone who may approve the time entries of the whole company.
+// Approving a request books a time entry on every working day of the leave, which uses up quota
+// like any other booking, and cancelling an approved request deletes the entries again.
+type LeaveRequestService struct {
+	unitOfWork *repositories.UnitOfWork
+}
+
+type LeaveRequestServiceInterface interface {
+	Request(actor *models.User, req *dto.CreateLeaveRequestRequest) (*models.LeaveRequest, error)
+	List(actor *models.User) ([]models.LeaveRequest, error)
+	Inbox(actor *models.User) ([]models.LeaveRequest, error)
+	Approve(actor *models.User, id uint, req *dto.DecideLeaveRequestRequest, now time.Time) (*models.LeaveRequest, error)
+	Reject(actor *models.User, id uint, req *dto.DecideLeaveRequestRequest, now time.Time) (*models.LeaveRequest, error)
+	Cancel(actor *models.User, id uint, req *dto.DecideLeaveRequestRequest, now time.Time) (*models.LeaveRequest, error)
+}
+
+// NewLeaveRequestService creates a LeaveRequestService. The database should be scoped to the actor's company, see repositories.WithTenant.
+func NewLeaveRequestService(db *gorm.DB) *LeaveRequestService {
+	return NewLeaveRequestServiceWithUnitOfWork(repositories.NewUnitOfWork(db))
+}
+
+// NewLeaveRequestServiceWithUnitOfWork creates a LeaveRequestService whose repositories join the given unit of work.
+func NewLeaveRequestServiceWithUnitOfWork(uow *repositories.UnitOfWork) *LeaveRequestService {
+	return &LeaveRequestService{
+		unitOfWork: uow,
+	}
+}
+
+// Request requests leave for the actor on a quota relevant time entry type, otherwise it fails with
+// ErrLeaveTypeNotQuotaRelevant. It fails with ErrInvalidLeavePeriod if the period ends before it starts, is longer
+// than MAX_LEAVE_DAYS or covers no working day, such as a period of weekends and holidays only.
+func (s *LeaveRequestService) Request(actor *models.User, req *dto.CreateLeaveRequestRequest) (*models.LeaveRequest, error) {
+	if err := authorize(s.unitOfWork, actor, actor.ID, ACTION_WRITE); err != nil {
+		return nil, err
+	}
+	timeEntryType, err := getTimeEntryType(s.unitOfWork, req.TimeEntryTypeID)
+	if err != nil {
+		return nil, err
+	}
+	if !timeEntryType.IsQuotaRelevant {
+		return nil, ErrLeaveTypeNotQuotaRelevant
+	}
+	start, err := time.Parse(time.DateOnly, req.StartDate)
+	if err != nil {
+		return nil, err
+	}
+	end, err := time.Parse(time.DateOnly, req.EndDate)
+	if err != nil {
+		return nil, err
+	}
+	if end.Before(start) || !end.Before(start.AddDate(0, 0, MAX_LEAVE_DAYS)) {
+		return nil, ErrInvalidLeavePeriod
+	}
+	if req.HalfDay && !end.Equal(start) {
+		return nil, ErrInvalidLeavePeriod
+	}
+	days, err := leaveDays(s.unitOfWork, actor, start, end)
+	if err != nil {
+		return nil, err
+	}
+	if len(days) == 0 {
+		return nil, ErrInvalidLeavePeriod
+	}
+
+	leaveRequest := &models.LeaveRequest{
+		CompanyID:       actor.CompanyID,
+		UserID:          actor.ID,
+		TimeEntryTypeID: req.TimeEntryTypeID,
+		StartDate:       start,
+		EndDate:         end,
+		HalfDay:         req.HalfDay,
+		Note:            req.Note,
+		Status:          models.LEAVE_REQUEST_REQUESTED,
+	}
+	if err := s.unitOfWork.LeaveRequests().Create(leaveRequest); err != nil {
+		return nil, err
+	}
+	return leaveRequest, nil
+}
+
+// List returns the leave requests of the actor, the latest leave first.
+func (s *LeaveRequestService) List(actor *models.User) ([]models.LeaveRequest, error) {
+	if err := authorize(s.unitOfWork, actor, actor.ID, ACTION_READ); err != nil {
+		return nil, err
+	}
+	return s.unitOfWork.LeaveRequests().GetByUserID(actor.ID)
+}
+
+// Inbox returns the pending leave requests the actor may decide on, the earliest leave first.
+func (s *LeaveRequestService) Inbox(actor *models.User) ([]models.LeaveRequest, error) {
+	if actor.Role.HasPermission(models.PERMISSION_TIME_ENTRIES_APPROVE_ALL) {
+		return s.unitOfWork.LeaveRequests().GetPending(nil)
+	}
+	if !actor.Role.HasPermission(models.PERMISSION_TIME_ENTRIES_APPROVE_TEAM) {
+		return nil, auth.ErrPermissionDenied
+	}
+	team, err := s.unitOfWork.Users().GetByManagerID(actor.ID)
+	if err != nil {
+		return nil, err
+	}
+	userIDs := make([]uint, 0, len(team))
+	for _, member := range team {
+		userIDs = append(userIDs, member.ID)
+	}
+	return s.unitOfWork.LeaveRequests().GetPending(userIDs)
+}
+
+// Approve approves a pending leave request and books its time entries. It fails with ErrLeaveRequestNotPending
+// if the request was decided before, and like any booking if the entries overlap others or exceed the quota.
+func (s *LeaveRequestService) Approve(actor *models.User, id uint, req *dto.DecideLeaveRequestRequest, now time.Time) (*models.LeaveRequest, error) {
+	var approved *models.LeaveRequest
+	err := s.unitOfWork.Transaction(func(uow *repositories.UnitOfWork) error {
+		leaveRequest, err := getDecidable(uow, actor, id)
+		if err != nil {
+			return err
+		}
+		if err := decide(uow, actor, leaveRequest, models.LEAVE_REQUEST_APPROVED, req.Note, now); err != nil {
+			return err
+		}
+		if err := bookLeave(uow, actor, leaveRequest); err != nil {
+			return err
+		}
+		approved = leaveRequest
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return approved, nil
+}
+
+// Reject rejects a pending leave request. It fails with ErrLeaveRequestNotPending if the request was decided before.
+func (s *LeaveRequestService) Reject(actor *models.User, id uint, req *dto.DecideLeaveRequestRequest, now time.Time) (*models.LeaveRequest, error) {
+	var rejected *models.LeaveRequest
+	err := s.unitOfWork.Transaction(func(uow *repositories.UnitOfWork) error {
+		leaveRequest, err := getDecidable(uow, actor, id)
+		if err != nil {
+			return err
+		}
+		if err := decide(uow, actor, leaveRequest, models.LEAVE_REQUEST_REJECTED, req.Note, now); err != nil {
+			return err
+		}
+		rejected = leaveRequest
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return rejected, nil
+}
+
+// Cancel withdraws a requested or approved leave request. The user who requested it may cancel it as well as
+// anyone who may decide on it. The time entries of approved leave are deleted, which refunds the quota they used up.
+// It fails with ErrLeaveRequestClosed if the request was rejected or cancelled before.
+func (s *LeaveRequestService) Cancel(actor *models.User, id uint, req *dto.DecideLeaveRequestRequest, now time.Time) (*models.LeaveRequest, error) {
+	var cancelled *models.LeaveRequest
+	err := s.unitOfWork.Transaction(func(uow *repositories.UnitOfWork) error {
+		leaveRequest, err := uow.LeaveRequests().GetByID(id)
+		if err != nil {
+			return err
+		}
+		if err := authorize(uow, actor, leaveRequest.UserID, ACTION_READ); err != nil {
+			return hideForbidden(err)
+		}
+		action := ACTION_APPROVE
+		if leaveRequest.UserID == actor.ID {
+			action = ACTION_WRITE
+		}
+		if err := authorize(uow, actor, leaveRequest.UserID, action); err != nil {
+			return err
+		}
+		if leaveRequest.Status != models.LEAVE_REQUEST_REQUESTED && leaveRequest.Status != models.LEAVE_REQUEST_APPROVED {
+			return ErrLeaveRequestClosed
+		}
+
+		if leaveRequest.Status == models.LEAVE_REQUEST_APPROVED {
+			entries, err := uow.TimeEntries().GetByLeaveRequestID(leaveRequest.ID)
+			if err != nil {
+				return err
+			}
+			for i := range entries {
+				if err := deleteEntry(uow, actor, &entries[i], "leave request cancelled"); err != nil {
+					return err
+				}
+			}
+		}
+		err = decide(uow, actor, leaveRequest, models.LEAVE_REQUEST_CANCELLED, req.Note, now)
+		if errors.Is(err, ErrLeaveRequestNotPending) {
+			return ErrLeaveRequestClosed
+		}
+		if err != nil {
+			return err
+		}
+		cancelled = leaveRequest
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return cancelled, nil
+}
+
+// getDecidable loads a pending leave request the actor may approve or reject. Only those who may approve the
+// time entries of the whole company decide on their own leave. Requests the actor may not even read are
+// reported as gorm.ErrRecordNotFound, readable ones as auth.ErrPermissionDenied.
+func getDecidable(uow *repositories.UnitOfWork, actor *models.User, id uint) (*models.LeaveRequest, error) {
+	leaveRequest, err := uow.LeaveRequests().GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if err := authorize(uow, actor, leaveRequest.UserID, ACTION_READ); err != nil {
+		return nil, hideForbidden(err)
+	}
+	if err := authorize(uow, actor, leaveRequest.UserID, ACTION_APPROVE); err != nil {
+		return nil, err
+	}
+	if leaveRequest.UserID == actor.ID && !actor.Role.HasPermission(models.PERMISSION_TIME_ENTRIES_APPROVE_ALL) {
+		return nil, auth.ErrPermissionDenied
+	}
+	if leaveRequest.Status != models.LEAVE_REQUEST_REQUESTED {
+		return nil, ErrLeaveRequestNotPending
+	}
+	return leaveRequest, nil
+}
+
+// decide moves a leave request to the status and records the actor's decision.
+// It returns ErrLeaveRequestNotPending if the request was changed concurrently.
+func decide(uow *repositories.UnitOfWork, actor *models.User, leaveRequest *models.LeaveRequest, status, note string, now time.Time) error {
+	from := leaveRequest.Status
+	leaveRequest.Status = status
+	leaveRequest.DecidedByID = &actor.ID
+	leaveRequest.DecidedAt = sql.NullTime{Time: now, Valid: true}
+	leaveRequest.DecisionNote = note
+	err := uow.LeaveRequests().Transition(leaveRequest, from)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return ErrLeaveRequestNotPending
+	}
+	return err
+}
+
+// bookLeave books a time entry on every working day of an approved leave request. The entries start at the
+// beginning of the day in the company's timezone and last the hours the user is scheduled to work that day, or
+// half of them for half-day leave, so a target day is fully covered by the leave.
+func bookLeave(uow *repositories.UnitOfWork, actor *models.User, leaveRequest *models.LeaveRequest) error {
+	user, err := uow.Users().GetByID(leaveRequest.UserID)
+	if err != nil {
+		return err
+	}
+	company, err := uow.Companies().GetByID(leaveRequest.CompanyID)
+	if err != nil {
+		return err
+	}
+
+	days, err := leaveDays(uow, user, leaveRequest.StartDate, leaveRequest.EndDate)
+	if err != nil {
+		return err
+	}
+	for _, day := range days {
+		hours := day.hours
+		if leaveRequest.HalfDay {
+			hours /= 2
+		}
+		entry := &models.TimeEntry{
+			StartTime:       time.Date(day.date.Year(), day.date.Month(), day.date.Day(), 0, 0, 0, 0, company.Location()),
+			Note:            leaveRequest.Note,
+			CompanyID:       leaveRequest.CompanyID,
+			UserID:          leaveRequest.UserID,
+			TimeEntryTypeID: leaveRequest.TimeEntryTypeID,
+			LeaveRequestID:  &leaveRequest.ID,
+		}
+		entry.Close(entry.StartTime.Add(time.Duration(hours * float64(time.Hour))))
+		if err := NewTimeEntryValidatorWithUnitOfWork(uow).Validate(entry); err != nil {
+			return err
+		}
+		if err := createEntry(uow, actor, entry, "leave request approved"); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// leaveDay is a working day of a leave and the hours the leave takes on it.
+type leaveDay struct {
+	date  time.Time
+	hours float64
+}
+
+// leaveDays returns the working days of the user from start to end, both inclusive, see schedule.TargetHoursService.
+// Days the user is not scheduled to work on and the holidays of the user's holiday calendar are not working days,
+// and half-day holidays only take half of the scheduled hours. Approved leave is ignored, as it is booked from these days.
+func leaveDays(uow *repositories.UnitOfWork, user *models.User, start, end time.Time) ([]leaveDay, error) {
+	target, err := schedule.NewTargetHoursServiceWithUnitOfWork(uow).TargetHoursOf(user, start, end)
+	if err != nil {
+		return nil, err
+	}
+
+	var days []leaveDay
+	for _, day := range target.Days {
+		hours := day.ScheduledHours - day.HolidayHours
+		if hours <= 0 {
+			continue
+		}
+		date, err := time.Parse(time.DateOnly, day.Date)
+		if err != nil {
+			return nil, err
+		}
+		days = append(days, leaveDay{date: date, hours: hours})
+	}
+	return days, nil
+}
@@ -0,0 +1,293 @@
+package timeentry_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/r-52/embrace/models"
+	dto "github.com/r-52/embrace/models/dto/timeentry"
+	"github.com/r-52/embrace/repositories"
+	"github.com/r-52/embrace/services/auth"
+	"github.com/r-52/embrace/services/holiday"
+	"github.com/r-52/embrace/services/schedule"
+	"github.com/r-52/embrace/services/servicetest"
+	"github.com/r-52/embrace/services/timeentry"
+	"gorm.io/gorm"
+)
+
+func leaveRequestsFor(f *fixture, actor *models.User) *timeentry.LeaveRequestService {
+	return timeentry.NewLeaveRequestService(repositories.WithTenant(f.db, actor.CompanyID))
+}
+
+// setupVacation creates a vacation type whose quota of the employee holds the given number of days,
+// together with the tables of quotas and leave requests.
+func setupVacation(t *testing.T, f *fixture, days int) (*models.TimeEntryType, *models.UserQuota) {
+	servicetest.Migrate(t, f.db, &models.Quota{}, &models.UserQuota{}, &models.UserQuotaCarryOver{}, &models.UserQuotaTransaction{},
+		&models.LeaveRequest{}, &models.WorkSchedule{}, &models.WorkScheduleDay{}, &models.UserWorkSchedule{})
+	vacation := &models.TimeEntryType{Name: "Vacation", Color: "#00ff00", CompanyID: f.employee.CompanyID, IsQuotaRelevant: true, QuotaName: "vacation"}
+	servicetest.MustCreate(t, f.db, vacation)
+	vacationQuota := &models.Quota{Name: "vacation", CompanyID: f.employee.CompanyID, Count: models.QuotaUnits(30), QuotaResetAt: models.QUOTA_RESET_FIRST_OF_YEAR}
+	servicetest.MustCreate(t, f.db, vacationQuota)
+	userQuota := &models.UserQuota{UserID: f.employee.ID, QuotaID: vacationQuota.ID, Count: models.QuotaUnits(days)}
+	servicetest.MustCreate(t, f.db, userQuota)
+	return vacation, userQuota
+}
+
+func TestLeaveRequestService_Approve_And_Cancel(t *testing.T) {
+	f := setupFixture(t)
+	vacation, userQuota := setupVacation(t, f, 10)
+	balance := func() float64 {
+		result, _ := repositories.NewUserQuotaRepository(f.db).GetByID(userQuota.ID)
+		return result.Count.Units()
+	}
+
+	// Friday to Tuesday covers three working days.
+	req := &dto.CreateLeaveRequestRequest{StartDate: "2024-03-08", EndDate: "2024-03-12", TimeEntryTypeID: vacation.ID, Note: "beach"}
+	leaveRequest, err := leaveRequestsFor(f, f.employee).Request(f.employee, req)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if leaveRequest.Status != models.LEAVE_REQUEST_REQUESTED || balance() != 10 {
+		t.Errorf("expected a pending request that uses no quota, got %+v and balance %v", leaveRequest, balance())
+	}
+
+	approved, err := leaveRequestsFor(f, f.manager).Approve(f.manager, leaveRequest.ID, &dto.DecideLeaveRequestRequest{Note: "ok"}, monday)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if approved.Status != models.LEAVE_REQUEST_APPROVED || *approved.DecidedByID != f.manager.ID || approved.DecisionNote != "ok" {
+		t.Errorf("unexpected decision: %+v", approved)
+	}
+	entries, _ := repositories.NewTimeEntryRepository(f.db).GetByLeaveRequestID(leaveRequest.ID)
+	if len(entries) != 3 {
+		t.Fatalf("expected 3 booked entries, got %d", len(entries))
+	}
+	if balance() != 7 {
+		t.Errorf("expected balance 7 after approval, got %v", balance())
+	}
+	if _, err := leaveRequestsFor(f, f.manager).Reject(f.manager, leaveRequest.ID, &dto.DecideLeaveRequestRequest{}, monday); !errors.Is(err, timeentry.ErrLeaveRequestNotPending) {
+		t.Errorf("expected ErrLeaveRequestNotPending, got %v", err)
+	}
+
+	if _, err := leaveRequestsFor(f, f.employee).Cancel(f.employee, leaveRequest.ID, &dto.DecideLeaveRequestRequest{}, monday); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	entries, _ = repositories.NewTimeEntryRepository(f.db).GetByLeaveRequestID(leaveRequest.ID)
+	if len(entries) != 0 || balance() != 10 {
+		t.Errorf("expected the entries to be deleted and the quota refunded, got %d entries and balance %v", len(entries), balance())
+	}
+	if _, err := leaveRequestsFor(f, f.employee).Cancel(f.employee, leaveRequest.ID, &dto.DecideLeaveRequestRequest{}, monday); !errors.Is(err, timeentry.ErrLeaveRequestClosed) {
+		t.Errorf("expected ErrLeaveRequestClosed, got %v", err)
+	}
+}
+
+func TestLeaveRequestService_Half_Day_And_Reject(t *testing.T) {
+	f := setupFixture(t)
+	vacation, userQuota := setupVacation(t, f, 1)
+
+	half, err := leaveRequestsFor(f, f.employee).Request(f.employee, &dto.CreateLeaveRequestRequest{StartDate: "2024-03-04", EndDate: "2024-03-04", HalfDay: true, TimeEntryTypeID: vacation.ID})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := leaveRequestsFor(f, f.admin).Approve(f.admin, half.ID, &dto.DecideLeaveRequestRequest{}, monday); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	result, _ := repositories.NewUserQuotaRepository(f.db).GetByID(userQuota.ID)
+	if result.Count.Units() != 0.5 {
+		t.Errorf("expected balance 0.5 after half a day, got %v", result.Count.Units())
+	}
+
+	rejected, err := leaveRequestsFor(f, f.employee).Request(f.employee, &dto.CreateLeaveRequestRequest{StartDate: "2024-03-05", EndDate: "2024-03-05", TimeEntryTypeID: vacation.ID})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := leaveRequestsFor(f, f.manager).Reject(f.manager, rejected.ID, &dto.DecideLeaveRequestRequest{Note: "busy"}, monday); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	entries, _ := repositories.NewTimeEntryRepository(f.db).GetByLeaveRequestID(rejected.ID)
+	if len(entries) != 0 {
+		t.Errorf("expected a rejected request to book nothing, got %d entries", len(entries))
+	}
+	if _, err := leaveRequestsFor(f, f.employee).Cancel(f.employee, rejected.ID, &dto.DecideLeaveRequestRequest{}, monday); !errors.Is(err, timeentry.ErrLeaveRequestClosed) {
+		t.Errorf("expected ErrLeaveRequestClosed, got %v", err)
+	}
+}
+
+func TestLeaveRequestService_Request_Rejects_Invalid_Periods(t *testing.T) {
+	f := setupFixture(t)
+	vacation, _ := setupVacation(t, f, 10)
+
+	tests := []struct {
+		name  string
+		start string
+		end   string
+		half  bool
+	}{
+		{name: "weekend only", start: "2024-03-09", end: "2024-03-10"},
+		{name: "end before start", start: "2024-03-05", end: "2024-03-04"},
+		{name: "longer than MAX_LEAVE_DAYS", start: "2024-03-04", end: "2025-03-05"},
+		{name: "half day over several days", start: "2024-03-04", end: "2024-03-05", half: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := &dto.CreateLeaveRequestRequest{StartDate: tt.start, EndDate: tt.end, HalfDay: tt.half, TimeEntryTypeID: vacation.ID}
+			if _, err := leaveRequestsFor(f, f.employee).Request(f.employee, req); !errors.Is(err, timeentry.ErrInvalidLeavePeriod) {
+				t.Errorf("expected ErrInvalidLeavePeriod, got %v", err)
+			}
+		})
+	}
+
+	// MAX_LEAVE_DAYS calendar days are the most a single request may cover.
+	year := &dto.CreateLeaveRequestRequest{StartDate: "2024-03-04", EndDate: "2025-03-04", TimeEntryTypeID: vacation.ID}
+	if _, err := leaveRequestsFor(f, f.employee).Request(f.employee, year); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	work := &dto.CreateLeaveRequestRequest{StartDate: "2024-03-04", EndDate: "2024-03-04", TimeEntryTypeID: f.timeEntryType.ID}
+	if _, err := leaveRequestsFor(f, f.employee).Request(f.employee, work); !errors.Is(err, timeentry.ErrLeaveTypeNotQuotaRelevant) {
+		t.Errorf("expected ErrLeaveTypeNotQuotaRelevant, got %v", err)
+	}
+}
+
+func TestLeaveRequestService_Inbox_And_Permissions(t *testing.T) {
+	f := setupFixture(t)
+	vacation, userQuota := setupVacation(t, f, 10)
+	for _, user := range []*models.User{f.colleague, f.manager} {
+		servicetest.MustCreate(t, f.db, &models.UserQuota{UserID: user.ID, QuotaID: userQuota.QuotaID, Count: models.QuotaUnits(10)})
+	}
+	request := func(user *models.User, day string) *models.LeaveRequest {
+		leaveRequest, err := leaveRequestsFor(f, user).Request(user, &dto.CreateLeaveRequestRequest{StartDate: day, EndDate: day, TimeEntryTypeID: vacation.ID})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		return leaveRequest
+	}
+	employeeRequest := request(f.employee, "2024-03-04")
+	colleagueRequest := request(f.colleague, "2024-03-05")
+	managerRequest := request(f.manager, "2024-03-06")
+
+	tests := []struct {
+		name     string
+		actor    *models.User
+		expected int
+		err      error
+	}{
+		{name: "manager sees team", actor: f.manager, expected: 1},
+		{name: "admin sees company", actor: f.admin, expected: 3},
+		{name: "employee may not decide", actor: f.employee, err: auth.ErrPermissionDenied},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			inbox, err := leaveRequestsFor(f, tt.actor).Inbox(tt.actor)
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("expected %v, got %v", tt.err, err)
+			}
+			if len(inbox) != tt.expected {
+				t.Errorf("expected %d requests, got %d", tt.expected, len(inbox))
+			}
+		})
+	}
+
+	decisions := []struct {
+		name     string
+		actor    *models.User
+		id       uint
+		expected error
+	}{
+		{name: "employee approves own", actor: f.employee, id: employeeRequest.ID, expected: auth.ErrPermissionDenied},
+		{name: "employee approves colleague", actor: f.employee, id: colleagueRequest.ID, expected: gorm.ErrRecordNotFound},
+		{name: "manager approves others", actor: f.manager, id: colleagueRequest.ID, expected: gorm.ErrRecordNotFound},
+		{name: "manager approves own", actor: f.manager, id: managerRequest.ID, expected: auth.ErrPermissionDenied},
+		{name: "foreign admin", actor: f.foreignUser, id: employeeRequest.ID, expected: gorm.ErrRecordNotFound},
+		{name: "admin approves manager", actor: f.admin, id: managerRequest.ID},
+	}
+	for _, tt := range decisions {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := leaveRequestsFor(f, tt.actor).Approve(tt.actor, tt.id, &dto.DecideLeaveRequestRequest{}, monday)
+			if !errors.Is(err, tt.expected) {
+				t.Errorf("expected %v, got %v", tt.expected, err)
+			}
+		})
+	}
+
+	if _, err := leaveRequestsFor(f, f.colleague).Cancel(f.colleague, employeeRequest.ID, &dto.DecideLeaveRequestRequest{}, monday); !errors.Is(err, gorm.ErrRecordNotFound) {
+		t.Errorf("expected ErrRecordNotFound, got %v", err)
+	}
+	if requests, _ := leaveRequestsFor(f, f.employee).List(f.employee); len(requests) != 1 || requests[0].ID != employeeRequest.ID {
+		t.Errorf("expected the own request, got %v", requests)
+	}
+}
+
+func TestLeaveRequestService_Skips_Holidays(t *testing.T) {
+	f := setupFixture(t)
+	vacation, userQuota := setupVacation(t, f, 10)
+	calendar := &models.HolidayCalendar{CompanyID: f.employee.CompanyID, Name: "Berlin", Region: holiday.REGION_BERLIN}
+	servicetest.MustCreate(t, f.db, calendar)
+	christmasEve := &models.Holiday{HolidayCalendarID: calendar.ID, Date: time.Date(2024, 12, 24, 0, 0, 0, 0, time.UTC), Name: "Heiligabend", HalfDay: true}
+	servicetest.MustCreate(t, f.db, christmasEve)
+	f.db.Model(f.employee).Update("holiday_calendar_id", calendar.ID)
+	f.employee.HolidayCalendarID = &calendar.ID
+
+	// Christmas week has a half day, two public holidays and two working days.
+	leaveRequest, err := leaveRequestsFor(f, f.employee).Request(f.employee, &dto.CreateLeaveRequestRequest{StartDate: "2024-12-23", EndDate: "2024-12-27", TimeEntryTypeID: vacation.ID})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := leaveRequestsFor(f, f.manager).Approve(f.manager, leaveRequest.ID, &dto.DecideLeaveRequestRequest{}, monday); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	entries, _ := repositories.NewTimeEntryRepository(f.db).GetByLeaveRequestID(leaveRequest.ID)
+	if len(entries) != 3 {
+		t.Errorf("expected entries on 23, 24 and 27 December, got %d", len(entries))
+	}
+	result, _ := repositories.NewUserQuotaRepository(f.db).GetByID(userQuota.ID)
+	if result.Count.Units() != 7.5 {
+		t.Errorf("expected 2.5 days to be consumed, got a balance of %v", result.Count.Units())
+	}
+
+	holidaysOnly := &dto.CreateLeaveRequestRequest{StartDate: "2024-12-25", EndDate: "2024-12-26", TimeEntryTypeID: vacation.ID}
+	if _, err := leaveRequestsFor(f, f.employee).Request(f.employee, holidaysOnly); !errors.Is(err, timeentry.ErrInvalidLeavePeriod) {
+		t.Errorf("expected ErrInvalidLeavePeriod, got %v", err)
+	}
+}
+
+func TestLeaveRequestService_Follows_Part_Time_Schedule(t *testing.T) {
+	f := setupFixture(t)
+	vacation, userQuota := setupVacation(t, f, 10)
+	f.db.Model(f.employee).Updates(map[string]interface{}{"working_days_per_week": 3, "daily_working_hours": 6})
+	f.employee.WorkingDaysPerWeek, f.employee.DailyWorkingHours = 3, 6
+
+	// A week of leave only covers Monday to Wednesday.
+	leaveRequest, err := leaveRequestsFor(f, f.employee).Request(f.employee, &dto.CreateLeaveRequestRequest{StartDate: "2024-03-04", EndDate: "2024-03-08", TimeEntryTypeID: vacation.ID})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := leaveRequestsFor(f, f.manager).Approve(f.manager, leaveRequest.ID, &dto.DecideLeaveRequestRequest{}, monday); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	entries, _ := repositories.NewTimeEntryRepository(f.db).GetByLeaveRequestID(leaveRequest.ID)
+	if len(entries) != 3 {
+		t.Fatalf("expected entries on Monday to Wednesday, got %d", len(entries))
+	}
+	for _, entry := range entries {
+		if entry.StartTime.Weekday() > time.Wednesday || entry.EndTime.Time.Sub(entry.StartTime) != 6*time.Hour {
+			t.Errorf("expected 6 hours on a scheduled day, got %v to %v", entry.StartTime, entry.EndTime.Time)
+		}
+	}
+	result, _ := repositories.NewUserQuotaRepository(f.db).GetByID(userQuota.ID)
+	if result.Count.Units() != 7 {
+		t.Errorf("expected 3 days to be consumed, got a balance of %v", result.Count.Units())
+	}
+	target, err := schedule.NewTargetHoursService(repositories.WithTenant(f.db, f.employee.CompanyID)).TargetHoursOf(f.employee, monday, monday.AddDate(0, 0, 4))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if target.ScheduledHours != 18 || target.AbsenceHours != 18 || target.TargetHours != 0 {
+		t.Errorf("expected the leave to cover the scheduled hours, got %+v", target)
+	}
+
+	offDays := &dto.CreateLeaveRequestRequest{StartDate: "2024-03-14", EndDate: "2024-03-15", TimeEntryTypeID: vacation.ID}
+	if _, err := leaveRequestsFor(f, f.employee).Request(f.employee, offDays); !errors.Is(err, timeentry.ErrInvalidLeavePeriod) {
+		t.Errorf("expected ErrInvalidLeavePeriod for days off, got %v", err)
+	}
+}
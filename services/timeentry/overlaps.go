@@ -0,0 +1,167 @@
+package timeentry
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/r-52/embrace/models"
+	dto "github.com/r-52/embrace/models/dto/timeentry"
+	"github.com/r-52/embrace/repositories"
+	"github.com/r-52/embrace/services/auth"
+)
+
+const OVERLAP_STRATEGY_MERGE = "merge"
+const OVERLAP_STRATEGY_SPLIT = "split"
+
+// OVERLAPS_RESOLVED is the reason the audit trail records for the changes of ResolveOverlaps.
+const OVERLAPS_RESOLVED = "overlaps resolved"
+
+// ResolveOverlaps removes the overlaps between the closed entries of a user that start within the requested range.
+// It is meant for administrators cleaning up imported data and requires the permission to write everyone's entries.
+// It returns the entries of the range after the cleanup.
+func (s *TimeEntryService) ResolveOverlaps(actor *models.User, req *dto.ResolveOverlapsRequest) ([]models.TimeEntry, error) {
+	if !actor.Role.HasPermission(models.PERMISSION_TIME_ENTRIES_WRITE_ALL) {
+		return nil, auth.ErrPermissionDenied
+	}
+
+	filter := repositories.TimeEntryFilter{
+		CompanyID: actor.CompanyID,
+		UserIDs:   []uint{req.UserID},
+		From:      req.From,
+		To:        req.To,
+	}
+	var resolved []models.TimeEntry
+	err := s.unitOfWork.Transaction(func(uow *repositories.UnitOfWork) error {
+		if _, err := uow.Users().GetByID(req.UserID); err != nil {
+			return ErrUnknownUser
+		}
+
+		entries, err := uow.TimeEntries().Find(filter)
+		if err != nil {
+			return err
+		}
+		closed := make([]*models.TimeEntry, 0, len(entries))
+		for i := range entries {
+			if !entries[i].IsRunning() {
+				closed = append(closed, &entries[i])
+			}
+		}
+
+		switch req.Strategy {
+		case OVERLAP_STRATEGY_MERGE:
+			err = mergeOverlaps(uow, actor, closed)
+		case OVERLAP_STRATEGY_SPLIT:
+			err = splitOverlaps(uow, actor, closed)
+		}
+		if err != nil {
+			return err
+		}
+
+		resolved, err = uow.TimeEntries().Find(filter)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return resolved, nil
+}
+
+// mergeOverlaps joins every group of overlapping entries into its first entry, which keeps its type and
+// collects the notes of the others. The other entries are deleted. The entries have to be sorted by start time.
+func mergeOverlaps(uow *repositories.UnitOfWork, actor *models.User, entries []*models.TimeEntry) error {
+	var current *models.TimeEntry
+	var original models.TimeEntry
+	merged := false
+	flush := func() error {
+		if current == nil || !merged {
+			return nil
+		}
+		return updateEntry(uow, actor, &original, current, OVERLAPS_RESOLVED)
+	}
+
+	for _, entry := range entries {
+		if current == nil || !entry.StartTime.Before(current.EndTime.Time) {
+			if err := flush(); err != nil {
+				return err
+			}
+			current, original, merged = entry, *entry, false
+			continue
+		}
+
+		// Deleting first credits the quota before the grown entry debits it.
+		if err := deleteEntry(uow, actor, entry, OVERLAPS_RESOLVED); err != nil {
+			return err
+		}
+		if entry.EndTime.Time.After(current.EndTime.Time) {
+			current.Close(entry.EndTime.Time)
+		}
+		current.Note = joinNotes(current.Note, entry.Note)
+		merged = true
+	}
+	return flush()
+}
+
+// splitOverlaps keeps entries that start later whole and cuts the earlier entries they overlap around them.
+// Cutting an entry in the middle creates a new entry for its remainder. Entries that start together with a
+// later one are deleted, except for a remainder. The entries have to be sorted by start time.
+func splitOverlaps(uow *repositories.UnitOfWork, actor *models.User, entries []*models.TimeEntry) error {
+	for i := 0; i+1 < len(entries); i++ {
+		entry, next := entries[i], entries[i+1]
+		if !next.StartTime.Before(entry.EndTime.Time) {
+			continue
+		}
+
+		var remainder *models.TimeEntry
+		if entry.EndTime.Time.After(next.EndTime.Time) {
+			remainder = &models.TimeEntry{
+				StartTime:       next.EndTime.Time,
+				Note:            entry.Note,
+				CompanyID:       entry.CompanyID,
+				UserID:          entry.UserID,
+				TimeEntryTypeID: entry.TimeEntryTypeID,
+			}
+			remainder.Close(entry.EndTime.Time)
+		}
+
+		// Shrinking first credits the quota before the remainder debits it.
+		if !next.StartTime.After(entry.StartTime) {
+			if err := deleteEntry(uow, actor, entry, OVERLAPS_RESOLVED); err != nil {
+				return err
+			}
+		} else {
+			before := *entry
+			entry.Close(next.StartTime)
+			if err := updateEntry(uow, actor, &before, entry, OVERLAPS_RESOLVED); err != nil {
+				return err
+			}
+		}
+
+		if remainder != nil {
+			if err := createEntry(uow, actor, remainder, OVERLAPS_RESOLVED); err != nil {
+				return err
+			}
+			entries = insertByStart(entries, remainder)
+		}
+	}
+	return nil
+}
+
+func insertByStart(entries []*models.TimeEntry, entry *models.TimeEntry) []*models.TimeEntry {
+	index := sort.Search(len(entries), func(i int) bool {
+		return entries[i].StartTime.After(entry.StartTime)
+	})
+	entries = append(entries, nil)
+	copy(entries[index+1:], entries[index:])
+	entries[index] = entry
+	return entries
+}
+
+func joinNotes(notes ...string) string {
+	parts := make([]string, 0, len(notes))
+	for _, note := range notes {
+		if note != "" {
+			parts = append(parts, note)
+		}
+	}
+	return strings.Join(parts, "\n")
+}
@@ -0,0 +1,112 @@
+package timeentry_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/r-52/embrace/models"
+	dto "github.com/r-52/embrace/models/dto/timeentry"
+	"github.com/r-52/embrace/services/auth"
+	"github.com/r-52/embrace/services/servicetest"
+	"github.com/r-52/embrace/services/timeentry"
+)
+
+type span struct {
+	start, end int
+	note       string
+}
+
+// seedOverlapping writes entries directly, the way data imported before validation existed looks.
+func seedOverlapping(t *testing.T, f *fixture, spans ...span) {
+	for _, s := range spans {
+		entry := &models.TimeEntry{
+			StartTime:       monday.Add(time.Duration(s.start) * time.Hour),
+			Note:            s.note,
+			CompanyID:       f.employee.CompanyID,
+			UserID:          f.employee.ID,
+			TimeEntryTypeID: f.timeEntryType.ID,
+		}
+		entry.Close(monday.Add(time.Duration(s.end) * time.Hour))
+		servicetest.MustCreate(t, f.db, entry)
+	}
+}
+
+func spansOf(entries []models.TimeEntry) []span {
+	spans := make([]span, 0, len(entries))
+	for _, entry := range entries {
+		spans = append(spans, span{
+			start: int(entry.StartTime.Sub(monday).Hours()),
+			end:   int(entry.EndTime.Time.Sub(monday).Hours()),
+			note:  entry.Note,
+		})
+	}
+	return spans
+}
+
+func TestTimeEntryService_ResolveOverlaps(t *testing.T) {
+	tests := []struct {
+		name     string
+		strategy string
+		seeded   []span
+		expected []span
+	}{
+		{
+			name:     "merge",
+			strategy: timeentry.OVERLAP_STRATEGY_MERGE,
+			seeded:   []span{{0, 4, "a"}, {2, 6, "b"}, {5, 7, ""}, {8, 9, "c"}},
+			expected: []span{{0, 7, "a\nb"}, {8, 9, "c"}},
+		},
+		{
+			name:     "split inner",
+			strategy: timeentry.OVERLAP_STRATEGY_SPLIT,
+			seeded:   []span{{0, 8, "day"}, {2, 3, "meeting"}},
+			expected: []span{{0, 2, "day"}, {2, 3, "meeting"}, {3, 8, "day"}},
+		},
+		{
+			name:     "split chain",
+			strategy: timeentry.OVERLAP_STRATEGY_SPLIT,
+			seeded:   []span{{0, 4, "a"}, {2, 6, "b"}, {2, 3, "c"}, {8, 9, "d"}},
+			expected: []span{{0, 2, "a"}, {2, 3, "c"}, {3, 6, "b"}, {8, 9, "d"}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := setupFixture(t)
+			seedOverlapping(t, f, tt.seeded...)
+
+			req := &dto.ResolveOverlapsRequest{UserID: f.employee.ID, From: monday, To: monday.AddDate(0, 0, 1), Strategy: tt.strategy}
+			entries, err := serviceFor(f, f.admin).ResolveOverlaps(f.admin, req)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			got := spansOf(entries)
+			if len(got) != len(tt.expected) {
+				t.Fatalf("expected %v, got %v", tt.expected, got)
+			}
+			for i := range got {
+				if got[i] != tt.expected[i] {
+					t.Errorf("expected %v, got %v", tt.expected, got)
+					break
+				}
+			}
+			for _, entry := range entries {
+				if entry.Duration.Float64 != entry.EndTime.Time.Sub(entry.StartTime).Hours() {
+					t.Errorf("expected the duration of entry %d to match its span, got %v", entry.ID, entry.Duration.Float64)
+				}
+			}
+		})
+	}
+}
+
+func TestTimeEntryService_ResolveOverlaps_Requires_Admin(t *testing.T) {
+	f := setupFixture(t)
+	req := &dto.ResolveOverlapsRequest{UserID: f.employee.ID, From: monday, To: monday.AddDate(0, 0, 1), Strategy: timeentry.OVERLAP_STRATEGY_MERGE}
+	if _, err := serviceFor(f, f.manager).ResolveOverlaps(f.manager, req); !errors.Is(err, auth.ErrPermissionDenied) {
+		t.Errorf("expected ErrPermissionDenied, got %v", err)
+	}
+	req.UserID = f.foreignUser.ID
+	if _, err := serviceFor(f, f.admin).ResolveOverlaps(f.admin, req); !errors.Is(err, timeentry.ErrUnknownUser) {
+		t.Errorf("expected ErrUnknownUser, got %v", err)
+	}
+}
@@ -0,0 +1,91 @@
+package timeentry_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	dto "github.com/r-52/embrace/models/dto/timeentry"
+	"github.com/r-52/embrace/repositories"
+	"github.com/r-52/embrace/services/quota"
+	"github.com/r-52/embrace/services/timeentry"
+)
+
+func TestTimeEntryService_Consumes_Quota(t *testing.T) {
+	f := setupFixture(t)
+	vacation, userQuota := setupVacation(t, f, 1)
+	balance := func() float64 {
+		result, _ := repositories.NewUserQuotaRepository(f.db).GetByID(userQuota.ID)
+		return result.Count.Units()
+	}
+
+	// Leave is not booked directly, but by approving a leave request.
+	service := serviceFor(f, f.employee)
+	req := createRequest(f, 0, monday)
+	req.TimeEntryTypeID = vacation.ID
+	if _, err := service.Create(f.employee, req); !errors.Is(err, timeentry.ErrQuotaRelevantEntry) {
+		t.Errorf("expected ErrQuotaRelevantEntry, got %v", err)
+	}
+	importReq := &dto.ImportTimeEntriesRequest{Entries: []dto.CreateTimeEntryRequest{*req}}
+	if _, err := service.Import(f.employee, importReq); !errors.Is(err, timeentry.ErrQuotaRelevantEntry) {
+		t.Errorf("expected ErrQuotaRelevantEntry for an import, got %v", err)
+	}
+	leaveRequest, err := leaveRequestsFor(f, f.employee).Request(f.employee, &dto.CreateLeaveRequestRequest{StartDate: "2024-03-04", EndDate: "2024-03-04", TimeEntryTypeID: vacation.ID})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := leaveRequestsFor(f, f.manager).Approve(f.manager, leaveRequest.ID, &dto.DecideLeaveRequestRequest{}, monday); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if balance() != 0 {
+		t.Errorf("expected balance 0 after booking, got %v", balance())
+	}
+
+	exceeding, err := leaveRequestsFor(f, f.employee).Request(f.employee, &dto.CreateLeaveRequestRequest{StartDate: "2024-03-05", EndDate: "2024-03-05", TimeEntryTypeID: vacation.ID})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := leaveRequestsFor(f, f.manager).Approve(f.manager, exceeding.ID, &dto.DecideLeaveRequestRequest{}, monday); !errors.Is(err, quota.ErrQuotaExceeded) {
+		t.Errorf("expected ErrQuotaExceeded, got %v", err)
+	}
+	count, _ := repositories.NewTimeEntryRepository(f.db).CountByUserID(f.employee.ID)
+	if count != 1 {
+		t.Errorf("expected the rejected entry to be rolled back, got %v entries", count)
+	}
+
+	// The entries of a leave request are neither moved nor deleted, but removed by cancelling the request.
+	entries, _ := repositories.NewTimeEntryRepository(f.db).GetByLeaveRequestID(leaveRequest.ID)
+	if len(entries) != 1 {
+		t.Fatalf("expected 1 booked entry, got %d", len(entries))
+	}
+	moved := &dto.UpdateTimeEntryRequest{StartTime: monday.AddDate(0, 0, 1), EndTime: monday.AddDate(0, 0, 1).Add(8 * time.Hour), TimeEntryTypeID: f.timeEntryType.ID}
+	if _, err := service.Update(f.employee, entries[0].ID, moved); !errors.Is(err, timeentry.ErrLeaveEntry) {
+		t.Errorf("expected ErrLeaveEntry for an update, got %v", err)
+	}
+	if err := service.Delete(f.employee, entries[0].ID, &dto.DeleteTimeEntryRequest{}); !errors.Is(err, timeentry.ErrLeaveEntry) {
+		t.Errorf("expected ErrLeaveEntry for a deletion, got %v", err)
+	}
+	if balance() != 0 {
+		t.Errorf("expected balance 0 after the rejected changes, got %v", balance())
+	}
+
+	// Work cannot be turned into leave either, and deleting work changes nothing.
+	work, err := service.Create(f.employee, createRequest(f, 0, monday.AddDate(0, 0, 7)))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	update := &dto.UpdateTimeEntryRequest{StartTime: work.StartTime, EndTime: work.EndTime.Time, TimeEntryTypeID: vacation.ID}
+	if _, err := service.Update(f.employee, work.ID, update); !errors.Is(err, timeentry.ErrQuotaRelevantEntry) {
+		t.Errorf("expected ErrQuotaRelevantEntry, got %v", err)
+	}
+	if err := service.Delete(f.employee, work.ID, &dto.DeleteTimeEntryRequest{}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if balance() != 0 {
+		t.Errorf("expected balance 0 after deleting work, got %v", balance())
+	}
+
+	if _, err := timerFor(f, f.employee).ClockIn(f.employee, &dto.ClockInRequest{TimeEntryTypeID: vacation.ID}, monday); !errors.Is(err, timeentry.ErrQuotaRelevantTimer) {
+		t.Errorf("expected ErrQuotaRelevantTimer, got %v", err)
+	}
+}
@@ -0,0 +1,314 @@
+package timeentry
+
+import (
+	"errors"
+
+	"github.com/r-52/embrace/models"
+	dto "github.com/r-52/embrace/models/dto/timeentry"
+	"github.com/r-52/embrace/repositories"
+	"github.com/r-52/embrace/services/compliance"
+	"github.com/r-52/embrace/services/quota"
+	"gorm.io/gorm"
+)
+
+type TimeEntryService struct {
+	unitOfWork *repositories.UnitOfWork
+}
+
+type TimeEntryServiceInterface interface {
+	List(actor *models.User, req *dto.ListTimeEntriesRequest) ([]models.TimeEntry, error)
+	Get(actor *models.User, id uint) (*models.TimeEntry, error)
+	Create(actor *models.User, req *dto.CreateTimeEntryRequest) (*models.TimeEntry, error)
+	Import(actor *models.User, req *dto.ImportTimeEntriesRequest) ([]models.TimeEntry, error)
+	Update(actor *models.User, id uint, req *dto.UpdateTimeEntryRequest) (*models.TimeEntry, error)
+	Delete(actor *models.User, id uint, req *dto.DeleteTimeEntryRequest) error
+	History(actor *models.User, id uint) ([]models.TimeEntryAudit, error)
+	ResolveOverlaps(actor *models.User, req *dto.ResolveOverlapsRequest) ([]models.TimeEntry, error)
+}
+
+// NewTimeEntryService creates a TimeEntryService. The database should be scoped to the actor's company, see repositories.WithTenant.
+func NewTimeEntryService(db *gorm.DB) *TimeEntryService {
+	return NewTimeEntryServiceWithUnitOfWork(repositories.NewUnitOfWork(db))
+}
+
+// NewTimeEntryServiceWithUnitOfWork creates a TimeEntryService whose repositories join the given unit of work.
+func NewTimeEntryServiceWithUnitOfWork(uow *repositories.UnitOfWork) *TimeEntryService {
+	return &TimeEntryService{
+		unitOfWork: uow,
+	}
+}
+
+// List returns the time entries matching the request that the actor may read, ordered by start time.
+func (s *TimeEntryService) List(actor *models.User, req *dto.ListTimeEntriesRequest) ([]models.TimeEntry, error) {
+	filter := repositories.TimeEntryFilter{
+		CompanyID:       actor.CompanyID,
+		TimeEntryTypeID: req.TimeEntryTypeID,
+		From:            req.From,
+	}
+	if !req.To.IsZero() {
+		filter.To = req.To.AddDate(0, 0, 1)
+	}
+
+	if req.UserID != 0 {
+		if err := authorize(s.unitOfWork, actor, req.UserID, ACTION_READ); err != nil {
+			return nil, err
+		}
+		filter.UserIDs = []uint{req.UserID}
+	} else {
+		userIDs, err := readableUserIDs(s.unitOfWork, actor)
+		if err != nil {
+			return nil, err
+		}
+		filter.UserIDs = userIDs
+	}
+
+	return s.unitOfWork.TimeEntries().Find(filter)
+}
+
+// Get returns a single time entry. Entries the actor may not read are reported as gorm.ErrRecordNotFound.
+func (s *TimeEntryService) Get(actor *models.User, id uint) (*models.TimeEntry, error) {
+	entry, err := s.unitOfWork.TimeEntries().GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if err := authorize(s.unitOfWork, actor, entry.UserID, ACTION_READ); err != nil {
+		return nil, hideForbidden(err)
+	}
+	return entry, nil
+}
+
+// Create books a closed time entry for the actor or, with the matching permission, for another user of the company.
+// Entries that violate a blocking labor law rule are rejected with a *compliance.ViolationError, other violations
+// are returned as the entry's Warnings.
+func (s *TimeEntryService) Create(actor *models.User, req *dto.CreateTimeEntryRequest) (*models.TimeEntry, error) {
+	var created *models.TimeEntry
+	err := s.unitOfWork.Transaction(func(uow *repositories.UnitOfWork) error {
+		entry, err := book(uow, actor, req)
+		if err != nil {
+			return err
+		}
+		created = entry
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return created, nil
+}
+
+// Import books many closed time entries in one transaction. The entries are validated like single
+// bookings and against each other. If one fails, nothing is booked and an *ImportError names it.
+func (s *TimeEntryService) Import(actor *models.User, req *dto.ImportTimeEntriesRequest) ([]models.TimeEntry, error) {
+	imported := make([]models.TimeEntry, 0, len(req.Entries))
+	err := s.unitOfWork.Transaction(func(uow *repositories.UnitOfWork) error {
+		for i := range req.Entries {
+			entry, err := book(uow, actor, &req.Entries[i])
+			if err != nil {
+				return &ImportError{Index: i, Err: err}
+			}
+			imported = append(imported, *entry)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return imported, nil
+}
+
+// Update replaces the times, note and type of a time entry. Like bookings, changes are checked against the labor law
+// rules of the company, see compliance.ComplianceService.Check. The reason of the request is recorded in the audit trail.
+// Entries of a leave request are rejected with ErrLeaveEntry and quota relevant types with ErrQuotaRelevantEntry.
+func (s *TimeEntryService) Update(actor *models.User, id uint, req *dto.UpdateTimeEntryRequest) (*models.TimeEntry, error) {
+	var updated *models.TimeEntry
+	err := s.unitOfWork.Transaction(func(uow *repositories.UnitOfWork) error {
+		entry, err := s.getWritable(uow, actor, id)
+		if err != nil {
+			return err
+		}
+		if _, err := getBookableTimeEntryType(uow, req.TimeEntryTypeID); err != nil {
+			return err
+		}
+
+		before := *entry
+		entry.StartTime = req.StartTime
+		entry.Note = req.Note
+		entry.TimeEntryTypeID = req.TimeEntryTypeID
+		entry.Close(req.EndTime)
+		if err := NewTimeEntryValidatorWithUnitOfWork(uow).Validate(entry); err != nil {
+			return err
+		}
+		if err := updateEntry(uow, actor, &before, entry, req.Reason); err != nil {
+			return err
+		}
+		if err := compliance.NewComplianceServiceWithUnitOfWork(uow).Check(entry); err != nil {
+			return err
+		}
+		updated = entry
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return updated, nil
+}
+
+// Delete removes a time entry. The reason of the request is recorded in the audit trail.
+// Entries of a leave request are rejected with ErrLeaveEntry.
+func (s *TimeEntryService) Delete(actor *models.User, id uint, req *dto.DeleteTimeEntryRequest) error {
+	return s.unitOfWork.Transaction(func(uow *repositories.UnitOfWork) error {
+		entry, err := s.getWritable(uow, actor, id)
+		if err != nil {
+			return err
+		}
+		return deleteEntry(uow, actor, entry, req.Reason)
+	})
+}
+
+// History returns the audit trail of a time entry, the oldest change first. It includes the deletion of deleted entries.
+// Entries the actor may not read are reported as gorm.ErrRecordNotFound.
+func (s *TimeEntryService) History(actor *models.User, id uint) ([]models.TimeEntryAudit, error) {
+	audits, err := s.unitOfWork.TimeEntryAudits().GetByTimeEntryID(id)
+	if err != nil {
+		return nil, err
+	}
+	if len(audits) == 0 {
+		return nil, gorm.ErrRecordNotFound
+	}
+	if err := authorize(s.unitOfWork, actor, audits[0].UserID, ACTION_READ); err != nil {
+		return nil, hideForbidden(err)
+	}
+	return audits, nil
+}
+
+// getWritable loads a time entry the actor may change. Entries the actor may not even
+// read are reported as gorm.ErrRecordNotFound, readable ones as auth.ErrPermissionDenied.
+// Entries of a leave request are only removed by cancelling it and reported as ErrLeaveEntry.
+func (s *TimeEntryService) getWritable(uow *repositories.UnitOfWork, actor *models.User, id uint) (*models.TimeEntry, error) {
+	entry, err := uow.TimeEntries().GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if err := authorize(uow, actor, entry.UserID, ACTION_READ); err != nil {
+		return nil, hideForbidden(err)
+	}
+	if err := authorize(uow, actor, entry.UserID, ACTION_WRITE); err != nil {
+		return nil, err
+	}
+	if entry.LeaveRequestID != nil {
+		return nil, ErrLeaveEntry
+	}
+	return entry, nil
+}
+
+// book creates a closed time entry for the actor or the user named in the request. Leave is rejected, see ErrQuotaRelevantEntry.
+func book(uow *repositories.UnitOfWork, actor *models.User, req *dto.CreateTimeEntryRequest) (*models.TimeEntry, error) {
+	ownerID := req.UserID
+	if ownerID == 0 {
+		ownerID = actor.ID
+	}
+	if err := authorize(uow, actor, ownerID, ACTION_WRITE); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrUnknownUser
+		}
+		return nil, err
+	}
+	if _, err := getBookableTimeEntryType(uow, req.TimeEntryTypeID); err != nil {
+		return nil, err
+	}
+
+	entry := &models.TimeEntry{
+		StartTime:       req.StartTime,
+		Note:            req.Note,
+		CompanyID:       actor.CompanyID,
+		UserID:          ownerID,
+		TimeEntryTypeID: req.TimeEntryTypeID,
+	}
+	entry.Close(req.EndTime)
+	if err := NewTimeEntryValidatorWithUnitOfWork(uow).Validate(entry); err != nil {
+		return nil, err
+	}
+	if err := createEntry(uow, actor, entry, ""); err != nil {
+		return nil, err
+	}
+	if err := compliance.NewComplianceServiceWithUnitOfWork(uow).Check(entry); err != nil {
+		return nil, err
+	}
+	return entry, nil
+}
+
+func getTimeEntryType(uow *repositories.UnitOfWork, timeEntryTypeID uint) (*models.TimeEntryType, error) {
+	timeEntryType, err := uow.TimeEntryTypes().GetByID(timeEntryTypeID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrUnknownTimeEntryType
+	}
+	return timeEntryType, err
+}
+
+// getBookableTimeEntryType loads a time entry type that may be booked directly, see ErrQuotaRelevantEntry.
+func getBookableTimeEntryType(uow *repositories.UnitOfWork, timeEntryTypeID uint) (*models.TimeEntryType, error) {
+	timeEntryType, err := getTimeEntryType(uow, timeEntryTypeID)
+	if err != nil {
+		return nil, err
+	}
+	if timeEntryType.IsQuotaRelevant {
+		return nil, ErrQuotaRelevantEntry
+	}
+	return timeEntryType, nil
+}
+
+// createEntry inserts a time entry with the duration of its company's policy, debits the quota it uses up
+// on behalf of the actor and records it in the audit trail with the reason. Entries in a locked timesheet period
+// are rejected with ErrTimesheetLocked.
+func createEntry(uow *repositories.UnitOfWork, actor *models.User, entry *models.TimeEntry, reason string) error {
+	if err := checkUnlocked(uow, entry); err != nil {
+		return err
+	}
+	if err := applyDurationPolicy(uow, entry); err != nil {
+		return err
+	}
+	if err := uow.TimeEntries().Create(entry); err != nil {
+		return err
+	}
+	if err := quota.NewQuotaLedgerWithUnitOfWork(uow).Apply(actor, nil, entry); err != nil {
+		return err
+	}
+	return recordAudit(uow, actor, nil, entry, reason)
+}
+
+// updateEntry saves a time entry with the duration of its company's policy, books the difference to its state
+// `before` on the quotas and records the change in the audit trail. Entries are neither changed in nor moved into
+// a locked timesheet period, see createEntry.
+func updateEntry(uow *repositories.UnitOfWork, actor *models.User, before, entry *models.TimeEntry, reason string) error {
+	if err := checkUnlocked(uow, before); err != nil {
+		return err
+	}
+	if err := checkUnlocked(uow, entry); err != nil {
+		return err
+	}
+	if err := applyDurationPolicy(uow, entry); err != nil {
+		return err
+	}
+	if err := uow.TimeEntries().Update(entry); err != nil {
+		return err
+	}
+	if err := quota.NewQuotaLedgerWithUnitOfWork(uow).Apply(actor, before, entry); err != nil {
+		return err
+	}
+	return recordAudit(uow, actor, before, entry, reason)
+}
+
+// deleteEntry removes a time entry, credits the quota it used up and records the deletion in the audit trail.
+// Entries in a locked timesheet period are kept, see createEntry.
+func deleteEntry(uow *repositories.UnitOfWork, actor *models.User, entry *models.TimeEntry, reason string) error {
+	if err := checkUnlocked(uow, entry); err != nil {
+		return err
+	}
+	if err := uow.TimeEntries().Delete(entry.ID); err != nil {
+		return err
+	}
+	if err := quota.NewQuotaLedgerWithUnitOfWork(uow).Apply(actor, entry, nil); err != nil {
+		return err
+	}
+	return recordAudit(uow, actor, entry, nil, reason)
+}
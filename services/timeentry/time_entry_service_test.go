@@ -0,0 +1,232 @@
+package timeentry_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/r-52/embrace/models"
+	dto "github.com/r-52/embrace/models/dto/timeentry"
+	"github.com/r-52/embrace/repositories"
+	"github.com/r-52/embrace/services/auth"
+	"github.com/r-52/embrace/services/compliance"
+	"github.com/r-52/embrace/services/role"
+	"github.com/r-52/embrace/services/servicetest"
+	"github.com/r-52/embrace/services/timeentry"
+	"gorm.io/gorm"
+)
+
+type fixture struct {
+	db            *gorm.DB
+	admin         *models.User
+	manager       *models.User
+	employee      *models.User
+	colleague     *models.User
+	timeEntryType *models.TimeEntryType
+	foreignUser   *models.User
+	foreignType   *models.TimeEntryType
+}
+
+func setupFixture(t *testing.T) *fixture {
+	return setupFixtureWithDb(t, repositories.GetDatabase())
+}
+
+func setupFixtureWithDb(t *testing.T, db *gorm.DB) *fixture {
+	servicetest.Migrate(t, db, &models.Company{}, &models.User{}, &models.UserProfile{}, &models.UserRole{}, &models.RolePermission{},
+		&models.TimeEntryType{}, &models.TimeEntry{}, &models.HolidayCalendar{}, &models.Holiday{}, &models.OvertimePolicy{},
+		&models.OvertimeWorkTimeType{}, &models.ComplianceRuleSet{}, &models.ComplianceRule{}, &models.DurationPolicy{},
+		&models.TimesheetPeriod{}, &models.TimeEntryAudit{})
+	f := &fixture{db: db}
+
+	companyID := servicetest.Company(t, db, "acme").ID
+	f.admin = servicetest.User(t, db, companyID, role.DEFAULT_ROLE_ADMIN, "admin", nil)
+	f.manager = servicetest.User(t, db, companyID, role.DEFAULT_ROLE_MANAGER, "manager", nil)
+	f.employee = servicetest.User(t, db, companyID, role.DEFAULT_ROLE_EMPLOYEE, "employee", &f.manager.ID)
+	f.colleague = servicetest.User(t, db, companyID, role.DEFAULT_ROLE_EMPLOYEE, "colleague", nil)
+	f.timeEntryType = createTimeEntryType(t, db, companyID, "Work")
+
+	foreignCompanyID := servicetest.Company(t, db, "globex").ID
+	f.foreignUser = servicetest.User(t, db, foreignCompanyID, role.DEFAULT_ROLE_ADMIN, "foreign", nil)
+	f.foreignType = createTimeEntryType(t, db, foreignCompanyID, "Foreign work")
+	return f
+}
+
+func createTimeEntryType(t *testing.T, db *gorm.DB, companyID uint, name string) *models.TimeEntryType {
+	timeEntryType := &models.TimeEntryType{Name: name, Color: "#000000", CompanyID: companyID}
+	servicetest.MustCreate(t, db, timeEntryType)
+	return timeEntryType
+}
+
+func serviceFor(f *fixture, actor *models.User) *timeentry.TimeEntryService {
+	return timeentry.NewTimeEntryService(repositories.WithTenant(f.db, actor.CompanyID))
+}
+
+func createRequest(f *fixture, userID uint, start time.Time) *dto.CreateTimeEntryRequest {
+	return &dto.CreateTimeEntryRequest{
+		StartTime:       start,
+		EndTime:         start.Add(8 * time.Hour),
+		TimeEntryTypeID: f.timeEntryType.ID,
+		UserID:          userID,
+	}
+}
+
+var monday = time.Date(2024, 3, 4, 8, 0, 0, 0, time.UTC)
+
+func TestTimeEntryService_Create(t *testing.T) {
+	f := setupFixture(t)
+
+	entry, err := serviceFor(f, f.employee).Create(f.employee, createRequest(f, 0, monday))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if entry.UserID != f.employee.ID || entry.CompanyID != f.employee.CompanyID {
+		t.Errorf("unexpected owner: %+v", entry)
+	}
+	if !entry.Duration.Valid || entry.Duration.Float64 != 8 {
+		t.Errorf("expected a duration of 8 hours, got %+v", entry.Duration)
+	}
+
+	tests := []struct {
+		name     string
+		actor    *models.User
+		userID   uint
+		expected error
+	}{
+		{name: "employee for colleague", actor: f.employee, userID: f.colleague.ID, expected: auth.ErrPermissionDenied},
+		{name: "manager for team", actor: f.manager, userID: f.employee.ID},
+		{name: "manager for others", actor: f.manager, userID: f.colleague.ID, expected: auth.ErrPermissionDenied},
+		{name: "admin for anyone", actor: f.admin, userID: f.colleague.ID},
+		{name: "admin for foreign user", actor: f.admin, userID: f.foreignUser.ID, expected: timeentry.ErrUnknownUser},
+	}
+	for i, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := serviceFor(f, tt.actor).Create(tt.actor, createRequest(f, tt.userID, monday.AddDate(0, 0, i+1)))
+			if !errors.Is(err, tt.expected) {
+				t.Errorf("expected %v, got %v", tt.expected, err)
+			}
+		})
+	}
+}
+
+func TestTimeEntryService_Create_Rejects_Unknown_Type(t *testing.T) {
+	f := setupFixture(t)
+
+	req := createRequest(f, 0, monday)
+	req.TimeEntryTypeID = f.foreignType.ID
+	if _, err := serviceFor(f, f.employee).Create(f.employee, req); !errors.Is(err, timeentry.ErrUnknownTimeEntryType) {
+		t.Errorf("expected ErrUnknownTimeEntryType, got %v", err)
+	}
+}
+
+func TestTimeEntryService_List(t *testing.T) {
+	f := setupFixture(t)
+	for _, user := range []*models.User{f.admin, f.manager, f.employee, f.colleague} {
+		if _, err := serviceFor(f, f.admin).Create(f.admin, createRequest(f, user.ID, monday)); err != nil {
+			t.Fatalf("failed to create entry: %v", err)
+		}
+	}
+	if _, err := serviceFor(f, f.admin).Create(f.admin, createRequest(f, f.employee.ID, monday.AddDate(0, 0, 1))); err != nil {
+		t.Fatalf("failed to create entry: %v", err)
+	}
+
+	day := time.Date(2024, 3, 4, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name     string
+		actor    *models.User
+		req      dto.ListTimeEntriesRequest
+		expected int
+		err      error
+	}{
+		{name: "admin sees company", actor: f.admin, expected: 5},
+		{name: "manager sees team", actor: f.manager, expected: 3},
+		{name: "employee sees own", actor: f.employee, expected: 2},
+		{name: "single day", actor: f.employee, req: dto.ListTimeEntriesRequest{From: day, To: day}, expected: 1},
+		{name: "employee asks for colleague", actor: f.employee, req: dto.ListTimeEntriesRequest{UserID: f.colleague.ID}, err: auth.ErrPermissionDenied},
+		{name: "manager asks for member", actor: f.manager, req: dto.ListTimeEntriesRequest{UserID: f.employee.ID}, expected: 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			entries, err := serviceFor(f, tt.actor).List(tt.actor, &tt.req)
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("expected %v, got %v", tt.err, err)
+			}
+			if len(entries) != tt.expected {
+				t.Errorf("expected %d entries, got %d", tt.expected, len(entries))
+			}
+		})
+	}
+}
+
+func TestTimeEntryService_Update_And_Delete(t *testing.T) {
+	f := setupFixture(t)
+	entry, err := serviceFor(f, f.employee).Create(f.employee, createRequest(f, 0, monday))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	update := &dto.UpdateTimeEntryRequest{
+		StartTime:       monday,
+		EndTime:         monday.Add(4 * time.Hour),
+		Note:            "half day",
+		TimeEntryTypeID: f.timeEntryType.ID,
+	}
+	if _, err := serviceFor(f, f.colleague).Update(f.colleague, entry.ID, update); !errors.Is(err, gorm.ErrRecordNotFound) {
+		t.Errorf("expected ErrRecordNotFound for a colleague, got %v", err)
+	}
+	if _, err := serviceFor(f, f.foreignUser).Update(f.foreignUser, entry.ID, update); !errors.Is(err, gorm.ErrRecordNotFound) {
+		t.Errorf("expected ErrRecordNotFound for another company, got %v", err)
+	}
+
+	updated, err := serviceFor(f, f.manager).Update(f.manager, entry.ID, update)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if updated.Note != "half day" || updated.Duration.Float64 != 4 || updated.UserID != f.employee.ID {
+		t.Errorf("unexpected entry after update: %+v", updated)
+	}
+
+	if err := serviceFor(f, f.colleague).Delete(f.colleague, entry.ID, &dto.DeleteTimeEntryRequest{}); !errors.Is(err, gorm.ErrRecordNotFound) {
+		t.Errorf("expected ErrRecordNotFound for a colleague, got %v", err)
+	}
+	if err := serviceFor(f, f.employee).Delete(f.employee, entry.ID, &dto.DeleteTimeEntryRequest{}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := serviceFor(f, f.employee).Get(f.employee, entry.ID); !errors.Is(err, gorm.ErrRecordNotFound) {
+		t.Errorf("expected ErrRecordNotFound, got %v", err)
+	}
+}
+
+func TestTimeEntryService_Checks_Compliance(t *testing.T) {
+	f := setupFixture(t)
+
+	entry, err := serviceFor(f, f.employee).Create(f.employee, createRequest(f, 0, monday))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(entry.Warnings) != 1 || entry.Warnings[0].Rule != models.COMPLIANCE_RULE_BREAKS {
+		t.Errorf("expected a warning about the missing break, got %+v", entry.Warnings)
+	}
+
+	ruleSet := &models.ComplianceRuleSet{CompanyID: f.employee.CompanyID, Rules: []models.ComplianceRule{
+		{Rule: models.COMPLIANCE_RULE_DAILY_MAXIMUM, Enabled: true, Severity: models.COMPLIANCE_SEVERITY_BLOCKING},
+	}}
+	servicetest.MustCreate(t, f.db, ruleSet)
+	update := &dto.UpdateTimeEntryRequest{StartTime: monday, EndTime: monday.Add(11 * time.Hour), TimeEntryTypeID: f.timeEntryType.ID}
+	if _, err := serviceFor(f, f.employee).Update(f.employee, entry.ID, update); !errors.Is(err, compliance.ErrComplianceViolation) {
+		t.Errorf("expected ErrComplianceViolation, got %v", err)
+	}
+	stored, _ := serviceFor(f, f.employee).Get(f.employee, entry.ID)
+	if stored.Duration.Float64 != 8 {
+		t.Errorf("expected the entry to be unchanged, got %+v", stored)
+	}
+
+	_, err = serviceFor(f, f.employee).Create(f.employee, &dto.CreateTimeEntryRequest{
+		StartTime: monday.AddDate(0, 0, 1), EndTime: monday.AddDate(0, 0, 1).Add(11 * time.Hour), TimeEntryTypeID: f.timeEntryType.ID,
+	})
+	if !errors.Is(err, compliance.ErrComplianceViolation) {
+		t.Errorf("expected ErrComplianceViolation, got %v", err)
+	}
+	if entries, _ := serviceFor(f, f.employee).List(f.employee, &dto.ListTimeEntriesRequest{}); len(entries) != 1 {
+		t.Errorf("expected the rejected entry not to be booked, got %d entries", len(entries))
+	}
+}
@@ -0,0 +1,268 @@
+package timeentry
+
+import (
+	"errors"
+	"time"
+
+	"github.com/r-52/embrace/models"
+	dto "github.com/r-52/embrace/models/dto/timeentry"
+	"github.com/r-52/embrace/repositories"
+	"gorm.io/gorm"
+)
+
+const TIMER_STATE_RUNNING = "running"
+const TIMER_STATE_PAUSED = "paused"
+const TIMER_STATE_STOPPED = "stopped"
+
+// TimerService implements clocking in and out. The running timer of a user is their time entry
+// without an end time. Pausing closes that entry and resuming starts a new one with the same type
+// and note, so breaks remain visible as gaps between the entries.
+type TimerService struct {
+	unitOfWork *repositories.UnitOfWork
+}
+
+type TimerServiceInterface interface {
+	Current(actor *models.User, now time.Time) (*dto.TimerResponse, error)
+	ClockIn(actor *models.User, req *dto.ClockInRequest, now time.Time) (*dto.TimerResponse, error)
+	ClockOut(actor *models.User, now time.Time) (*dto.TimerResponse, error)
+	Pause(actor *models.User, now time.Time) (*dto.TimerResponse, error)
+	Resume(actor *models.User, now time.Time) (*dto.TimerResponse, error)
+}
+
+// NewTimerService creates a TimerService. The database should be scoped to the actor's company, see repositories.WithTenant.
+func NewTimerService(db *gorm.DB) *TimerService {
+	return NewTimerServiceWithUnitOfWork(repositories.NewUnitOfWork(db))
+}
+
+// NewTimerServiceWithUnitOfWork creates a TimerService whose repositories join the given unit of work.
+func NewTimerServiceWithUnitOfWork(uow *repositories.UnitOfWork) *TimerService {
+	return &TimerService{
+		unitOfWork: uow,
+	}
+}
+
+// Current returns the running or paused timer of the actor.
+func (s *TimerService) Current(actor *models.User, now time.Time) (*dto.TimerResponse, error) {
+	if err := authorize(s.unitOfWork, actor, actor.ID, ACTION_READ); err != nil {
+		return nil, err
+	}
+
+	running, err := s.unitOfWork.TimeEntries().GetRunningByUserID(actor.ID)
+	if err == nil {
+		return newTimerResponse(TIMER_STATE_RUNNING, running, now), nil
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+
+	paused, err := s.unitOfWork.TimeEntries().GetPausedByUserID(actor.ID)
+	if err == nil {
+		return newTimerResponse(TIMER_STATE_PAUSED, paused, now), nil
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+	return newTimerResponse(TIMER_STATE_STOPPED, nil, now), nil
+}
+
+// ClockIn starts a timer for the actor. It returns ErrAlreadyClockedIn if a timer is running,
+// also when another request started one at the same time, ErrTimerPaused if a timer is paused
+// and an *OverlapError if an entry booked in advance ends after now. Quota relevant types cannot
+// be clocked, they are booked as closed entries.
+func (s *TimerService) ClockIn(actor *models.User, req *dto.ClockInRequest, now time.Time) (*dto.TimerResponse, error) {
+	if err := authorize(s.unitOfWork, actor, actor.ID, ACTION_WRITE); err != nil {
+		return nil, err
+	}
+	timeEntryType, err := getTimeEntryType(s.unitOfWork, req.TimeEntryTypeID)
+	if err != nil {
+		return nil, err
+	}
+	if timeEntryType.IsQuotaRelevant {
+		return nil, ErrQuotaRelevantTimer
+	}
+
+	_, err = s.unitOfWork.TimeEntries().GetPausedByUserID(actor.ID)
+	if err == nil {
+		return nil, ErrTimerPaused
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+
+	entry := &models.TimeEntry{
+		StartTime:       now,
+		Note:            req.Note,
+		CompanyID:       actor.CompanyID,
+		UserID:          actor.ID,
+		TimeEntryTypeID: req.TimeEntryTypeID,
+	}
+	err = s.unitOfWork.Transaction(func(uow *repositories.UnitOfWork) error {
+		return start(uow, actor, entry, "timer started")
+	})
+	if err != nil {
+		return nil, err
+	}
+	return newTimerResponse(TIMER_STATE_RUNNING, entry, now), nil
+}
+
+// ClockOut stops the running timer of the actor and returns the closed entry. A paused timer
+// is stopped at the time it was paused. Without a timer it returns ErrNotClockedIn.
+func (s *TimerService) ClockOut(actor *models.User, now time.Time) (*dto.TimerResponse, error) {
+	if err := authorize(s.unitOfWork, actor, actor.ID, ACTION_WRITE); err != nil {
+		return nil, err
+	}
+
+	running, err := s.unitOfWork.TimeEntries().GetRunningByUserID(actor.ID)
+	if err == nil {
+		err = s.unitOfWork.Transaction(func(uow *repositories.UnitOfWork) error {
+			return stop(uow, actor, running, now, false, "timer stopped")
+		})
+		if err != nil {
+			return nil, notClockedIn(err)
+		}
+		return newTimerResponse(TIMER_STATE_STOPPED, running, now), nil
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+
+	paused, err := s.unitOfWork.TimeEntries().GetPausedByUserID(actor.ID)
+	if err != nil {
+		return nil, notClockedIn(err)
+	}
+	err = s.unitOfWork.Transaction(func(uow *repositories.UnitOfWork) error {
+		return unpause(uow, actor, paused, "timer stopped")
+	})
+	if err != nil {
+		return nil, notClockedIn(err)
+	}
+	return newTimerResponse(TIMER_STATE_STOPPED, paused, now), nil
+}
+
+// Pause closes the running timer of the actor so it can be resumed later.
+// Without a running timer it returns ErrNotClockedIn.
+func (s *TimerService) Pause(actor *models.User, now time.Time) (*dto.TimerResponse, error) {
+	if err := authorize(s.unitOfWork, actor, actor.ID, ACTION_WRITE); err != nil {
+		return nil, err
+	}
+
+	running, err := s.unitOfWork.TimeEntries().GetRunningByUserID(actor.ID)
+	if err != nil {
+		return nil, notClockedIn(err)
+	}
+	err = s.unitOfWork.Transaction(func(uow *repositories.UnitOfWork) error {
+		return stop(uow, actor, running, now, true, "timer paused")
+	})
+	if err != nil {
+		return nil, notClockedIn(err)
+	}
+	return newTimerResponse(TIMER_STATE_PAUSED, running, now), nil
+}
+
+// Resume starts a new timer with the type and note of the paused one.
+// Without a paused timer it returns ErrTimerNotPaused.
+func (s *TimerService) Resume(actor *models.User, now time.Time) (*dto.TimerResponse, error) {
+	if err := authorize(s.unitOfWork, actor, actor.ID, ACTION_WRITE); err != nil {
+		return nil, err
+	}
+
+	var entry *models.TimeEntry
+	err := s.unitOfWork.Transaction(func(uow *repositories.UnitOfWork) error {
+		paused, err := uow.TimeEntries().GetPausedByUserID(actor.ID)
+		if err != nil {
+			return err
+		}
+		if err := unpause(uow, actor, paused, "timer resumed"); err != nil {
+			return err
+		}
+
+		entry = &models.TimeEntry{
+			StartTime:       now,
+			Note:            paused.Note,
+			CompanyID:       actor.CompanyID,
+			UserID:          actor.ID,
+			TimeEntryTypeID: paused.TimeEntryTypeID,
+		}
+		return start(uow, actor, entry, "timer resumed")
+	})
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrTimerNotPaused
+	}
+	if err != nil {
+		return nil, err
+	}
+	return newTimerResponse(TIMER_STATE_RUNNING, entry, now), nil
+}
+
+// start validates and inserts a running time entry and records it in the audit trail. The unique index on running
+// entries rejects a second one, even if two requests pass the validation at the same time. Timers do not start in
+// a locked timesheet period.
+func start(uow *repositories.UnitOfWork, actor *models.User, entry *models.TimeEntry, reason string) error {
+	if err := checkUnlocked(uow, entry); err != nil {
+		return err
+	}
+	err := NewTimeEntryValidatorWithUnitOfWork(uow).Validate(entry)
+	if errors.Is(err, ErrTimeEntryOverlaps) {
+		if _, runningErr := uow.TimeEntries().GetRunningByUserID(entry.UserID); runningErr == nil {
+			return ErrAlreadyClockedIn
+		}
+	}
+	if err != nil {
+		return err
+	}
+
+	err = uow.TimeEntries().Create(entry)
+	if errors.Is(err, gorm.ErrDuplicatedKey) {
+		return ErrAlreadyClockedIn
+	}
+	if err != nil {
+		return err
+	}
+	return recordAudit(uow, actor, nil, entry, reason)
+}
+
+// stop closes a running time entry at `now` with the duration of its company's policy, marks it as paused or not,
+// and records the change in the audit trail. It returns gorm.ErrRecordNotFound if the timer was stopped concurrently.
+func stop(uow *repositories.UnitOfWork, actor *models.User, running *models.TimeEntry, now time.Time, paused bool, reason string) error {
+	before := *running
+	running.Close(now)
+	running.Paused = paused
+	if err := applyDurationPolicy(uow, running); err != nil {
+		return err
+	}
+	if err := uow.TimeEntries().Stop(running); err != nil {
+		return err
+	}
+	return recordAudit(uow, actor, &before, running, reason)
+}
+
+// unpause clears the paused flag of a time entry and records the change in the audit trail.
+func unpause(uow *repositories.UnitOfWork, actor *models.User, paused *models.TimeEntry, reason string) error {
+	before := *paused
+	if err := uow.TimeEntries().Unpause(paused.ID); err != nil {
+		return err
+	}
+	paused.Paused = false
+	return recordAudit(uow, actor, &before, paused, reason)
+}
+
+func notClockedIn(err error) error {
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return ErrNotClockedIn
+	}
+	return err
+}
+
+func newTimerResponse(state string, entry *models.TimeEntry, now time.Time) *dto.TimerResponse {
+	response := &dto.TimerResponse{State: state}
+	if entry == nil {
+		return response
+	}
+	response.Entry = dto.NewTimeEntryResponse(entry)
+	if entry.IsRunning() {
+		response.Elapsed = now.Sub(entry.StartTime).Hours()
+	} else {
+		response.Elapsed = entry.Duration.Float64
+	}
+	return response
+}
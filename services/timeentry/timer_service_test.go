@@ -0,0 +1,165 @@
+package timeentry_test
+
+import (
+	"errors"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/r-52/embrace/models"
+	dto "github.com/r-52/embrace/models/dto/timeentry"
+	"github.com/r-52/embrace/repositories"
+	"github.com/r-52/embrace/services/timeentry"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func timerFor(f *fixture, actor *models.User) *timeentry.TimerService {
+	return timeentry.NewTimerService(repositories.WithTenant(f.db, actor.CompanyID))
+}
+
+func TestTimerService_Clock_In_And_Out(t *testing.T) {
+	f := setupFixture(t)
+	timer := timerFor(f, f.employee)
+
+	current, err := timer.Current(f.employee, monday)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if current.State != timeentry.TIMER_STATE_STOPPED || current.Entry != nil {
+		t.Errorf("expected a stopped timer, got %+v", current)
+	}
+
+	req := &dto.ClockInRequest{TimeEntryTypeID: f.timeEntryType.ID, Note: "support"}
+	if _, err := timer.ClockIn(f.employee, req, monday); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := timer.ClockIn(f.employee, req, monday.Add(time.Minute)); !errors.Is(err, timeentry.ErrAlreadyClockedIn) {
+		t.Errorf("expected ErrAlreadyClockedIn, got %v", err)
+	}
+
+	current, err = timer.Current(f.employee, monday.Add(90*time.Minute))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if current.State != timeentry.TIMER_STATE_RUNNING || current.Elapsed != 1.5 || current.Entry.EndTime != nil {
+		t.Errorf("expected a running timer, got %+v", current)
+	}
+
+	stopped, err := timer.ClockOut(f.employee, monday.Add(8*time.Hour))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if stopped.State != timeentry.TIMER_STATE_STOPPED || *stopped.Entry.Duration != 8 || stopped.Entry.Note != "support" {
+		t.Errorf("unexpected timer after clock out: %+v", stopped)
+	}
+	if _, err := timer.ClockOut(f.employee, monday.Add(9*time.Hour)); !errors.Is(err, timeentry.ErrNotClockedIn) {
+		t.Errorf("expected ErrNotClockedIn, got %v", err)
+	}
+
+	// Other users have their own timer.
+	if _, err := timerFor(f, f.colleague).ClockIn(f.colleague, req, monday); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestTimerService_Pause_And_Resume(t *testing.T) {
+	f := setupFixture(t)
+	timer := timerFor(f, f.employee)
+
+	if _, err := timer.Pause(f.employee, monday); !errors.Is(err, timeentry.ErrNotClockedIn) {
+		t.Errorf("expected ErrNotClockedIn, got %v", err)
+	}
+	if _, err := timer.Resume(f.employee, monday); !errors.Is(err, timeentry.ErrTimerNotPaused) {
+		t.Errorf("expected ErrTimerNotPaused, got %v", err)
+	}
+
+	req := &dto.ClockInRequest{TimeEntryTypeID: f.timeEntryType.ID, Note: "support"}
+	if _, err := timer.ClockIn(f.employee, req, monday); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	paused, err := timer.Pause(f.employee, monday.Add(4*time.Hour))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if paused.State != timeentry.TIMER_STATE_PAUSED || paused.Elapsed != 4 {
+		t.Errorf("unexpected timer after pause: %+v", paused)
+	}
+	if _, err := timer.ClockIn(f.employee, req, monday.Add(5*time.Hour)); !errors.Is(err, timeentry.ErrTimerPaused) {
+		t.Errorf("expected ErrTimerPaused, got %v", err)
+	}
+
+	resumed, err := timer.Resume(f.employee, monday.Add(5*time.Hour))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if resumed.State != timeentry.TIMER_STATE_RUNNING || resumed.Entry.Note != "support" || resumed.Entry.ID == paused.Entry.ID {
+		t.Errorf("unexpected timer after resume: %+v", resumed)
+	}
+
+	if _, err := timer.Pause(f.employee, monday.Add(7*time.Hour)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	// Clocking out while paused ends the day at the pause.
+	stopped, err := timer.ClockOut(f.employee, monday.Add(9*time.Hour))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if stopped.State != timeentry.TIMER_STATE_STOPPED || stopped.Elapsed != 2 || stopped.Entry.Paused {
+		t.Errorf("unexpected timer after clock out: %+v", stopped)
+	}
+
+	entries, err := repositories.NewTimeEntryRepository(f.db).GetByUserID(f.employee.ID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(entries) != 2 || entries[0].Duration.Float64 != 4 || entries[1].Duration.Float64 != 2 {
+		t.Errorf("expected two closed entries with 4 and 2 hours, got %+v", entries)
+	}
+}
+
+func TestTimerService_Concurrent_Clock_Ins(t *testing.T) {
+	dsn := filepath.Join(t.TempDir(), "timer.sqlite") + "?_busy_timeout=5000&_txlock=immediate"
+	db, err := gorm.Open(sqlite.Open(dsn), &gorm.Config{TranslateError: true})
+	if err != nil {
+		t.Fatalf("failed to open database: %v", err)
+	}
+	if err := repositories.RegisterTenantCallbacks(db); err != nil {
+		t.Fatalf("failed to register tenant callbacks: %v", err)
+	}
+	f := setupFixtureWithDb(t, db)
+
+	const devices = 8
+	var wg sync.WaitGroup
+	errs := make(chan error, devices)
+	for i := 0; i < devices; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := timerFor(f, f.employee).ClockIn(f.employee, &dto.ClockInRequest{TimeEntryTypeID: f.timeEntryType.ID}, time.Now())
+			errs <- err
+		}()
+	}
+	wg.Wait()
+	close(errs)
+
+	succeeded := 0
+	for err := range errs {
+		switch {
+		case err == nil:
+			succeeded++
+		case !errors.Is(err, timeentry.ErrAlreadyClockedIn):
+			t.Errorf("expected ErrAlreadyClockedIn, got %v", err)
+		}
+	}
+	if succeeded != 1 {
+		t.Errorf("expected exactly one clock in to succeed, got %d", succeeded)
+	}
+
+	var running int64
+	db.Model(&models.TimeEntry{}).Where("user_id = ? AND end_time IS NULL", f.employee.ID).Count(&running)
+	if running != 1 {
+		t.Errorf("expected one running entry, got %d", running)
+	}
+}
@@ -0,0 +1,267 @@
+package timeentry
+
+import (
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/r-52/embrace/models"
+	dto "github.com/r-52/embrace/models/dto/timeentry"
+	"github.com/r-52/embrace/repositories"
+	"github.com/r-52/embrace/services/auth"
+	"gorm.io/gorm"
+)
+
+// TimesheetService implements the monthly sign-off of timesheets, see models.TimesheetPeriod. Users submit their own
+// timesheet, which their manager or anyone who may approve the time entries of the whole company approves. Locking
+// a month and reopening a period are up to the company's admins.
+type TimesheetService struct {
+	unitOfWork *repositories.UnitOfWork
+}
+
+type TimesheetServiceInterface interface {
+	Get(actor *models.User, userID uint, month time.Time) (*models.TimesheetPeriod, error)
+	Missing(actor *models.User, month time.Time) ([]models.TimesheetPeriod, error)
+	Submit(actor *models.User, month time.Time, now time.Time) (*models.TimesheetPeriod, error)
+	Approve(actor *models.User, userID uint, month time.Time, now time.Time) (*models.TimesheetPeriod, error)
+	Lock(actor *models.User, month time.Time, now time.Time) ([]models.TimesheetPeriod, error)
+	Reopen(actor *models.User, userID uint, month time.Time, req *dto.ReopenTimesheetRequest, now time.Time) (*models.TimesheetPeriod, error)
+}
+
+// NewTimesheetService creates a TimesheetService. The database should be scoped to the actor's company, see repositories.WithTenant.
+func NewTimesheetService(db *gorm.DB) *TimesheetService {
+	return NewTimesheetServiceWithUnitOfWork(repositories.NewUnitOfWork(db))
+}
+
+// NewTimesheetServiceWithUnitOfWork creates a TimesheetService whose repositories join the given unit of work.
+func NewTimesheetServiceWithUnitOfWork(uow *repositories.UnitOfWork) *TimesheetService {
+	return &TimesheetService{
+		unitOfWork: uow,
+	}
+}
+
+// Get returns the timesheet period of a user for the month starting at `month`. Users the actor may not read are
+// reported as gorm.ErrRecordNotFound.
+func (s *TimesheetService) Get(actor *models.User, userID uint, month time.Time) (*models.TimesheetPeriod, error) {
+	if err := authorize(s.unitOfWork, actor, userID, ACTION_READ); err != nil {
+		return nil, hideForbidden(err)
+	}
+	return periodOf(s.unitOfWork, userID, actor.CompanyID, month)
+}
+
+// Missing returns the open timesheet periods of the month of the users whose timesheets the actor may approve,
+// that is the users who did not submit their timesheet yet, ordered by user.
+func (s *TimesheetService) Missing(actor *models.User, month time.Time) ([]models.TimesheetPeriod, error) {
+	var users []*models.User
+	var err error
+	if actor.Role.HasPermission(models.PERMISSION_TIME_ENTRIES_APPROVE_ALL) {
+		users, err = s.unitOfWork.Users().GetUsersByCompanyID(actor.CompanyID)
+	} else if actor.Role.HasPermission(models.PERMISSION_TIME_ENTRIES_APPROVE_TEAM) {
+		users, err = s.unitOfWork.Users().GetByManagerID(actor.ID)
+	} else {
+		return nil, auth.ErrPermissionDenied
+	}
+	if err != nil {
+		return nil, err
+	}
+	stored, err := s.unitOfWork.TimesheetPeriods().GetByMonth(month)
+	if err != nil {
+		return nil, err
+	}
+	periods := map[uint]models.TimesheetPeriod{}
+	for _, period := range stored {
+		periods[period.UserID] = period
+	}
+
+	missing := []models.TimesheetPeriod{}
+	for _, user := range users {
+		period, ok := periods[user.ID]
+		if !ok {
+			period = models.TimesheetPeriod{CompanyID: actor.CompanyID, UserID: user.ID, Month: month, Status: models.TIMESHEET_OPEN}
+		}
+		if period.Status == models.TIMESHEET_OPEN {
+			missing = append(missing, period)
+		}
+	}
+	return missing, nil
+}
+
+// Submit submits the actor's timesheet for the month. It fails with ErrTimesheetNotOpen if it was submitted before.
+func (s *TimesheetService) Submit(actor *models.User, month time.Time, now time.Time) (*models.TimesheetPeriod, error) {
+	if err := authorize(s.unitOfWork, actor, actor.ID, ACTION_WRITE); err != nil {
+		return nil, err
+	}
+	return s.transition(actor.ID, actor.CompanyID, month, func(period *models.TimesheetPeriod) error {
+		if period.Status != models.TIMESHEET_OPEN {
+			return ErrTimesheetNotOpen
+		}
+		period.Status = models.TIMESHEET_SUBMITTED
+		period.SubmittedAt = sql.NullTime{Time: now, Valid: true}
+		return nil
+	})
+}
+
+// Approve approves the submitted timesheet of a user for the month. Only those who may approve the time entries of
+// the whole company approve their own timesheet. Users the actor may not even read are reported as
+// gorm.ErrRecordNotFound. It fails with ErrTimesheetNotSubmitted if the timesheet is not waiting for approval.
+func (s *TimesheetService) Approve(actor *models.User, userID uint, month time.Time, now time.Time) (*models.TimesheetPeriod, error) {
+	if err := authorize(s.unitOfWork, actor, userID, ACTION_READ); err != nil {
+		return nil, hideForbidden(err)
+	}
+	if err := authorize(s.unitOfWork, actor, userID, ACTION_APPROVE); err != nil {
+		return nil, err
+	}
+	if userID == actor.ID && !actor.Role.HasPermission(models.PERMISSION_TIME_ENTRIES_APPROVE_ALL) {
+		return nil, auth.ErrPermissionDenied
+	}
+	return s.transition(userID, actor.CompanyID, month, func(period *models.TimesheetPeriod) error {
+		if period.Status != models.TIMESHEET_SUBMITTED {
+			return ErrTimesheetNotSubmitted
+		}
+		period.Status = models.TIMESHEET_APPROVED
+		period.ApprovedByID = &actor.ID
+		period.ApprovedAt = sql.NullTime{Time: now, Valid: true}
+		return nil
+	})
+}
+
+// Lock closes the month for payroll. It locks the timesheet periods of every user of the company, whether they were
+// approved or not, so their time entries cannot be changed anymore. Periods that were locked before are kept.
+func (s *TimesheetService) Lock(actor *models.User, month time.Time, now time.Time) ([]models.TimesheetPeriod, error) {
+	var locked []models.TimesheetPeriod
+	err := s.unitOfWork.Transaction(func(uow *repositories.UnitOfWork) error {
+		users, err := uow.Users().GetUsersByCompanyID(actor.CompanyID)
+		if err != nil {
+			return err
+		}
+		for _, user := range users {
+			period, err := storedPeriodOf(uow, user.ID, actor.CompanyID, month)
+			if err != nil {
+				return err
+			}
+			if !period.IsLocked() {
+				from := period.Status
+				period.Status = models.TIMESHEET_LOCKED
+				period.LockedByID = &actor.ID
+				period.LockedAt = sql.NullTime{Time: now, Valid: true}
+				if err := uow.TimesheetPeriods().Transition(period, from); err != nil {
+					return err
+				}
+			}
+			locked = append(locked, *period)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return locked, nil
+}
+
+// Reopen opens the timesheet period of a user for the month again, so its time entries can be changed and the
+// timesheet has to be submitted anew. The reason is recorded with the period. It fails with ErrTimesheetOpen
+// if the period is open.
+func (s *TimesheetService) Reopen(actor *models.User, userID uint, month time.Time, req *dto.ReopenTimesheetRequest, now time.Time) (*models.TimesheetPeriod, error) {
+	if _, err := s.unitOfWork.Users().GetByID(userID); err != nil {
+		return nil, err
+	}
+	return s.transition(userID, actor.CompanyID, month, func(period *models.TimesheetPeriod) error {
+		if period.Status == models.TIMESHEET_OPEN {
+			return ErrTimesheetOpen
+		}
+		*period = models.TimesheetPeriod{
+			Model:        period.Model,
+			CompanyID:    period.CompanyID,
+			UserID:       period.UserID,
+			Month:        period.Month,
+			Status:       models.TIMESHEET_OPEN,
+			ReopenedByID: &actor.ID,
+			ReopenedAt:   sql.NullTime{Time: now, Valid: true},
+			ReopenReason: req.Reason,
+		}
+		return nil
+	})
+}
+
+// transition moves the timesheet period of a user for the month as `change` says, storing the period first if the
+// month has none yet. If the period was changed concurrently, `change` is applied to its new state once more.
+func (s *TimesheetService) transition(userID, companyID uint, month time.Time, change func(period *models.TimesheetPeriod) error) (*models.TimesheetPeriod, error) {
+	var changed *models.TimesheetPeriod
+	err := s.unitOfWork.Transaction(func(uow *repositories.UnitOfWork) error {
+		period, err := storedPeriodOf(uow, userID, companyID, month)
+		if err != nil {
+			return err
+		}
+		for retried := false; ; retried = true {
+			from := period.Status
+			if err := change(period); err != nil {
+				return err
+			}
+			err = uow.TimesheetPeriods().Transition(period, from)
+			if !errors.Is(err, gorm.ErrRecordNotFound) || retried {
+				break
+			}
+			if period, err = uow.TimesheetPeriods().GetByUserIDAndMonth(userID, month); err != nil {
+				return err
+			}
+		}
+		if err != nil {
+			return err
+		}
+		changed = period
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return changed, nil
+}
+
+// periodOf returns the timesheet period of a user for the month, or an open period that is not stored if the
+// month has none.
+func periodOf(uow *repositories.UnitOfWork, userID, companyID uint, month time.Time) (*models.TimesheetPeriod, error) {
+	period, err := uow.TimesheetPeriods().GetByUserIDAndMonth(userID, month)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return &models.TimesheetPeriod{CompanyID: companyID, UserID: userID, Month: month, Status: models.TIMESHEET_OPEN}, nil
+	}
+	return period, err
+}
+
+// storedPeriodOf returns the timesheet period of a user for the month, storing an open one if the month has none.
+func storedPeriodOf(uow *repositories.UnitOfWork, userID, companyID uint, month time.Time) (*models.TimesheetPeriod, error) {
+	period, err := periodOf(uow, userID, companyID, month)
+	if err != nil || period.ID != 0 {
+		return period, err
+	}
+	if err := uow.TimesheetPeriods().Create(period); err != nil {
+		return nil, err
+	}
+	return period, nil
+}
+
+// checkUnlocked returns ErrTimesheetLocked if the time entry starts in a locked timesheet period of its user.
+func checkUnlocked(uow *repositories.UnitOfWork, entry *models.TimeEntry) error {
+	locked, err := isLocked(uow, entry)
+	if err != nil {
+		return err
+	}
+	if locked {
+		return ErrTimesheetLocked
+	}
+	return nil
+}
+
+// isLocked reports whether the time entry starts in a locked timesheet period of its user. Periods are months in
+// the company's timezone.
+func isLocked(uow *repositories.UnitOfWork, entry *models.TimeEntry) (bool, error) {
+	company, err := uow.Companies().GetByID(entry.CompanyID)
+	if err != nil {
+		return false, err
+	}
+	start := entry.StartTime.In(company.Location())
+	period, err := periodOf(uow, entry.UserID, entry.CompanyID, time.Date(start.Year(), start.Month(), 1, 0, 0, 0, 0, time.UTC))
+	if err != nil {
+		return false, err
+	}
+	return period.IsLocked(), nil
+}
@@ -0,0 +1,127 @@
+package timeentry_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/r-52/embrace/models"
+	dto "github.com/r-52/embrace/models/dto/timeentry"
+	"github.com/r-52/embrace/repositories"
+	"github.com/r-52/embrace/services/auth"
+	"github.com/r-52/embrace/services/timeentry"
+	"gorm.io/gorm"
+)
+
+func timesheetsFor(f *fixture, actor *models.User) *timeentry.TimesheetService {
+	return timeentry.NewTimesheetService(repositories.WithTenant(f.db, actor.CompanyID))
+}
+
+var march = time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+
+func TestTimesheetService_Sign_Off(t *testing.T) {
+	f := setupFixture(t)
+
+	missing, err := timesheetsFor(f, f.manager).Missing(f.manager, march)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(missing) != 1 || missing[0].UserID != f.employee.ID || missing[0].Status != models.TIMESHEET_OPEN {
+		t.Errorf("expected the manager's team to be missing, got %+v", missing)
+	}
+	if _, err := timesheetsFor(f, f.employee).Missing(f.employee, march); !errors.Is(err, auth.ErrPermissionDenied) {
+		t.Errorf("expected ErrPermissionDenied, got %v", err)
+	}
+
+	if _, err := timesheetsFor(f, f.manager).Approve(f.manager, f.employee.ID, march, monday); !errors.Is(err, timeentry.ErrTimesheetNotSubmitted) {
+		t.Errorf("expected ErrTimesheetNotSubmitted, got %v", err)
+	}
+	submitted, err := timesheetsFor(f, f.employee).Submit(f.employee, march, monday)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if submitted.Status != models.TIMESHEET_SUBMITTED || !submitted.SubmittedAt.Valid {
+		t.Errorf("unexpected submission: %+v", submitted)
+	}
+	if _, err := timesheetsFor(f, f.employee).Submit(f.employee, march, monday); !errors.Is(err, timeentry.ErrTimesheetNotOpen) {
+		t.Errorf("expected ErrTimesheetNotOpen, got %v", err)
+	}
+	if missing, _ := timesheetsFor(f, f.admin).Missing(f.admin, march); len(missing) != 3 {
+		t.Errorf("expected everyone but the employee to be missing, got %+v", missing)
+	}
+
+	if _, err := timesheetsFor(f, f.colleague).Approve(f.colleague, f.employee.ID, march, monday); !errors.Is(err, gorm.ErrRecordNotFound) {
+		t.Errorf("expected ErrRecordNotFound, got %v", err)
+	}
+	approved, err := timesheetsFor(f, f.manager).Approve(f.manager, f.employee.ID, march, monday)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if approved.Status != models.TIMESHEET_APPROVED || *approved.ApprovedByID != f.manager.ID {
+		t.Errorf("unexpected approval: %+v", approved)
+	}
+
+	period, err := timesheetsFor(f, f.employee).Get(f.employee, f.employee.ID, march)
+	if err != nil || period.Status != models.TIMESHEET_APPROVED {
+		t.Errorf("expected the approved period, got %v %+v", err, period)
+	}
+	if period, err := timesheetsFor(f, f.employee).Get(f.employee, f.employee.ID, march.AddDate(0, 1, 0)); err != nil || period.Status != models.TIMESHEET_OPEN {
+		t.Errorf("expected April to be open, got %v %+v", err, period)
+	}
+}
+
+func TestTimesheetService_Lock_And_Reopen(t *testing.T) {
+	f := setupFixture(t)
+	entry, err := serviceFor(f, f.employee).Create(f.employee, createRequest(f, 0, monday))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	locked, err := timesheetsFor(f, f.admin).Lock(f.admin, march, monday)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(locked) != 4 {
+		t.Fatalf("expected the periods of every user to be locked, got %+v", locked)
+	}
+	for _, period := range locked {
+		if !period.IsLocked() || *period.LockedByID != f.admin.ID {
+			t.Errorf("unexpected period: %+v", period)
+		}
+	}
+
+	update := &dto.UpdateTimeEntryRequest{StartTime: monday, EndTime: monday.Add(4 * time.Hour), TimeEntryTypeID: f.timeEntryType.ID}
+	if _, err := serviceFor(f, f.admin).Update(f.admin, entry.ID, update); !errors.Is(err, timeentry.ErrTimesheetLocked) {
+		t.Errorf("expected ErrTimesheetLocked on update, got %v", err)
+	}
+	if err := serviceFor(f, f.employee).Delete(f.employee, entry.ID, &dto.DeleteTimeEntryRequest{}); !errors.Is(err, timeentry.ErrTimesheetLocked) {
+		t.Errorf("expected ErrTimesheetLocked on delete, got %v", err)
+	}
+	if _, err := serviceFor(f, f.employee).Create(f.employee, createRequest(f, 0, monday.AddDate(0, 0, 1))); !errors.Is(err, timeentry.ErrTimesheetLocked) {
+		t.Errorf("expected ErrTimesheetLocked on create, got %v", err)
+	}
+	moved := &dto.UpdateTimeEntryRequest{StartTime: monday.AddDate(0, 1, 0), EndTime: monday.AddDate(0, 1, 0).Add(time.Hour), TimeEntryTypeID: f.timeEntryType.ID}
+	if _, err := serviceFor(f, f.employee).Update(f.employee, entry.ID, moved); !errors.Is(err, timeentry.ErrTimesheetLocked) {
+		t.Errorf("expected ErrTimesheetLocked when moving out of the period, got %v", err)
+	}
+	if _, err := serviceFor(f, f.colleague).Create(f.colleague, createRequest(f, 0, monday.AddDate(0, 1, 0))); err != nil {
+		t.Errorf("expected April to be open, got %v", err)
+	}
+
+	reopened, err := timesheetsFor(f, f.admin).Reopen(f.admin, f.employee.ID, march, &dto.ReopenTimesheetRequest{Reason: "missing sick day"}, monday)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if reopened.Status != models.TIMESHEET_OPEN || reopened.LockedByID != nil || *reopened.ReopenedByID != f.admin.ID || reopened.ReopenReason != "missing sick day" {
+		t.Errorf("unexpected reopening: %+v", reopened)
+	}
+	if _, err := timesheetsFor(f, f.admin).Reopen(f.admin, f.employee.ID, march, &dto.ReopenTimesheetRequest{Reason: "again"}, monday); !errors.Is(err, timeentry.ErrTimesheetOpen) {
+		t.Errorf("expected ErrTimesheetOpen, got %v", err)
+	}
+	if _, err := serviceFor(f, f.employee).Update(f.employee, entry.ID, update); err != nil {
+		t.Errorf("expected the reopened period to be writable, got %v", err)
+	}
+	if _, err := serviceFor(f, f.colleague).Create(f.colleague, createRequest(f, 0, monday)); !errors.Is(err, timeentry.ErrTimesheetLocked) {
+		t.Errorf("expected the colleague's period to stay locked, got %v", err)
+	}
+}
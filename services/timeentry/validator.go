@@ -0,0 +1,61 @@
+package timeentry
+
+import (
+	"time"
+
+	"github.com/r-52/embrace/models"
+	"github.com/r-52/embrace/repositories"
+	"gorm.io/gorm"
+)
+
+// MAX_TIME_ENTRY_DURATION is the longest span a single time entry may cover.
+const MAX_TIME_ENTRY_DURATION = 24 * time.Hour
+
+// TimeEntryValidator checks time entries before they are written. Every path that books or changes
+// time entries runs it, so the rules hold no matter how an entry enters the system.
+type TimeEntryValidator struct {
+	unitOfWork *repositories.UnitOfWork
+}
+
+type TimeEntryValidatorInterface interface {
+	Validate(entry *models.TimeEntry) error
+}
+
+// NewTimeEntryValidator creates a TimeEntryValidator. The database should be scoped to the company of the entries, see repositories.WithTenant.
+func NewTimeEntryValidator(db *gorm.DB) *TimeEntryValidator {
+	return NewTimeEntryValidatorWithUnitOfWork(repositories.NewUnitOfWork(db))
+}
+
+// NewTimeEntryValidatorWithUnitOfWork creates a TimeEntryValidator that sees the uncommitted changes of the given unit of work.
+func NewTimeEntryValidatorWithUnitOfWork(uow *repositories.UnitOfWork) *TimeEntryValidator {
+	return &TimeEntryValidator{
+		unitOfWork: uow,
+	}
+}
+
+// Validate returns ErrEndBeforeStart if a closed entry does not end after it starts, ErrTimeEntryTooLong if it
+// is longer than MAX_TIME_ENTRY_DURATION and an *OverlapError if it overlaps another entry of the same user.
+// Running entries overlap every entry that ends after their start.
+func (v *TimeEntryValidator) Validate(entry *models.TimeEntry) error {
+	if entry.EndTime.Valid {
+		if !entry.EndTime.Time.After(entry.StartTime) {
+			return ErrEndBeforeStart
+		}
+		if entry.EndTime.Time.Sub(entry.StartTime) > MAX_TIME_ENTRY_DURATION {
+			return ErrTimeEntryTooLong
+		}
+	}
+
+	overlapping, err := v.unitOfWork.TimeEntries().GetOverlapping(entry.UserID, entry.StartTime, entry.EndTime, entry.ID)
+	if err != nil {
+		return err
+	}
+	if len(overlapping) > 0 {
+		entryIDs := make([]uint, 0, len(overlapping))
+		for _, other := range overlapping {
+			entryIDs = append(entryIDs, other.ID)
+		}
+		return &OverlapError{EntryIDs: entryIDs}
+	}
+	return nil
+}
@@ -0,0 +1,114 @@
+package timeentry_test
+
+import (
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/r-52/embrace/models"
+	dto "github.com/r-52/embrace/models/dto/timeentry"
+	"github.com/r-52/embrace/repositories"
+	"github.com/r-52/embrace/services/timeentry"
+)
+
+func TestTimeEntryValidator_Validate(t *testing.T) {
+	f := setupFixture(t)
+	booked, err := serviceFor(f, f.employee).Create(f.employee, createRequest(f, 0, monday))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	validator := timeentry.NewTimeEntryValidator(repositories.WithTenant(f.db, f.employee.CompanyID))
+
+	entry := func(userID uint, start time.Time, hours float64) *models.TimeEntry {
+		entry := &models.TimeEntry{UserID: userID, StartTime: start}
+		entry.Close(start.Add(time.Duration(hours * float64(time.Hour))))
+		return entry
+	}
+	tests := []struct {
+		name     string
+		entry    *models.TimeEntry
+		expected error
+	}{
+		{name: "before", entry: entry(f.employee.ID, monday.Add(-2*time.Hour), 2)},
+		{name: "after", entry: entry(f.employee.ID, monday.Add(8*time.Hour), 1)},
+		{name: "other user", entry: entry(f.colleague.ID, monday, 8)},
+		{name: "end before start", entry: entry(f.employee.ID, monday.AddDate(0, 0, 1), -1), expected: timeentry.ErrEndBeforeStart},
+		{name: "empty", entry: entry(f.employee.ID, monday.AddDate(0, 0, 1), 0), expected: timeentry.ErrEndBeforeStart},
+		{name: "too long", entry: entry(f.employee.ID, monday.AddDate(0, 0, 1), 25), expected: timeentry.ErrTimeEntryTooLong},
+		{name: "overlaps start", entry: entry(f.employee.ID, monday.Add(-time.Hour), 2), expected: timeentry.ErrTimeEntryOverlaps},
+		{name: "inside", entry: entry(f.employee.ID, monday.Add(time.Hour), 1), expected: timeentry.ErrTimeEntryOverlaps},
+		{name: "running", entry: &models.TimeEntry{UserID: f.employee.ID, StartTime: monday.Add(7 * time.Hour)}, expected: timeentry.ErrTimeEntryOverlaps},
+		{name: "itself", entry: &models.TimeEntry{Model: booked.Model, UserID: f.employee.ID, StartTime: monday, EndTime: sql.NullTime{Time: monday.Add(time.Hour), Valid: true}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validator.Validate(tt.entry)
+			if !errors.Is(err, tt.expected) {
+				t.Errorf("expected %v, got %v", tt.expected, err)
+			}
+		})
+	}
+
+	var overlapErr *timeentry.OverlapError
+	if err := validator.Validate(entry(f.employee.ID, monday, 1)); !errors.As(err, &overlapErr) || len(overlapErr.EntryIDs) != 1 || overlapErr.EntryIDs[0] != booked.ID {
+		t.Errorf("expected an overlap with entry %d, got %v", booked.ID, err)
+	}
+}
+
+func TestTimeEntryService_Validates_All_Paths(t *testing.T) {
+	f := setupFixture(t)
+	service := serviceFor(f, f.employee)
+	booked, err := service.Create(f.employee, createRequest(f, 0, monday))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	later, err := service.Create(f.employee, createRequest(f, 0, monday.AddDate(0, 0, 1)))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if _, err := service.Create(f.employee, createRequest(f, 0, monday.Add(4*time.Hour))); !errors.Is(err, timeentry.ErrTimeEntryOverlaps) {
+		t.Errorf("expected ErrTimeEntryOverlaps on create, got %v", err)
+	}
+
+	update := &dto.UpdateTimeEntryRequest{StartTime: monday, EndTime: monday.AddDate(0, 0, 1).Add(time.Hour), TimeEntryTypeID: f.timeEntryType.ID}
+	if _, err := service.Update(f.employee, booked.ID, update); !errors.Is(err, timeentry.ErrTimeEntryTooLong) {
+		t.Errorf("expected ErrTimeEntryTooLong on update, got %v", err)
+	}
+	update.StartTime = later.StartTime.Add(-time.Hour)
+	update.EndTime = later.StartTime.Add(time.Hour)
+	if _, err := service.Update(f.employee, booked.ID, update); !errors.Is(err, timeentry.ErrTimeEntryOverlaps) {
+		t.Errorf("expected ErrTimeEntryOverlaps on update, got %v", err)
+	}
+
+	tuesday := monday.AddDate(0, 0, 2)
+	_, err = service.Import(f.employee, &dto.ImportTimeEntriesRequest{Entries: []dto.CreateTimeEntryRequest{
+		*createRequest(f, 0, tuesday),
+		*createRequest(f, 0, tuesday.Add(2*time.Hour)),
+	}})
+	var importErr *timeentry.ImportError
+	if !errors.As(err, &importErr) || importErr.Index != 1 || !errors.Is(err, timeentry.ErrTimeEntryOverlaps) {
+		t.Errorf("expected the second imported entry to overlap, got %v", err)
+	}
+	count, _ := repositories.NewTimeEntryRepository(f.db).CountByUserID(f.employee.ID)
+	if count != 2 {
+		t.Errorf("expected a failed import to book nothing, got %d entries", count)
+	}
+
+	imported, err := service.Import(f.employee, &dto.ImportTimeEntriesRequest{Entries: []dto.CreateTimeEntryRequest{
+		*createRequest(f, 0, tuesday),
+		*createRequest(f, 0, tuesday.Add(8*time.Hour)),
+	}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(imported) != 2 {
+		t.Errorf("expected 2 imported entries, got %d", len(imported))
+	}
+
+	// A timer cannot start inside an entry that was booked in advance.
+	if _, err := timerFor(f, f.employee).ClockIn(f.employee, &dto.ClockInRequest{TimeEntryTypeID: f.timeEntryType.ID}, tuesday.Add(time.Hour)); !errors.Is(err, timeentry.ErrTimeEntryOverlaps) {
+		t.Errorf("expected ErrTimeEntryOverlaps on clock in, got %v", err)
+	}
+}
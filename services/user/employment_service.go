@@ -0,0 +1,133 @@
+package user
+
+import (
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/r-52/embrace/models"
+	quotas "github.com/r-52/embrace/models/dto/quota"
+	users "github.com/r-52/embrace/models/dto/user"
+	"github.com/r-52/embrace/repositories"
+	"github.com/r-52/embrace/services/quota"
+	"gorm.io/gorm"
+)
+
+// EmploymentService changes the employment data of users and keeps their quota entitlements in line with it.
+type EmploymentService struct {
+	unitOfWork *repositories.UnitOfWork
+}
+
+type EmploymentServiceInterface interface {
+	UpdateEmployment(actor *models.User, userID uint, req *users.UpdateEmploymentRequest, now time.Time) (*users.EmploymentResponse, error)
+}
+
+// NewEmploymentService creates an EmploymentService. The database should be scoped to the company of the users, see repositories.WithTenant.
+func NewEmploymentService(db *gorm.DB) *EmploymentService {
+	return NewEmploymentServiceWithUnitOfWork(repositories.NewUnitOfWork(db))
+}
+
+// NewEmploymentServiceWithUnitOfWork creates an EmploymentService whose repositories join the given unit of work.
+func NewEmploymentServiceWithUnitOfWork(uow *repositories.UnitOfWork) *EmploymentService {
+	return &EmploymentService{
+		unitOfWork: uow,
+	}
+}
+
+// UpdateEmployment replaces the employment data of a user and recalculates their quota entitlements for the current periods on behalf of the actor.
+// It returns ErrEmploymentEndBeforeStart if the employment would end before it starts and ErrUnknownHolidayCalendar
+// if the holiday calendar does not exist.
+func (s *EmploymentService) UpdateEmployment(actor *models.User, userID uint, req *users.UpdateEmploymentRequest, now time.Time) (*users.EmploymentResponse, error) {
+	start, err := parseDay(req.EmploymentStart)
+	if err != nil {
+		return nil, err
+	}
+	end, err := parseDay(req.EmploymentEnd)
+	if err != nil {
+		return nil, err
+	}
+	if start.Valid && end.Valid && end.Time.Before(start.Time) {
+		return nil, ErrEmploymentEndBeforeStart
+	}
+
+	var response *users.EmploymentResponse
+	err = s.unitOfWork.Transaction(func(uow *repositories.UnitOfWork) error {
+		user, err := uow.Users().GetByID(userID)
+		if err != nil {
+			return err
+		}
+		user.EmploymentStart = start
+		user.EmploymentEnd = end
+		user.WorkingDaysPerWeek = req.WorkingDaysPerWeek
+		if req.DailyWorkingHours > 0 {
+			user.DailyWorkingHours = req.DailyWorkingHours
+		}
+		if req.HolidayCalendarID != nil {
+			user.HolidayCalendarID, err = getHolidayCalendarID(uow, user, *req.HolidayCalendarID)
+			if err != nil {
+				return err
+			}
+		}
+		if err := uow.Users().Update(user); err != nil {
+			return err
+		}
+
+		userQuotas, err := quota.NewQuotaAllocatorWithUnitOfWork(uow).Recalculate(actor, userID, now)
+		if err != nil {
+			return err
+		}
+		response = newEmploymentResponse(user, userQuotas)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return response, nil
+}
+
+func newEmploymentResponse(user *models.User, userQuotas []models.UserQuota) *users.EmploymentResponse {
+	response := &users.EmploymentResponse{
+		UserID:             user.ID,
+		WorkingDaysPerWeek: user.WorkingDaysPerWeek,
+		DailyWorkingHours:  user.DailyWorkingHours,
+		HolidayCalendarID:  user.HolidayCalendarID,
+		Quotas:             []*quotas.UserQuotaResponse{},
+	}
+	if user.EmploymentStart.Valid {
+		response.EmploymentStart = user.EmploymentStart.Time.Format(time.DateOnly)
+	}
+	if user.EmploymentEnd.Valid {
+		response.EmploymentEnd = user.EmploymentEnd.Time.Format(time.DateOnly)
+	}
+	for i := range userQuotas {
+		response.Quotas = append(response.Quotas, quotas.NewUserQuotaResponse(&userQuotas[i]))
+	}
+	return response
+}
+
+// getHolidayCalendarID checks that a holiday calendar belongs to the user's company. The ID 0 stands for no calendar.
+func getHolidayCalendarID(uow *repositories.UnitOfWork, user *models.User, calendarID uint) (*uint, error) {
+	if calendarID == 0 {
+		return nil, nil
+	}
+	calendar, err := uow.HolidayCalendars().GetByID(calendarID)
+	if errors.Is(err, gorm.ErrRecordNotFound) || (err == nil && calendar.CompanyID != user.CompanyID) {
+		return nil, ErrUnknownHolidayCalendar
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &calendar.ID, nil
+}
+
+// parseDay parses a calendar day into midnight UTC. An empty value is an open date.
+func parseDay(value string) (sql.NullTime, error) {
+	if value == "" {
+		return sql.NullTime{}, nil
+	}
+	day, err := time.Parse(time.DateOnly, value)
+	if err != nil {
+		return sql.NullTime{}, err
+	}
+	return sql.NullTime{Time: day, Valid: true}, nil
+}
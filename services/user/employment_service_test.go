@@ -0,0 +1,98 @@
+package user_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/r-52/embrace/models"
+	users "github.com/r-52/embrace/models/dto/user"
+	"github.com/r-52/embrace/repositories"
+	"github.com/r-52/embrace/services/user"
+	"gorm.io/gorm"
+)
+
+func setupEmployment(t *testing.T) (*gorm.DB, *models.User, *models.UserQuota) {
+	db := setupDb(t)
+	if err := db.AutoMigrate(&models.Quota{}, &models.UserQuota{}, &models.UserQuotaTransaction{}, &models.HolidayCalendar{}, &models.Holiday{}); err != nil {
+		t.Fatalf("failed to migrate database: %v", err)
+	}
+	company := &models.Company{Name: "acme", PrimaryEmail: "acme@example.com"}
+	db.Create(company)
+	employee := &models.User{Email: "employee@example.com", Password: "secret", CompanyID: company.ID, UserProfile: models.UserProfile{Slug: "employee"}}
+	db.Create(employee)
+	vacation := &models.Quota{Name: "vacation", CompanyID: company.ID, Count: models.QuotaUnits(30), QuotaResetAt: models.QUOTA_RESET_FIRST_OF_YEAR}
+	db.Create(vacation)
+	userQuota := &models.UserQuota{UserID: employee.ID, QuotaID: vacation.ID, Count: models.QuotaUnits(25), Entitlement: models.QuotaUnits(30)}
+	db.Create(userQuota)
+	return repositories.WithTenant(db, company.ID), employee, userQuota
+}
+
+func TestEmploymentService_UpdateEmployment_Recalculates_Quotas(t *testing.T) {
+	db, employee, userQuota := setupEmployment(t)
+	now := time.Date(2024, 9, 1, 12, 0, 0, 0, time.UTC)
+
+	req := &users.UpdateEmploymentRequest{EmploymentStart: "2024-07-01", WorkingDaysPerWeek: 4, DailyWorkingHours: 6.5}
+	res, err := user.NewEmploymentService(db).UpdateEmployment(employee, employee.ID, req, now)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if res.EmploymentStart != "2024-07-01" || res.EmploymentEnd != "" || res.WorkingDaysPerWeek != 4 || res.DailyWorkingHours != 6.5 {
+		t.Errorf("expected the employment data to be returned, got %+v", res)
+	}
+	if len(res.Quotas) != 1 || res.Quotas[0].Entitlement.Units() != 12 || res.Quotas[0].Count.Units() != 7 || res.Quotas[0].Name != "vacation" {
+		t.Errorf("expected an entitlement of 12 with 5 days consumed, got %+v", res.Quotas)
+	}
+
+	updated, _ := repositories.NewUserRepository(db).GetByID(employee.ID)
+	if !updated.EmploymentStart.Valid || updated.WorkingDaysPerWeek != 4 || updated.DailyWorkingHours != 6.5 {
+		t.Errorf("expected the employment data to be stored, got %+v", updated)
+	}
+	stored, _ := repositories.NewUserQuotaRepository(db).GetByID(userQuota.ID)
+	if stored.Count.Units() != 7 {
+		t.Errorf("expected count 7, got %v", stored.Count)
+	}
+}
+
+func TestEmploymentService_UpdateEmployment_Rejects_End_Before_Start(t *testing.T) {
+	db, employee, _ := setupEmployment(t)
+
+	req := &users.UpdateEmploymentRequest{EmploymentStart: "2024-07-01", EmploymentEnd: "2024-06-30", WorkingDaysPerWeek: 5}
+	if _, err := user.NewEmploymentService(db).UpdateEmployment(employee, employee.ID, req, time.Now()); !errors.Is(err, user.ErrEmploymentEndBeforeStart) {
+		t.Errorf("expected ErrEmploymentEndBeforeStart, got %v", err)
+	}
+}
+
+func TestEmploymentService_UpdateEmployment_Assigns_Holiday_Calendar(t *testing.T) {
+	db, employee, _ := setupEmployment(t)
+	calendar := &models.HolidayCalendar{CompanyID: employee.CompanyID, Name: "Munich", Region: "DE-BY"}
+	if err := repositories.NewHolidayCalendarRepository(db).Create(calendar); err != nil {
+		t.Fatalf("failed to create calendar: %v", err)
+	}
+
+	req := &users.UpdateEmploymentRequest{WorkingDaysPerWeek: 5, HolidayCalendarID: &calendar.ID}
+	res, err := user.NewEmploymentService(db).UpdateEmployment(employee, employee.ID, req, time.Now())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if res.HolidayCalendarID == nil || *res.HolidayCalendarID != calendar.ID {
+		t.Errorf("expected calendar %d to be assigned, got %v", calendar.ID, res.HolidayCalendarID)
+	}
+
+	// Omitting the calendar keeps it, 0 removes it.
+	req.HolidayCalendarID = nil
+	if res, _ := user.NewEmploymentService(db).UpdateEmployment(employee, employee.ID, req, time.Now()); res.HolidayCalendarID == nil {
+		t.Errorf("expected the calendar to be kept")
+	}
+	none := uint(0)
+	req.HolidayCalendarID = &none
+	if res, _ := user.NewEmploymentService(db).UpdateEmployment(employee, employee.ID, req, time.Now()); res.HolidayCalendarID != nil {
+		t.Errorf("expected the calendar to be removed, got %v", *res.HolidayCalendarID)
+	}
+
+	unknown := calendar.ID + 100
+	req.HolidayCalendarID = &unknown
+	if _, err := user.NewEmploymentService(db).UpdateEmployment(employee, employee.ID, req, time.Now()); !errors.Is(err, user.ErrUnknownHolidayCalendar) {
+		t.Errorf("expected ErrUnknownHolidayCalendar, got %v", err)
+	}
+}
@@ -0,0 +1,15 @@
+package user
+
+import "errors"
+
+// ErrEmailAlreadyExists is returned when a user with the requested email is already registered.
+var ErrEmailAlreadyExists = errors.New("E1000")
+
+// ErrInvalidRole is returned when the requested role does not exist in the user's company.
+var ErrInvalidRole = errors.New("E1002")
+
+// ErrEmploymentEndBeforeStart is returned when the last day of employment lies before the first one.
+var ErrEmploymentEndBeforeStart = errors.New("E1003")
+
+// ErrUnknownHolidayCalendar is returned when a user should be assigned a holiday calendar that does not exist in their company.
+var ErrUnknownHolidayCalendar = errors.New("E1004")
@@ -2,31 +2,53 @@ package user
 
 import (
 	"errors"
+	"fmt"
+	"strings"
+	"unicode"
 
 	"github.com/r-52/embrace/models"
 	users "github.com/r-52/embrace/models/dto/user"
 	"github.com/r-52/embrace/repositories"
+	"github.com/r-52/embrace/services/role"
 	"gorm.io/gorm"
 )
 
 type UserCreator struct {
-	userRepository *repositories.UserRepository
+	userRepository        *repositories.UserRepository
+	userProfileRepository *repositories.UserProfileRepository
+	userRoleRepository    *repositories.UserRoleRepository
 }
 
 func NewUserCreator(db *gorm.DB) *UserCreator {
+	return NewUserCreatorWithUnitOfWork(repositories.NewUnitOfWork(db))
+}
+
+// NewUserCreatorWithUnitOfWork creates a UserCreator whose repositories join the given unit of work,
+// so the user, its profile and its role are written in the caller's transaction.
+func NewUserCreatorWithUnitOfWork(uow *repositories.UnitOfWork) *UserCreator {
 	return &UserCreator{
-		userRepository: repositories.NewUserRepository(db),
+		userRepository:        uow.Users(),
+		userProfileRepository: uow.UserProfiles(),
+		userRoleRepository:    uow.UserRoles(),
 	}
 }
 
+// CreateUser creates a user with its profile and attaches it to an existing role of its company.
+// If no role ID is given the company's default employee role is used.
+// It returns ErrEmailAlreadyExists if the email is taken and ErrInvalidRole if the role does not belong to the company.
 func (userCreator *UserCreator) CreateUser(req *users.CreateUserRequest) (*users.CreateUserResponse, error) {
 	// TODO: validate struct
 	maybeUser, err := userCreator.userRepository.GetByEmail(req.Email)
-	if !errors.Is(err, gorm.ErrRecordNotFound) {
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
 		return nil, err
 	}
 	if maybeUser != nil {
-		return nil, errors.New("E1000")
+		return nil, ErrEmailAlreadyExists
+	}
+
+	roleID, err := userCreator.resolveRoleID(req)
+	if err != nil {
+		return nil, err
 	}
 
 	passwordService := NewPasswordService(req.Password)
@@ -35,6 +57,11 @@ func (userCreator *UserCreator) CreateUser(req *users.CreateUserRequest) (*users
 		return nil, err
 	}
 
+	slug, err := userCreator.uniqueSlug(req)
+	if err != nil {
+		return nil, err
+	}
+
 	user := models.User{
 		Email:     req.Email,
 		Password:  hashedPassword,
@@ -46,12 +73,9 @@ func (userCreator *UserCreator) CreateUser(req *users.CreateUserRequest) (*users
 			Location:  req.Location,
 			Title:     req.Title,
 			Position:  req.Position,
+			Slug:      slug,
 		},
-		Role: models.UserRole{
-			InternalUsage: 1,
-			Name:          "admin",
-			CompanyID:     req.CompanyID,
-		},
+		RoleID: roleID,
 	}
 
 	err = userCreator.userRepository.Create(&user)
@@ -62,5 +86,72 @@ func (userCreator *UserCreator) CreateUser(req *users.CreateUserRequest) (*users
 		ID:        user.ID,
 		Email:     user.Email,
 		CompanyID: user.CompanyID,
+		RoleID:    user.RoleID,
 	}, nil
 }
+
+func (userCreator *UserCreator) resolveRoleID(req *users.CreateUserRequest) (uint, error) {
+	if req.RoleID == 0 {
+		employee, err := userCreator.userRoleRepository.GetByCompanyIDAndName(req.CompanyID, role.DEFAULT_ROLE_EMPLOYEE)
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return 0, ErrInvalidRole
+		}
+		if err != nil {
+			return 0, err
+		}
+		return employee.ID, nil
+	}
+
+	existing, err := userCreator.userRoleRepository.GetByID(req.RoleID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return 0, ErrInvalidRole
+	}
+	if err != nil {
+		return 0, err
+	}
+	if existing.CompanyID != req.CompanyID {
+		return 0, ErrInvalidRole
+	}
+	return existing.ID, nil
+}
+
+// uniqueSlug derives a profile slug from the user's name, falling back to the
+// local part of the email, and appends a numeric suffix until it is unused.
+func (userCreator *UserCreator) uniqueSlug(req *users.CreateUserRequest) (string, error) {
+	base := slugify(req.FirstName + " " + req.LastName)
+	if base == "" {
+		base = slugify(strings.Split(req.Email, "@")[0])
+	}
+	if base == "" {
+		base = "user"
+	}
+
+	slug := base
+	for i := 2; ; i++ {
+		_, err := userCreator.userProfileRepository.GetBySlug(slug)
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return slug, nil
+		}
+		if err != nil {
+			return "", err
+		}
+		slug = fmt.Sprintf("%s-%d", base, i)
+	}
+}
+
+func slugify(value string) string {
+	var builder strings.Builder
+	dash := false
+	for _, r := range strings.ToLower(value) {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			builder.WriteRune(r)
+			dash = false
+			continue
+		}
+		if !dash && builder.Len() > 0 {
+			builder.WriteRune('-')
+			dash = true
+		}
+	}
+	return strings.TrimSuffix(builder.String(), "-")
+}
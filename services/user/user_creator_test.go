@@ -0,0 +1,93 @@
+package user_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/r-52/embrace/models"
+	users "github.com/r-52/embrace/models/dto/user"
+	"github.com/r-52/embrace/repositories"
+	"github.com/r-52/embrace/services/role"
+	"github.com/r-52/embrace/services/user"
+	"gorm.io/gorm"
+)
+
+func setupDb(t *testing.T) *gorm.DB {
+	db := repositories.GetDatabase()
+	err := db.AutoMigrate(&models.Company{}, &models.User{}, &models.UserProfile{}, &models.UserRole{}, &models.RolePermission{})
+	if err != nil {
+		t.Fatalf("failed to migrate database: %v", err)
+	}
+	for _, companyID := range []uint{1, 2} {
+		if err := role.NewRoleService(db).SeedDefaultRoles(companyID); err != nil {
+			t.Fatalf("failed to seed roles: %v", err)
+		}
+	}
+	return db
+}
+
+func newCreateUserRequest(email string, companyID, roleID uint) *users.CreateUserRequest {
+	return &users.CreateUserRequest{
+		Email:     email,
+		Password:  "password",
+		CompanyID: companyID,
+		RoleID:    roleID,
+		FirstName: "Test",
+		LastName:  "User",
+	}
+}
+
+func TestUserCreator_CreateUser_Uses_Employee_Role_By_Default(t *testing.T) {
+	db := setupDb(t)
+	employee, _ := repositories.NewUserRoleRepository(db).GetByCompanyIDAndName(1, role.DEFAULT_ROLE_EMPLOYEE)
+
+	created, err := user.NewUserCreator(db).CreateUser(newCreateUserRequest("employee@test.com", 1, 0))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if created.RoleID != employee.ID {
+		t.Errorf("expected employee role %d, got %d", employee.ID, created.RoleID)
+	}
+}
+
+func TestUserCreator_CreateUser_Attaches_Existing_Role(t *testing.T) {
+	db := setupDb(t)
+	manager, _ := repositories.NewUserRoleRepository(db).GetByCompanyIDAndName(1, role.DEFAULT_ROLE_MANAGER)
+	countBefore, _ := repositories.NewUserRoleRepository(db).CountByCompanyID(1)
+
+	created, err := user.NewUserCreator(db).CreateUser(newCreateUserRequest("manager@test.com", 1, manager.ID))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if created.RoleID != manager.ID {
+		t.Errorf("expected manager role %d, got %d", manager.ID, created.RoleID)
+	}
+
+	countAfter, _ := repositories.NewUserRoleRepository(db).CountByCompanyID(1)
+	if countAfter != countBefore {
+		t.Errorf("expected no new role to be created, had %d roles, now %d", countBefore, countAfter)
+	}
+}
+
+func TestUserCreator_CreateUser_Rejects_Role_Of_Other_Company(t *testing.T) {
+	db := setupDb(t)
+	foreign, _ := repositories.NewUserRoleRepository(db).GetByCompanyIDAndName(2, role.DEFAULT_ROLE_ADMIN)
+
+	_, err := user.NewUserCreator(db).CreateUser(newCreateUserRequest("foreign@test.com", 1, foreign.ID))
+	if !errors.Is(err, user.ErrInvalidRole) {
+		t.Errorf("expected ErrInvalidRole, got %v", err)
+	}
+}
+
+func TestUserCreator_CreateUser_With_Duplicate_Email(t *testing.T) {
+	db := setupDb(t)
+	creator := user.NewUserCreator(db)
+
+	if _, err := creator.CreateUser(newCreateUserRequest("twice@test.com", 1, 0)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	_, err := creator.CreateUser(newCreateUserRequest("twice@test.com", 1, 0))
+	if !errors.Is(err, user.ErrEmailAlreadyExists) {
+		t.Errorf("expected ErrEmailAlreadyExists, got %v", err)
+	}
+}